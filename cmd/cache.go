@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pragmaticivan/faro/internal/scancache"
+	"github.com/pragmaticivan/faro/internal/vuln"
+	"github.com/spf13/cobra"
+)
+
+// clearCacheCmd removes cached OSV vulnerability responses and scan results.
+var clearCacheCmd = &cobra.Command{
+	Use:   "clear-cache",
+	Short: "Remove cached vulnerability data and scan results",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := vuln.DefaultCacheDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := vuln.NewDiskCache(dir, 0, nil).Clear(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Cleared vulnerability cache at %s\n", dir)
+
+		dir, err = scancache.DefaultDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := scancache.New(dir, scancache.DefaultTTL, nil).Clear(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Cleared scan cache at %s\n", dir)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(clearCacheCmd)
+}
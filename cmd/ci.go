@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ciProviderFlag       string
+	ciReportFlag         string
+	ciFailOnOutdatedFlag bool
+)
+
+// ciCmd runs a non-interactive scan with CI-friendly defaults.
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Check dependencies with CI-friendly output and exit codes",
+	Long: `ci runs a one-shot scan with vulnerability checks enabled and reports in the
+format native to the CI system it detects:
+
+  GitHub Actions  ::warning:: annotations for vulnerable dependencies and a
+                  Markdown table appended to $GITHUB_STEP_SUMMARY
+  GitLab CI       a Code Quality report (gl-code-quality-report.json)
+
+It exits non-zero when a dependency's current version has known
+vulnerabilities or the vulnerability check fails, or on any available
+update with --fail-on-outdated.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := app.RunCI(
+			app.CIOptions{
+				Manager:        managerFlag,
+				Filter:         filterFlag,
+				Dev:            devFlag,
+				Transitive:     transitiveFlag,
+				All:            allFlag,
+				NoDev:          noDevFlag,
+				Cooldown:       cooldownFlag,
+				Provider:       ciProviderFlag,
+				ReportPath:     ciReportFlag,
+				FailOnOutdated: ciFailOnOutdatedFlag,
+				NoCache:        noCacheFlag,
+				CacheTTL:       cacheTTLFlag,
+				OSVURL:         osvURLFlag,
+				VulnSource:     vulnSourceFlag,
+				GoEnv:          goEnvFlag,
+				Registry:       registryFlag,
+				NoColor:        noColorFlag,
+				Path:           pathFlag,
+				Timeout:        timeoutFlag,
+			},
+			app.Deps{
+				Out:    os.Stdout,
+				Now:    time.Now,
+				Getenv: os.Getenv,
+			},
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	ciCmd.Flags().StringVarP(&filterFlag, "filter", "f", "", "Filter packages by name (substring or regex; a trailing / matches a path prefix such as golang.org/x/)")
+	ciCmd.Flags().BoolVar(&devFlag, "dev", false, "Include dev and test dependencies hidden by default (npm peer/optional, Python dependency groups and extras)")
+	ciCmd.Flags().BoolVar(&transitiveFlag, "transitive", false, "Include dependencies the project doesn't declare (Go modules not in go.mod, undeclared packages)")
+	ciCmd.Flags().BoolVar(&allFlag, "all", false, "Include dev and transitive dependencies")
+	_ = ciCmd.Flags().MarkDeprecated("all", "use --dev and --transitive")
+	ciCmd.Flags().BoolVar(&noDevFlag, "no-dev", false, "Exclude dev and test dependencies, even with --all")
+	ciCmd.Flags().IntVarP(&cooldownFlag, "cooldown", "c", 0, "Minimum age (days) for an update to be considered")
+	ciCmd.Flags().StringVarP(&managerFlag, "manager", "m", "", "Package manager to use (go, npm, yarn, pnpm, pip, poetry, uv, maven, gradle, bundler, conda)")
+	ciCmd.Flags().StringVar(&ciProviderFlag, "provider", "", "CI provider override (local, github, gitlab); auto-detected by default")
+	ciCmd.Flags().StringVar(&ciReportFlag, "report", app.DefaultGitLabReportPath, "Path of the GitLab Code Quality report, relative to the project directory")
+	ciCmd.Flags().BoolVar(&ciFailOnOutdatedFlag, "fail-on-outdated", false, "Exit non-zero when any update is available")
+	ciCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Query OSV directly instead of using cached vulnerability data")
+	ciCmd.Flags().DurationVar(&cacheTTLFlag, "cache-ttl", 0, "How long cached vulnerability data stays fresh, e.g. 6h (default 24h, also honors FARO_CACHE_TTL)")
+	ciCmd.Flags().StringVar(&vulnSourceFlag, "vuln-source", "osv", "Vulnerability database to query: osv or ghsa (GitHub Advisory Database, needs GITHUB_TOKEN)")
+	ciCmd.Flags().StringArrayVar(&goEnvFlag, "go-env", nil, "Extra KEY=VALUE environment for go commands, e.g. GOPROXY for private modules (repeatable)")
+	ciCmd.Flags().StringVar(&registryFlag, "registry", "", "Registry URL for version lookups: sets GOPROXY for Go and npm_config_registry for npm, yarn and pnpm scans")
+	ciCmd.Flags().StringVar(&osvURLFlag, "osv-url", "", "OSV API endpoint for vulnerability checks, e.g. a private mirror (also honors FARO_OSV_URL)")
+	ciCmd.Flags().DurationVar(&timeoutFlag, "timeout", defaultTimeout, "Maximum time to wait for the package manager while scanning (0 disables)")
+	ciCmd.Flags().StringVarP(&pathFlag, "path", "C", "", "Project directory to scan (defaults to the current directory)")
+	ciCmd.Flags().BoolVar(&noColorFlag, "no-color", false, "Disable colored output (also honors NO_COLOR)")
+	rootCmd.AddCommand(ciCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pragmaticivan/faro/internal/detector"
+	"github.com/spf13/cobra"
+)
+
+// detectFormatFlag selects the detect output: a table by default, or json.
+var detectFormatFlag string
+
+// detectCmd lists the package managers detected in the current directory.
+var detectCmd = &cobra.Command{
+	Use:   "detect",
+	Short: "List the package managers detected in the current directory",
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := os.Getwd()
+		if err == nil {
+			err = runDetect(cmd.OutOrStdout(), dir, detectFormatFlag)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// runDetect prints every manager detected in dir with its config and lock
+// files, followed by the one faro would use. With format "json" it writes
+// the detected managers as a JSON array instead.
+func runDetect(out io.Writer, dir, format string) error {
+	if format != "" && format != "json" {
+		return fmt.Errorf("unsupported --format value: %q (supported: json)", format)
+	}
+	results, err := detector.Detect(dir)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "MANAGER\tCONFIG\tLOCK")
+	for _, r := range results {
+		lock := r.LockFile
+		if lock == "" {
+			lock = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Manager, r.ConfigFile, lock)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	selected, err := detector.DetectSingle(dir)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "\nSelected: %s\n", selected.Manager)
+	return nil
+}
+
+func init() {
+	detectCmd.Flags().StringVar(&detectFormatFlag, "format", "", "Output format: json for a machine-readable list (defaults to a table)")
+	rootCmd.AddCommand(detectCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectCommand(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"go.mod", "package.json", "package-lock.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	t.Chdir(dir)
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"detect"})
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	})
+
+	Execute()
+
+	want := "MANAGER  CONFIG        LOCK\n" +
+		"go       go.mod        go.sum\n" +
+		"npm      package.json  package-lock.json\n" +
+		"\nSelected: go\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDetectCommand_JSON(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"go.mod", "package.json", "package-lock.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	t.Chdir(dir)
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(&buf)
+	rootCmd.SetArgs([]string{"detect", "--format", "json"})
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+		detectFormatFlag = ""
+	})
+
+	Execute()
+
+	var got []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", buf.String(), err)
+	}
+	want := []map[string]string{
+		{"manager": "go", "configFile": "go.mod", "lockFile": "go.sum"},
+		{"manager": "npm", "configFile": "package.json", "lockFile": "package-lock.json"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected managers %v, want %v", got, want)
+	}
+	for i := range want {
+		if !maps.Equal(got[i], want[i]) {
+			t.Errorf("entry %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if err := runDetect(&buf, dir, "yaml"); err == nil || !strings.Contains(err.Error(), "--format") {
+		t.Errorf("expected an unsupported --format error, got %v", err)
+	}
+}
+
+func TestRunDetect_NothingFound(t *testing.T) {
+	var buf bytes.Buffer
+	err := runDetect(&buf, t.TempDir(), "")
+	if err == nil || !strings.Contains(err.Error(), "no supported package manager") {
+		t.Fatalf("expected the no supported package manager error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
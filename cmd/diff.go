@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/app"
+	"github.com/pragmaticivan/faro/internal/format"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd compares two JSON reports, or one with a fresh scan.
+var diffCmd = &cobra.Command{
+	Use:   "diff <old.json> [new.json]",
+	Short: "Show what changed since an earlier --format json report",
+	Long: `diff compares two reports written by faro --format json and lists the updates
+that became available or are no longer listed, and the vulnerabilities that were
+fixed or introduced. Without new.json it scans the project (-C, --manager) with
+vulnerability checks, so save reports with -v to compare vulnerabilities.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		newPath := ""
+		if len(args) == 2 {
+			newPath = args[1]
+		}
+		if err := runDiff(cmd.OutOrStdout(), args[0], newPath, scanReport); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// runDiff prints the changes from the report at oldPath to the one at
+// newPath, or to the report scan writes when newPath is empty.
+func runDiff(out io.Writer, oldPath, newPath string, scan func(io.Writer) error) error {
+	older, err := readReport(oldPath)
+	if err != nil {
+		return err
+	}
+
+	var newer format.Report
+	if newPath != "" {
+		newer, err = readReport(newPath)
+	} else {
+		var buf bytes.Buffer
+		if err = scan(&buf); err == nil {
+			newer, err = format.ReadJSON(&buf)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return format.WriteDiff(out, format.DiffReports(older, newer))
+}
+
+// readReport reads the JSON report at path.
+func readReport(path string) (format.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return format.Report{}, err
+	}
+	defer func() { _ = f.Close() }()
+	report, err := format.ReadJSON(f)
+	if err != nil {
+		return format.Report{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return report, nil
+}
+
+// scanReport writes a --format json report, with vulnerability counts, of
+// the project selected by -C and --manager.
+func scanReport(w io.Writer) error {
+	return app.Run(app.RunOptions{
+		FormatFlag:          "json",
+		ShowVulnerabilities: true,
+		Manager:             managerFlag,
+		Path:                pathFlag,
+		Timeout:             defaultTimeout,
+	}, app.Deps{Out: w, Now: time.Now})
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&pathFlag, "path", "C", "", "Project directory to scan when new.json is omitted (defaults to the current directory)")
+	diffCmd.Flags().StringVarP(&managerFlag, "manager", "m", "", "Package manager to scan with when new.json is omitted (auto, all, go, npm, ...)")
+	rootCmd.AddCommand(diffCmd)
+}
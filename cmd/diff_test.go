@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old.json")
+	newPath := filepath.Join(dir, "new.json")
+	if err := os.WriteFile(oldPath, []byte(`{"schemaVersion":1,"projects":[{"dir":".","manager":"go","updates":[
+		{"name":"a","version":"v1.0.0","update":{"version":"v1.1.0"}}]}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	newReport := `{"schemaVersion":1,"projects":[{"dir":".","manager":"go","updates":[
+		{"name":"b","version":"v1.0.0","update":{"version":"v2.0.0"}}]}]}`
+	if err := os.WriteFile(newPath, []byte(newReport), 0644); err != nil {
+		t.Fatal(err)
+	}
+	noScan := func(io.Writer) error {
+		t.Fatal("expected no scan when new.json is given")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := runDiff(&buf, oldPath, newPath, noScan); err != nil {
+		t.Fatalf("runDiff() error: %v", err)
+	}
+	want := "New updates (1):\n . go: b  v1.0.0 → v2.0.0\n\nNo longer listed (1):\n . go: a  v1.0.0 → v1.1.0\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output %q, want %q", got, want)
+	}
+
+	// Without new.json the old report is compared with a scan
+	buf.Reset()
+	scan := func(w io.Writer) error {
+		_, err := io.WriteString(w, newReport)
+		return err
+	}
+	if err := runDiff(&buf, oldPath, "", scan); err != nil {
+		t.Fatalf("runDiff() error: %v", err)
+	}
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected output against a scan %q, want %q", got, want)
+	}
+
+	if err := runDiff(&buf, filepath.Join(dir, "missing.json"), newPath, noScan); err == nil {
+		t.Error("expected a missing report to fail")
+	}
+	if err := os.WriteFile(oldPath, []byte("Checking for updates...\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runDiff(&buf, oldPath, newPath, noScan); err == nil || !strings.Contains(err.Error(), "old.json") {
+		t.Errorf("expected an invalid report to fail naming the file, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pragmaticivan/faro/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+// restoreCmd puts back the files backed up by an upgrade run with --backup.
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the manifests and lockfiles backed up by --backup",
+	Long: `restore moves every <file>.faro.bak left by an upgrade run with --backup back
+over the original file, undoing the changes faro made to it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := pathFlag
+		var err error
+		if dir == "" {
+			dir, err = os.Getwd()
+		}
+		if err == nil {
+			err = runRestore(cmd.OutOrStdout(), dir)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// runRestore restores the backups in dir and lists the files it put back.
+func runRestore(out io.Writer, dir string) error {
+	restored, err := updater.RestoreBackups(dir)
+	for _, file := range restored {
+		_, _ = fmt.Fprintf(out, "Restored %s\n", file)
+	}
+	if err != nil {
+		return err
+	}
+	if len(restored) == 0 {
+		return fmt.Errorf("no %s files found in %s", updater.BackupSuffix, dir)
+	}
+	return nil
+}
+
+func init() {
+	restoreCmd.Flags().StringVarP(&pathFlag, "path", "C", "", "Project directory to restore (defaults to the current directory)")
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+func TestRunRestore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"changed":true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "package.json"+updater.BackupSuffix), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := runRestore(&buf, dir); err != nil {
+		t.Fatalf("runRestore() error: %v", err)
+	}
+	if got, want := buf.String(), "Restored package.json\n"; got != want {
+		t.Fatalf("unexpected output %q, want %q", got, want)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "package.json")); string(data) != `{}` {
+		t.Fatalf("expected package.json to be restored, got %s", data)
+	}
+
+	if err := runRestore(&buf, dir); err == nil {
+		t.Fatalf("expected an error when no backups are left")
+	}
+}
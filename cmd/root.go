@@ -7,6 +7,7 @@ import (
 
 	"github.com/pragmaticivan/faro/internal/app"
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/selfupdate"
 	"github.com/pragmaticivan/faro/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -16,13 +17,56 @@ var (
 	upgradeFlag         bool
 	verifyFlag          bool // Interactive mode (verify/select); using -i
 	filterFlag          string
-	allFlag             bool
+	allFlag             bool // Deprecated alias for --dev --transitive
+	devFlag             bool
+	noDevFlag           bool
+	transitiveFlag      bool
 	cooldownFlag        int
 	formatFlag          string
+	templateFlag        string
+	maxResultsFlag      int
 	vulnerabilitiesFlag bool
 	managerFlag         string // Package manager override
+	allowGoBumpFlag     bool
+	noCacheFlag         bool
+	cacheTTLFlag        time.Duration
+	cacheScanFlag       bool
+	noScanCacheFlag     bool
+	osvURLFlag          string
+	vulnSourceFlag      string
+	goEnvFlag           []string
+	registryFlag        string
+	noColorFlag         bool
+	quietFlag           bool
+	countFlag           bool
+	verboseFlag         bool
+	logLevelFlag        string
+	depTypeFlag         []string
+	majorOnlyFlag       bool
+	frozenFlag          bool
+	recursiveFlag       bool
+	maxDepthFlag        int
+	pathFlag            string
+	manifestFlag        string
+	timeoutFlag         time.Duration
+	sortFlag            string
+	groupByFlag         string
+	inRangeFlag         bool
+	patchFlag           bool
+	diffVersionsFlag    bool
+	vulnOnlyFlag        bool
+	failOnVulnFlag      string
+	minSeverityFlag     string
+	yesFlag             bool
+	verifyCmdFlag       string
+	backupFlag          bool
+	verifySumsFlag      bool
+	checkSelfFlag       bool
 )
 
+// defaultTimeout bounds how long scanning may wait on the package manager.
+const defaultTimeout = 120 * time.Second
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "faro",
@@ -31,16 +75,63 @@ var rootCmd = &cobra.Command{
 
 It allows you to list available updates, interactively select them, and upgrade your lockfiles for Go, Node.js, and Python projects.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if checkSelfFlag {
+			if err := checkSelf(os.Stdout, selfupdate.New(nil), selfupdate.CurrentVersion(), true); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		err := app.Run(
 			app.RunOptions{
 				Upgrade:             upgradeFlag,
 				Interactive:         verifyFlag,
 				Filter:              filterFlag,
+				Dev:                 devFlag,
+				Transitive:          transitiveFlag,
 				All:                 allFlag,
+				NoDev:               noDevFlag,
 				Cooldown:            cooldownFlag,
 				FormatFlag:          formatFlag,
+				Template:            templateFlag,
+				MaxResults:          maxResultsFlag,
 				ShowVulnerabilities: vulnerabilitiesFlag,
 				Manager:             managerFlag,
+				AllowGoBump:         allowGoBumpFlag,
+				NoCache:             noCacheFlag,
+				CacheTTL:            cacheTTLFlag,
+				ScanCache:           cacheScanFlag,
+				NoScanCache:         noScanCacheFlag,
+				OSVURL:              osvURLFlag,
+				VulnSource:          vulnSourceFlag,
+				GoEnv:               goEnvFlag,
+				Registry:            registryFlag,
+				NoColor:             noColorFlag,
+				Quiet:               quietFlag,
+				Count:               countFlag,
+				Verbose:             verboseFlag,
+				LogLevel:            logLevelFlag,
+				DepTypes:            depTypeFlag,
+				MajorOnly:           majorOnlyFlag,
+				Frozen:              frozenFlag,
+				Yes:                 yesFlag,
+				VerifyCmd:           verifyCmdFlag,
+				Backup:              backupFlag,
+				VerifySums:          verifySumsFlag,
+				Recursive:           recursiveFlag,
+				MaxDepth:            maxDepthFlag,
+				Path:                pathFlag,
+				Manifest:            manifestFlag,
+				Timeout:             timeoutFlag,
+				Sort:                sortFlag,
+				GroupBy:             groupByFlag,
+				InRange:             inRangeFlag,
+				Patch:               patchFlag,
+				DiffVersions:        diffVersionsFlag,
+				VulnOnly:            vulnOnlyFlag,
+				FailOnVuln:          failOnVulnFlag,
+				MinSeverity:         minSeverityFlag,
 			},
 			app.Deps{
 				Out: os.Stdout,
@@ -51,9 +142,11 @@ It allows you to list available updates, interactively select them, and upgrade
 			},
 		)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			// stderr keeps --format json/csv output on stdout parseable
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		autoCheckSelf()
 	},
 }
 
@@ -67,11 +160,52 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().BoolVarP(&upgradeFlag, "upgrade", "u", false, "Upgrade all packages to the latest version")
+	rootCmd.Flags().BoolVarP(&yesFlag, "yes", "y", false, "Apply -u upgrades without asking for confirmation (required when stdin is not a terminal)")
+	rootCmd.Flags().StringVar(&verifyCmdFlag, "verify-cmd", "", "Shell command run after -u applies updates, e.g. 'go build ./...'; restores the manifest and lockfile if it fails")
+	rootCmd.Flags().BoolVar(&backupFlag, "backup", false, "Copy each manifest and lockfile to <file>.faro.bak before updating; undo with faro restore")
+	rootCmd.Flags().BoolVar(&verifySumsFlag, "verify-sums", false, "Run go mod verify after upgrading Go modules and fail if a downloaded module doesn't match go.sum")
 	rootCmd.Flags().BoolVarP(&verifyFlag, "interactive", "i", false, "Interactive mode")
-	rootCmd.Flags().StringVarP(&filterFlag, "filter", "f", "", "Filter packages using regex")
-	rootCmd.Flags().BoolVar(&allFlag, "all", false, "Include transitive updates (not listed in go.mod)")
+	rootCmd.Flags().BoolVar(&recursiveFlag, "recursive", false, "Scan every project found in subdirectories (skips node_modules, vendor, .venv, .git and .gitignore-excluded directories)")
+	rootCmd.Flags().IntVar(&maxDepthFlag, "max-depth", 3, "How many directory levels --recursive descends")
+	rootCmd.Flags().BoolVar(&frozenFlag, "frozen", false, "Only report updates; never modify project files (errors with -u, ignores -i)")
+	rootCmd.Flags().StringVarP(&filterFlag, "filter", "f", "", "Filter packages by name (substring or regex; a trailing / matches a path prefix such as golang.org/x/)")
+	rootCmd.Flags().BoolVar(&devFlag, "dev", false, "Include dev and test dependencies hidden by default (npm peer/optional, Python dependency groups and extras)")
+	rootCmd.Flags().BoolVar(&transitiveFlag, "transitive", false, "Include dependencies the project doesn't declare (Go modules not in go.mod, undeclared packages)")
+	rootCmd.Flags().BoolVar(&allFlag, "all", false, "Include dev and transitive dependencies")
+	_ = rootCmd.Flags().MarkDeprecated("all", "use --dev and --transitive")
+	rootCmd.Flags().BoolVar(&noDevFlag, "no-dev", false, "Exclude dev and test dependencies, even with --all")
 	rootCmd.Flags().IntVarP(&cooldownFlag, "cooldown", "c", 0, "Minimum age (days) for an update to be considered")
-	rootCmd.Flags().StringVar(&formatFlag, "format", "", "Output format modifiers: group,lines,time (comma-delimited)")
+	rootCmd.Flags().StringVar(&formatFlag, "format", "", "Output format modifiers: group,lines,time,json,ndjson,csv,releases,delta (comma-delimited)")
+	rootCmd.Flags().StringVar(&templateFlag, "template", "", "Render each update with a Go text/template, e.g. '{{.Name}}:{{.Update.Version}}'")
+	rootCmd.Flags().IntVar(&maxResultsFlag, "max-results", 0, "Print at most N updates, then how many more there are (0 for no limit; machine-readable formats stay complete)")
+	rootCmd.Flags().StringVar(&groupByFlag, "group-by", "", "Group updates by bump, type, scope, workspace or manager (implies --format group)")
+	rootCmd.Flags().StringVar(&sortFlag, "sort", "", "Order updates by name, bump, age or severity (severity requires -v)")
 	rootCmd.Flags().BoolVarP(&vulnerabilitiesFlag, "vulnerabilities", "v", false, "Show vulnerability counts for current and updated versions")
-	rootCmd.Flags().StringVarP(&managerFlag, "manager", "m", "", "Package manager to use (go, npm, yarn, pnpm, pip, poetry, uv)")
+	rootCmd.Flags().BoolVar(&vulnOnlyFlag, "vuln-only", false, "Only list dependencies with known vulnerabilities in their current version (implies -v)")
+	rootCmd.Flags().StringVar(&failOnVulnFlag, "fail-on-vuln", "", "Exit non-zero when a dependency has a vulnerability of this severity or higher: low, medium, high, critical (implies -v)")
+	rootCmd.Flags().StringVar(&minSeverityFlag, "min-severity", "", "Only show vulnerability counts of this severity or higher: low, medium, high, critical")
+	rootCmd.Flags().StringVarP(&managerFlag, "manager", "m", "", "Package manager to use (auto, all, go, npm, yarn, pnpm, pip, poetry, uv, maven, gradle, bundler, conda); all scans with every detected manager")
+	rootCmd.Flags().StringArrayVar(&goEnvFlag, "go-env", nil, "Extra KEY=VALUE environment for go commands, e.g. GOPROXY for private modules (repeatable)")
+	rootCmd.Flags().StringVar(&registryFlag, "registry", "", "Registry URL for version lookups: sets GOPROXY for Go and npm_config_registry for npm, yarn and pnpm scans")
+	rootCmd.Flags().BoolVar(&allowGoBumpFlag, "allow-go-bump", false, "Include Go updates that require raising the go directive in go.mod")
+	rootCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Query OSV directly instead of using cached vulnerability data")
+	rootCmd.Flags().DurationVar(&cacheTTLFlag, "cache-ttl", 0, "How long cached vulnerability data stays fresh, e.g. 6h (default 24h, also honors FARO_CACHE_TTL)")
+	rootCmd.Flags().BoolVar(&cacheScanFlag, "cache-scan", false, "Reuse the last scan results while manifests and lockfiles are unchanged, for up to 15 minutes (also honors FARO_SCAN_CACHE)")
+	rootCmd.Flags().BoolVar(&noScanCacheFlag, "no-scan-cache", false, "Always scan, even with --cache-scan or FARO_SCAN_CACHE")
+	rootCmd.Flags().StringVar(&vulnSourceFlag, "vuln-source", "osv", "Vulnerability database to query: osv or ghsa (GitHub Advisory Database, needs GITHUB_TOKEN)")
+	rootCmd.Flags().StringVar(&osvURLFlag, "osv-url", "", "OSV API endpoint for vulnerability checks, e.g. a private mirror (also honors FARO_OSV_URL)")
+	rootCmd.Flags().DurationVar(&timeoutFlag, "timeout", defaultTimeout, "Maximum time to wait for the package manager and vulnerability lookups while scanning (0 disables)")
+	rootCmd.Flags().StringVarP(&pathFlag, "path", "C", "", "Project directory to scan (defaults to the current directory)")
+	rootCmd.Flags().StringVar(&manifestFlag, "manifest", "", "go.mod to scan, relative to the project directory, e.g. services/api/go.mod or go.tools.mod (implies --manager go; lists updates only)")
+	rootCmd.Flags().BoolVar(&diffVersionsFlag, "diff-versions", false, "Show how many major, minor and patch releases each package is behind (npm, go; same as --format delta)")
+	rootCmd.Flags().BoolVar(&patchFlag, "patch", false, "Limit updates to patch releases of the current minor version and upgrade with go get -u=patch (Go)")
+	rootCmd.Flags().BoolVar(&inRangeFlag, "in-range", false, "Target the newest version allowed by package.json ranges and update only the lockfile (npm, yarn, pnpm)")
+	rootCmd.Flags().BoolVar(&majorOnlyFlag, "major-only", false, "Only show updates that raise the major version (including 0.x minor bumps)")
+	rootCmd.Flags().StringSliceVar(&depTypeFlag, "dep-type", nil, "Only show updates of these dependency types: direct, indirect, dev, peer, optional, transitive (repeatable)")
+	rootCmd.Flags().BoolVar(&checkSelfFlag, "check-self", false, "Check whether a newer faro release is available instead of scanning (a daily check also runs on terminals unless FARO_NO_UPDATE_CHECK or CI is set)")
+	rootCmd.Flags().BoolVar(&noColorFlag, "no-color", false, "Disable colored output (also honors NO_COLOR)")
+	rootCmd.Flags().BoolVarP(&quietFlag, "quiet", "q", false, "Print only the update list, without progress banners or hints")
+	rootCmd.Flags().BoolVar(&countFlag, "count", false, "Print only the number of updates found (honors --filter and --dep-type)")
+	rootCmd.Flags().BoolVar(&verboseFlag, "verbose", false, "Log every package manager command, with its exit status, to stderr")
+	rootCmd.Flags().StringVar(&logLevelFlag, "log-level", "warn", "Diagnostics written to stderr: error, warn, info or debug")
 }
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/app"
+	"github.com/spf13/cobra"
+)
+
+// safeUpgradeCmd applies every non-major update in one go.
+var safeUpgradeCmd = &cobra.Command{
+	Use:   "safe-upgrade",
+	Short: "Apply all minor and patch updates, skipping major ones",
+	Long: `safe-upgrade scans for updates, drops the ones that raise the major version
+(0.x minor bumps count as major, as with --major-only) and applies the rest
+with the project's package manager. It is a shortcut for upgrading with -u
+while leaving breaking changes for review.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := app.Run(
+			app.RunOptions{
+				Upgrade:    true,
+				SkipMajor:  true,
+				Yes:        yesFlag,
+				VerifyCmd:  verifyCmdFlag,
+				Backup:     backupFlag,
+				VerifySums: verifySumsFlag,
+				Filter:     filterFlag,
+				Dev:        devFlag,
+				Transitive: transitiveFlag,
+				All:        allFlag,
+				NoDev:      noDevFlag,
+				Cooldown:   cooldownFlag,
+				Manager:    managerFlag,
+				DepTypes:   depTypeFlag,
+				NoColor:    noColorFlag,
+				Path:       pathFlag,
+				Timeout:    timeoutFlag,
+				GoEnv:      goEnvFlag,
+				Registry:   registryFlag,
+			},
+			app.Deps{
+				Out: os.Stdout,
+				Now: time.Now,
+			},
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	safeUpgradeCmd.Flags().BoolVarP(&yesFlag, "yes", "y", false, "Apply the upgrades without asking for confirmation (required when stdin is not a terminal)")
+	safeUpgradeCmd.Flags().StringVar(&verifyCmdFlag, "verify-cmd", "", "Shell command run after the upgrade, e.g. 'npm test'; restores the manifest and lockfile if it fails")
+	safeUpgradeCmd.Flags().BoolVar(&backupFlag, "backup", false, "Copy each manifest and lockfile to <file>.faro.bak before updating; undo with faro restore")
+	safeUpgradeCmd.Flags().BoolVar(&verifySumsFlag, "verify-sums", false, "Run go mod verify after upgrading Go modules and fail if a downloaded module doesn't match go.sum")
+	safeUpgradeCmd.Flags().StringVarP(&filterFlag, "filter", "f", "", "Filter packages by name (substring or regex; a trailing / matches a path prefix such as golang.org/x/)")
+	safeUpgradeCmd.Flags().BoolVar(&devFlag, "dev", false, "Include dev and test dependencies hidden by default (npm peer/optional, Python dependency groups and extras)")
+	safeUpgradeCmd.Flags().BoolVar(&transitiveFlag, "transitive", false, "Include dependencies the project doesn't declare (Go modules not in go.mod, undeclared packages)")
+	safeUpgradeCmd.Flags().BoolVar(&allFlag, "all", false, "Include dev and transitive dependencies")
+	_ = safeUpgradeCmd.Flags().MarkDeprecated("all", "use --dev and --transitive")
+	safeUpgradeCmd.Flags().BoolVar(&noDevFlag, "no-dev", false, "Exclude dev and test dependencies, even with --all")
+	safeUpgradeCmd.Flags().IntVarP(&cooldownFlag, "cooldown", "c", 0, "Minimum age (days) for an update to be considered")
+	safeUpgradeCmd.Flags().StringVarP(&managerFlag, "manager", "m", "", "Package manager to use (auto, all, go, npm, yarn, pnpm, pip, poetry, uv, maven, gradle, bundler, conda); all scans with every detected manager")
+	safeUpgradeCmd.Flags().StringSliceVar(&depTypeFlag, "dep-type", nil, "Only apply updates of these dependency types: direct, indirect, dev, peer, optional, transitive (repeatable)")
+	safeUpgradeCmd.Flags().StringArrayVar(&goEnvFlag, "go-env", nil, "Extra KEY=VALUE environment for go commands, e.g. GOPROXY for private modules (repeatable)")
+	safeUpgradeCmd.Flags().StringVar(&registryFlag, "registry", "", "Registry URL for version lookups: sets GOPROXY for Go and npm_config_registry for npm, yarn and pnpm scans")
+	safeUpgradeCmd.Flags().DurationVar(&timeoutFlag, "timeout", defaultTimeout, "Maximum time to wait for the package manager while scanning (0 disables)")
+	safeUpgradeCmd.Flags().StringVarP(&pathFlag, "path", "C", "", "Project directory to scan (defaults to the current directory)")
+	safeUpgradeCmd.Flags().BoolVar(&noColorFlag, "no-color", false, "Disable colored output (also honors NO_COLOR)")
+	rootCmd.AddCommand(safeUpgradeCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/selfupdate"
+	"github.com/pragmaticivan/faro/internal/style"
+)
+
+// noSelfCheckEnvs turn off the daily release check when any is set.
+var noSelfCheckEnvs = []string{"FARO_NO_SELF_CHECK", "FARO_NO_UPDATE_CHECK"}
+
+// selfCheckTimeout bounds the release lookup.
+const selfCheckTimeout = 5 * time.Second
+
+// checkSelf looks up the latest faro release and writes a notice to w if it
+// is newer than current. With force (--check-self) it always queries and
+// reports the outcome, errors included. Otherwise it queries at most once per
+// selfupdate.CheckInterval, only for release builds, and stays silent unless
+// there is a newer release.
+func checkSelf(w io.Writer, c *selfupdate.Checker, current string, force bool) error {
+	if !force {
+		if current == "" || !c.Due() {
+			return nil
+		}
+		_ = c.MarkChecked()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), selfCheckTimeout)
+	defer cancel()
+	latest, err := c.Latest(ctx)
+	if err != nil {
+		if force {
+			return err
+		}
+		return nil
+	}
+
+	if notice := selfupdate.Notice(current, latest); notice != "" {
+		_, err = fmt.Fprintln(w, notice)
+		return err
+	}
+	if force {
+		if current == "" {
+			current = "a development build"
+		}
+		_, err = fmt.Fprintf(w, "The latest faro release is %s; you have %s\n", latest, current)
+	}
+	return err
+}
+
+// selfCheckDisabled reports whether the environment opts out of the daily
+// release check, via noSelfCheckEnvs or a CI runner's $CI.
+func selfCheckDisabled(getenv func(string) string) bool {
+	for _, key := range noSelfCheckEnvs {
+		if getenv(key) != "" {
+			return true
+		}
+	}
+	return getenv("CI") != ""
+}
+
+// autoCheckSelf runs the daily release check after a scan, on terminals only
+// so scripts and CI never see the notice or wait on the network.
+func autoCheckSelf() {
+	if selfCheckDisabled(os.Getenv) || !style.IsTerminal(os.Stderr) {
+		return
+	}
+	_ = checkSelf(os.Stderr, selfupdate.New(nil), selfupdate.CurrentVersion(), false)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/selfupdate"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func fakeRelease(status int, body string, requests *int) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		*requests++
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})}
+}
+
+func TestCheckSelf(t *testing.T) {
+	var requests int
+	c := selfupdate.New(fakeRelease(http.StatusOK, `{"tag_name": "v1.5.0"}`, &requests))
+
+	var buf bytes.Buffer
+	if err := checkSelf(&buf, c, "v1.4.0", true); err != nil {
+		t.Fatalf("checkSelf() error: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "A new faro release is available: v1.5.0 (you have v1.4.0)") {
+		t.Errorf("unexpected notice %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := checkSelf(&buf, c, "v1.5.0", true); err != nil {
+		t.Fatalf("checkSelf() error: %v", err)
+	}
+	if got := buf.String(); got != "The latest faro release is v1.5.0; you have v1.5.0\n" {
+		t.Errorf("unexpected up-to-date message %q", got)
+	}
+
+	// Development builds are never checked automatically
+	buf.Reset()
+	requests = 0
+	if err := checkSelf(&buf, c, "", false); err != nil || requests != 0 || buf.Len() != 0 {
+		t.Errorf("expected no automatic check for a development build, got %d requests, %q, %v", requests, buf.String(), err)
+	}
+
+	// Failures are only reported when asked for
+	c = selfupdate.New(fakeRelease(http.StatusForbidden, `{}`, &requests))
+	if err := checkSelf(&buf, c, "v1.4.0", true); err == nil {
+		t.Error("expected --check-self to report a failed lookup")
+	}
+}
+
+func TestSelfCheckDisabled(t *testing.T) {
+	for _, env := range []map[string]string{
+		{"FARO_NO_SELF_CHECK": "1"},
+		{"FARO_NO_UPDATE_CHECK": "1"},
+		{"CI": "true"},
+	} {
+		if !selfCheckDisabled(func(k string) string { return env[k] }) {
+			t.Errorf("expected %v to disable the release check", env)
+		}
+	}
+	if selfCheckDisabled(func(string) string { return "" }) {
+		t.Error("expected the release check to run by default")
+	}
+}
@@ -1,17 +1,30 @@
 package app
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 	"github.com/pragmaticivan/faro/internal/detector"
 	"github.com/pragmaticivan/faro/internal/factory"
 	"github.com/pragmaticivan/faro/internal/format"
+	"github.com/pragmaticivan/faro/internal/log"
+	"github.com/pragmaticivan/faro/internal/scancache"
 	"github.com/pragmaticivan/faro/internal/scanner"
 	"github.com/pragmaticivan/faro/internal/style"
 	"github.com/pragmaticivan/faro/internal/tui"
@@ -23,67 +36,359 @@ type RunOptions struct {
 	Upgrade             bool
 	Interactive         bool
 	Filter              string
-	All                 bool
+	Dev                 bool // Include dev/test dependencies hidden by default: Node peer and optional, Python groups and extras
+	Transitive          bool // Include dependencies the project doesn't declare, e.g. Go modules outside go.mod
+	All                 bool // Deprecated: sets Dev and Transitive
+	NoDev               bool // Exclude dev/test dependencies, overriding All and DepTypes
 	Cooldown            int
 	FormatFlag          string
 	ShowVulnerabilities bool
-	Manager             string // Package manager override
+	Manager             string        // Package manager override
+	AllowGoBump         bool          // Keep Go updates that would raise the go directive
+	NoCache             bool          // Skip the on-disk OSV response cache
+	CacheTTL            time.Duration // How long cached OSV responses stay fresh; zero uses $FARO_CACHE_TTL or 24h
+	ScanCache           bool          // Reuse cached scan results while the project's manifests and lockfiles are unchanged
+	NoScanCache         bool          // Always scan, even with ScanCache or $FARO_SCAN_CACHE
+	OSVURL              string        // OSV API endpoint; empty uses $FARO_OSV_URL or the public API
+	VulnSource          string        // Advisory database: osv (default) or ghsa, which needs $GITHUB_TOKEN
+	NoColor             bool          // Disable colored output even on a terminal
+	Quiet               bool          // Omit progress banners and hints, keeping the update list
+	Count               bool          // Print only the number of updates found
+	Verbose             bool          // Log every package manager command to deps.Stderr
+	LogLevel            string        // Diagnostics written to deps.Stderr: error, warn (default), info or debug
+	Frozen              bool          // Only report; never run an updater
+	Yes                 bool          // Apply -u upgrades without asking for confirmation
+	Backup              bool          // Copy manifests and lockfiles to <file>.faro.bak before updating
+	VerifySums          bool          // Run go mod verify after Go upgrades
+	Recursive           bool          // Scan every project found in subdirectories
+	MaxDepth            int           // How many directory levels --recursive descends
+
+	// Path is the project directory to scan; empty uses the current directory
+	Path string
+
+	// Timeout bounds the package manager commands run while scanning and the
+	// vulnerability lookups that follow; zero means no limit. Projects
+	// scanned together share it.
+	Timeout time.Duration
+
+	// GoEnv holds extra KEY=VALUE environment variables for the go commands
+	// run while scanning, e.g. GOPROXY or GONOSUMDB for private modules
+	GoEnv []string
+
+	// Registry points version resolution at another registry: GOPROXY for
+	// Go and npm_config_registry for npm, yarn and pnpm scans
+	Registry string
+
+	// MaxResults caps the human-readable list at this many updates, noting
+	// how many more there are; machine-readable formats stay complete.
+	// Zero means no cap.
+	MaxResults int
+
+	// Manifest is the go.mod to scan when it isn't the one in Path, relative
+	// to Path, e.g. a monorepo submodule's "services/api/go.mod" or an
+	// alternate "go.tools.mod" next to go.mod; implies the Go manager
+	Manifest string
+
+	// Template is a text/template rendered once per listed module instead of
+	// the usual output, e.g. "{{.Name}}:{{.Update.Version}}"
+	Template string
+
+	// DepTypes keeps only updates of the given dependency categories
+	// (direct, indirect, dev, peer, optional, transitive); empty keeps
+	// everything. Indirect is Go's go.mod // indirect requirements, apart
+	// from transitive modules outside go.mod. Filtering on anything but
+	// direct and indirect implies Dev or Transitive so hidden categories
+	// are scanned.
+	DepTypes []string
+
+	// MajorOnly keeps only updates that raise the major version (0.x minor
+	// bumps included)
+	MajorOnly bool
+
+	// InRange targets the wanted version, the newest one the declared range
+	// allows, and updates only the lockfile (npm, yarn and pnpm)
+	InRange bool
+
+	// Patch limits updates to the newest patch release of the current minor
+	// version and upgrades with `go get -u=patch` (Go only)
+	Patch bool
+
+	// SkipMajor drops updates that raise the major version (0.x minor bumps
+	// included), the inverse of MajorOnly; used by `faro safe-upgrade`
+	SkipMajor bool
+
+	// FailOnVuln makes Run fail when a listed dependency's current version has
+	// a vulnerability of this severity or higher (low, medium, high,
+	// critical); implies ShowVulnerabilities
+	FailOnVuln string
+
+	// MinSeverity hides vulnerability counts below this severity (low,
+	// medium, high, critical) from the human-readable output; empty shows
+	// them all
+	MinSeverity string
+
+	// VulnOnly lists only dependencies whose current version has known
+	// vulnerabilities; implies ShowVulnerabilities
+	VulnOnly bool
+
+	// GroupBy buckets grouped output by bump, type, scope, workspace or
+	// manager; setting it implies the group format
+	GroupBy string
+
+	// DiffVersions shows how many releases each package is behind, as with
+	// the delta format modifier (npm and Go)
+	DiffVersions bool
+
+	// Sort orders the listed updates: name, bump, age or severity; empty
+	// keeps the scanner's order
+	Sort string
+
+	// VerifyCmd is a shell command run in the project directory after -u
+	// applies updates, e.g. "go build ./..." or "npm test". If it fails,
+	// the updater's manifests and lockfiles are restored.
+	VerifyCmd string
 }
 
 type Deps struct {
 	Out              io.Writer
 	Now              func() time.Time
 	StartInteractive func(direct, indirect, transitive []scanner.Module, opts tui.Options)
-	Scanner          scanner.Scanner // Optional: verify overrides for testing
-	Updater          updater.Updater // Optional: verify overrides for testing
+	Scanner          scanner.Scanner                             // Optional: verify overrides for testing
+	Scanners         map[detector.PackageManager]scanner.Scanner // Optional: per-manager overrides for testing, ahead of Scanner
+	Updater          updater.Updater                             // Optional: verify overrides for testing
+	VulnClient       vuln.Client                                 // Optional: overrides the OSV client for testing
+	ScanCache        *scancache.Cache                            // Optional: defaults to the user cache dir when the scan cache is enabled
+	Getenv           func(string) string                         // Optional: defaults to os.Getenv
+	Stderr           io.Writer                                   // Optional: receives logs and the --verbose command log; defaults to os.Stderr
+	Stdin            io.Reader                                   // Optional: answers the -u confirmation prompt; defaults to os.Stdin
+}
+
+// withDefaults returns deps with its unset optional fields filled in.
+func (d Deps) withDefaults() Deps {
+	if d.Now == nil {
+		d.Now = time.Now
+	}
+	if d.Getenv == nil {
+		d.Getenv = os.Getenv
+	}
+	if d.Stderr == nil {
+		d.Stderr = os.Stderr
+	}
+	if d.Stdin == nil {
+		d.Stdin = os.Stdin
+	}
+	return d
 }
 
-// checkVulnerabilities checks for vulnerabilities in current and update versions
-func checkVulnerabilities(ctx context.Context, modules []scanner.Module, vulnClient vuln.Client) {
-	for i := range modules {
-		if modules[i].Update != nil {
+// vulnCheckChunk is how many modules checkVulnerabilities sends to the
+// client at once, so progress is reported while large projects are checked.
+const vulnCheckChunk = 50
+
+// checkVulnerabilities checks for vulnerabilities in current and update versions.
+// Current versions are checked for every module, so vulnerable packages
+// without an update are still reported; update versions only when there is one.
+// Modules are sent to the client in chunks of vulnCheckChunk, and a non-nil
+// progress writer receives a "Checked N/M packages" line after each. If a
+// chunk fails, the error is returned and the remaining counts are left empty.
+func checkVulnerabilities(ctx context.Context, modules []scanner.Module, vulnClient vuln.Client, progress io.Writer) error {
+	for start := 0; start < len(modules); start += vulnCheckChunk {
+		chunk := modules[start:min(start+vulnCheckChunk, len(modules))]
+
+		// Each module's current version is followed by its update, if any
+		queries := make([]vuln.Query, 0, 2*len(chunk))
+		for _, m := range chunk {
 			// Use Name field, fallback to Path for backward compatibility
-			pkgName := modules[i].Name
+			pkgName := m.Name
 			if pkgName == "" {
-				pkgName = modules[i].Path
+				pkgName = m.Path
+			}
+			queries = append(queries, vuln.Query{Name: pkgName, Version: m.Version})
+			if m.Update != nil {
+				queries = append(queries, vuln.Query{Name: pkgName, Version: m.Update.Version})
 			}
+		}
 
-			// Check current version
-			if currentCounts, err := vulnClient.CheckModule(ctx, pkgName, modules[i].Version); err == nil {
-				modules[i].VulnCurrent = scanner.VulnInfo{
-					Low:      currentCounts.Low,
-					Medium:   currentCounts.Medium,
-					High:     currentCounts.High,
-					Critical: currentCounts.Critical,
-					Total:    currentCounts.Total,
-				}
+		results, err := vulnClient.CheckModules(ctx, queries)
+		if err != nil {
+			return err
+		}
+		next := 0
+		for i := range chunk {
+			chunk[i].VulnCurrent = vulnInfo(results[next])
+			next++
+			if chunk[i].Update != nil {
+				chunk[i].VulnUpdate = vulnInfo(results[next])
+				next++
 			}
+		}
 
-			// Check update version
-			if updateCounts, err := vulnClient.CheckModule(ctx, pkgName, modules[i].Update.Version); err == nil {
-				modules[i].VulnUpdate = scanner.VulnInfo{
-					Low:      updateCounts.Low,
-					Medium:   updateCounts.Medium,
-					High:     updateCounts.High,
-					Critical: updateCounts.Critical,
-					Total:    updateCounts.Total,
-				}
+		if progress != nil {
+			_, _ = fmt.Fprintf(progress, "Checked %d/%d packages\n", start+len(chunk), len(modules))
+		}
+	}
+	return nil
+}
+
+// checkProjectVulnerabilities fills in the vulnerability counts of modules,
+// pm's packages, from the advisory source opts select. It returns false, with
+// a warning, when there is no vulnerability data for pm. A failed check is
+// logged and recorded on gate, which then fails closed. A non-nil progress
+// writer receives a banner and the number of packages checked.
+func checkProjectVulnerabilities(ctx context.Context, opts RunOptions, deps Deps, pm detector.PackageManager, modules []scanner.Module, progress io.Writer, gate *vulnGate) bool {
+	if !factory.SupportsVulnerabilities(pm) {
+		log.Warnf("vulnerability data is not available for %s packages; skipping the check", pm)
+		return false
+	}
+	if len(modules) == 0 {
+		return true
+	}
+	if progress != nil {
+		_, _ = fmt.Fprintln(progress, "Checking vulnerabilities...")
+	}
+	if err := checkVulnerabilities(ctx, modules, resolveVulnClient(pm, vulnClientOptions(opts.VulnSource, opts.NoCache, opts.CacheTTL, opts.OSVURL, deps.Getenv), deps), progress); err != nil {
+		log.Warnf("vulnerability check failed; counts are unavailable: %v", err)
+		gate.checkFailed(err)
+	}
+	return true
+}
+
+// severityLevels lists the --fail-on-vuln and --min-severity thresholds from
+// least to most severe, matching the style.Severity levels.
+var severityLevels = []string{"low", "medium", "high", "critical"}
+
+// vulnGate tallies the dependencies that fail --fail-on-vuln. A nil gate
+// never fails.
+type vulnGate struct {
+	level     int // Index into severityLevels
+	failing   int
+	checkErrs []error // Failed vulnerability checks, whose counts are unknown
+}
+
+// newVulnGate validates a --fail-on-vuln severity; empty disables the gate.
+func newVulnGate(severity string) (*vulnGate, error) {
+	if strings.TrimSpace(severity) == "" {
+		return nil, nil
+	}
+	level, err := parseSeverity("--fail-on-vuln", severity)
+	if err != nil {
+		return nil, err
+	}
+	return &vulnGate{level: level}, nil
+}
+
+// parseSeverity returns the index into severityLevels of the severity given
+// to flag.
+func parseSeverity(flag, severity string) (int, error) {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	level := slices.Index(severityLevels, severity)
+	if level < 0 {
+		return 0, fmt.Errorf("unsupported %s value: %q (supported: %s)", flag, severity, strings.Join(severityLevels, ", "))
+	}
+	return level, nil
+}
+
+// check counts the modules whose current version has a vulnerability at or
+// above the gate's severity.
+func (g *vulnGate) check(modules []scanner.Module) {
+	if g == nil {
+		return
+	}
+	for _, m := range modules {
+		v := m.VulnCurrent
+		buckets := []int{v.Low, v.Medium, v.High, v.Critical}
+		for _, n := range buckets[g.level:] {
+			if n > 0 {
+				g.failing++
+				break
 			}
 		}
 	}
 }
 
+// checkFailed records a vulnerability check that didn't complete. The gate
+// fails closed: dependencies that couldn't be checked may be vulnerable.
+func (g *vulnGate) checkFailed(err error) {
+	if g == nil {
+		return
+	}
+	g.checkErrs = append(g.checkErrs, err)
+}
+
+// err reports the failing dependencies and failed checks, if any.
+func (g *vulnGate) err() error {
+	if g == nil {
+		return nil
+	}
+	var errs []error
+	if g.failing > 0 {
+		errs = append(errs, fmt.Errorf("found %d dependencies with %s or higher severity vulnerabilities", g.failing, severityLevels[g.level]))
+	}
+	if len(g.checkErrs) > 0 {
+		errs = append(errs, fmt.Errorf("vulnerability check failed: %w", errors.Join(g.checkErrs...)))
+	}
+	return errors.Join(errs...)
+}
+
+// vulnInfo converts OSV severity counts to a module's VulnInfo.
+func vulnInfo(c vuln.SeverityCounts) scanner.VulnInfo {
+	return scanner.VulnInfo{
+		Low:      c.Low,
+		Medium:   c.Medium,
+		High:     c.High,
+		Critical: c.Critical,
+		Total:    c.Total,
+	}
+}
+
+// filterVulnerable keeps only modules whose current version has known
+// vulnerabilities.
+func filterVulnerable(modules []scanner.Module) []scanner.Module {
+	kept := make([]scanner.Module, 0, len(modules))
+	for _, m := range modules {
+		if m.VulnCurrent.Total > 0 {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// dropUnaffected drops modules that have neither an update nor known
+// vulnerabilities in their current version.
+func dropUnaffected(modules []scanner.Module) []scanner.Module {
+	kept := make([]scanner.Module, 0, len(modules))
+	for _, m := range modules {
+		if m.Update != nil || m.VulnCurrent.Total > 0 {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// withUpdates drops modules that have no update, which are only listed for
+// their vulnerabilities and can't be upgraded.
+func withUpdates(modules []scanner.Module) []scanner.Module {
+	kept := make([]scanner.Module, 0, len(modules))
+	for _, m := range modules {
+		if m.Update != nil {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
 // groupModules splits modules into direct, indirect, and transitive categories
 func groupModules(modules []scanner.Module) (direct, indirect, transitive []scanner.Module) {
 	for _, m := range modules {
 		// Check if it's a direct dependency
 		if m.Direct {
-			// Further categorize based on dependency type
-			switch m.DependencyType {
-			case "devDependencies", "dev", "indirect":
-				indirect = append(indirect, m)
-			default:
+			// Further categorize based on dependency type; anything other than
+			// a primary dependency (dev, peer, optional, Poetry groups, Maven
+			// test scope, Gradle test configurations, Bundler groups, ...)
+			// goes to the secondary group
+			if primaryDependencyTypes[m.DependencyType] {
 				direct = append(direct, m)
+			} else {
+				indirect = append(indirect, m)
 			}
 		} else {
 			// Handle legacy Go fields for backward compatibility
@@ -101,8 +406,190 @@ func groupModules(modules []scanner.Module) (direct, indirect, transitive []scan
 	return direct, indirect, transitive
 }
 
-// printLinesFormat outputs modules in simple line format (path@version)
-func printLinesFormat(out io.Writer, direct, indirect, transitive []scanner.Module, includeAll bool) {
+// primaryDependencyTypes are the DependencyType values of a project's main
+// (non-dev, non-test) dependencies across all scanners.
+var primaryDependencyTypes = map[string]bool{
+	"":               true,
+	"direct":         true,
+	"dependencies":   true, // npm, yarn, pnpm
+	"main":           true, // pip, Poetry, uv
+	"compile":        true, // Maven scopes
+	"runtime":        true,
+	"import":         true,
+	"implementation": true, // Gradle configurations
+	"api":            true,
+	"runtimeOnly":    true,
+	"default":        true, // Gems outside any Gemfile group
+}
+
+// depTypeNames lists the categories accepted by --dep-type.
+var depTypeNames = []string{"direct", "indirect", "dev", "peer", "optional", "transitive"}
+
+// depCategory maps a module onto a --dep-type category using the
+// DependencyType values reported by the scanners.
+func depCategory(m scanner.Module) string {
+	if !m.Direct {
+		// Go modules listed in go.mod, with or without // indirect; only
+		// those outside it are transitive
+		if m.FromGoMod {
+			if m.Indirect {
+				return "indirect"
+			}
+			return "direct"
+		}
+		return "transitive"
+	}
+	if primaryDependencyTypes[m.DependencyType] {
+		return "direct"
+	}
+	switch m.DependencyType {
+	case "peerDependencies":
+		return "peer"
+	case "optionalDependencies", "optional":
+		return "optional"
+	case "indirect", "transitive":
+		return "transitive"
+	default:
+		// devDependencies, dev and other Poetry/uv dependency groups
+		return "dev"
+	}
+}
+
+// parseEnv validates --go-env values, which must be KEY=VALUE pairs.
+func parseEnv(values []string) ([]string, error) {
+	env := make([]string, 0, len(values))
+	for _, v := range values {
+		if key, _, ok := strings.Cut(v, "="); !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --go-env value: %q (expected KEY=VALUE)", v)
+		}
+		env = append(env, v)
+	}
+	return env, nil
+}
+
+// parseRegistry validates a --registry URL, which must be absolute http(s).
+func parseRegistry(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid --registry value: %q (expected an http or https URL)", raw)
+	}
+	return raw, nil
+}
+
+// scanEnv returns the extra environment for pm's scanner: goEnv for Go, and
+// the variables pointing pm at registry, if set.
+func scanEnv(pm detector.PackageManager, registry string, goEnv []string) ([]string, error) {
+	var env []string
+	switch pm {
+	case detector.Go:
+		if registry != "" {
+			env = append(env, "GOPROXY="+registry)
+		}
+		// --go-env comes last so an explicit GOPROXY wins
+		return append(env, goEnv...), nil
+	case detector.Npm, detector.Pnpm:
+		if registry != "" {
+			env = append(env, "npm_config_registry="+registry)
+		}
+	case detector.Yarn:
+		if registry != "" {
+			// Yarn Berry ignores npm_config_* and reads its own setting
+			env = append(env, "npm_config_registry="+registry, "YARN_NPM_REGISTRY_SERVER="+registry)
+		}
+	default:
+		if registry != "" {
+			return nil, fmt.Errorf("--registry is only supported for go, npm, yarn and pnpm projects")
+		}
+	}
+	return env, nil
+}
+
+// parseDepTypes validates --dep-type values and returns them as a set.
+func parseDepTypes(values []string) (map[string]bool, error) {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v == "" {
+			continue
+		}
+		if !slices.Contains(depTypeNames, v) {
+			return nil, fmt.Errorf("unsupported --dep-type value: %q (supported: %s)", v, strings.Join(depTypeNames, ", "))
+		}
+		set[v] = true
+	}
+	return set, nil
+}
+
+// depTypesNeed reports whether the --dep-type categories include ones that
+// are only scanned with --dev or --transitive. Direct and Go indirect
+// dependencies are always listed, so asking for just those skips the rest.
+func depTypesNeed(depTypes map[string]bool) (dev, transitive bool) {
+	for t := range depTypes {
+		switch t {
+		case "direct", "indirect":
+		case "transitive":
+			transitive = true
+		default:
+			dev = true
+		}
+	}
+	return dev, transitive
+}
+
+// retargetPatch points each update at the newest patch release of the
+// module's current minor version, dropping updates that have none.
+func retargetPatch(modules []scanner.Module) []scanner.Module {
+	var kept []scanner.Module
+	for _, m := range modules {
+		if m.Update == nil {
+			kept = append(kept, m)
+			continue
+		}
+		target := format.NewestPatch(m.Version, m.Update.Versions)
+		if target == "" {
+			continue
+		}
+		update := *m.Update
+		update.Version, update.Time = target, "" // Time was the latest release's
+		m.Update = &update
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// filterByDepType keeps the modules whose category is in depTypes. An empty
+// set keeps every module.
+func filterByDepType(modules []scanner.Module, depTypes map[string]bool) []scanner.Module {
+	if len(depTypes) == 0 {
+		return modules
+	}
+	var kept []scanner.Module
+	for _, m := range modules {
+		if depTypes[depCategory(m)] {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// filterMajor keeps the modules whose update is a major version bump, or,
+// when major is false, every other module.
+func filterMajor(modules []scanner.Module, major bool) []scanner.Module {
+	var kept []scanner.Module
+	for _, m := range modules {
+		if format.IsMajorUpdate(m) == major {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// printLinesFormat outputs modules in simple line format (path@version),
+// each prefixed with prefix
+func printLinesFormat(out io.Writer, prefix string, direct, indirect, transitive []scanner.Module, includeAll bool) {
 	all := make([]scanner.Module, 0, len(direct)+len(indirect)+len(transitive))
 	all = append(all, direct...)
 	all = append(all, indirect...)
@@ -117,21 +604,102 @@ func printLinesFormat(out io.Writer, direct, indirect, transitive []scanner.Modu
 		if name == "" {
 			name = m.Path // Fallback for backward compatibility
 		}
-		_, _ = fmt.Fprintf(out, "%s@%s\n", name, m.Update.Version)
+		_, _ = fmt.Fprintf(out, "%s%s@%s\n", prefix, name, m.Update.Version)
+	}
+}
+
+// selectForUpdate flattens the groups that are shown and upgraded; transitive
+// modules are only included with --transitive
+func selectForUpdate(direct, indirect, transitive []scanner.Module, includeAll bool) []scanner.Module {
+	selected := make([]scanner.Module, 0, len(direct)+len(indirect)+len(transitive))
+	selected = append(selected, direct...)
+	selected = append(selected, indirect...)
+	if includeAll {
+		selected = append(selected, transitive...)
+	}
+	return selected
+}
+
+// lineOptions controls the optional columns appended to each update line
+type lineOptions struct {
+	showVulns    bool
+	showTime     bool
+	showReleases bool
+	showDelta    bool
+	minSeverity  int // Hide vulnerability counts below this style.Severity* level
+	manager      detector.PackageManager
+	grouping     format.Grouping
+	now          time.Time
+}
+
+// formatUpdateLine renders a single module update with its optional columns
+func formatUpdateLine(m scanner.Module, maxPathLen int, opts lineOptions) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	name := m.Name
+	if name == "" {
+		name = m.Path // Fallback
+	}
+	if m.Update == nil {
+		// Listed for its vulnerabilities only
+		line := " " + style.FormatCurrent(name, m.Version, maxPathLen)
+		if vulns := style.FormatVulnInfo(style.VisibleVulns(m.VulnCurrent, opts.minSeverity)); opts.showVulns && vulns != "" {
+			line += " " + vulns + "  " + dim.Render("(no update available)")
+		}
+		return line
+	}
+
+	line := " " + style.FormatUpdate(name, m.Version, m.Update.Version, maxPathLen)
+	// Node managers also report the newest version the declared range allows
+	if w := m.Update.Wanted; w != "" && w != m.Update.Version && w != m.Version {
+		line += " " + dim.Render("(wanted "+w+")")
+	}
+	if m.RangeBlocked {
+		line += " " + dim.Render("(range-blocked)")
+	}
+	if via := format.Via(m.RequiredBy); via != "" {
+		line += " " + dim.Render("("+via+")")
+	}
+	current, update := style.VisibleVulns(m.VulnCurrent, opts.minSeverity), style.VisibleVulns(m.VulnUpdate, opts.minSeverity)
+	if vulns := style.FormatVulnTransition(current, update); opts.showVulns && vulns != "" {
+		line += " " + vulns
+	}
+	if m.Update.Deprecated != "" {
+		line += "  " + style.FormatDeprecated(m.Update.Deprecated)
+	}
+	if opts.showTime {
+		pt := format.PublishTime(m.Update.Time, opts.now)
+		if pt != "" {
+			line += "  " + dim.Render(pt)
+		}
+		if age := format.CurrentAge(m.Time, opts.now); age != "" {
+			line += "  " + dim.Render("("+age+")")
+		}
+	}
+	if opts.showDelta && len(m.Update.Versions) > 0 {
+		if d, ok := format.VersionDelta(m.Version, m.Update.Version, m.Update.Versions); ok && d.Total() > 0 {
+			line += "  " + dim.Render("("+d.String()+")")
+		}
+	}
+	if opts.showReleases {
+		if url := format.ReleaseURL(opts.manager, m); url != "" {
+			line += "  " + dim.Render(url)
+		}
 	}
+	return line
 }
 
 // printGroupedOutput prints modules organized by group labels
-func printGroupedOutput(out io.Writer, group []scanner.Module, maxPathLen int, showVulns bool, showTime bool, now time.Time) {
+func printGroupedOutput(out io.Writer, group []scanner.Module, maxPathLen int, opts lineOptions) {
 	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 
 	byLabel := make(map[string][]scanner.Module)
 	order := make(map[string]int)
 	for _, m := range group {
-		label := format.GroupLabel(m)
+		label := format.GroupLabel(m, opts.grouping)
 		byLabel[label] = append(byLabel[label], m)
 		if _, ok := order[label]; !ok {
-			order[label] = format.GroupSortKey(m)
+			order[label] = format.GroupSortKey(m, opts.grouping)
 		}
 	}
 	labels := make([]string, 0, len(byLabel))
@@ -148,234 +716,1018 @@ func printGroupedOutput(out io.Writer, group []scanner.Module, maxPathLen int, s
 	for _, label := range labels {
 		_, _ = fmt.Fprintf(out, "\n%s\n", dim.Render(label))
 		for _, m := range byLabel[label] {
-			name := m.Name
-			if name == "" {
-				name = m.Path // Fallback
-			}
-			line := " " + style.FormatUpdate(name, m.Version, m.Update.Version, maxPathLen)
-			if showVulns && m.VulnCurrent.Total > 0 {
-				line += " " + formatVulnCounts(m.VulnCurrent, m.VulnUpdate)
-			}
-			if showTime {
-				pt := format.PublishTime(m.Update.Time, now)
-				if pt != "" {
-					line += "  " + dim.Render(pt)
-				}
-			}
-			_, _ = fmt.Fprintln(out, line)
+			_, _ = fmt.Fprintln(out, formatUpdateLine(m, maxPathLen, opts))
 		}
 	}
 }
 
 // printSimpleOutput prints modules in simple list format
-func printSimpleOutput(out io.Writer, group []scanner.Module, maxPathLen int, showVulns bool, showTime bool, now time.Time) {
-	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-
+func printSimpleOutput(out io.Writer, group []scanner.Module, maxPathLen int, opts lineOptions) {
 	for _, m := range group {
-		name := m.Name
-		if name == "" {
-			name = m.Path // Fallback
-		}
-		line := " " + style.FormatUpdate(name, m.Version, m.Update.Version, maxPathLen)
-		if showVulns && m.VulnCurrent.Total > 0 {
-			line += " " + formatVulnCounts(m.VulnCurrent, m.VulnUpdate)
-		}
-		if showTime {
-			pt := format.PublishTime(m.Update.Time, now)
-			if pt != "" {
-				line += "  " + dim.Render(pt)
-			}
-		}
-		_, _ = fmt.Fprintln(out, line)
+		_, _ = fmt.Fprintln(out, formatUpdateLine(m, maxPathLen, opts))
 	}
 }
 
 // printGroup outputs a titled group of modules
-func printGroup(out io.Writer, title string, group []scanner.Module, maxPathLen int, grouped bool, showVulns bool, showTime bool, now time.Time) {
+func printGroup(out io.Writer, title string, group []scanner.Module, maxPathLen int, grouped bool, opts lineOptions) {
 	if len(group) == 0 {
 		return
 	}
 	_, _ = fmt.Fprintf(out, "\n%s\n", title)
 
 	if grouped {
-		printGroupedOutput(out, group, maxPathLen, showVulns, showTime, now)
+		printGroupedOutput(out, group, maxPathLen, opts)
 	} else {
-		printSimpleOutput(out, group, maxPathLen, showVulns, showTime, now)
+		printSimpleOutput(out, group, maxPathLen, opts)
 	}
 }
 
-// calculateMaxPathLen finds the longest module path for alignment
-func calculateMaxPathLen(direct, indirect, transitive []scanner.Module) int {
-	maxPathLen := 0
-	for _, group := range [][]scanner.Module{direct, indirect, transitive} {
-		for _, m := range group {
-			name := m.Name
-			if name == "" {
-				name = m.Path
-			}
-			if len(name) > maxPathLen {
-				maxPathLen = len(name)
-			}
-		}
+// printWarnings outputs non-fatal scanner warnings, if the scanner reports any
+func printWarnings(out io.Writer, s scanner.Scanner) {
+	reporter, ok := s.(scanner.WarningReporter)
+	if !ok {
+		return
+	}
+	for _, w := range reporter.Warnings() {
+		_, _ = fmt.Fprintf(out, "Warning: %s\n", w)
 	}
-	return maxPathLen
 }
 
-func Run(opts RunOptions, deps Deps) error {
-	if deps.Out == nil {
-		return fmt.Errorf("missing deps.Out")
+// capResults returns the modules of each printed group, transitive ones
+// only with includeAll, keeping at most max across the groups in order, and
+// how many were left out. A max of zero keeps them all.
+func capResults(direct, indirect, transitive []scanner.Module, includeAll bool, max int) (d, i, t []scanner.Module, hidden int) {
+	if !includeAll {
+		transitive = nil
 	}
-	if deps.Now == nil {
-		deps.Now = time.Now
+	if max <= 0 {
+		return direct, indirect, transitive, 0
 	}
-
-	// Detect or validate package manager
-	workDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+	take := func(group []scanner.Module) []scanner.Module {
+		n := min(len(group), max)
+		max -= n
+		hidden += len(group) - n
+		return group[:n]
 	}
+	return take(direct), take(indirect), take(transitive), hidden
+}
 
-	var pm detector.PackageManager
-	if opts.Manager != "" {
-		// Use explicit manager
-		pm, err = detector.Validate(opts.Manager)
-		if err != nil {
-			return err
-		}
-	} else {
-		// Auto-detect
-		result, err := detector.DetectSingle(workDir)
-		if err != nil {
-			return fmt.Errorf("failed to detect package manager: %w\nSpecify one with --manager flag", err)
+// countGroups returns how many of the printed groups are non-empty
+func countGroups(direct, indirect, transitive []scanner.Module, includeAll bool) int {
+	groups := 0
+	for _, group := range [][]scanner.Module{direct, indirect} {
+		if len(group) > 0 {
+			groups++
 		}
-		pm = result.Manager
 	}
+	if includeAll && len(transitive) > 0 {
+		groups++
+	}
+	return groups
+}
 
-	// Create scanner and updater for the detected package manager
-	var pkgScanner scanner.Scanner
-	if deps.Scanner != nil {
-		pkgScanner = deps.Scanner
-	} else {
-		pkgScanner, err = factory.CreateScanner(pm, workDir)
+// calculateMaxPathLen finds the longest module path for alignment
+func calculateMaxPathLen(direct, indirect, transitive []scanner.Module) int {
+	return max(scanner.MaxPathLength(direct), scanner.MaxPathLength(indirect), scanner.MaxPathLength(transitive))
+}
+
+// resolveWorkDir returns the absolute project directory for path, defaulting
+// to the current directory, and checks that it is an existing directory.
+func resolveWorkDir(path string) (string, error) {
+	if path == "" {
+		workDir, err := os.Getwd()
 		if err != nil {
-			return err
+			return "", fmt.Errorf("failed to get working directory: %w", err)
 		}
+		return workDir, nil
 	}
 
-	formats, err := format.ParseFlag(opts.FormatFlag)
+	workDir, err := filepath.Abs(path)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	info, err := os.Stat(workDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to access path %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("path %s is not a directory", path)
 	}
+	return workDir, nil
+}
 
-	if !formats.Lines {
-		_, _ = fmt.Fprintf(deps.Out, "Using package manager: %s\n", pm)
-		_, _ = fmt.Fprintln(deps.Out, "Checking for updates...")
+// scanContext returns the context scanners run their commands under, limited
+// to timeout when it is positive.
+func scanContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
 	}
+	return context.WithTimeout(context.Background(), timeout)
+}
 
-	// Get updates using the package-specific scanner
-	modules, err := pkgScanner.GetUpdates(scanner.Options{
-		Filter:       opts.Filter,
-		IncludeAll:   opts.All,
-		CooldownDays: opts.Cooldown,
-		WorkDir:      workDir,
-	})
-	if err != nil {
-		return err
+// Special --manager values: auto picks the preferred detected manager, the
+// same as leaving it empty, and all scans with every detected manager.
+const (
+	managerAuto = "auto"
+	managerAll  = "all"
+)
+
+// resolveManager validates an explicit manager or auto-detects one in workDir.
+func resolveManager(manager, workDir string) (detector.PackageManager, error) {
+	if manager != "" && manager != managerAuto {
+		// Use explicit manager
+		return detector.Validate(manager)
 	}
 
-	if len(modules) == 0 {
-		if !formats.Lines {
-			_, _ = fmt.Fprintln(deps.Out, "All dependencies match the latest package versions :)")
-		}
-		return nil
+	// Auto-detect
+	result, err := detector.DetectSingle(workDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect package manager: %w\nSpecify one with --manager flag", err)
 	}
+	return result.Manager, nil
+}
 
-	// Check vulnerabilities if requested
-	if opts.ShowVulnerabilities {
-		if !formats.Lines {
-			_, _ = fmt.Fprintln(deps.Out, "Checking vulnerabilities...")
-		}
-		vulnClient := factory.CreateVulnClient(pm)
-		ctx := context.Background()
-		checkVulnerabilities(ctx, modules, vulnClient)
+// resolveScanner returns the scanner override from deps or creates one for pm.
+func resolveScanner(pm detector.PackageManager, workDir string, deps Deps) (scanner.Scanner, error) {
+	if s, ok := deps.Scanners[pm]; ok {
+		return s, nil
 	}
+	if deps.Scanner != nil {
+		return deps.Scanner, nil
+	}
+	return factory.CreateScanner(pm, workDir)
+}
 
-	direct, indirect, transitive := groupModules(modules)
+// scanCacheEnv enables the scan cache like --cache-scan, e.g. for editor
+// integrations that run faro often.
+const scanCacheEnv = "FARO_SCAN_CACHE"
 
-	// Adapt group labels based on package manager
-	directLabel, indirectLabel, transitiveLabel := getGroupLabels(pm)
+// resolveScanCache returns the cache scans go through, or nil when it is
+// disabled: it is enabled by opts.ScanCache or $FARO_SCAN_CACHE, and
+// opts.NoScanCache wins over both.
+func resolveScanCache(opts RunOptions, deps Deps) (*scancache.Cache, error) {
+	enabled, _ := strconv.ParseBool(deps.Getenv(scanCacheEnv))
+	if opts.NoScanCache || !(opts.ScanCache || enabled) {
+		return nil, nil
+	}
+	if deps.ScanCache != nil {
+		return deps.ScanCache, nil
+	}
+	dir, err := scancache.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return scancache.New(dir, scancache.DefaultTTL, nil), nil
+}
 
-	if opts.Interactive {
+// resolveUpdater returns the updater override from deps or creates one for
+// pm, taking backups with opts.Backup and verifying Go checksums with
+// opts.VerifySums. With opts.InRange, updates go through the updater's
+// in-range mode so the manifest is left untouched; with opts.Patch, through
+// its patch-only mode.
+func resolveUpdater(pm detector.PackageManager, workDir string, opts RunOptions, deps Deps) (updater.Updater, error) {
+	u := deps.Updater
+	if u == nil {
+		var err error
+		if u, err = factory.CreateUpdater(pm, workDir, factory.UpdaterOptions{Backup: opts.Backup, VerifyChecksums: opts.VerifySums}); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case opts.InRange:
+		r, ok := u.(updater.InRangeUpdater)
+		if !ok {
+			return nil, fmt.Errorf("--in-range is not supported for %s", pm)
+		}
+		return inRangeUpdater{r}, nil
+	case opts.Patch:
+		p, ok := u.(updater.PatchUpdater)
+		if !ok {
+			return nil, fmt.Errorf("--patch is not supported for %s", pm)
+		}
+		return patchUpdater{p}, nil
+	}
+	return u, nil
+}
+
+// inRangeUpdater adapts an updater.InRangeUpdater to updater.Updater so the
+// upgrade and interactive flows apply updates within the declared ranges.
+type inRangeUpdater struct {
+	updater.InRangeUpdater
+}
+
+func (u inRangeUpdater) UpdatePackages(modules []scanner.Module) error {
+	return u.UpdateInRange(modules)
+}
+
+func (u inRangeUpdater) UpdateSinglePackage(module scanner.Module) error {
+	return u.UpdateInRange([]scanner.Module{module})
+}
+
+// patchUpdater adapts an updater.PatchUpdater to updater.Updater so the
+// upgrade and interactive flows leave version selection to the package
+// manager's patch-only resolution.
+type patchUpdater struct {
+	updater.PatchUpdater
+}
+
+func (u patchUpdater) UpdatePackages(modules []scanner.Module) error {
+	return u.UpdatePatch(modules)
+}
+
+func (u patchUpdater) UpdateSinglePackage(module scanner.Module) error {
+	return u.UpdatePatch([]scanner.Module{module})
+}
+
+// resolveVulnClient returns the vuln client override from deps or creates one for pm.
+func resolveVulnClient(pm detector.PackageManager, opts factory.VulnOptions, deps Deps) vuln.Client {
+	if deps.VulnClient != nil {
+		return deps.VulnClient
+	}
+	return factory.CreateVulnClient(pm, opts)
+}
+
+// vulnClientOptions collects the vulnerability client settings, reading the
+// GitHub token from the environment.
+func vulnClientOptions(source string, noCache bool, cacheTTL time.Duration, osvURL string, getenv func(string) string) factory.VulnOptions {
+	return factory.VulnOptions{
+		Source:      vuln.Source(source),
+		NoCache:     noCache,
+		CacheTTL:    cacheTTL,
+		OSVURL:      osvURL,
+		GitHubToken: getenv("GITHUB_TOKEN"),
+	}
+}
+
+// resolveSources validates the registry and vulnerability source options
+// of opts and fills in their defaults from getenv, for Run and RunCI alike.
+func resolveSources(opts *RunOptions, getenv func(string) string) error {
+	var err error
+	if opts.GoEnv, err = parseEnv(opts.GoEnv); err != nil {
+		return err
+	}
+	if opts.Registry, err = parseRegistry(opts.Registry); err != nil {
+		return err
+	}
+	if opts.OSVURL, err = resolveOSVURL(opts.OSVURL, getenv); err != nil {
+		return err
+	}
+	if opts.CacheTTL, err = resolveCacheTTL(opts.CacheTTL, getenv); err != nil {
+		return err
+	}
+	opts.VulnSource, err = resolveVulnSource(opts.VulnSource, getenv)
+	return err
+}
+
+// resolveVulnSource validates the --vuln-source value. The GitHub Advisory
+// Database only serves authenticated requests, so ghsa needs $GITHUB_TOKEN.
+func resolveVulnSource(flag string, getenv func(string) string) (string, error) {
+	source, err := vuln.ParseSource(flag)
+	if err != nil {
+		return "", err
+	}
+	if source == vuln.SourceGHSA && getenv("GITHUB_TOKEN") == "" {
+		return "", fmt.Errorf("--vuln-source ghsa requires the GITHUB_TOKEN environment variable")
+	}
+	return string(source), nil
+}
+
+// resolveOSVURL validates the OSV endpoint from the --osv-url flag, falling
+// back to $FARO_OSV_URL. An empty result selects the public API.
+func resolveOSVURL(flag string, getenv func(string) string) (string, error) {
+	raw := flag
+	if raw == "" {
+		raw = getenv(vuln.BaseURLEnv)
+	}
+	if raw == "" {
+		return "", nil
+	}
+	return vuln.ParseBaseURL(raw)
+}
+
+// resolveCacheTTL returns how long cached OSV responses stay fresh from the
+// --cache-ttl flag, falling back to $FARO_CACHE_TTL. Zero selects
+// vuln.DefaultCacheTTL.
+func resolveCacheTTL(flag time.Duration, getenv func(string) string) (time.Duration, error) {
+	if flag < 0 {
+		return 0, fmt.Errorf("--cache-ttl must not be negative, got %s", flag)
+	}
+	if flag > 0 {
+		return flag, nil
+	}
+	raw := getenv(vuln.CacheTTLEnv)
+	if raw == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a non-negative duration such as 6h", vuln.CacheTTLEnv, raw)
+	}
+	return ttl, nil
+}
+
+func Run(opts RunOptions, deps Deps) error {
+	if deps.Out == nil {
+		return fmt.Errorf("missing deps.Out")
+	}
+	deps = deps.withDefaults()
+	defer style.SetColorEnabled(style.Colored())
+	style.SetColorEnabled(!opts.NoColor && style.ColorEnabled(deps.Out, deps.Getenv))
+
+	level, err := log.ParseLevel(opts.LogLevel)
+	if err != nil {
+		return err
+	}
+	defer log.SetDefault(log.Default())
+	log.SetDefault(log.New(deps.Stderr, level))
+
+	if opts.Verbose {
+		defer scanner.SetCommandLog(scanner.CommandLog())
+		scanner.SetCommandLog(deps.Stderr)
+	}
+
+	// Frozen runs only list updates, so nothing can touch the project files
+	if opts.Frozen {
+		if opts.Upgrade {
+			return fmt.Errorf("--frozen cannot be combined with -u/--upgrade")
+		}
+		opts.Interactive = false
+	}
+
+	if opts.MajorOnly && opts.SkipMajor {
+		return fmt.Errorf("--major-only cannot be combined with safe-upgrade")
+	}
+	if opts.Patch && (opts.MajorOnly || opts.InRange) {
+		return fmt.Errorf("--patch cannot be combined with --major-only or --in-range")
+	}
+	if opts.VerifyCmd != "" && !opts.Upgrade {
+		return fmt.Errorf("--verify-cmd requires -u/--upgrade")
+	}
+	if opts.VerifySums && !opts.Upgrade {
+		return fmt.Errorf("--verify-sums requires -u/--upgrade")
+	}
+	if opts.MaxResults < 0 {
+		return fmt.Errorf("--max-results must not be negative")
+	}
+	if opts.Count && (opts.Upgrade || opts.Interactive) {
+		return fmt.Errorf("--count cannot be combined with -u/--upgrade or -i/--interactive")
+	}
+	if opts.Manifest != "" {
+		if opts.Upgrade || opts.Interactive {
+			return fmt.Errorf("--manifest only lists updates; run -u or -i from the module's directory")
+		}
+		if opts.Recursive || opts.Manager == managerAll {
+			return fmt.Errorf("--manifest cannot be combined with --recursive or --manager all")
+		}
+		if opts.Manager == "" || opts.Manager == managerAuto {
+			opts.Manager = string(detector.Go)
+		}
+	}
+
+	// Detect or validate package manager
+	workDir, err := resolveWorkDir(opts.Path)
+	if err != nil {
+		return err
+	}
+
+	formats, err := format.ParseFlag(opts.FormatFlag)
+	if err != nil {
+		return err
+	}
+
+	groupBy, err := format.ParseGroupBy(opts.GroupBy)
+	if err != nil {
+		return err
+	}
+	if opts.GroupBy != "" {
+		formats.Group = true
+	}
+	opts.GroupBy = string(groupBy)
+	if opts.Template != "" {
+		if formats.MachineReadable() {
+			return fmt.Errorf("--template cannot be combined with --format json, ndjson, csv or lines")
+		}
+		formats.Template, err = format.ParseTemplate(opts.Template)
+		if err != nil {
+			return err
+		}
+	}
+	if opts.Count && formats.MachineReadable() {
+		return fmt.Errorf("--count cannot be combined with --format json, ndjson, csv or lines, or --template")
+	}
+	if opts.DiffVersions {
+		formats.Delta = true
+	}
+
+	depTypes, err := parseDepTypes(opts.DepTypes)
+	if err != nil {
+		return err
+	}
+	if opts.All {
+		opts.Dev, opts.Transitive = true, true
+	}
+	needDev, needTransitive := depTypesNeed(depTypes)
+	opts.Dev = (opts.Dev || needDev) && !opts.NoDev
+	opts.Transitive = opts.Transitive || needTransitive
+
+	if err := resolveSources(&opts, deps.Getenv); err != nil {
+		return err
+	}
+	if deps.ScanCache, err = resolveScanCache(opts, deps); err != nil {
+		return err
+	}
+
+	gate, err := newVulnGate(opts.FailOnVuln)
+	if err != nil {
+		return err
+	}
+	if opts.MinSeverity != "" {
+		level, err := parseSeverity("--min-severity", opts.MinSeverity)
+		if err != nil {
+			return err
+		}
+		formats.MinSeverity = level
+	}
+	if opts.VulnOnly || gate != nil {
+		opts.ShowVulnerabilities = true
+	}
+
+	sortKey, err := format.ParseSortKey(opts.Sort)
+	if err != nil {
+		return err
+	}
+	if sortKey == format.SortSeverity && !opts.ShowVulnerabilities {
+		return fmt.Errorf("--sort severity requires -v/--vulnerabilities")
+	}
+	opts.Sort = string(sortKey)
+
+	if opts.Manager == managerAuto {
+		opts.Manager = ""
+	}
+	if opts.Recursive {
+		return runRecursive(opts, deps, workDir, formats, depTypes, gate)
+	}
+	if opts.Manager == managerAll {
+		return runAllManagers(opts, deps, workDir, formats, depTypes, gate)
+	}
+
+	pm, err := resolveManager(opts.Manager, workDir)
+	if err != nil {
+		return err
+	}
+
+	var report format.Report
+	if err := runProject(opts, deps, ".", workDir, pm, formats, depTypes, &report, gate); err != nil {
+		return err
+	}
+	if err := writeReport(opts, deps, formats, report); err != nil {
+		return err
+	}
+	return gate.err()
+}
+
+// writeReport writes what runProject collected in report for the whole run:
+// the JSON report, or the number of updates with --count.
+func writeReport(opts RunOptions, deps Deps, formats format.Options, report format.Report) error {
+	if opts.Count {
+		total := 0
+		for _, p := range report.Projects {
+			total += len(p.Updates)
+		}
+		_, _ = fmt.Fprintln(deps.Out, total)
+		return nil
+	}
+	if formats.JSON {
+		return format.WriteJSON(deps.Out, report)
+	}
+	return nil
+}
+
+// runRecursive scans every project found under workDir in turn. JSON output
+// combines them into a single report. With --manager all, every manager
+// detected in a project directory is scanned, not just the preferred one.
+func runRecursive(opts RunOptions, deps Deps, workDir string, formats format.Options, depTypes map[string]bool, gate *vulnGate) error {
+	if opts.Interactive {
+		return fmt.Errorf("--recursive cannot be combined with -i/--interactive")
+	}
+	if formats.CSV {
+		return fmt.Errorf("--format csv cannot be combined with --recursive")
+	}
+
+	projects, err := detector.DetectRecursive(workDir, opts.MaxDepth)
+	if err != nil {
+		return fmt.Errorf("failed to detect package managers: %w", err)
+	}
+
+	switch opts.Manager {
+	case "":
+	case managerAll:
+		var all []detector.DetectionResult
+		for _, p := range projects {
+			results, err := detector.DetectAll(filepath.Join(workDir, filepath.FromSlash(p.Dir)))
+			if err != nil {
+				return err
+			}
+			for _, r := range results {
+				r.Dir = p.Dir
+				all = append(all, r)
+			}
+		}
+		projects = all
+	default:
+		// An explicit --manager limits the scan to that manager's projects
+		pm, err := detector.Validate(opts.Manager)
+		if err != nil {
+			return err
+		}
+		kept := projects[:0]
+		for _, p := range projects {
+			if p.Manager == pm {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == 0 {
+			return fmt.Errorf("no %s projects found in %s", pm, workDir)
+		}
+		projects = kept
+	}
+
+	return runProjects(opts, deps, workDir, projects, formats, depTypes, gate)
+}
+
+// runAllManagers scans workDir once with every package manager detected
+// there, for --manager all.
+func runAllManagers(opts RunOptions, deps Deps, workDir string, formats format.Options, depTypes map[string]bool, gate *vulnGate) error {
+	if opts.Interactive {
+		return fmt.Errorf("--manager all cannot be combined with -i/--interactive")
+	}
+	if formats.CSV {
+		return fmt.Errorf("--format csv cannot be combined with --manager all")
+	}
+
+	projects, err := detector.DetectAll(workDir)
+	if err != nil {
+		return fmt.Errorf("failed to detect package managers: %w", err)
+	}
+	for i := range projects {
+		projects[i].Dir = "."
+	}
+	return runProjects(opts, deps, workDir, projects, formats, depTypes, gate)
+}
+
+// maxParallelScans bounds how many projects runProjects scans at once.
+const maxParallelScans = 4
+
+// runProjects scans the projects concurrently, then reports each in turn
+// under a "==> " header naming its directory, its manager, or both with
+// --recursive --manager all. A project that fails to scan doesn't stop the
+// others from being reported; the failures are returned together at the end.
+// Lines output is prefixed with the manager when the projects use several.
+func runProjects(opts RunOptions, deps Deps, workDir string, projects []detector.DetectionResult, formats format.Options, depTypes map[string]bool, gate *vulnGate) error {
+	// Lines from different ecosystems would be ambiguous without the manager
+	managers := make(map[detector.PackageManager]bool)
+	for _, p := range projects {
+		managers[p.Manager] = true
+	}
+	formats.ManagerPrefix = len(managers) > 1
+
+	scanCtx, cancel := scanContext(opts.Timeout)
+	defer cancel()
+	done := checkingForUpdates(opts, deps, formats)
+	scans := scanProjects(scanCtx, opts, deps, workDir, projects, formats)
+	done()
+
+	var report format.Report
+	var failed []error
+	reported := 0
+	for i, p := range projects {
+		label := p.Dir
+		if opts.Manager == managerAll {
+			label = string(p.Manager)
+			if opts.Recursive {
+				label = fmt.Sprintf("%s (%s)", p.Dir, p.Manager)
+			}
+		}
+		if scans[i].err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", label, scans[i].err))
+			continue
+		}
+		if !formats.MachineReadable() && !opts.Count {
+			if reported > 0 {
+				_, _ = fmt.Fprintln(deps.Out)
+			}
+			_, _ = fmt.Fprintf(deps.Out, "==> %s\n", label)
+		}
+		reported++
+		if showBanners(opts, formats) {
+			_, _ = fmt.Fprintf(deps.Out, "Using package manager: %s\n", p.Manager)
+		}
+		projectDir := filepath.Join(workDir, filepath.FromSlash(p.Dir))
+		if err := reportProject(scanCtx, opts, deps, p.Dir, projectDir, p.Manager, formats, depTypes, &report, gate, scans[i]); err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+	}
+	if err := writeReport(opts, deps, formats, report); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return errors.Join(failed...)
+	}
+	return gate.err()
+}
+
+// scanProjects scans the projects under workDir concurrently, at most
+// maxParallelScans at a time, all bounded by ctx. Results are in project order.
+func scanProjects(ctx context.Context, opts RunOptions, deps Deps, workDir string, projects []detector.DetectionResult, formats format.Options) []projectScan {
+	scans := make([]projectScan, len(projects))
+	sem := make(chan struct{}, maxParallelScans)
+	var wg sync.WaitGroup
+	for i, p := range projects {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			projectDir := filepath.Join(workDir, filepath.FromSlash(p.Dir))
+			scans[i] = scanProject(ctx, opts, deps, projectDir, p.Manager, formats)
+		}()
+	}
+	wg.Wait()
+	return scans
+}
+
+// runProject scans the project in workDir with pm and prints or applies its
+// updates. JSON results are added to report, under dir, for the caller to write;
+// vulnerable dependencies are tallied in gate, if any.
+func runProject(opts RunOptions, deps Deps, dir, workDir string, pm detector.PackageManager, formats format.Options, depTypes map[string]bool, report *format.Report, gate *vulnGate) error {
+	if showBanners(opts, formats) {
+		_, _ = fmt.Fprintf(deps.Out, "Using package manager: %s\n", pm)
+	}
+
+	scanCtx, cancel := scanContext(opts.Timeout)
+	defer cancel()
+	done := checkingForUpdates(opts, deps, formats)
+	scan := scanProject(scanCtx, opts, deps, workDir, pm, formats)
+	done()
+	return reportProject(scanCtx, opts, deps, dir, workDir, pm, formats, depTypes, report, gate, scan)
+}
+
+// showBanners reports whether progress banners are printed; they are left
+// out of machine-readable and quiet output.
+func showBanners(opts RunOptions, formats format.Options) bool {
+	return !formats.MachineReadable() && !opts.Quiet && !opts.Count
+}
+
+// checkingForUpdates announces a scan that is starting and returns the
+// function to call once it is done. On a terminal a spinner runs until then,
+// and is erased so the results take its place; otherwise a plain banner is
+// printed. --verbose keeps the banner, as the command log would garble the
+// spinner line.
+func checkingForUpdates(opts RunOptions, deps Deps, formats format.Options) (done func()) {
+	if !showBanners(opts, formats) {
+		return func() {}
+	}
+	if !opts.Verbose && style.IsTerminal(deps.Out) {
+		return style.StartSpinner(deps.Out, "Checking for updates...")
+	}
+	_, _ = fmt.Fprintln(deps.Out, "Checking for updates...")
+	return func() {}
+}
+
+// projectScan is the outcome of scanning one project, kept apart from
+// reporting so several projects can be scanned at once.
+type projectScan struct {
+	scanner scanner.Scanner
+	modules []scanner.Module
+	err     error
+}
+
+// scanProject runs pm's scanner on the project in workDir, bounded by ctx.
+func scanProject(ctx context.Context, opts RunOptions, deps Deps, workDir string, pm detector.PackageManager, formats format.Options) projectScan {
+	if opts.InRange && pm != detector.Npm && pm != detector.Yarn && pm != detector.Pnpm {
+		return projectScan{err: fmt.Errorf("--in-range is only supported for npm, yarn and pnpm projects")}
+	}
+	if opts.Manifest != "" && pm != detector.Go {
+		return projectScan{err: fmt.Errorf("--manifest is only supported for Go projects")}
+	}
+	if opts.Patch && pm != detector.Go {
+		return projectScan{err: fmt.Errorf("--patch is only supported for Go projects")}
+	}
+
+	env, err := scanEnv(pm, opts.Registry, opts.GoEnv)
+	if err != nil {
+		return projectScan{err: err}
+	}
+
+	// Create scanner and updater for the detected package manager
+	pkgScanner, err := resolveScanner(pm, workDir, deps)
+	if err != nil {
+		return projectScan{err: err}
+	}
+	if files := detector.ProjectFiles(pm); deps.ScanCache != nil && files != nil {
+		if opts.Manifest != "" {
+			files = append(files, opts.Manifest, filepath.Join(filepath.Dir(opts.Manifest), "go.sum"))
+		}
+		pkgScanner = deps.ScanCache.Wrap(pkgScanner, string(pm), workDir, files)
+	}
+
+	// Get updates using the package-specific scanner
+	log.Infof("scanning %s with %s", workDir, pm)
+	modules, err := pkgScanner.GetUpdates(scanner.Options{
+		Filter:            opts.Filter,
+		IncludeDev:        opts.Dev,
+		IncludeTransitive: opts.Transitive,
+		CooldownDays:      opts.Cooldown,
+		WorkDir:           workDir,
+		Manifest:          opts.Manifest,
+		AllowGoBump:       opts.AllowGoBump,
+		IncludeVulnScan:   opts.ShowVulnerabilities,
+		InRange:           opts.InRange,
+		ListVersions:      formats.Delta || opts.Patch,
+		PublishTimes:      formats.Time || opts.Sort == string(format.SortAge),
+		Deprecations:      !formats.Lines && !formats.CSV,
+		Registry:          opts.Registry,
+		Env:               env,
+		Context:           ctx,
+	})
+	return projectScan{scanner: pkgScanner, modules: modules, err: err}
+}
+
+// reportProject prints or applies the updates scanProject found for the
+// project in workDir, as described for runProject. Vulnerability lookups are
+// bounded by ctx.
+func reportProject(ctx context.Context, opts RunOptions, deps Deps, dir, workDir string, pm detector.PackageManager, formats format.Options, depTypes map[string]bool, report *format.Report, gate *vulnGate, scan projectScan) error {
+	if scan.err != nil {
+		return scan.err
+	}
+	pkgScanner, modules := scan.scanner, scan.modules
+	banners := showBanners(opts, formats)
+
+	log.Debugf("%s scanner returned %d packages", pm, len(modules))
+	if !formats.MachineReadable() && !opts.Count {
+		printWarnings(deps.Out, pkgScanner)
+	}
+
+	modules = filterByDepType(modules, depTypes)
+	if opts.Patch {
+		modules = retargetPatch(modules)
+	}
+	if opts.MajorOnly {
+		modules = filterMajor(modules, true)
+	}
+	skippedMajor := 0
+	if opts.SkipMajor {
+		kept := filterMajor(modules, false)
+		skippedMajor = len(modules) - len(kept)
+		modules = kept
+	}
+
+	// Check vulnerabilities if requested
+	if opts.ShowVulnerabilities {
+		var progress io.Writer
+		if banners {
+			progress = deps.Out
+		}
+		if checkProjectVulnerabilities(ctx, opts, deps, pm, modules, progress, gate) {
+			gate.check(modules)
+
+			// Up-to-date packages were only scanned to report their vulnerabilities
+			modules = dropUnaffected(modules)
+			if opts.VulnOnly {
+				modules = filterVulnerable(modules)
+			}
+		} else if opts.VulnOnly {
+			modules = nil
+		}
+	}
+
+	if opts.Count {
+		direct, indirect, transitive := groupModules(modules)
+		report.AddProject(dir, pm.String(), withUpdates(selectForUpdate(direct, indirect, transitive, opts.Transitive)))
+		return nil
+	}
+
+	if len(modules) == 0 {
+		if formats.JSON {
+			report.AddProject(dir, pm.String(), nil)
+			return nil
+		}
+		if formats.CSV {
+			return format.WriteCSV(deps.Out, nil, opts.ShowVulnerabilities)
+		}
+		if formats.Lines || formats.NDJSON || formats.Template != nil {
+			return nil
+		}
+		switch {
+		case opts.VulnOnly:
+			_, _ = fmt.Fprintln(deps.Out, "No vulnerable dependencies found :)")
+		case skippedMajor > 0:
+			_, _ = fmt.Fprintf(deps.Out, "No minor or patch updates; skipped %d major updates (review them with --major-only).\n", skippedMajor)
+		default:
+			_, _ = fmt.Fprintln(deps.Out, "All dependencies match the latest package versions :)")
+		}
+		return nil
+	}
+
+	format.SortModules(modules, format.SortKey(opts.Sort))
+
+	direct, indirect, transitive := groupModules(modules)
+
+	// Adapt group labels based on package manager
+	directLabel, indirectLabel, transitiveLabel := getGroupLabels(pm)
+
+	if opts.Interactive {
 		if deps.StartInteractive == nil {
 			return fmt.Errorf("missing deps.StartInteractive")
 		}
 		// Create updater for interactive mode
-		var updaterInstance updater.Updater
-		if deps.Updater != nil {
-			updaterInstance = deps.Updater
-		} else {
-			updaterInstance, err = factory.CreateUpdater(pm, workDir)
-			if err != nil {
-				return fmt.Errorf("failed to create updater: %w", err)
-			}
+		updaterInstance, err := resolveUpdater(pm, workDir, opts, deps)
+		if err != nil {
+			return fmt.Errorf("failed to create updater: %w", err)
 		}
+		// Vulnerable packages without an update have nothing to select
+		direct, indirect, transitive := groupModules(withUpdates(modules))
 		deps.StartInteractive(direct, indirect, transitive, tui.Options{
-			FormatGroup:     formats.Group,
-			FormatTime:      formats.Time,
-			Updater:         updaterInstance,
-			DirectLabel:     directLabel,
-			IndirectLabel:   indirectLabel,
-			TransitiveLabel: transitiveLabel,
+			FormatGroup:         formats.Group,
+			Grouping:            format.Grouping{By: format.GroupBy(opts.GroupBy), Manager: pm.String()},
+			FormatTime:          formats.Time,
+			ShowVulnerabilities: opts.ShowVulnerabilities,
+			MinSeverity:         formats.MinSeverity,
+			Updater:             updaterInstance,
+			DirectLabel:         directLabel,
+			IndirectLabel:       indirectLabel,
+			TransitiveLabel:     transitiveLabel,
 		})
 		return nil
 	}
 
 	if formats.Lines {
-		printLinesFormat(deps.Out, direct, indirect, transitive, opts.All)
+		prefix := ""
+		if formats.ManagerPrefix {
+			prefix = pm.String() + ":"
+		}
+		printLinesFormat(deps.Out, prefix, direct, indirect, transitive, opts.Transitive)
 		return nil
 	}
 
-	_, _ = fmt.Fprintln(deps.Out, "\nAvailable updates:")
+	if formats.JSON {
+		report.AddProject(dir, pm.String(), selectForUpdate(direct, indirect, transitive, opts.Transitive))
+		return nil
+	}
 
-	maxPathLen := calculateMaxPathLen(direct, indirect, transitive)
-	now := deps.Now()
+	if formats.NDJSON {
+		return format.WriteNDJSON(deps.Out, dir, pm.String(), selectForUpdate(direct, indirect, transitive, opts.Transitive))
+	}
 
-	printGroup(deps.Out, directLabel, direct, maxPathLen, formats.Group, opts.ShowVulnerabilities, formats.Time, now)
-	printGroup(deps.Out, indirectLabel, indirect, maxPathLen, formats.Group, opts.ShowVulnerabilities, formats.Time, now)
-	if opts.All {
-		printGroup(deps.Out, transitiveLabel, transitive, maxPathLen, formats.Group, opts.ShowVulnerabilities, formats.Time, now)
+	if formats.CSV {
+		return format.WriteCSV(deps.Out, selectForUpdate(direct, indirect, transitive, opts.Transitive), opts.ShowVulnerabilities)
 	}
 
-	packagesToUpdate := make([]scanner.Module, 0, len(direct)+len(indirect)+len(transitive))
-	packagesToUpdate = append(packagesToUpdate, direct...)
-	packagesToUpdate = append(packagesToUpdate, indirect...)
-	if opts.All {
-		packagesToUpdate = append(packagesToUpdate, transitive...)
+	if formats.Template != nil {
+		return format.WriteTemplate(deps.Out, formats.Template, selectForUpdate(direct, indirect, transitive, opts.Transitive))
+	}
+
+	_, _ = fmt.Fprintln(deps.Out, "\nAvailable updates:")
+
+	// --max-results shortens the list; the summary and upgrade cover everything
+	shownDirect, shownIndirect, shownTransitive, hidden := capResults(direct, indirect, transitive, opts.Transitive, opts.MaxResults)
+	maxPathLen := calculateMaxPathLen(shownDirect, shownIndirect, shownTransitive)
+	lineOpts := lineOptions{
+		showVulns:    opts.ShowVulnerabilities,
+		showTime:     formats.Time,
+		showReleases: formats.Releases,
+		showDelta:    formats.Delta,
+		minSeverity:  formats.MinSeverity,
+		manager:      pm,
+		grouping:     format.Grouping{By: format.GroupBy(opts.GroupBy), Manager: pm.String()},
+		now:          deps.Now(),
 	}
 
+	printGroup(deps.Out, directLabel, shownDirect, maxPathLen, formats.Group, lineOpts)
+	printGroup(deps.Out, indirectLabel, shownIndirect, maxPathLen, formats.Group, lineOpts)
+	printGroup(deps.Out, transitiveLabel, shownTransitive, maxPathLen, formats.Group, lineOpts)
+	if hidden > 0 {
+		_, _ = fmt.Fprintf(deps.Out, "\n... and %d more\n", hidden)
+	}
+
+	packagesToUpdate := selectForUpdate(direct, indirect, transitive, opts.Transitive)
+
+	summary := format.Summarize(packagesToUpdate)
+	_, _ = fmt.Fprintf(deps.Out, "\n%s\n", summary.Line(countGroups(direct, indirect, transitive, opts.Transitive), opts.ShowVulnerabilities))
+
 	if opts.Upgrade {
-		var updaterInstance updater.Updater
-		if deps.Updater != nil {
-			updaterInstance = deps.Updater
-		} else {
-			updaterInstance, err = factory.CreateUpdater(pm, workDir)
+		updaterInstance, err := resolveUpdater(pm, workDir, opts, deps)
+		if err != nil {
+			return err
+		}
+
+		if skippedMajor > 0 {
+			_, _ = fmt.Fprintf(deps.Out, "Skipping %d major updates (review them with --major-only).\n", skippedMajor)
+		}
+		toApply := withUpdates(packagesToUpdate)
+		if len(toApply) > 0 && !opts.Yes {
+			prompt := fmt.Sprintf("\nAbout to upgrade %d packages:\n", len(toApply))
+			prompt += upgradeGroupLine(directLabel, direct)
+			prompt += upgradeGroupLine(indirectLabel, indirect)
+			if opts.Transitive {
+				prompt += upgradeGroupLine(transitiveLabel, transitive)
+			}
+			ok, err := confirm(deps.Stdin, deps.Out, prompt+"Proceed? [y/N] ")
 			if err != nil {
 				return err
 			}
+			if !ok {
+				_, _ = fmt.Fprintln(deps.Out, "Upgrade cancelled.")
+				return nil
+			}
+		}
+		var snapshot *updater.Snapshot
+		if opts.VerifyCmd != "" {
+			reporter, ok := updaterInstance.(updater.FileReporter)
+			if !ok {
+				return fmt.Errorf("--verify-cmd is not supported for %s", pm)
+			}
+			if snapshot, err = updater.TakeSnapshot(workDir, reporter.Files(toApply)); err != nil {
+				return fmt.Errorf("failed to back up files before upgrading: %w", err)
+			}
 		}
-
 		_, _ = fmt.Fprintln(deps.Out, "\nUpgrading...")
-		if err := updaterInstance.UpdatePackages(packagesToUpdate); err != nil {
+		if err := updaterInstance.UpdatePackages(toApply); err != nil {
 			return err
 		}
 		_, _ = fmt.Fprintln(deps.Out, "Done.")
+		if snapshot != nil {
+			return verifyUpgrade(opts.VerifyCmd, workDir, snapshot, deps)
+		}
 		return nil
 	}
 
-	_, _ = fmt.Fprintln(deps.Out, "\nRun with -u to upgrade, or -i for interactive mode.")
+	if !opts.Frozen && !opts.Quiet {
+		_, _ = fmt.Fprintln(deps.Out, "\nRun with -u to upgrade, or -i for interactive mode.")
+	}
 	return nil
 }
 
+// verifyUpgrade runs the --verify-cmd command in workDir and, if it fails,
+// restores the files snapshotted before the upgrade.
+func verifyUpgrade(command, workDir string, snapshot *updater.Snapshot, deps Deps) error {
+	_, _ = fmt.Fprintf(deps.Out, "\nVerifying with %s...\n", command)
+	cmd := shellCommand(command)
+	cmd.Dir = workDir
+	cmd.Stdout = deps.Out
+	cmd.Stderr = deps.Stderr
+	err := cmd.Run()
+	if err == nil {
+		_, _ = fmt.Fprintln(deps.Out, "Verification passed.")
+		return nil
+	}
+
+	files := strings.Join(snapshot.Files(), ", ")
+	if restoreErr := snapshot.Restore(); restoreErr != nil {
+		return fmt.Errorf("verification failed: %w; restoring %s also failed: %v", err, files, restoreErr)
+	}
+	_, _ = fmt.Fprintf(deps.Out, "Verification failed; restored %s.\n", files)
+	return fmt.Errorf("verification command %q failed: %w", command, err)
+}
+
+// shellCommand runs command through the platform shell, so --verify-cmd can
+// use pipes, && and quoting.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// upgradeGroupLine returns the confirmation prompt line counting the updates
+// in a group, or "" when it has none.
+func upgradeGroupLine(label string, modules []scanner.Module) string {
+	n := len(withUpdates(modules))
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf("  %d in %s\n", n, label)
+}
+
+// confirm asks a yes/no question on w and reads the answer from r, which must
+// be a terminal; anything but y or yes declines. Without a terminal nobody can
+// answer, so it fails and points at --yes instead.
+func confirm(r io.Reader, w io.Writer, prompt string) (bool, error) {
+	f, ok := r.(interface{ Fd() uintptr })
+	if !ok || !term.IsTerminal(f.Fd()) {
+		return false, fmt.Errorf("refusing to upgrade without confirmation: stdin is not a terminal (pass --yes to apply updates)")
+	}
+	_, _ = fmt.Fprint(w, prompt)
+	// A read error such as EOF leaves an empty answer, which declines
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
 // getGroupLabels returns appropriate group labels based on the package manager.
 func getGroupLabels(pm detector.PackageManager) (direct, indirect, transitive string) {
 	switch pm {
@@ -385,7 +1737,7 @@ func getGroupLabels(pm detector.PackageManager) (direct, indirect, transitive st
 			"Transitive (not in go.mod)"
 	case detector.Npm, detector.Yarn, detector.Pnpm:
 		return "Dependencies (package.json)",
-			"DevDependencies (package.json)",
+			"Dev, peer & optional dependencies (package.json)",
 			"Transitive"
 	case detector.Pip:
 		return "Main dependencies (requirements.txt)",
@@ -393,45 +1745,27 @@ func getGroupLabels(pm detector.PackageManager) (direct, indirect, transitive st
 			"Transitive"
 	case detector.Poetry, detector.Uv:
 		return "Main dependencies",
-			"Dev dependencies",
+			"Dev, optional & other dependency groups",
+			"Transitive"
+	case detector.Gradle:
+		return "Dependencies (implementation, api, runtimeOnly)",
+			"Test & other configurations",
+			"Transitive"
+	case detector.Bundler:
+		return "Gems (Gemfile)",
+			"Development gems",
 			"Transitive"
+	case detector.Conda:
+		return "Conda packages (environment.yml)",
+			"Other dependencies",
+			"Transitive"
+	case detector.Maven:
+		return "Dependencies (pom.xml)",
+			"Test, provided & system scoped dependencies (pom.xml)",
+			"Inherited"
 	default:
 		return "Direct dependencies",
 			"Indirect dependencies",
 			"Transitive"
 	}
 }
-
-// formatVulnCounts creates a compact string showing vulnerability transitions
-// e.g., "[L (1), M (2), H (2)] → [L (0)]" or just "[L (1), M (2)]" if no update info
-func formatVulnCounts(current, update scanner.VulnInfo) string {
-	green := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
-	red := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
-
-	currentStr := style.FormatVulnInfo(current)
-	if currentStr == "" {
-		return ""
-	}
-
-	updateStr := style.FormatVulnInfo(update)
-
-	// Show transition with arrow
-	fixed := current.Total - update.Total
-
-	if fixed > 0 {
-		// Vulnerabilities were fixed
-		if updateStr == "" {
-			return fmt.Sprintf("%s → %s", currentStr, green.Render(fmt.Sprintf("✓ (fixes %d)", fixed)))
-		}
-		return fmt.Sprintf("%s → %s %s", currentStr, updateStr, green.Render(fmt.Sprintf("(fixes %d)", fixed)))
-	} else if fixed < 0 {
-		// More vulnerabilities in update
-		return fmt.Sprintf("%s → %s %s", currentStr, updateStr, red.Render(fmt.Sprintf("(+%d)", -fixed)))
-	} else if update.Total > 0 {
-		// Same count but might be different types
-		return fmt.Sprintf("%s → %s", currentStr, updateStr)
-	}
-
-	// No change or no update checked
-	return currentStr
-}
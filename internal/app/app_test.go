@@ -2,19 +2,35 @@ package app
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pragmaticivan/faro/internal/detector"
+	"github.com/pragmaticivan/faro/internal/format"
+	"github.com/pragmaticivan/faro/internal/scancache"
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/style"
 	"github.com/pragmaticivan/faro/internal/tui"
+	"github.com/pragmaticivan/faro/internal/vuln"
 )
 
 type mockScanner struct {
-	modules []scanner.Module
+	modules  []scanner.Module
+	lastOpts scanner.Options
 }
 
 func (m *mockScanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	m.lastOpts = opts
 	return m.modules, nil
 }
 
@@ -37,6 +53,43 @@ func (m *mockUpdater) UpdateSinglePackage(module scanner.Module) error {
 	return nil
 }
 
+// mockInRangeUpdater records in-range updates separately from regular ones.
+type mockInRangeUpdater struct {
+	mockUpdater
+	inRangeModules []scanner.Module
+}
+
+func (m *mockInRangeUpdater) UpdateInRange(modules []scanner.Module) error {
+	m.inRangeModules = modules
+	return nil
+}
+
+// mockPatchUpdater records patch-only updates separately from regular ones.
+type mockPatchUpdater struct {
+	mockUpdater
+	patchModules []scanner.Module
+}
+
+func (m *mockPatchUpdater) UpdatePatch(modules []scanner.Module) error {
+	m.patchModules = modules
+	return nil
+}
+
+// fileUpdater rewrites go.mod in dir, standing in for a real updater.
+type fileUpdater struct {
+	mockUpdater
+	dir string
+}
+
+func (m *fileUpdater) UpdatePackages(modules []scanner.Module) error {
+	m.called = true
+	return os.WriteFile(filepath.Join(m.dir, "go.mod"), []byte("module example.com/app\n\nrequire a v1.1.0\n"), 0644)
+}
+
+func (m *fileUpdater) Files(_ []scanner.Module) []string {
+	return []string{"go.mod", "go.sum"}
+}
+
 func TestRun_FormatLines_NoBanners(t *testing.T) {
 	var out bytes.Buffer
 	fixedNow := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
@@ -63,6 +116,37 @@ func TestRun_FormatLines_NoBanners(t *testing.T) {
 	}
 }
 
+func TestRun_Template(t *testing.T) {
+	var out bytes.Buffer
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true, Indirect: true},
+		// Listed only for its vulnerabilities; the template sees updates alone
+		{Name: "c", Path: "c", Version: "v1.0.0", FromGoMod: true},
+	}
+
+	err := Run(RunOptions{Template: "{{.Name}}:{{.Update.Version}}", Manager: "go", ShowVulnerabilities: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{counts: map[string]vuln.SeverityCounts{"c@v1.0.0": {High: 1, Total: 1}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got, want := out.String(), "a:v1.1.0\nb:v1.0.1\n"; got != want {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+
+	err = Run(RunOptions{Template: "{{.Name", Manager: "go"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err == nil || !strings.Contains(err.Error(), "invalid --template") {
+		t.Errorf("expected a template parse error, got %v", err)
+	}
+	err = Run(RunOptions{Template: "{{.Name}}", FormatFlag: "json", Manager: "go"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err == nil || !strings.Contains(err.Error(), "--template cannot be combined") {
+		t.Errorf("expected --template with json to fail, got %v", err)
+	}
+}
+
 func TestRun_Interactive_CallsHook(t *testing.T) {
 	var out bytes.Buffer
 	called := false
@@ -113,7 +197,7 @@ func TestRun_Upgrade_CallsUpdatePackages(t *testing.T) {
 	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true}}
 	mockUp := &mockUpdater{}
 
-	err := Run(RunOptions{Upgrade: true, Manager: "go"}, Deps{
+	err := Run(RunOptions{Upgrade: true, Yes: true, Manager: "go"}, Deps{
 		Out:              &out,
 		Scanner:          &mockScanner{modules: mods},
 		Updater:          mockUp,
@@ -130,6 +214,143 @@ func TestRun_Upgrade_CallsUpdatePackages(t *testing.T) {
 	}
 }
 
+func TestRun_Upgrade_RequiresConfirmationWithoutTerminal(t *testing.T) {
+	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true}}
+
+	var out bytes.Buffer
+	up := &mockUpdater{}
+	err := Run(RunOptions{Upgrade: true, Manager: "go"}, Deps{
+		Out:     &out,
+		Stdin:   strings.NewReader("y\n"),
+		Scanner: &mockScanner{modules: mods},
+		Updater: up,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("expected an error pointing at --yes, got %v", err)
+	}
+	if up.called {
+		t.Fatalf("expected the updater not to be invoked")
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Upgrade: true, Yes: true, Manager: "go"}, Deps{
+		Out:     &out,
+		Stdin:   strings.NewReader(""),
+		Scanner: &mockScanner{modules: mods},
+		Updater: up,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !up.called {
+		t.Fatalf("expected --yes to apply the updates")
+	}
+	if strings.Contains(out.String(), "Proceed?") {
+		t.Fatalf("expected no prompt with --yes, got:\n%s", out.String())
+	}
+}
+
+func TestRun_VerifyCmd(t *testing.T) {
+	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true}}
+	const original = "module example.com/app\n\nrequire a v1.0.0\n"
+
+	run := func(t *testing.T, verifyCmd string) (string, error) {
+		t.Helper()
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(original), 0644); err != nil {
+			t.Fatal(err)
+		}
+		up := &fileUpdater{dir: dir}
+		var out bytes.Buffer
+		err := Run(RunOptions{Upgrade: true, Yes: true, Manager: "go", Path: dir, VerifyCmd: verifyCmd}, Deps{
+			Out:     &out,
+			Stderr:  &out,
+			Scanner: &mockScanner{modules: mods},
+			Updater: up,
+		})
+		if !up.called {
+			t.Fatalf("expected the updater to run")
+		}
+		data, readErr := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if readErr != nil {
+			t.Fatal(readErr)
+		}
+		if _, statErr := os.Stat(filepath.Join(dir, "go.sum")); !os.IsNotExist(statErr) {
+			t.Fatalf("expected go.sum to stay absent, got %v", statErr)
+		}
+		return string(data), err
+	}
+
+	t.Run("success keeps updates", func(t *testing.T) {
+		got, err := run(t, "test -f go.mod")
+		if err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		if got == original {
+			t.Fatalf("expected go.mod to keep the update")
+		}
+	})
+
+	t.Run("failure restores files", func(t *testing.T) {
+		got, err := run(t, "echo broken >&2; exit 3")
+		if err == nil || !strings.Contains(err.Error(), "verification command") {
+			t.Fatalf("expected a verification error, got %v", err)
+		}
+		if got != original {
+			t.Fatalf("expected go.mod to be restored, got:\n%s", got)
+		}
+	})
+}
+
+func TestRun_VerifyCmdRequiresUpgrade(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", VerifyCmd: "true"}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "--verify-cmd requires") {
+		t.Fatalf("expected a --verify-cmd error, got %v", err)
+	}
+
+	err = Run(RunOptions{Manager: "go", VerifySums: true}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "--verify-sums requires") {
+		t.Fatalf("expected a --verify-sums error, got %v", err)
+	}
+}
+
+func TestRun_FrozenNeverUpdates(t *testing.T) {
+	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true}}
+
+	var out bytes.Buffer
+	up := &mockUpdater{}
+	err := Run(RunOptions{Frozen: true, Upgrade: true, Manager: "go"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+		Updater: up,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--frozen cannot be combined") {
+		t.Fatalf("expected a --frozen -u error, got %v", err)
+	}
+	if up.called {
+		t.Fatalf("expected the updater not to be invoked")
+	}
+
+	out.Reset()
+	interactive := false
+	err = Run(RunOptions{Frozen: true, Interactive: true, Manager: "go"}, Deps{
+		Out:              &out,
+		Scanner:          &mockScanner{modules: mods},
+		Updater:          up,
+		StartInteractive: func(_, _, _ []scanner.Module, _ tui.Options) { interactive = true },
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if interactive || up.called {
+		t.Fatalf("expected --frozen to skip interactive mode and updates")
+	}
+	if text := out.String(); !strings.Contains(text, "Available updates") || strings.Contains(text, "Run with -u") {
+		t.Errorf("expected a plain listing, got %q", text)
+	}
+}
+
 func TestRun_GroupedOutput_PrintsHeadings(t *testing.T) {
 	var out bytes.Buffer
 	fixedNow := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
@@ -154,3 +375,1795 @@ func TestRun_GroupedOutput_PrintsHeadings(t *testing.T) {
 		t.Fatalf("expected headings, got: %q", text)
 	}
 }
+
+func TestRun_FormatTime_ShowsCurrentAge(t *testing.T) {
+	var out bytes.Buffer
+	fixedNow := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
+	mods := []scanner.Module{{
+		Path:      "a",
+		Version:   "v1.0.0",
+		Time:      "2024-12-01T00:00:00Z",
+		Update:    &scanner.UpdateInfo{Version: "v1.0.1", Time: "2026-01-10T00:00:00Z"},
+		FromGoMod: true,
+	}}
+
+	err := Run(RunOptions{FormatFlag: "time", Manager: "go"}, Deps{
+		Out:     &out,
+		Now:     func() time.Time { return fixedNow },
+		Scanner: &mockScanner{modules: mods},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if text := out.String(); !strings.Contains(text, "2026-01-10 (7d ago)") || !strings.Contains(text, "(current is 412 days old)") {
+		t.Fatalf("expected update and current ages, got: %q", text)
+	}
+}
+
+func TestRun_FormatReleases_AppendsURL(t *testing.T) {
+	var out bytes.Buffer
+	mods := []scanner.Module{{
+		Path:      "github.com/spf13/cobra",
+		Version:   "v1.8.0",
+		Update:    &scanner.UpdateInfo{Version: "v1.9.0"},
+		FromGoMod: true,
+	}}
+
+	err := Run(RunOptions{FormatFlag: "releases", Manager: "go"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(out.String(), "https://github.com/spf13/cobra/releases") {
+		t.Fatalf("expected release URL, got: %q", out.String())
+	}
+}
+
+func TestRun_PrintsSummaryLine(t *testing.T) {
+	var out bytes.Buffer
+	mods := []scanner.Module{
+		{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, FromGoMod: true},
+		{Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true, Indirect: true},
+		{Path: "c", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}},
+	}
+
+	err := Run(RunOptions{Manager: "go"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	// Transitive updates are hidden without --all, so they are not counted
+	want := "2 updates available (1 major, 0 minor, 1 patch) across 2 groups"
+	if !strings.Contains(out.String(), want) {
+		t.Fatalf("expected summary %q, got: %q", want, out.String())
+	}
+}
+
+type warningScanner struct {
+	mockScanner
+	warnings []string
+}
+
+func (w *warningScanner) Warnings() []string {
+	return w.warnings
+}
+
+func TestRun_PrintsScannerWarnings(t *testing.T) {
+	var out bytes.Buffer
+	s := &warningScanner{warnings: []string{"skipping example.com/x v2.0.0: requires go 1.23"}}
+
+	err := Run(RunOptions{Manager: "go"}, Deps{Out: &out, Scanner: s})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(out.String(), "Warning: skipping example.com/x") {
+		t.Fatalf("expected warning, got: %q", out.String())
+	}
+
+	out.Reset()
+	if err := Run(RunOptions{Manager: "go", FormatFlag: "lines"}, Deps{Out: &out, Scanner: s}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if strings.Contains(out.String(), "Warning") {
+		t.Fatalf("did not expect warnings in lines format: %q", out.String())
+	}
+}
+
+func TestRun_FormatJSON_NestsPerProject(t *testing.T) {
+	var out bytes.Buffer
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "t", Path: "t", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}},
+	}
+
+	err := Run(RunOptions{FormatFlag: "json", Manager: "go"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	var report format.Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("expected pure JSON output, got %q: %v", out.String(), err)
+	}
+	if report.SchemaVersion != format.SchemaVersion {
+		t.Fatalf("expected schemaVersion %d, got %d", format.SchemaVersion, report.SchemaVersion)
+	}
+	if len(report.Projects) != 1 || report.Projects[0].Manager != "go" || report.Projects[0].Dir != "." {
+		t.Fatalf("unexpected projects: %+v", report.Projects)
+	}
+	if len(report.Projects[0].Updates) != 1 || report.Projects[0].Updates[0].Name != "a" {
+		t.Fatalf("expected only the go.mod update without --all, got %+v", report.Projects[0].Updates)
+	}
+}
+
+func TestRun_FormatNDJSON(t *testing.T) {
+	var out bytes.Buffer
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v2.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.1"}, FromGoMod: true, Indirect: true},
+	}
+
+	err := Run(RunOptions{FormatFlag: "ndjson", Manager: "go"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per module and nothing else, got %q", out.String())
+	}
+	var names []string
+	for _, line := range lines {
+		var m scanner.Module
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("line %q does not decode to a module: %v", line, err)
+		}
+		if m.Update == nil {
+			t.Fatalf("expected an update on line %q", line)
+		}
+		names = append(names, m.Name+"@"+m.Update.Version)
+	}
+	if want := []string{"a@v1.1.0", "b@v2.0.1"}; !slices.Equal(names, want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+}
+
+func TestRun_FormatCSV(t *testing.T) {
+	var out bytes.Buffer
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "b,c", Path: "b,c", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, Direct: true, DependencyType: "direct"},
+	}
+
+	err := Run(RunOptions{FormatFlag: "csv", Manager: "go", ShowVulnerabilities: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{counts: map[string]vuln.SeverityCounts{"a@v1.0.0": {High: 2, Total: 2}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("expected pure CSV output, got %q: %v", out.String(), err)
+	}
+	want := [][]string{
+		{"name", "current", "update", "dependency_type", "direct", "vulns_current", "vulns_update"},
+		{"a", "v1.0.0", "v1.1.0", "", "false", "2", "0"},
+		{"b,c", "v1.0.0", "v2.0.0", "direct", "true", "0", "0"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %q", len(want), records)
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestRun_MinSeverity(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.2.0"}, FromGoMod: true},
+	}
+	vulns := &mockVulnClient{counts: map[string]vuln.SeverityCounts{
+		"a@v1.0.0": {Low: 1, Total: 1},
+		"b@v1.0.0": {High: 1, Total: 1},
+	}}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", ShowVulnerabilities: true, MinSeverity: "high", NoColor: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: vulns,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	got := out.String()
+	if strings.Contains(got, "L (1)") {
+		t.Errorf("expected the low finding to be hidden at --min-severity high, got %q", got)
+	}
+	if !strings.Contains(got, "H (1)") {
+		t.Errorf("expected the high finding to be shown, got %q", got)
+	}
+
+	// The threshold only lasts for the run
+	out.Reset()
+	err = Run(RunOptions{Manager: "go", ShowVulnerabilities: true, NoColor: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: vulns,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(out.String(), "L (1)") {
+		t.Errorf("expected the low finding without --min-severity, got %q", out.String())
+	}
+
+	err = Run(RunOptions{Manager: "go", MinSeverity: "severe"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err == nil || !strings.Contains(err.Error(), "--min-severity") {
+		t.Errorf("expected an unknown severity to fail, got %v", err)
+	}
+}
+
+func TestRun_VulnerabilityProgress(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, FromGoMod: true},
+		{Name: "c", Path: "c", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", ShowVulnerabilities: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if text := out.String(); !strings.Contains(text, "Checked 3/3 packages\n") {
+		t.Fatalf("expected a progress line for 3/3 packages, got %q", text)
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Manager: "go", FormatFlag: "lines", ShowVulnerabilities: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if strings.Contains(out.String(), "Checked") {
+		t.Errorf("expected no progress in lines format, got %q", out.String())
+	}
+}
+
+func TestCheckVulnerabilities_ReportsProgressPerChunk(t *testing.T) {
+	n := 2*vulnCheckChunk + 3
+	mods := make([]scanner.Module, n)
+	for i := range mods {
+		mods[i] = scanner.Module{Name: "m" + strconv.Itoa(i), Version: "v1.0.0"}
+	}
+	last := mods[n-1].Name
+	vulns := &mockVulnClient{counts: map[string]vuln.SeverityCounts{last + "@v1.0.0": {High: 1, Total: 1}}}
+
+	var out bytes.Buffer
+	if err := checkVulnerabilities(context.Background(), mods, vulns, &out); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := fmt.Sprintf("Checked %d/%d packages\nChecked %d/%d packages\nChecked %d/%d packages\n",
+		vulnCheckChunk, n, 2*vulnCheckChunk, n, n, n)
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+	if mods[n-1].VulnCurrent.High != 1 {
+		t.Errorf("expected the last chunk to be checked, got %+v", mods[n-1].VulnCurrent)
+	}
+
+	out.Reset()
+	vulns.err = errors.New("osv unreachable")
+	if err := checkVulnerabilities(context.Background(), mods, vulns, &out); err == nil {
+		t.Fatal("expected the client error to be returned")
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no progress after a failed chunk, got %q", out.String())
+	}
+}
+
+func TestRun_VulnerabilitiesSkippedForConda(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "numpy", Version: "1.26.0", Update: &scanner.UpdateInfo{Version: "1.26.4"}, Direct: true, DependencyType: "main"},
+	}
+	vulns := &mockVulnClient{counts: map[string]vuln.SeverityCounts{"numpy@1.26.0": {High: 1, Total: 1}}}
+
+	var out, stderr bytes.Buffer
+	err := Run(RunOptions{Manager: "conda", FormatFlag: "lines", ShowVulnerabilities: true}, Deps{
+		Out:        &out,
+		Stderr:     &stderr,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: vulns,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if out.String() != "numpy@1.26.4\n" {
+		t.Errorf("expected the update to be listed, got %q", out.String())
+	}
+	if !strings.Contains(stderr.String(), "vulnerability data is not available for conda packages") {
+		t.Errorf("expected a warning about skipping the check, got %q", stderr.String())
+	}
+}
+
+func TestRun_VulnOnly(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, FromGoMod: true},
+		// Vulnerable with no fix released yet
+		{Name: "pinned", Path: "pinned", Version: "v3.0.0", FromGoMod: true},
+	}
+	vulns := &mockVulnClient{counts: map[string]vuln.SeverityCounts{
+		"b@v1.0.0":      {High: 1, Total: 1},
+		"pinned@v3.0.0": {Critical: 1, Total: 1},
+	}}
+
+	var out bytes.Buffer
+	upd := &mockUpdater{}
+	err := Run(RunOptions{Manager: "go", VulnOnly: true, Upgrade: true, Yes: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		Updater:    upd,
+		VulnClient: vulns,
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	text := out.String()
+	if strings.Contains(text, " a ") || !strings.Contains(text, "b") {
+		t.Errorf("expected only vulnerable modules, got %q", text)
+	}
+	if !strings.Contains(text, "pinned") || !strings.Contains(text, "no update available") {
+		t.Errorf("expected the vulnerable module without an update to be listed, got %q", text)
+	}
+	if !strings.Contains(text, "Checked 3/3 packages") {
+		t.Errorf("expected modules without updates to be checked, got %q", text)
+	}
+	if len(upd.lastModules) != 1 || upd.lastModules[0].Name != "b" {
+		t.Errorf("expected only b to be upgraded, got %+v", upd.lastModules)
+	}
+}
+
+func TestRun_Vulnerabilities_ReportsPinnedPackages(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		// Up to date; only returned for the vulnerability scan
+		{Name: "clean", Path: "clean", Version: "v1.0.0", FromGoMod: true},
+		{Name: "pinned", Path: "pinned", Version: "v3.0.0", FromGoMod: true},
+	}
+	s := &mockScanner{modules: mods}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", ShowVulnerabilities: true}, Deps{
+		Out:     &out,
+		Scanner: s,
+		VulnClient: &mockVulnClient{counts: map[string]vuln.SeverityCounts{
+			"pinned@v3.0.0": {High: 2, Total: 2},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !s.lastOpts.IncludeVulnScan {
+		t.Error("expected -v to ask the scanner for up-to-date packages")
+	}
+
+	text := out.String()
+	if !strings.Contains(text, "pinned") || !strings.Contains(text, "no update available") {
+		t.Errorf("expected the pinned vulnerable package to be listed, got %q", text)
+	}
+	if strings.Contains(text, "clean") {
+		t.Errorf("expected up-to-date packages without vulnerabilities to be dropped, got %q", text)
+	}
+	if !strings.Contains(text, "1 update available") {
+		t.Errorf("expected the summary to count only real updates, got %q", text)
+	}
+}
+
+func TestRun_FailOnVuln(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, FromGoMod: true},
+	}
+	vulns := &mockVulnClient{counts: map[string]vuln.SeverityCounts{
+		"a@v1.0.0": {Medium: 3, Total: 3},
+		"b@v1.0.0": {High: 1, Total: 1},
+	}}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", FormatFlag: "json", FailOnVuln: "high"}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: vulns,
+	})
+	if err == nil || !strings.Contains(err.Error(), "found 1 dependencies with high or higher severity") {
+		t.Fatalf("expected the high finding to fail the run, got %v", err)
+	}
+	var report format.Report
+	if jsonErr := json.Unmarshal(out.Bytes(), &report); jsonErr != nil {
+		t.Fatalf("expected the JSON report to be written before failing, got %q: %v", out.String(), jsonErr)
+	}
+	if len(report.Projects) != 1 || len(report.Projects[0].Updates) != 2 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Manager: "go", FormatFlag: "lines", FailOnVuln: "critical"}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: vulns,
+	})
+	if err != nil {
+		t.Fatalf("expected no failure below the critical threshold, got %v", err)
+	}
+
+	err = Run(RunOptions{Manager: "go", FailOnVuln: "severe"}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "unsupported --fail-on-vuln") {
+		t.Fatalf("expected an unsupported --fail-on-vuln error, got %v", err)
+	}
+}
+
+func TestRun_VulnCheckFailure(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+	}
+	vulns := &mockVulnClient{err: errors.New("OSV API unreachable")}
+
+	// With --fail-on-vuln an unchecked dependency fails the run
+	var out, stderr bytes.Buffer
+	err := Run(RunOptions{Manager: "go", FormatFlag: "lines", FailOnVuln: "high"}, Deps{
+		Out:        &out,
+		Stderr:     &stderr,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: vulns,
+	})
+	if err == nil || !strings.Contains(err.Error(), "vulnerability check failed") || !strings.Contains(err.Error(), "OSV API unreachable") {
+		t.Fatalf("expected the failed check to fail the gate, got %v", err)
+	}
+	if out.String() != "a@v1.1.0\n" {
+		t.Errorf("expected the updates to be listed anyway, got %q", out.String())
+	}
+
+	// Otherwise it's a warning
+	out.Reset()
+	stderr.Reset()
+	err = Run(RunOptions{Manager: "go", FormatFlag: "lines", ShowVulnerabilities: true}, Deps{
+		Out:        &out,
+		Stderr:     &stderr,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: vulns,
+	})
+	if err != nil {
+		t.Fatalf("expected no error without --fail-on-vuln, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "vulnerability check failed") {
+		t.Errorf("expected a warning on stderr, got %q", stderr.String())
+	}
+}
+
+func TestRun_VulnCheckUsesRunContext(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+	}
+	vulns := &mockVulnClient{}
+	err := Run(RunOptions{Manager: "go", FormatFlag: "lines", ShowVulnerabilities: true, Timeout: time.Minute}, Deps{
+		Out:        &bytes.Buffer{},
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: vulns,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if vulns.ctx == nil {
+		t.Fatal("expected the vulnerability client to be called")
+	}
+	if _, ok := vulns.ctx.Deadline(); !ok {
+		t.Error("expected --timeout to bound the vulnerability lookups")
+	}
+	if vulns.ctx.Err() == nil {
+		t.Error("expected the lookups' context to be cancelled once Run returns")
+	}
+}
+
+func TestRun_VulnOnly_NoneVulnerable(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", VulnOnly: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(out.String(), "No vulnerable dependencies found") {
+		t.Errorf("expected a no-vulnerabilities message, got %q", out.String())
+	}
+}
+
+func TestRun_FormatCSV_VulnerableWithoutUpdate(t *testing.T) {
+	var out bytes.Buffer
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "pinned", Path: "pinned", Version: "v3.0.0", FromGoMod: true},
+	}
+
+	err := Run(RunOptions{FormatFlag: "csv", Manager: "go", VulnOnly: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{counts: map[string]vuln.SeverityCounts{"pinned@v3.0.0": {Critical: 1, Total: 1}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("expected pure CSV output, got %q: %v", out.String(), err)
+	}
+	want := [][]string{
+		{"name", "current", "update", "dependency_type", "direct", "vulns_current", "vulns_update"},
+		{"pinned", "v3.0.0", "", "", "false", "1", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %q", len(want), records)
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestRun_FormatCSV_NoUpdates(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(RunOptions{FormatFlag: "csv", Manager: "go"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{},
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got := out.String(); got != "name,current,update,dependency_type,direct\n" {
+		t.Fatalf("expected only the header row, got %q", got)
+	}
+}
+
+func TestRun_NoEscapeCodesWhenNotATerminal(t *testing.T) {
+	t.Cleanup(func() { style.SetColorEnabled(true) })
+	style.SetColorEnabled(true)
+
+	var out bytes.Buffer
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true, Indirect: true},
+	}
+
+	err := Run(RunOptions{Manager: "go", FormatFlag: "group", ShowVulnerabilities: true}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{counts: map[string]vuln.SeverityCounts{"a@v1.0.0": {High: 1, Total: 1}}},
+		Getenv:     func(string) string { return "" },
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Fatalf("expected no escape codes in piped output, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "a") || !strings.Contains(out.String(), "v2.0.0") {
+		t.Fatalf("expected plain update lines, got %q", out.String())
+	}
+}
+
+func TestRun_GroupByScope(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "github.com/aws/aws-sdk-go", Path: "github.com/aws/aws-sdk-go", Version: "v1.44.0", Update: &scanner.UpdateInfo{Version: "v1.55.0"}, FromGoMod: true},
+		{Name: "github.com/spf13/cobra", Path: "github.com/spf13/cobra", Version: "v1.7.0", Update: &scanner.UpdateInfo{Version: "v1.8.0"}, FromGoMod: true},
+		{Name: "github.com/aws/smithy-go", Path: "github.com/aws/smithy-go", Version: "v1.13.0", Update: &scanner.UpdateInfo{Version: "v1.20.0"}, FromGoMod: true},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", GroupBy: "scope"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+		Getenv:  func(string) string { return "" },
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	// --group-by turns on grouped output with one heading per scope
+	got := out.String()
+	aws := strings.Index(got, "\ngithub.com/aws\n")
+	spf13 := strings.Index(got, "\ngithub.com/spf13\n")
+	if aws < 0 || spf13 < 0 || strings.Count(got, "\ngithub.com/aws\n") != 1 {
+		t.Fatalf("expected one heading per scope, got %q", got)
+	}
+	if sdk, smithy := strings.Index(got, "aws-sdk-go"), strings.Index(got, "smithy-go"); sdk < aws || smithy < aws || sdk > spf13 || smithy > spf13 {
+		t.Errorf("expected both aws modules under the github.com/aws heading, got %q", got)
+	}
+
+	err = Run(RunOptions{Manager: "go", GroupBy: "license"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err == nil || !strings.Contains(err.Error(), "unsupported --group-by") {
+		t.Fatalf("expected an unsupported --group-by error, got %v", err)
+	}
+}
+
+func TestRun_SkipMajor_ExcludesMajorFromUpgrade(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "c", Path: "c", Version: "v0.1.0", Update: &scanner.UpdateInfo{Version: "v0.2.0"}, FromGoMod: true},
+		{Name: "d", Path: "d", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true},
+	}
+
+	var out bytes.Buffer
+	sc := &mockScanner{modules: mods}
+	up := &mockUpdater{}
+	err := Run(RunOptions{Manager: "go", Upgrade: true, Yes: true, SkipMajor: true, Cooldown: 3}, Deps{
+		Out:     &out,
+		Scanner: sc,
+		Updater: up,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if sc.lastOpts.CooldownDays != 3 {
+		t.Errorf("expected the cooldown to reach the scanner, got %d", sc.lastOpts.CooldownDays)
+	}
+
+	var applied []string
+	for _, m := range up.lastModules {
+		applied = append(applied, m.Name)
+	}
+	if !slices.Equal(applied, []string{"b", "d"}) {
+		t.Errorf("applied %v, want the minor and patch updates only", applied)
+	}
+	if !strings.Contains(out.String(), "Skipping 2 major updates") {
+		t.Errorf("expected the skipped majors to be reported, got %q", out.String())
+	}
+}
+
+func TestRun_SkipMajor_OnlyMajorUpdates(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, FromGoMod: true},
+	}
+
+	var out bytes.Buffer
+	up := &mockUpdater{}
+	err := Run(RunOptions{Manager: "go", Upgrade: true, SkipMajor: true}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+		Updater: up,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if up.called {
+		t.Error("expected no upgrade when every update is major")
+	}
+	if !strings.Contains(out.String(), "skipped 1 major updates") {
+		t.Errorf("expected the skipped major to be reported, got %q", out.String())
+	}
+}
+
+func TestGroupModules_NodePeerAndOptional(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "react", Direct: true, DependencyType: "dependencies"},
+		{Name: "jest", Direct: true, DependencyType: "devDependencies"},
+		{Name: "react-dom", Direct: true, DependencyType: "peerDependencies"},
+		{Name: "fsevents", Direct: true, DependencyType: "optionalDependencies"},
+		{Name: "lodash", DependencyType: "transitive"},
+	}
+
+	direct, indirect, transitive := groupModules(mods)
+	if len(direct) != 1 || direct[0].Name != "react" {
+		t.Fatalf("unexpected direct group: %+v", direct)
+	}
+	if len(indirect) != 3 {
+		t.Fatalf("expected dev, peer and optional in the secondary group, got %+v", indirect)
+	}
+	if len(transitive) != 1 || transitive[0].Name != "lodash" {
+		t.Fatalf("unexpected transitive group: %+v", transitive)
+	}
+}
+
+func TestGroupModules_PoetryGroups(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "requests", Direct: true, DependencyType: "main"},
+		{Name: "pytest", Direct: true, DependencyType: "test"},
+		{Name: "mkdocs", Direct: true, DependencyType: "docs"},
+	}
+
+	direct, indirect, _ := groupModules(mods)
+	if len(direct) != 1 || len(indirect) != 2 {
+		t.Fatalf("expected non-main groups in the secondary group, got direct=%+v indirect=%+v", direct, indirect)
+	}
+}
+
+func TestRun_DepTypeDev_NpmOnlyDevDependencies(t *testing.T) {
+	var out bytes.Buffer
+	s := &mockScanner{modules: []scanner.Module{
+		{Name: "react", Version: "18.0.0", Update: &scanner.UpdateInfo{Version: "18.2.0"}, Direct: true, DependencyType: "dependencies"},
+		{Name: "jest", Version: "29.0.0", Update: &scanner.UpdateInfo{Version: "29.7.0"}, Direct: true, DependencyType: "devDependencies"},
+		{Name: "eslint", Version: "8.0.0", Update: &scanner.UpdateInfo{Version: "8.57.0"}, Direct: true, DependencyType: "devDependencies"},
+		{Name: "react-dom", Version: "18.0.0", Update: &scanner.UpdateInfo{Version: "18.2.0"}, Direct: true, DependencyType: "peerDependencies"},
+		{Name: "lodash", Version: "4.17.0", Update: &scanner.UpdateInfo{Version: "4.17.21"}, DependencyType: "transitive"},
+	}}
+
+	err := Run(RunOptions{Manager: "npm", FormatFlag: "lines", DepTypes: []string{"dev"}}, Deps{Out: &out, Scanner: s})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got := out.String(); got != "jest@29.7.0\neslint@8.57.0\n" {
+		t.Fatalf("expected only devDependencies, got %q", got)
+	}
+	if !s.lastOpts.IncludeDev || s.lastOpts.IncludeTransitive {
+		t.Errorf("expected --dep-type dev to scan dev but not transitive dependencies")
+	}
+}
+
+func TestRun_DevAndTransitive(t *testing.T) {
+	tests := []struct {
+		opts            RunOptions
+		dev, transitive bool
+	}{
+		{RunOptions{Dev: true}, true, false},
+		{RunOptions{Transitive: true}, false, true},
+		{RunOptions{All: true}, true, true},
+	}
+	for _, tt := range tests {
+		s := &mockScanner{}
+		tt.opts.Manager = "npm"
+		if err := Run(tt.opts, Deps{Out: &bytes.Buffer{}, Scanner: s}); err != nil {
+			t.Fatalf("%+v: unexpected err: %v", tt.opts, err)
+		}
+		if s.lastOpts.IncludeDev != tt.dev || s.lastOpts.IncludeTransitive != tt.transitive {
+			t.Errorf("dev=%v transitive=%v all=%v: got IncludeDev=%v IncludeTransitive=%v", tt.opts.Dev, tt.opts.Transitive, tt.opts.All, s.lastOpts.IncludeDev, s.lastOpts.IncludeTransitive)
+		}
+	}
+}
+
+func TestRun_NoDev(t *testing.T) {
+	for _, opts := range []RunOptions{
+		{NoDev: true, All: true},
+		{NoDev: true, Dev: true, Transitive: true},
+		{NoDev: true, DepTypes: []string{"peer"}},
+	} {
+		s := &mockScanner{}
+		opts.Manager = "npm"
+		if err := Run(opts, Deps{Out: &bytes.Buffer{}, Scanner: s}); err != nil {
+			t.Fatalf("%+v: unexpected err: %v", opts, err)
+		}
+		if s.lastOpts.IncludeDev {
+			t.Errorf("all=%v dev=%v depTypes=%v: expected --no-dev to force IncludeDev off", opts.All, opts.Dev, opts.DepTypes)
+		}
+		if wantTransitive := opts.All || opts.Transitive; s.lastOpts.IncludeTransitive != wantTransitive {
+			t.Errorf("all=%v transitive=%v: IncludeTransitive = %v, want %v", opts.All, opts.Transitive, s.lastOpts.IncludeTransitive, wantTransitive)
+		}
+	}
+}
+
+func TestRun_DepTypeIndirect_GoExcludesTransitive(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, Direct: true, DependencyType: "direct", FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, DependencyType: "indirect", FromGoMod: true, Indirect: true},
+		{Name: "c", Path: "c", Version: "v0.1.0", Update: &scanner.UpdateInfo{Version: "v0.2.0"}, DependencyType: "transitive", Indirect: true},
+	}
+
+	tests := []struct {
+		depTypes          []string
+		want              string
+		includeTransitive bool
+	}{
+		{[]string{"indirect"}, "b@v1.0.1\n", false},
+		{[]string{"direct", "indirect"}, "a@v1.1.0\nb@v1.0.1\n", false},
+		{[]string{"transitive"}, "c@v0.2.0\n", true},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		s := &mockScanner{modules: mods}
+		err := Run(RunOptions{Manager: "go", FormatFlag: "lines", DepTypes: tt.depTypes}, Deps{Out: &out, Scanner: s})
+		if err != nil {
+			t.Fatalf("%v: unexpected err: %v", tt.depTypes, err)
+		}
+		if got := out.String(); got != tt.want {
+			t.Errorf("%v: output = %q, want %q", tt.depTypes, got, tt.want)
+		}
+		if s.lastOpts.IncludeTransitive != tt.includeTransitive {
+			t.Errorf("%v: IncludeTransitive = %v, want %v", tt.depTypes, s.lastOpts.IncludeTransitive, tt.includeTransitive)
+		}
+	}
+}
+
+func TestRun_MaxResults(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+		{Name: "b", Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.2.0"}, FromGoMod: true},
+		{Name: "c", Path: "c", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.3.0"}, FromGoMod: true, Indirect: true},
+		{Name: "d", Path: "d", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.4.0"}, FromGoMod: true, Indirect: true},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", MaxResults: 3, NoColor: true}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	got := out.String()
+	for _, name := range []string{"a ", "b ", "c "} {
+		if !strings.Contains(got, " "+name) {
+			t.Errorf("expected %q to be listed, got %q", name, got)
+		}
+	}
+	if strings.Contains(got, "v1.4.0") {
+		t.Errorf("expected d to be cut off, got %q", got)
+	}
+	if !strings.Contains(got, "... and 1 more") {
+		t.Errorf("expected a count of the hidden updates, got %q", got)
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Manager: "go", MaxResults: 1, FormatFlag: "lines"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got := strings.Count(out.String(), "\n"); got != 4 {
+		t.Errorf("expected machine-readable output to stay complete, got %q", out.String())
+	}
+
+	err = Run(RunOptions{Manager: "go", MaxResults: -1}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err == nil || !strings.Contains(err.Error(), "--max-results") {
+		t.Errorf("expected a negative --max-results to fail, got %v", err)
+	}
+}
+
+func TestRun_MajorOnly(t *testing.T) {
+	var out bytes.Buffer
+	s := &mockScanner{modules: []scanner.Module{
+		{Name: "github.com/a/major", Version: "v1.2.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, Direct: true},
+		{Name: "github.com/b/minor", Version: "v1.2.0", Update: &scanner.UpdateInfo{Version: "v1.3.0"}, Direct: true},
+		{Name: "github.com/c/v0minor", Version: "v0.4.0", Update: &scanner.UpdateInfo{Version: "v0.5.0"}, Direct: true},
+		{Name: "github.com/d/patch", Version: "v0.4.0", Update: &scanner.UpdateInfo{Version: "v0.4.1"}, Direct: true},
+	}}
+
+	err := Run(RunOptions{Manager: "go", FormatFlag: "lines", MajorOnly: true}, Deps{Out: &out, Scanner: s})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got := out.String(); got != "github.com/a/major@v2.0.0\ngithub.com/c/v0minor@v0.5.0\n" {
+		t.Fatalf("expected only major bumps, got %q", got)
+	}
+}
+
+func TestRun_DepTypeInvalid(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "npm", DepTypes: []string{"prod"}}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "unsupported --dep-type") {
+		t.Fatalf("expected an unsupported --dep-type error, got %v", err)
+	}
+}
+
+func TestRun_SortByName(t *testing.T) {
+	var out bytes.Buffer
+	s := &mockScanner{modules: []scanner.Module{
+		{Name: "vue", Version: "3.0.0", Update: &scanner.UpdateInfo{Version: "3.3.0"}, Direct: true, DependencyType: "dependencies"},
+		{Name: "axios", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.6.0"}, Direct: true, DependencyType: "dependencies"},
+		{Name: "react", Version: "17.0.0", Update: &scanner.UpdateInfo{Version: "18.2.0"}, Direct: true, DependencyType: "dependencies"},
+	}}
+
+	err := Run(RunOptions{Manager: "npm", FormatFlag: "lines", Sort: "name"}, Deps{Out: &out, Scanner: s})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got := out.String(); got != "axios@1.6.0\nreact@18.2.0\nvue@3.3.0\n" {
+		t.Fatalf("expected updates sorted by name, got %q", got)
+	}
+}
+
+func TestRun_SortValidation(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "npm", Sort: "size"}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "unsupported --sort") {
+		t.Fatalf("expected an unsupported --sort error, got %v", err)
+	}
+
+	err = Run(RunOptions{Manager: "npm", Sort: "severity"}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "requires -v") {
+		t.Fatalf("expected --sort severity to require -v, got %v", err)
+	}
+}
+
+func TestRun_OSVURLValidation(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "npm", OSVURL: "osv.example.com"}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "invalid OSV URL") {
+		t.Fatalf("expected an invalid --osv-url error, got %v", err)
+	}
+
+	env := map[string]string{"FARO_OSV_URL": "ftp://osv.example.com"}
+	err = Run(RunOptions{Manager: "npm"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{},
+		Getenv:  func(k string) string { return env[k] },
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid OSV URL") {
+		t.Fatalf("expected an invalid FARO_OSV_URL error, got %v", err)
+	}
+}
+
+func TestRun_VulnSourceGHSARequiresToken(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "npm", VulnSource: "ghsa"}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{},
+		Getenv:  func(string) string { return "" },
+	})
+	if err == nil || !strings.Contains(err.Error(), "GITHUB_TOKEN") {
+		t.Fatalf("expected a missing GITHUB_TOKEN error, got %v", err)
+	}
+
+	err = Run(RunOptions{Manager: "npm", VulnSource: "nvd"}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "unsupported --vuln-source") {
+		t.Fatalf("expected an unsupported --vuln-source error, got %v", err)
+	}
+}
+
+func TestRun_GoEnv(t *testing.T) {
+	var out bytes.Buffer
+	sc := &mockScanner{}
+	err := Run(RunOptions{Manager: "go", GoEnv: []string{"GOPROXY=https://goproxy.example.com"}}, Deps{Out: &out, Scanner: sc})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(sc.lastOpts.Env) != 1 || sc.lastOpts.Env[0] != "GOPROXY=https://goproxy.example.com" {
+		t.Errorf("expected --go-env to reach the scanner, got %v", sc.lastOpts.Env)
+	}
+
+	err = Run(RunOptions{Manager: "go", GoEnv: []string{"GOPROXY"}}, Deps{Out: &out, Scanner: sc})
+	if err == nil || !strings.Contains(err.Error(), "invalid --go-env") {
+		t.Fatalf("expected an invalid --go-env error, got %v", err)
+	}
+}
+
+func TestRun_Registry(t *testing.T) {
+	var out bytes.Buffer
+	sc := &mockScanner{}
+	err := Run(RunOptions{Manager: "npm", Registry: "https://npm.example.com/"}, Deps{Out: &out, Scanner: sc})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !slices.Equal(sc.lastOpts.Env, []string{"npm_config_registry=https://npm.example.com/"}) {
+		t.Errorf("expected npm_config_registry for npm, got %v", sc.lastOpts.Env)
+	}
+	if sc.lastOpts.Registry != "https://npm.example.com/" {
+		t.Errorf("expected the registry to reach the scanner's lookups, got %q", sc.lastOpts.Registry)
+	}
+
+	// An explicit --go-env GOPROXY is appended last so it wins
+	err = Run(RunOptions{Manager: "go", Registry: "https://proxy.example.com", GoEnv: []string{"GOPROXY=direct"}}, Deps{Out: &out, Scanner: sc})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !slices.Equal(sc.lastOpts.Env, []string{"GOPROXY=https://proxy.example.com", "GOPROXY=direct"}) {
+		t.Errorf("expected GOPROXY for go, got %v", sc.lastOpts.Env)
+	}
+
+	for _, registry := range []string{"npm.example.com", "ftp://npm.example.com", "https://"} {
+		err = Run(RunOptions{Manager: "npm", Registry: registry}, Deps{Out: &out, Scanner: sc})
+		if err == nil || !strings.Contains(err.Error(), "invalid --registry") {
+			t.Errorf("expected an invalid --registry error for %q, got %v", registry, err)
+		}
+	}
+
+	err = Run(RunOptions{Manager: "pip", Registry: "https://pypi.example.com"}, Deps{Out: &out, Scanner: sc})
+	if err == nil || !strings.Contains(err.Error(), "--registry is only supported") {
+		t.Errorf("expected --registry to be rejected for pip, got %v", err)
+	}
+}
+
+func TestResolveOSVURL(t *testing.T) {
+	env := map[string]string{"FARO_OSV_URL": "https://env.example.com/"}
+	getenv := func(k string) string { return env[k] }
+
+	got, err := resolveOSVURL("https://flag.example.com", getenv)
+	if err != nil || got != "https://flag.example.com" {
+		t.Errorf("flag should win over the env var, got %q, %v", got, err)
+	}
+	got, err = resolveOSVURL("", getenv)
+	if err != nil || got != "https://env.example.com" {
+		t.Errorf("expected the env var to be used, got %q, %v", got, err)
+	}
+	got, err = resolveOSVURL("", func(string) string { return "" })
+	if err != nil || got != "" {
+		t.Errorf("expected the public API by default, got %q, %v", got, err)
+	}
+}
+
+func TestResolveCacheTTL(t *testing.T) {
+	env := map[string]string{"FARO_CACHE_TTL": "6h"}
+	getenv := func(k string) string { return env[k] }
+
+	got, err := resolveCacheTTL(time.Hour, getenv)
+	if err != nil || got != time.Hour {
+		t.Errorf("flag should win over the env var, got %v, %v", got, err)
+	}
+	got, err = resolveCacheTTL(0, getenv)
+	if err != nil || got != 6*time.Hour {
+		t.Errorf("expected the env var to be used, got %v, %v", got, err)
+	}
+	got, err = resolveCacheTTL(0, func(string) string { return "" })
+	if err != nil || got != 0 {
+		t.Errorf("expected the default TTL, got %v, %v", got, err)
+	}
+	if _, err := resolveCacheTTL(0, func(string) string { return "a day" }); err == nil || !strings.Contains(err.Error(), "FARO_CACHE_TTL") {
+		t.Errorf("expected an invalid env var to be rejected, got %v", err)
+	}
+	if _, err := resolveCacheTTL(-time.Hour, getenv); err == nil {
+		t.Error("expected a negative --cache-ttl to be rejected")
+	}
+}
+
+func TestDepCategory(t *testing.T) {
+	tests := []struct {
+		m    scanner.Module
+		want string
+	}{
+		{scanner.Module{Direct: true, DependencyType: "direct"}, "direct"},
+		{scanner.Module{DependencyType: "indirect", FromGoMod: true, Indirect: true}, "indirect"},
+		{scanner.Module{FromGoMod: true}, "direct"},
+		{scanner.Module{Direct: true, DependencyType: "dependencies"}, "direct"},
+		{scanner.Module{Direct: true, DependencyType: "devDependencies"}, "dev"},
+		{scanner.Module{Direct: true, DependencyType: "peerDependencies"}, "peer"},
+		{scanner.Module{Direct: true, DependencyType: "optionalDependencies"}, "optional"},
+		{scanner.Module{DependencyType: "transitive"}, "transitive"},
+		{scanner.Module{Direct: true, DependencyType: "main"}, "direct"},
+		{scanner.Module{Direct: true, DependencyType: "dev"}, "dev"},
+		{scanner.Module{Direct: true, DependencyType: "docs"}, "dev"},
+		{scanner.Module{Direct: true, DependencyType: "optional"}, "optional"},
+	}
+	for _, tt := range tests {
+		if got := depCategory(tt.m); got != tt.want {
+			t.Errorf("depCategory(%+v) = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestRun_PathScansAnotherDirectory(t *testing.T) {
+	project := t.TempDir()
+	for _, name := range []string{"package.json", "package-lock.json"} {
+		if err := os.WriteFile(filepath.Join(project, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	// Nothing is detectable in the current directory
+	t.Chdir(t.TempDir())
+
+	var out bytes.Buffer
+	s := &mockScanner{}
+	if err := Run(RunOptions{Path: project}, Deps{Out: &out, Scanner: s}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !strings.Contains(out.String(), "Using package manager: npm") {
+		t.Fatalf("expected npm to be detected in --path, got %q", out.String())
+	}
+	if s.lastOpts.WorkDir != project {
+		t.Errorf("expected scanner WorkDir %q, got %q", project, s.lastOpts.WorkDir)
+	}
+}
+
+func TestRun_RecursiveScansEachProject(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"go.mod", "frontend/package.json", "frontend/package-lock.json", "frontend/node_modules/x/package-lock.json"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	mods := []scanner.Module{{Name: "a", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.1.0"}, Direct: true}}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Path: root, Recursive: true, MaxDepth: 3, FormatFlag: "json"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	var report format.Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("expected a single JSON report, got %q: %v", out.String(), err)
+	}
+	if len(report.Projects) != 2 ||
+		report.Projects[0].Dir != "." || report.Projects[0].Manager != "go" ||
+		report.Projects[1].Dir != "frontend" || report.Projects[1].Manager != "npm" {
+		t.Fatalf("unexpected projects: %+v", report.Projects)
+	}
+
+	out.Reset()
+	s := &mockScanner{modules: mods}
+	err = Run(RunOptions{Path: root, Recursive: true, MaxDepth: 3, Manager: "npm"}, Deps{Out: &out, Scanner: s})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if text := out.String(); !strings.Contains(text, "==> frontend\nUsing package manager: npm") || strings.Contains(text, "==> .") {
+		t.Errorf("expected only the npm project, got %q", text)
+	}
+	if s.lastOpts.WorkDir != filepath.Join(root, "frontend") {
+		t.Errorf("expected scanner WorkDir in frontend, got %q", s.lastOpts.WorkDir)
+	}
+
+	err = Run(RunOptions{Path: root, Recursive: true, Interactive: true}, Deps{Out: &out, Scanner: s})
+	if err == nil || !strings.Contains(err.Error(), "--recursive cannot be combined") {
+		t.Errorf("expected an interactive error, got %v", err)
+	}
+}
+
+func TestRun_ManagerAllScansEachManager(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"go.mod", "package.json", "package-lock.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	mods := []scanner.Module{{Name: "a", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.1.0"}, Direct: true}}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Path: dir, Manager: "all"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	text := out.String()
+	goAt := strings.Index(text, "==> go\nUsing package manager: go")
+	npmAt := strings.Index(text, "==> npm\nUsing package manager: npm")
+	if goAt < 0 || npmAt < goAt {
+		t.Fatalf("expected a go section followed by an npm section, got:\n%s", text)
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Path: dir, Manager: "all", FormatFlag: "json"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	var report format.Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("expected a single JSON report, got %q: %v", out.String(), err)
+	}
+	if len(report.Projects) != 2 || report.Projects[0].Manager != "go" || report.Projects[1].Manager != "npm" {
+		t.Fatalf("unexpected projects: %+v", report.Projects)
+	}
+
+	// auto keeps the single preferred manager
+	out.Reset()
+	err = Run(RunOptions{Path: dir, Manager: "auto"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if text := out.String(); !strings.Contains(text, "Using package manager: go") || strings.Contains(text, "npm") {
+		t.Fatalf("expected only the go scan with auto, got:\n%s", text)
+	}
+
+	err = Run(RunOptions{Path: dir, Manager: "all", Interactive: true}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "--manager all cannot be combined") {
+		t.Errorf("expected an interactive error, got %v", err)
+	}
+}
+
+// slowScanner returns its modules after delay, or the context's error if
+// the scan is cancelled first.
+type slowScanner struct {
+	delay   time.Duration
+	modules []scanner.Module
+}
+
+func (s *slowScanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	select {
+	case <-time.After(s.delay):
+		return s.modules, nil
+	case <-opts.Ctx().Done():
+		return nil, opts.Ctx().Err()
+	}
+}
+
+func (s *slowScanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
+	return nil, nil
+}
+
+func TestRun_ManagerAllScansConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"go.mod", "package.json", "package-lock.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	goMods := []scanner.Module{{Name: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, Direct: true}}
+	npmMods := []scanner.Module{{Name: "b", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "2.0.0"}, Direct: true}}
+
+	var out bytes.Buffer
+	start := time.Now()
+	err := Run(RunOptions{Path: dir, Manager: "all", FormatFlag: "lines"}, Deps{Out: &out, Scanners: map[detector.PackageManager]scanner.Scanner{
+		detector.Go:  &slowScanner{delay: 300 * time.Millisecond, modules: goMods},
+		detector.Npm: &slowScanner{delay: 300 * time.Millisecond, modules: npmMods},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 550*time.Millisecond {
+		t.Errorf("expected the scans to overlap, took %v", elapsed)
+	}
+	if got, want := out.String(), "go:a@v1.1.0\nnpm:b@2.0.0\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	// A scan outliving the timeout fails without hiding the other results
+	out.Reset()
+	start = time.Now()
+	err = Run(RunOptions{Path: dir, Manager: "all", FormatFlag: "lines", Timeout: 100 * time.Millisecond}, Deps{Out: &out, Scanners: map[detector.PackageManager]scanner.Scanner{
+		detector.Go:  &slowScanner{delay: 10 * time.Second, modules: goMods},
+		detector.Npm: &slowScanner{modules: npmMods},
+	}})
+	if err == nil || !strings.Contains(err.Error(), "go: context deadline exceeded") {
+		t.Errorf("expected the go scan to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 2*time.Second {
+		t.Errorf("expected the timeout to bound the run, took %v", elapsed)
+	}
+	if got := out.String(); got != "npm:b@2.0.0\n" {
+		t.Errorf("expected the npm results despite the go failure, got %q", got)
+	}
+}
+
+func TestRun_NoSpinnerWithoutTerminal(t *testing.T) {
+	mods := []scanner.Module{{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true}}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go"}, Deps{Out: &out, Scanner: &slowScanner{delay: 250 * time.Millisecond, modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Checking for updates...\n") {
+		t.Errorf("expected the plain banner, got %q", got)
+	}
+	if strings.ContainsAny(got, "\r"+strings.Join(style.SpinnerFrames, "")) {
+		t.Errorf("expected no spinner output when not on a terminal, got %q", got)
+	}
+}
+
+func TestRun_LinesPrefixesManagerWhenMultiScanning(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"go.mod", "package.json", "package-lock.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	mods := []scanner.Module{{Name: "express", Version: "4.17.0", Update: &scanner.UpdateInfo{Version: "4.18.2"}, Direct: true}}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Path: dir, FormatFlag: "lines"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got, want := out.String(), "express@4.18.2\n"; got != want {
+		t.Fatalf("expected bare lines for a single manager, got %q want %q", got, want)
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Path: dir, Manager: "all", FormatFlag: "lines"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got, want := out.String(), "go:express@4.18.2\nnpm:express@4.18.2\n"; got != want {
+		t.Fatalf("expected manager-prefixed lines, got %q want %q", got, want)
+	}
+}
+
+func TestRun_PathValidation(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(file, []byte("module x\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Path: filepath.Join(dir, "missing")}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "failed to access path") {
+		t.Errorf("expected an error for a missing path, got %v", err)
+	}
+
+	err = Run(RunOptions{Path: file}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "is not a directory") {
+		t.Errorf("expected an error for a file path, got %v", err)
+	}
+}
+
+func TestRun_InRange_UsesInRangeUpdater(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "express", Version: "4.18.0", Direct: true, DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "4.18.2", Wanted: "4.18.2"}},
+	}
+
+	var out bytes.Buffer
+	sc := &mockScanner{modules: mods}
+	up := &mockInRangeUpdater{}
+	err := Run(RunOptions{Manager: "npm", Upgrade: true, Yes: true, InRange: true}, Deps{
+		Out:     &out,
+		Scanner: sc,
+		Updater: up,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !sc.lastOpts.InRange {
+		t.Error("expected InRange to reach the scanner")
+	}
+	if up.called {
+		t.Error("expected UpdatePackages not to be called with --in-range")
+	}
+	if len(up.inRangeModules) != 1 || up.inRangeModules[0].Name != "express" {
+		t.Errorf("expected express to be updated in range, got %+v", up.inRangeModules)
+	}
+}
+
+func TestRun_InRange_UnsupportedManager(t *testing.T) {
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", InRange: true}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "--in-range") {
+		t.Fatalf("expected an --in-range error for go, got %v", err)
+	}
+}
+
+func TestRun_Patch(t *testing.T) {
+	versions := []string{"v1.2.0", "v1.2.1", "v1.2.3", "v1.3.0", "v2.0.0"}
+	mods := []scanner.Module{
+		{Name: "a", Version: "v1.2.0", Direct: true, Update: &scanner.UpdateInfo{Version: "v2.0.0", Versions: versions}},
+		{Name: "b", Version: "v1.3.0", Direct: true, Update: &scanner.UpdateInfo{Version: "v2.0.0", Versions: versions}},
+	}
+
+	var out bytes.Buffer
+	sc := &mockScanner{modules: mods}
+	up := &mockPatchUpdater{}
+	err := Run(RunOptions{Manager: "go", Upgrade: true, Yes: true, NoColor: true, Patch: true}, Deps{
+		Out:     &out,
+		Scanner: sc,
+		Updater: up,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !sc.lastOpts.ListVersions {
+		t.Error("expected --patch to ask the scanner for published versions")
+	}
+	if up.called {
+		t.Error("expected UpdatePackages not to be called with --patch")
+	}
+	// b has no newer patch release of v1.3, so only a is upgraded
+	if len(up.patchModules) != 1 || up.patchModules[0].Name != "a" || up.patchModules[0].Update.Version != "v1.2.3" {
+		t.Errorf("expected a to be patched to v1.2.3, got %+v", up.patchModules)
+	}
+	if !strings.Contains(out.String(), "v1.2.3") || strings.Contains(out.String(), "v2.0.0") {
+		t.Errorf("expected only the patch target to be listed, got %q", out.String())
+	}
+
+	err = Run(RunOptions{Manager: "npm", Patch: true}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "--patch") {
+		t.Errorf("expected a --patch error for npm, got %v", err)
+	}
+	err = Run(RunOptions{Manager: "go", Upgrade: true, Yes: true, Patch: true}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+		Updater: &mockUpdater{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "--patch is not supported") {
+		t.Errorf("expected an error for an updater without patch support, got %v", err)
+	}
+}
+
+func TestRun_ShowsWantedVersion(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "express", Version: "4.18.0", Direct: true, DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "5.0.0", Wanted: "4.18.2"}},
+		{Name: "lodash", Version: "4.17.20", Direct: true, DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "4.17.21", Wanted: "4.17.21"}},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "npm", NoColor: true}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "(wanted 4.18.2)") {
+		t.Errorf("expected the wanted version for express, got %q", got)
+	}
+	// lodash's wanted version is its latest, so there is nothing to add
+	if strings.Count(got, "(wanted") != 1 {
+		t.Errorf("expected a single wanted hint, got %q", got)
+	}
+}
+
+func TestRun_ShowsRangeBlocked(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "express", Version: "4.21.0", Direct: true, DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "5.0.0", Wanted: "4.21.0"}, RangeBlocked: true},
+		{Name: "lodash", Version: "4.17.20", Direct: true, DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "4.17.21", Wanted: "4.17.21"}},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "npm", NoColor: true}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	got := out.String()
+	if strings.Count(got, "(range-blocked)") != 1 || !strings.Contains(got, "5.0.0 (range-blocked)") {
+		t.Errorf("expected only express to be marked range-blocked, got %q", got)
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Manager: "npm", FormatFlag: "json"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if strings.Count(out.String(), `"rangeBlocked": true`) != 1 {
+		t.Errorf("expected rangeBlocked in the JSON output, got %q", out.String())
+	}
+}
+
+func TestRun_ShowsDeprecatedUpdate(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "request", Version: "2.88.0", Direct: true, DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "2.88.2", Deprecated: "request has been\ndeprecated"}},
+		{Name: "express", Version: "4.18.0", Direct: true, DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "5.0.0"}},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "npm", NoColor: true}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "⚠ deprecated: request has been deprecated") {
+		t.Errorf("expected a deprecation marker for request, got %q", got)
+	}
+	if strings.Count(got, "⚠") != 1 {
+		t.Errorf("expected a single deprecation marker, got %q", got)
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Manager: "npm", FormatFlag: "json"}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !strings.Contains(out.String(), `"deprecated": "request has been\ndeprecated"`) {
+		t.Errorf("expected the deprecation message in JSON output, got %q", out.String())
+	}
+}
+
+func TestRun_ShowsWhyTransitive(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Direct: true, DependencyType: "direct", FromGoMod: true, Update: &scanner.UpdateInfo{Version: "v1.1.0"}},
+		{Name: "t", Path: "t", Version: "v0.1.0", DependencyType: "transitive", Indirect: true, RequiredBy: []string{"a"}, Update: &scanner.UpdateInfo{Version: "v0.2.0"}},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", All: true, NoColor: true}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "(via a)") {
+		t.Errorf("expected the transitive update to name its direct dependency, got %q", got)
+	}
+	if strings.Count(got, "(via") != 1 {
+		t.Errorf("expected only the transitive update to be explained, got %q", got)
+	}
+}
+
+func TestRun_DiffVersions(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "express", Version: "4.17.0", Direct: true, DependencyType: "dependencies", Update: &scanner.UpdateInfo{
+			Version:  "5.0.0",
+			Versions: []string{"4.17.0", "4.17.1", "4.18.0", "5.0.0"},
+		}},
+	}
+
+	var out bytes.Buffer
+	sc := &mockScanner{modules: mods}
+	err := Run(RunOptions{Manager: "npm", NoColor: true, DiffVersions: true}, Deps{Out: &out, Scanner: sc})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !sc.lastOpts.ListVersions {
+		t.Error("expected --diff-versions to ask the scanner for version lists")
+	}
+	if !strings.Contains(out.String(), "(1 major, 1 minor, 1 patch behind)") {
+		t.Errorf("expected the version delta, got %q", out.String())
+	}
+}
+
+func TestRun_PublishTimesOnlyWhenShown(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "express", Version: "4.17.0", Direct: true, Update: &scanner.UpdateInfo{Version: "4.18.0"}},
+	}
+	tests := []struct {
+		name string
+		opts RunOptions
+		want bool
+	}{
+		{"default", RunOptions{}, false},
+		{"json", RunOptions{FormatFlag: "json"}, false},
+		{"time format", RunOptions{FormatFlag: "time"}, true},
+		{"sort by age", RunOptions{Sort: "age"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := &mockScanner{modules: mods}
+			opts := tt.opts
+			opts.Manager, opts.NoColor = "npm", true
+			if err := Run(opts, Deps{Out: &bytes.Buffer{}, Scanner: sc}); err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+			if sc.lastOpts.PublishTimes != tt.want {
+				t.Errorf("PublishTimes = %v, want %v", sc.lastOpts.PublishTimes, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_DeprecationsOnlyWhenShown(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "request", Version: "2.88.0", Direct: true, Update: &scanner.UpdateInfo{Version: "2.88.2"}},
+	}
+	tests := []struct {
+		format string
+		want   bool
+	}{
+		{"", true},
+		{"json", true},
+		{"lines", false},
+		{"csv", false},
+	}
+	for _, tt := range tests {
+		sc := &mockScanner{modules: mods}
+		if err := Run(RunOptions{Manager: "npm", NoColor: true, FormatFlag: tt.format}, Deps{Out: &bytes.Buffer{}, Scanner: sc}); err != nil {
+			t.Fatalf("Run(%q) error: %v", tt.format, err)
+		}
+		if sc.lastOpts.Deprecations != tt.want {
+			t.Errorf("format %q: Deprecations = %v, want %v", tt.format, sc.lastOpts.Deprecations, tt.want)
+		}
+	}
+}
+
+func TestRun_Quiet_OmitsBanners(t *testing.T) {
+	mods := []scanner.Module{
+		{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "go", NoColor: true, Quiet: true}, Deps{
+		Out:     &out,
+		Scanner: &mockScanner{modules: mods},
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	got := out.String()
+	for _, banner := range []string{"Using package manager", "Checking for updates...", "Run with -u"} {
+		if strings.Contains(got, banner) {
+			t.Errorf("expected %q to be omitted, got %q", banner, got)
+		}
+	}
+	if !strings.Contains(got, "a") || !strings.Contains(got, "v1.1.0") {
+		t.Errorf("expected the update line, got %q", got)
+	}
+}
+
+func TestRun_VerboseLogsCommands(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/verbose\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	var previous bytes.Buffer
+	scanner.SetCommandLog(&previous)
+	defer scanner.SetCommandLog(nil)
+
+	var out, stderr bytes.Buffer
+	err := Run(RunOptions{Manager: "go", Path: dir, Verbose: true, NoColor: true}, Deps{
+		Out:    &out,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	want := "$ go list -m -u -e -json all (in " + dir + "): exit status 0"
+	if !strings.Contains(stderr.String(), want) {
+		t.Errorf("expected %q in the command log, got %q", want, stderr.String())
+	}
+	if strings.Contains(out.String(), "$ go") {
+		t.Errorf("expected the command log to stay off stdout, got %q", out.String())
+	}
+	if scanner.CommandLog() != &previous {
+		t.Error("expected Run to restore the previous command log")
+	}
+}
+
+func TestRun_RestoresColor(t *testing.T) {
+	t.Cleanup(func() { style.SetColorEnabled(true) })
+	style.SetColorEnabled(true)
+
+	if err := Run(RunOptions{Manager: "go", NoColor: true}, Deps{Out: &bytes.Buffer{}, Scanner: &mockScanner{}}); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !style.Colored() {
+		t.Error("expected Run to restore color output on return")
+	}
+}
+
+func TestRun_LogLevel(t *testing.T) {
+	var out, stderr bytes.Buffer
+	err := Run(RunOptions{Manager: "go", LogLevel: "info"}, Deps{
+		Out:     &out,
+		Stderr:  &stderr,
+		Scanner: &mockScanner{},
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "info: scanning ") || strings.Contains(out.String(), "info:") {
+		t.Errorf("expected info logs on stderr only, got stderr %q, stdout %q", stderr.String(), out.String())
+	}
+
+	err = Run(RunOptions{Manager: "go", LogLevel: "trace"}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err == nil || !strings.Contains(err.Error(), "--log-level") {
+		t.Errorf("expected an unsupported --log-level error, got %v", err)
+	}
+}
+
+func TestRun_Count(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "react", Version: "18.0.0", Update: &scanner.UpdateInfo{Version: "18.2.0"}, Direct: true, DependencyType: "dependencies"},
+		{Name: "jest", Version: "29.0.0", Update: &scanner.UpdateInfo{Version: "29.7.0"}, Direct: true, DependencyType: "devDependencies"},
+		{Name: "eslint", Version: "8.0.0", Update: &scanner.UpdateInfo{Version: "8.57.0"}, Direct: true, DependencyType: "devDependencies"},
+	}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Manager: "npm", Count: true, DepTypes: []string{"dev"}}, Deps{Out: &out, Scanner: &mockScanner{modules: mods}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	// Only the two devDependencies survive --dep-type dev
+	if got := out.String(); got != "2\n" {
+		t.Errorf("expected only the filtered count, got %q", got)
+	}
+
+	out.Reset()
+	err = Run(RunOptions{Manager: "npm", Count: true}, Deps{Out: &out, Scanner: &mockScanner{}})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if got := out.String(); got != "0\n" {
+		t.Errorf("expected 0 without updates, got %q", got)
+	}
+
+	if err := Run(RunOptions{Manager: "npm", Count: true, FormatFlag: "json"}, Deps{Out: &out, Scanner: &mockScanner{}}); err == nil {
+		t.Error("expected --count to reject --format json")
+	}
+}
+
+func TestRun_ScanCache(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mods := []scanner.Module{
+		{Name: "github.com/pkg/errors", Path: "github.com/pkg/errors", Version: "v0.8.0", Update: &scanner.UpdateInfo{Version: "v0.9.1"}, FromGoMod: true},
+	}
+	cache := scancache.New(t.TempDir(), time.Hour, nil)
+	run := func(opts RunOptions, getenv func(string) string) (*mockScanner, string) {
+		t.Helper()
+		s := &mockScanner{modules: mods}
+		var out bytes.Buffer
+		opts.Manager, opts.Path, opts.NoColor = "go", dir, true
+		if err := Run(opts, Deps{Out: &out, Scanner: s, ScanCache: cache, Getenv: getenv}); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		return s, out.String()
+	}
+	noEnv := func(string) string { return "" }
+
+	if s, _ := run(RunOptions{ScanCache: true}, noEnv); s.lastOpts.WorkDir == "" {
+		t.Fatal("expected the first run to scan")
+	}
+	s, out := run(RunOptions{ScanCache: true}, noEnv)
+	if s.lastOpts.WorkDir != "" {
+		t.Error("expected a cache hit to skip the scanner")
+	}
+	if !strings.Contains(out, "v0.9.1") {
+		t.Errorf("expected the cached update to be listed, got %q", out)
+	}
+
+	// FARO_SCAN_CACHE enables the cache too
+	env := func(k string) string {
+		if k == "FARO_SCAN_CACHE" {
+			return "1"
+		}
+		return ""
+	}
+	if s, _ := run(RunOptions{}, env); s.lastOpts.WorkDir != "" {
+		t.Error("expected FARO_SCAN_CACHE to use the cache")
+	}
+
+	// --no-scan-cache and a disabled cache always scan
+	if s, _ := run(RunOptions{ScanCache: true, NoScanCache: true}, env); s.lastOpts.WorkDir == "" {
+		t.Error("expected --no-scan-cache to bypass the cache")
+	}
+	if s, _ := run(RunOptions{}, noEnv); s.lastOpts.WorkDir == "" {
+		t.Error("expected the cache to be opt-in")
+	}
+
+	// Changing the manifest invalidates the entry
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := run(RunOptions{ScanCache: true}, noEnv); s.lastOpts.WorkDir == "" {
+		t.Error("expected a changed go.mod to scan again")
+	}
+}
+
+func TestRun_Manifest(t *testing.T) {
+	// The directory has no go.mod of its own, so --manifest also picks the manager
+	dir := t.TempDir()
+	mods := []scanner.Module{
+		{Name: "a", Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, FromGoMod: true},
+	}
+	s := &mockScanner{modules: mods}
+
+	var out bytes.Buffer
+	err := Run(RunOptions{Path: dir, Manifest: "services/api/go.mod", NoColor: true}, Deps{Out: &out, Scanner: s})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if s.lastOpts.Manifest != "services/api/go.mod" {
+		t.Errorf("expected the manifest to reach the scanner, got %q", s.lastOpts.Manifest)
+	}
+	if !strings.Contains(out.String(), "v1.1.0") {
+		t.Errorf("expected the update to be listed, got %q", out.String())
+	}
+
+	for _, opts := range []RunOptions{
+		{Path: dir, Manifest: "go.mod", Manager: "npm"},
+		{Path: dir, Manifest: "go.mod", Upgrade: true, Yes: true},
+		{Path: dir, Manifest: "go.mod", Recursive: true},
+	} {
+		if err := Run(opts, Deps{Out: &out, Scanner: &mockScanner{modules: mods}}); err == nil || !strings.Contains(err.Error(), "--manifest") {
+			t.Errorf("expected %+v to fail, got %v", opts, err)
+		}
+	}
+}
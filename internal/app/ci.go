@@ -0,0 +1,193 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/ci"
+	"github.com/pragmaticivan/faro/internal/detector"
+	"github.com/pragmaticivan/faro/internal/format"
+	"github.com/pragmaticivan/faro/internal/log"
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/style"
+)
+
+// CIOptions configures a one-shot `faro ci` run.
+type CIOptions struct {
+	Manager        string
+	Filter         string
+	Dev            bool // Include dev/test dependencies hidden by default
+	Transitive     bool // Include dependencies the project doesn't declare
+	All            bool // Deprecated: sets Dev and Transitive
+	NoDev          bool // Exclude dev/test dependencies, overriding All
+	Cooldown       int
+	Provider       string        // Optional: overrides CI detection (local, github, gitlab)
+	ReportPath     string        // Path of the GitLab Code Quality report
+	FailOnOutdated bool          // Fail when any update is available, not just vulnerable ones
+	NoCache        bool          // Skip the on-disk OSV response cache
+	CacheTTL       time.Duration // How long cached OSV responses stay fresh; zero uses $FARO_CACHE_TTL or 24h
+	OSVURL         string        // OSV API endpoint; empty uses $FARO_OSV_URL or the public API
+	VulnSource     string        // Advisory database: osv (default) or ghsa, which needs $GITHUB_TOKEN
+	GoEnv          []string      // Extra KEY=VALUE environment for go commands, e.g. GOPROXY
+	Registry       string        // Registry for version resolution: GOPROXY for Go, npm_config_registry for npm, yarn and pnpm
+	NoColor        bool          // Disable colored output even on a terminal
+	Path           string        // Project directory to scan; empty uses the current directory
+	Timeout        time.Duration // Limit for the scanner's package manager commands; zero means none
+}
+
+// DefaultGitLabReportPath is where the GitLab Code Quality report is written by default.
+const DefaultGitLabReportPath = "gl-code-quality-report.json"
+
+// runOptions returns the RunOptions equivalent to opts, so RunCI resolves,
+// scans and checks projects the way Run does.
+func (opts CIOptions) runOptions() RunOptions {
+	return RunOptions{
+		Manager:    opts.Manager,
+		Filter:     opts.Filter,
+		Dev:        (opts.Dev || opts.All) && !opts.NoDev,
+		Transitive: opts.Transitive || opts.All,
+		NoDev:      opts.NoDev,
+		Cooldown:   opts.Cooldown,
+		NoCache:    opts.NoCache,
+		CacheTTL:   opts.CacheTTL,
+		OSVURL:     opts.OSVURL,
+		VulnSource: opts.VulnSource,
+		GoEnv:      opts.GoEnv,
+		Registry:   opts.Registry,
+		NoColor:    opts.NoColor,
+		Path:       opts.Path,
+		Timeout:    opts.Timeout,
+	}
+}
+
+// RunCI scans for updates with vulnerability checks enabled and renders the
+// results in the format native to the detected CI provider. It returns an
+// error when the exit-code policy is violated, including when the
+// vulnerability check itself fails, so callers can exit non-zero.
+func RunCI(opts CIOptions, deps Deps) error {
+	if deps.Out == nil {
+		return fmt.Errorf("missing deps.Out")
+	}
+	deps = deps.withDefaults()
+	defer style.SetColorEnabled(style.Colored())
+	style.SetColorEnabled(!opts.NoColor && style.ColorEnabled(deps.Out, deps.Getenv))
+	defer log.SetDefault(log.Default())
+	log.SetDefault(log.New(deps.Stderr, log.LevelWarn))
+
+	provider := ci.Detect(deps.Getenv)
+	if opts.Provider != "" {
+		p, err := ci.ParseProvider(opts.Provider)
+		if err != nil {
+			return err
+		}
+		provider = p
+	}
+
+	runOpts := opts.runOptions()
+	workDir, err := resolveWorkDir(runOpts.Path)
+	if err != nil {
+		return err
+	}
+	if err := resolveSources(&runOpts, deps.Getenv); err != nil {
+		return err
+	}
+
+	pm, err := resolveManager(runOpts.Manager, workDir)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(deps.Out, "Using package manager: %s (CI: %s)\n", pm, provider)
+
+	scanCtx, cancel := scanContext(runOpts.Timeout)
+	defer cancel()
+	scan := scanProject(scanCtx, runOpts, deps, workDir, pm, format.Options{})
+	if scan.err != nil {
+		return scan.err
+	}
+	modules := scan.modules
+	printWarnings(deps.Out, scan.scanner)
+
+	// Any failed check fails the run: CI can't vouch for unchecked packages
+	gate := &vulnGate{}
+	checkProjectVulnerabilities(scanCtx, runOpts, deps, pm, modules, nil, gate)
+
+	direct, indirect, transitive := groupModules(modules)
+	reported := selectForUpdate(direct, indirect, transitive, runOpts.Transitive)
+
+	if len(reported) == 0 {
+		_, _ = fmt.Fprintln(deps.Out, "All dependencies match the latest package versions :)")
+	} else {
+		maxPathLen := calculateMaxPathLen(reported, nil, nil)
+		printSimpleOutput(deps.Out, reported, maxPathLen, lineOptions{showVulns: true, manager: pm, now: deps.Now()})
+	}
+
+	configFile := detector.ConfigFileFor(pm)
+	switch provider {
+	case ci.GitHub:
+		ci.WriteGitHubAnnotations(deps.Out, reported, configFile)
+		if path := deps.Getenv("GITHUB_STEP_SUMMARY"); path != "" {
+			if err := writeGitHubSummary(path, pm, reported); err != nil {
+				return err
+			}
+		}
+	case ci.GitLab:
+		path := opts.ReportPath
+		if path == "" {
+			path = DefaultGitLabReportPath
+		}
+		// Relative to the scanned project, like the manifests it points at
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workDir, path)
+		}
+		if err := writeGitLabReport(path, reported, configFile); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(deps.Out, "Wrote code quality report to %s\n", path)
+	}
+
+	if err := gate.err(); err != nil {
+		return err
+	}
+	vulnerable := 0
+	for _, m := range reported {
+		if m.VulnCurrent.Total > 0 {
+			vulnerable++
+		}
+	}
+	if vulnerable > 0 {
+		return fmt.Errorf("found %d dependencies with known vulnerabilities", vulnerable)
+	}
+	if opts.FailOnOutdated && len(reported) > 0 {
+		return fmt.Errorf("found %d outdated dependencies", len(reported))
+	}
+	return nil
+}
+
+// writeGitHubSummary appends the Markdown summary to the step summary file.
+func writeGitHubSummary(path string, pm detector.PackageManager, modules []scanner.Module) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open step summary: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	ci.WriteGitHubSummary(f, pm.String(), modules)
+	return nil
+}
+
+// writeGitLabReport writes the Code Quality report to path.
+func writeGitLabReport(path string, modules []scanner.Module, configFile string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create code quality report: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := ci.WriteGitLabReport(f, modules, configFile); err != nil {
+		return fmt.Errorf("failed to write code quality report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/vuln"
+)
+
+type mockVulnClient struct {
+	counts map[string]vuln.SeverityCounts
+	err    error // Returned by CheckModules, e.g. to simulate OSV being unreachable
+	ctx    context.Context
+}
+
+func (m *mockVulnClient) CheckModule(_ context.Context, modulePath, version string) (vuln.SeverityCounts, error) {
+	return m.counts[modulePath+"@"+version], nil
+}
+
+func (m *mockVulnClient) CheckModules(ctx context.Context, queries []vuln.Query) ([]vuln.SeverityCounts, error) {
+	m.ctx = ctx
+	if m.err != nil {
+		return nil, m.err
+	}
+	results := make([]vuln.SeverityCounts, len(queries))
+	for i, q := range queries {
+		results[i], _ = m.CheckModule(ctx, q.Name, q.Version)
+	}
+	return results, nil
+}
+
+func TestRunCI_GitHub_AnnotatesAndFailsOnVulnerable(t *testing.T) {
+	var out bytes.Buffer
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	env := map[string]string{"GITHUB_ACTIONS": "true", "GITHUB_STEP_SUMMARY": summary}
+	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true}}
+
+	err := RunCI(CIOptions{Manager: "go"}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{counts: map[string]vuln.SeverityCounts{"a@v1.0.0": {High: 1, Total: 1}}},
+		Getenv:     func(k string) string { return env[k] },
+	})
+	if err == nil || !strings.Contains(err.Error(), "known vulnerabilities") {
+		t.Fatalf("expected vulnerability error, got: %v", err)
+	}
+	if !strings.Contains(out.String(), "::warning file=go.mod") {
+		t.Fatalf("expected annotation, got: %q", out.String())
+	}
+	data, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatalf("expected step summary: %v", err)
+	}
+	if !strings.Contains(string(data), "| `a` |") {
+		t.Fatalf("unexpected step summary: %q", data)
+	}
+}
+
+func TestRunCI_FailOnOutdated(t *testing.T) {
+	var out bytes.Buffer
+	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true}}
+	deps := Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{},
+		Getenv:     func(string) string { return "" },
+	}
+
+	if err := RunCI(CIOptions{Manager: "go"}, deps); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if err := RunCI(CIOptions{Manager: "go", FailOnOutdated: true}, deps); err == nil {
+		t.Fatalf("expected error with --fail-on-outdated")
+	}
+}
+
+func TestRunCI_GitLab_WritesReport(t *testing.T) {
+	var out bytes.Buffer
+	report := filepath.Join(t.TempDir(), "report.json")
+	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true}}
+
+	err := RunCI(CIOptions{Manager: "go", Provider: "gitlab", ReportPath: report}, Deps{
+		Out:        &out,
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{},
+		Getenv:     func(string) string { return "" },
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatalf("expected report: %v", err)
+	}
+	if !strings.Contains(string(data), "faro/outdated-dependency") {
+		t.Fatalf("unexpected report: %q", data)
+	}
+}
+
+func TestRunCI_GitLab_ReportRelativeToPath(t *testing.T) {
+	dir := t.TempDir()
+	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true}}
+
+	err := RunCI(CIOptions{Manager: "go", Provider: "gitlab", Path: dir}, Deps{
+		Out:        &bytes.Buffer{},
+		Scanner:    &mockScanner{modules: mods},
+		VulnClient: &mockVulnClient{},
+		Getenv:     func(string) string { return "" },
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, DefaultGitLabReportPath)); err != nil {
+		t.Fatalf("expected the report in the --path directory: %v", err)
+	}
+}
+
+func TestRunCI_VulnCheck(t *testing.T) {
+	mods := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, FromGoMod: true}}
+	deps := func(vc *mockVulnClient, stderr *bytes.Buffer) Deps {
+		return Deps{
+			Out:        &bytes.Buffer{},
+			Stderr:     stderr,
+			Scanner:    &mockScanner{modules: mods},
+			VulnClient: vc,
+			Getenv:     func(string) string { return "" },
+		}
+	}
+
+	// The lookups run under the scan's deadline
+	vc := &mockVulnClient{}
+	if err := RunCI(CIOptions{Manager: "go", Timeout: time.Minute}, deps(vc, &bytes.Buffer{})); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if vc.ctx == nil {
+		t.Fatal("expected the vulnerability client to be called")
+	}
+	if _, ok := vc.ctx.Deadline(); !ok {
+		t.Error("expected the vulnerability check to be bounded by --timeout")
+	}
+
+	// A failed check fails the run
+	var stderr bytes.Buffer
+	err := RunCI(CIOptions{Manager: "go"}, deps(&mockVulnClient{err: errors.New("osv unreachable")}, &stderr))
+	if err == nil || !strings.Contains(err.Error(), "vulnerability check failed") {
+		t.Errorf("expected a failed check to fail CI, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "osv unreachable") {
+		t.Errorf("expected a warning on stderr, got %q", stderr.String())
+	}
+
+	// Managers without vulnerability data skip the check with a warning
+	stderr.Reset()
+	vc = &mockVulnClient{}
+	if err := RunCI(CIOptions{Manager: "conda"}, deps(vc, &stderr)); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if vc.ctx != nil {
+		t.Error("expected no vulnerability lookups for conda")
+	}
+	if !strings.Contains(stderr.String(), "vulnerability data is not available") {
+		t.Errorf("expected a skipped-check warning, got %q", stderr.String())
+	}
+}
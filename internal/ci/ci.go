@@ -0,0 +1,171 @@
+// Package ci provides CI system detection and CI-native report rendering.
+package ci
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// Provider identifies the CI system faro is running under.
+type Provider string
+
+const (
+	Local  Provider = "local"
+	GitHub Provider = "github"
+	GitLab Provider = "gitlab"
+)
+
+// Detect determines the CI provider from environment variables.
+func Detect(getenv func(string) string) Provider {
+	if getenv("GITHUB_ACTIONS") == "true" {
+		return GitHub
+	}
+	if getenv("GITLAB_CI") == "true" {
+		return GitLab
+	}
+	return Local
+}
+
+// ParseProvider validates an explicit provider name.
+func ParseProvider(s string) (Provider, error) {
+	p := Provider(strings.ToLower(strings.TrimSpace(s)))
+	switch p {
+	case Local, GitHub, GitLab:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unsupported CI provider: %s (supported: local, github, gitlab)", s)
+	}
+}
+
+// moduleName returns the module name, falling back to the legacy Path field.
+func moduleName(m scanner.Module) string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.Path
+}
+
+// updateVersion returns the update version or an empty string.
+func updateVersion(m scanner.Module) string {
+	if m.Update == nil {
+		return ""
+	}
+	return m.Update.Version
+}
+
+// WriteGitHubAnnotations emits `::warning::` workflow commands for every module
+// whose current version has known vulnerabilities. file is the manifest the
+// annotations are attached to (e.g. go.mod).
+func WriteGitHubAnnotations(out io.Writer, modules []scanner.Module, file string) {
+	for _, m := range modules {
+		if m.VulnCurrent.Total == 0 {
+			continue
+		}
+		msg := fmt.Sprintf("%s %s has %d known vulnerabilities; update to %s",
+			moduleName(m), m.Version, m.VulnCurrent.Total, updateVersion(m))
+		if m.VulnUpdate.Total > 0 {
+			msg += fmt.Sprintf(" (%d remain)", m.VulnUpdate.Total)
+		}
+		_, _ = fmt.Fprintf(out, "::warning file=%s,title=%s::%s\n",
+			escapeProperty(file), escapeProperty("Vulnerable dependency"), escapeData(msg))
+	}
+}
+
+// WriteGitHubSummary renders a Markdown table suitable for $GITHUB_STEP_SUMMARY.
+func WriteGitHubSummary(w io.Writer, manager string, modules []scanner.Module) {
+	_, _ = fmt.Fprintf(w, "## faro: %d dependency updates (%s)\n\n", len(modules), manager)
+	if len(modules) == 0 {
+		_, _ = fmt.Fprintln(w, "All dependencies match the latest package versions.")
+		return
+	}
+	_, _ = fmt.Fprintln(w, "| Package | Current | Latest | Type | Vulnerabilities |")
+	_, _ = fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+	for _, m := range modules {
+		vulns := ""
+		if m.VulnCurrent.Total > 0 {
+			vulns = fmt.Sprintf("%d → %d", m.VulnCurrent.Total, m.VulnUpdate.Total)
+		}
+		_, _ = fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n",
+			moduleName(m), m.Version, updateVersion(m), m.DependencyType, vulns)
+	}
+}
+
+// codeQualityIssue is a single entry of a GitLab Code Quality report.
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// WriteGitLabReport writes a GitLab Code Quality report (JSON array) covering
+// every outdated module. Vulnerable modules are reported with a severity that
+// reflects their worst known advisory.
+func WriteGitLabReport(w io.Writer, modules []scanner.Module, file string) error {
+	issues := make([]codeQualityIssue, 0, len(modules))
+	for _, m := range modules {
+		name := moduleName(m)
+		issue := codeQualityIssue{
+			Description: fmt.Sprintf("%s %s can be updated to %s", name, m.Version, updateVersion(m)),
+			CheckName:   "faro/outdated-dependency",
+			Severity:    "info",
+		}
+		if m.VulnCurrent.Total > 0 {
+			issue.Description = fmt.Sprintf("%s %s has %d known vulnerabilities; update to %s",
+				name, m.Version, m.VulnCurrent.Total, updateVersion(m))
+			issue.CheckName = "faro/vulnerable-dependency"
+			issue.Severity = gitLabSeverity(m.VulnCurrent)
+		}
+		sum := sha1.Sum([]byte(issue.CheckName + ":" + name + "@" + m.Version))
+		issue.Fingerprint = hex.EncodeToString(sum[:])
+		issue.Location.Path = file
+		issue.Location.Lines.Begin = 1
+		issues = append(issues, issue)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// gitLabSeverity maps vulnerability counts to a Code Quality severity.
+func gitLabSeverity(v scanner.VulnInfo) string {
+	switch {
+	case v.Critical > 0:
+		return "critical"
+	case v.High > 0:
+		return "major"
+	case v.Medium > 0:
+		return "minor"
+	default:
+		return "info"
+	}
+}
+
+// escapeData escapes a workflow command message.
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeProperty escapes a workflow command property value.
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
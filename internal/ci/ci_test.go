@@ -0,0 +1,96 @@
+package ci
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func envFrom(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Provider
+	}{
+		{"github", map[string]string{"GITHUB_ACTIONS": "true"}, GitHub},
+		{"gitlab", map[string]string{"GITLAB_CI": "true"}, GitLab},
+		{"local", map[string]string{}, Local},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(envFrom(tt.env)); got != tt.want {
+				t.Errorf("Detect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProvider(t *testing.T) {
+	if p, err := ParseProvider("GitHub"); err != nil || p != GitHub {
+		t.Fatalf("ParseProvider(GitHub) = %v, %v", p, err)
+	}
+	if _, err := ParseProvider("jenkins"); err == nil {
+		t.Fatalf("expected error for unsupported provider")
+	}
+}
+
+func TestWriteGitHubAnnotations_OnlyVulnerable(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}, VulnCurrent: scanner.VulnInfo{High: 1, Total: 1}},
+		{Name: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}},
+	}
+	var buf bytes.Buffer
+	WriteGitHubAnnotations(&buf, mods, "go.mod")
+
+	got := buf.String()
+	if strings.Count(got, "::warning") != 1 {
+		t.Fatalf("expected exactly one annotation, got: %q", got)
+	}
+	if !strings.Contains(got, "file=go.mod") || !strings.Contains(got, "a v1.0.0 has 1 known vulnerabilities") {
+		t.Fatalf("unexpected annotation: %q", got)
+	}
+}
+
+func TestWriteGitHubSummary(t *testing.T) {
+	mods := []scanner.Module{{Name: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}, DependencyType: "direct"}}
+	var buf bytes.Buffer
+	WriteGitHubSummary(&buf, "go", mods)
+	if !strings.Contains(buf.String(), "| `a` | v1.0.0 | v2.0.0 | direct |") {
+		t.Fatalf("unexpected summary: %q", buf.String())
+	}
+}
+
+func TestWriteGitLabReport(t *testing.T) {
+	mods := []scanner.Module{
+		{Name: "a", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.0.1"}, VulnCurrent: scanner.VulnInfo{Critical: 1, Total: 1}},
+		{Name: "b", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.1.0"}},
+	}
+	var buf bytes.Buffer
+	if err := WriteGitLabReport(&buf, mods, "package.json"); err != nil {
+		t.Fatalf("WriteGitLabReport() error = %v", err)
+	}
+
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	if issues[0].Severity != "critical" || issues[0].CheckName != "faro/vulnerable-dependency" {
+		t.Errorf("unexpected vulnerable issue: %+v", issues[0])
+	}
+	if issues[1].Severity != "info" || issues[1].Location.Path != "package.json" {
+		t.Errorf("unexpected outdated issue: %+v", issues[1])
+	}
+	if issues[0].Fingerprint == issues[1].Fingerprint {
+		t.Errorf("expected distinct fingerprints")
+	}
+}
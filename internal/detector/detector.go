@@ -3,35 +3,44 @@ package detector
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 )
 
 // PackageManager represents a supported package manager.
 type PackageManager string
 
 const (
-	Go     PackageManager = "go"
-	Npm    PackageManager = "npm"
-	Yarn   PackageManager = "yarn"
-	Pnpm   PackageManager = "pnpm"
-	Pip    PackageManager = "pip"
-	Poetry PackageManager = "poetry"
-	Uv     PackageManager = "uv"
+	Go      PackageManager = "go"
+	Npm     PackageManager = "npm"
+	Yarn    PackageManager = "yarn"
+	Pnpm    PackageManager = "pnpm"
+	Pip     PackageManager = "pip"
+	Poetry  PackageManager = "poetry"
+	Uv      PackageManager = "uv"
+	Maven   PackageManager = "maven"
+	Gradle  PackageManager = "gradle"
+	Bundler PackageManager = "bundler"
+	Conda   PackageManager = "conda"
 )
 
 // DetectionResult contains information about a detected package manager.
 type DetectionResult struct {
-	Manager    PackageManager
-	ConfigFile string
-	LockFile   string
+	Manager    PackageManager `json:"manager"`
+	ConfigFile string         `json:"configFile"`
+	LockFile   string         `json:"lockFile,omitempty"`
+	Dir        string         `json:"dir,omitempty"` // Project directory relative to the root (DetectRecursive only)
 }
 
 // detector represents a package manager detection rule.
 type detector struct {
 	manager    PackageManager
 	files      []string // Files that must exist
-	configFile string   // Primary config file
+	anyOf      []string // At least one of these files must exist, if set
+	configFile string   // Primary config file; defaults to the anyOf file found
 	lockFile   string   // Lock file (if any)
 	priority   int      // Lower = higher priority
 }
@@ -86,6 +95,33 @@ var detectors = []detector{
 		lockFile:   "",
 		priority:   7,
 	},
+	{
+		manager:    Maven,
+		files:      []string{"pom.xml"},
+		configFile: "pom.xml",
+		lockFile:   "",
+		priority:   8,
+	},
+	{
+		manager:  Gradle,
+		anyOf:    []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"},
+		lockFile: "",
+		priority: 9,
+	},
+	{
+		manager:    Bundler,
+		files:      []string{"Gemfile"},
+		configFile: "Gemfile",
+		lockFile:   "Gemfile.lock",
+		priority:   10,
+	},
+	{
+		manager:    Conda,
+		files:      []string{"environment.yml"},
+		configFile: "environment.yml",
+		lockFile:   "",
+		priority:   11,
+	},
 }
 
 // Detect scans the given directory for package manager files and returns all detected managers.
@@ -96,17 +132,29 @@ func Detect(dir string) ([]DetectionResult, error) {
 	for _, d := range detectors {
 		allExist := true
 		for _, file := range d.files {
-			path := filepath.Join(dir, file)
-			if _, err := os.Stat(path); os.IsNotExist(err) {
+			if !exists(filepath.Join(dir, file)) {
 				allExist = false
 				break
 			}
 		}
+		configFile := d.configFile
+		if allExist && len(d.anyOf) > 0 {
+			allExist = false
+			for _, file := range d.anyOf {
+				if exists(filepath.Join(dir, file)) {
+					allExist = true
+					if configFile == "" {
+						configFile = file
+					}
+					break
+				}
+			}
+		}
 
 		if allExist {
 			results = append(results, DetectionResult{
 				Manager:    d.manager,
-				ConfigFile: d.configFile,
+				ConfigFile: configFile,
 				LockFile:   d.lockFile,
 			})
 		}
@@ -119,6 +167,12 @@ func Detect(dir string) ([]DetectionResult, error) {
 	return results, nil
 }
 
+// exists reports whether path exists.
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return !os.IsNotExist(err)
+}
+
 // DetectSingle detects a single package manager, preferring the highest priority match.
 // If multiple managers are detected, it returns the first one based on priority.
 func DetectSingle(dir string) (DetectionResult, error) {
@@ -129,14 +183,163 @@ func DetectSingle(dir string) (DetectionResult, error) {
 	return results[0], nil
 }
 
+// DetectAll returns every detected package manager that owns a distinct
+// config file, in priority order. Managers sharing a manifest with a
+// preferred one, such as npm next to pnpm or uv next to Poetry, are left out
+// as DetectSingle would pick the preferred one.
+func DetectAll(dir string) ([]DetectionResult, error) {
+	results, err := Detect(dir)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(results))
+	kept := results[:0]
+	for _, r := range results {
+		if seen[r.ConfigFile] {
+			continue
+		}
+		seen[r.ConfigFile] = true
+		kept = append(kept, r)
+	}
+	return kept, nil
+}
+
+// skippedDirs are never descended into by DetectRecursive.
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	".venv":        true,
+	".git":         true,
+}
+
+// DetectRecursive walks root and its subdirectories up to maxDepth levels
+// deep, skipping node_modules, vendor, .venv and .git as well as directories
+// excluded by .gitignore files, and returns the preferred package manager of
+// every directory that has one. Results are in walk order, so root comes
+// first.
+func DetectRecursive(root string, maxDepth int) ([]DetectionResult, error) {
+	var results []DetectionResult
+	var ignore gitignore
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel != "." {
+			if skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			if depth(rel) > maxDepth {
+				return filepath.SkipDir
+			}
+			if ignore.ignored(filepath.ToSlash(rel)) {
+				return filepath.SkipDir
+			}
+		}
+		ignore.load(path, rel)
+
+		result, err := DetectSingle(path)
+		if err != nil {
+			return nil // Not a project directory
+		}
+		result.Dir = filepath.ToSlash(rel)
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no supported package manager detected in %s or its subdirectories", root)
+	}
+	return results, nil
+}
+
+// depth returns the number of path elements in the relative path rel.
+func depth(rel string) int {
+	n := 1
+	for _, r := range rel {
+		if r == filepath.Separator {
+			n++
+		}
+	}
+	return n
+}
+
+// ConfigFileFor returns the primary config file for a package manager,
+// or an empty string if the manager is unknown.
+func ConfigFileFor(pm PackageManager) string {
+	for _, d := range detectors {
+		if d.manager == pm {
+			if d.configFile == "" && len(d.anyOf) > 0 {
+				return d.anyOf[0]
+			}
+			return d.configFile
+		}
+	}
+	return ""
+}
+
+// ProjectFiles returns the manifest and lock files that describe a pm
+// project, whether or not they exist; nil for custom managers.
+func ProjectFiles(pm PackageManager) []string {
+	for _, d := range detectors {
+		if d.manager != pm {
+			continue
+		}
+		var files []string
+		for _, f := range append(append(append([]string{}, d.files...), d.anyOf...), d.configFile, d.lockFile) {
+			if f != "" && !slices.Contains(files, f) {
+				files = append(files, f)
+			}
+		}
+		return files
+	}
+	return nil
+}
+
+// custom holds package managers registered at runtime with RegisterManager.
+var (
+	customMu sync.RWMutex
+	custom   = make(map[PackageManager]bool)
+)
+
+// RegisterManager makes Validate accept name, for package managers that are
+// provided outside faro. They have no detection rule, so they are only used
+// when selected explicitly.
+func RegisterManager(name PackageManager) {
+	customMu.Lock()
+	defer customMu.Unlock()
+	custom[name] = true
+}
+
+// IsCustom reports whether pm was registered with RegisterManager.
+func IsCustom(pm PackageManager) bool {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	return custom[pm]
+}
+
 // Validate checks if a given package manager name is supported.
 func Validate(manager string) (PackageManager, error) {
 	pm := PackageManager(manager)
 	switch pm {
-	case Go, Npm, Yarn, Pnpm, Pip, Poetry, Uv:
+	case Go, Npm, Yarn, Pnpm, Pip, Poetry, Uv, Maven, Gradle, Bundler, Conda:
 		return pm, nil
 	default:
-		return "", fmt.Errorf("unsupported package manager: %s (supported: go, npm, yarn, pnpm, pip, poetry, uv)", manager)
+		if pm != "" && IsCustom(pm) {
+			return pm, nil
+		}
+		return "", fmt.Errorf("unsupported package manager: %s (supported: go, npm, yarn, pnpm, pip, poetry, uv, maven, gradle, bundler, conda)", manager)
 	}
 }
 
@@ -3,6 +3,7 @@ package detector
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -53,6 +54,26 @@ func TestDetect(t *testing.T) {
 			files:        []string{"go.mod", "go.sum", "package.json", "package-lock.json"},
 			wantManagers: []PackageManager{Go, Npm},
 		},
+		{
+			name:         "maven project",
+			files:        []string{"pom.xml"},
+			wantManagers: []PackageManager{Maven},
+		},
+		{
+			name:         "gradle project",
+			files:        []string{"build.gradle.kts", "settings.gradle.kts"},
+			wantManagers: []PackageManager{Gradle},
+		},
+		{
+			name:         "bundler project",
+			files:        []string{"Gemfile", "Gemfile.lock"},
+			wantManagers: []PackageManager{Bundler},
+		},
+		{
+			name:         "conda project",
+			files:        []string{"environment.yml"},
+			wantManagers: []PackageManager{Conda},
+		},
 		{
 			name:    "no package manager",
 			files:   []string{"README.md"},
@@ -140,6 +161,150 @@ func TestDetectSingle(t *testing.T) {
 	}
 }
 
+func TestDetectAll(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        []string
+		wantManagers []PackageManager
+	}{
+		{
+			name:         "Go and npm side by side",
+			files:        []string{"go.mod", "package.json", "package-lock.json"},
+			wantManagers: []PackageManager{Go, Npm},
+		},
+		{
+			name:         "one manager per manifest",
+			files:        []string{"package.json", "yarn.lock", "package-lock.json", "pyproject.toml", "poetry.lock", "uv.lock"},
+			wantManagers: []PackageManager{Yarn, Poetry},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for _, file := range tt.files {
+				path := filepath.Join(tmpDir, file)
+				if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+					t.Fatalf("failed to create test file: %v", err)
+				}
+			}
+
+			results, err := DetectAll(tmpDir)
+			if err != nil {
+				t.Fatalf("DetectAll() error = %v", err)
+			}
+			var got []PackageManager
+			for _, r := range results {
+				got = append(got, r.Manager)
+			}
+			if !slices.Equal(got, tt.wantManagers) {
+				t.Errorf("DetectAll() = %v, want %v", got, tt.wantManagers)
+			}
+		})
+	}
+}
+
+func TestDetectRecursive(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"go.mod",
+		"frontend/package.json",
+		"frontend/package-lock.json",
+		"frontend/node_modules/left-pad/package.json",
+		"frontend/node_modules/left-pad/package-lock.json",
+		"services/api/requirements.txt",
+		"services/api/deep/nested/go.mod",
+		"vendor/example.com/lib/go.mod",
+		".git/go.mod",
+		"docs/README.md",
+	}
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", f, err)
+		}
+	}
+
+	results, err := DetectRecursive(root, 2)
+	if err != nil {
+		t.Fatalf("DetectRecursive() error = %v", err)
+	}
+	want := []DetectionResult{
+		{Manager: Go, ConfigFile: "go.mod", LockFile: "go.sum", Dir: "."},
+		{Manager: Npm, ConfigFile: "package.json", LockFile: "package-lock.json", Dir: "frontend"},
+		{Manager: Pip, ConfigFile: "requirements.txt", Dir: "services/api"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("DetectRecursive() = %+v, want %+v", results, want)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+
+	results, err = DetectRecursive(root, 4)
+	if err != nil {
+		t.Fatalf("DetectRecursive() error = %v", err)
+	}
+	if len(results) != 4 || results[3].Dir != "services/api/deep/nested" {
+		t.Errorf("expected the deeper project within maxDepth 4, got %+v", results)
+	}
+
+	results, err = DetectRecursive(root, 0)
+	if err != nil || len(results) != 1 || results[0].Dir != "." {
+		t.Errorf("expected only the root with maxDepth 0, got %+v, %v", results, err)
+	}
+
+	if _, err := DetectRecursive(filepath.Join(root, "docs"), 3); err == nil {
+		t.Error("expected an error when no project is found")
+	}
+}
+
+func TestDetectRecursive_SkipsDependencyAndIgnoredDirs(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"package.json":                       "",
+		"yarn.lock":                          "",
+		"node_modules/left-pad/package.json": "",
+		"node_modules/left-pad/yarn.lock":    "",
+		".venv/lib/requirements.txt":         "",
+		".gitignore":                         "# local checkouts\nbuild/\n/scratch\n",
+		"build/out/package.json":             "",
+		"build/out/package-lock.json":        "",
+		"scratch/go.mod":                     "",
+		"tools/scratch/go.mod":               "",
+		"services/.gitignore":                "legacy\n",
+		"services/legacy/requirements.txt":   "",
+		"services/api/requirements.txt":      "",
+	}
+	for f, contents := range files {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", f, err)
+		}
+	}
+
+	results, err := DetectRecursive(root, 3)
+	if err != nil {
+		t.Fatalf("DetectRecursive() error = %v", err)
+	}
+	var dirs []string
+	for _, r := range results {
+		dirs = append(dirs, r.Dir)
+	}
+	// /scratch is anchored to the root, so tools/scratch is still scanned
+	want := []string{".", "services/api", "tools/scratch"}
+	if !slices.Equal(dirs, want) {
+		t.Errorf("DetectRecursive() dirs = %v, want %v", dirs, want)
+	}
+}
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -154,6 +319,10 @@ func TestValidate(t *testing.T) {
 		{"valid pip", "pip", Pip, false},
 		{"valid poetry", "poetry", Poetry, false},
 		{"valid uv", "uv", Uv, false},
+		{"valid maven", "maven", Maven, false},
+		{"valid gradle", "gradle", Gradle, false},
+		{"valid bundler", "bundler", Bundler, false},
+		{"valid conda", "conda", Conda, false},
 		{"invalid manager", "invalid", "", true},
 		{"empty string", "", "", true},
 	}
@@ -171,3 +340,33 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigFileFor(t *testing.T) {
+	if got := ConfigFileFor(Go); got != "go.mod" {
+		t.Errorf("ConfigFileFor(Go) = %q, want go.mod", got)
+	}
+	if got := ConfigFileFor(Pnpm); got != "package.json" {
+		t.Errorf("ConfigFileFor(Pnpm) = %q, want package.json", got)
+	}
+	if got := ConfigFileFor("invalid"); got != "" {
+		t.Errorf("ConfigFileFor(invalid) = %q, want empty", got)
+	}
+}
+
+func TestProjectFiles(t *testing.T) {
+	tests := []struct {
+		pm   PackageManager
+		want []string
+	}{
+		{Go, []string{"go.mod", "go.sum"}},
+		{Npm, []string{"package-lock.json", "package.json"}},
+		{Poetry, []string{"poetry.lock", "pyproject.toml"}},
+		{Gradle, []string{"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts"}},
+		{"invalid", nil},
+	}
+	for _, tt := range tests {
+		if got := ProjectFiles(tt.pm); !slices.Equal(got, tt.want) {
+			t.Errorf("ProjectFiles(%s) = %v, want %v", tt.pm, got, tt.want)
+		}
+	}
+}
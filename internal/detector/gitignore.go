@@ -0,0 +1,133 @@
+package detector
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreRule is one pattern of a .gitignore file.
+type ignoreRule struct {
+	base    string // Slash-separated directory of the .gitignore, "" for the root
+	re      *regexp.Regexp
+	negate  bool // "!pattern" re-includes a path
+	hasPath bool // Patterns with a slash match from base, not at any level
+}
+
+// gitignore holds the rules of the .gitignore files found while walking,
+// in the order git applies them: parent directories first, later lines win.
+type gitignore struct {
+	rules []ignoreRule
+}
+
+// load adds the rules of the .gitignore in dir, given relative to the walk
+// root as rel. A missing or unreadable file adds nothing.
+func (g *gitignore) load(dir, rel string) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	base := filepath.ToSlash(rel)
+	if base == "." {
+		base = ""
+	}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if rule, ok := parseIgnoreRule(sc.Text(), base); ok {
+			g.rules = append(g.rules, rule)
+		}
+	}
+}
+
+// parseIgnoreRule parses a .gitignore line; blank lines and comments yield
+// no rule.
+func parseIgnoreRule(line, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`) // Escaped leading "#" or "!"
+	// Only directories are matched, so "build/" and "build" are the same
+	line = strings.TrimRight(line, "/")
+	if strings.Contains(line, "/") {
+		rule.hasPath = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	re, err := regexp.Compile("^" + globRegexp(line) + "$")
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globRegexp translates a gitignore glob into a regular expression: "*" and
+// "?" stay within a path segment, while "**/" and "/**" span segments.
+func globRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**"):
+			b.WriteString("(/.*)?")
+			i += 2
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// ignored reports whether the directory rel, slash-separated and relative
+// to the walk root, is excluded by the loaded rules.
+func (g *gitignore) ignored(rel string) bool {
+	ignored := false
+	for _, r := range g.rules {
+		target := rel
+		if r.base != "" {
+			if !strings.HasPrefix(rel, r.base+"/") {
+				continue
+			}
+			target = strings.TrimPrefix(rel, r.base+"/")
+		}
+		if !r.hasPath {
+			// Patterns without a slash match a name at any level
+			target = path.Base(target)
+		}
+		if r.re.MatchString(target) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
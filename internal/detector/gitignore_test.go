@@ -0,0 +1,50 @@
+package detector
+
+import "testing"
+
+func TestGitignoreIgnored(t *testing.T) {
+	var g gitignore
+	for _, line := range []string{
+		"# build output",
+		"",
+		"dist/",
+		"/tmp",
+		"examples/*/fixtures",
+		"**/generated",
+		"cache-*",
+		"!cache-keep",
+		`\#notes`,
+	} {
+		if rule, ok := parseIgnoreRule(line, ""); ok {
+			g.rules = append(g.rules, rule)
+		}
+	}
+	if rule, ok := parseIgnoreRule("legacy", "services"); ok {
+		g.rules = append(g.rules, rule)
+	}
+
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{"dist", true},
+		{"web/dist", true},
+		{"tmp", true},
+		{"web/tmp", false}, // Anchored to the root
+		{"examples/basic/fixtures", true},
+		{"examples/fixtures", false},
+		{"a/b/generated", true},
+		{"generated", true},
+		{"cache-v1", true},
+		{"cache-keep", false},
+		{"#notes", true},
+		{"services/legacy", true},
+		{"legacy", false}, // Only under services/
+		{"src", false},
+	}
+	for _, tt := range tests {
+		if got := g.ignored(tt.dir); got != tt.want {
+			t.Errorf("ignored(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
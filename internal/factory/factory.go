@@ -3,10 +3,15 @@ package factory
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pragmaticivan/faro/internal/detector"
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/scanner/bundler"
+	"github.com/pragmaticivan/faro/internal/scanner/conda"
 	"github.com/pragmaticivan/faro/internal/scanner/gomod"
+	"github.com/pragmaticivan/faro/internal/scanner/gradle"
+	"github.com/pragmaticivan/faro/internal/scanner/maven"
 	"github.com/pragmaticivan/faro/internal/scanner/npm"
 	"github.com/pragmaticivan/faro/internal/scanner/pip"
 	"github.com/pragmaticivan/faro/internal/scanner/pnpm"
@@ -14,7 +19,11 @@ import (
 	"github.com/pragmaticivan/faro/internal/scanner/uv"
 	"github.com/pragmaticivan/faro/internal/scanner/yarn"
 	"github.com/pragmaticivan/faro/internal/updater"
+	bundlerUpdater "github.com/pragmaticivan/faro/internal/updater/bundler"
+	condaUpdater "github.com/pragmaticivan/faro/internal/updater/conda"
 	gomodUpdater "github.com/pragmaticivan/faro/internal/updater/gomod"
+	gradleUpdater "github.com/pragmaticivan/faro/internal/updater/gradle"
+	mavenUpdater "github.com/pragmaticivan/faro/internal/updater/maven"
 	npmUpdater "github.com/pragmaticivan/faro/internal/updater/npm"
 	pipUpdater "github.com/pragmaticivan/faro/internal/updater/pip"
 	pnpmUpdater "github.com/pragmaticivan/faro/internal/updater/pnpm"
@@ -24,8 +33,12 @@ import (
 	"github.com/pragmaticivan/faro/internal/vuln"
 )
 
-// CreateScanner creates a scanner for the specified package manager.
+// CreateScanner creates a scanner for the specified package manager, preferring
+// one registered with RegisterScanner.
 func CreateScanner(pm detector.PackageManager, workDir string) (scanner.Scanner, error) {
+	if newScanner, ok := customScanner(pm); ok {
+		return newScanner(workDir), nil
+	}
 	switch pm {
 	case detector.Go:
 		return gomod.NewScanner(workDir), nil
@@ -41,16 +54,49 @@ func CreateScanner(pm detector.PackageManager, workDir string) (scanner.Scanner,
 		return poetry.NewScanner(workDir), nil
 	case detector.Uv:
 		return uv.NewScanner(workDir), nil
+	case detector.Maven:
+		return maven.NewScanner(workDir), nil
+	case detector.Gradle:
+		return gradle.NewScanner(workDir), nil
+	case detector.Bundler:
+		return bundler.NewScanner(workDir), nil
+	case detector.Conda:
+		return conda.NewScanner(workDir), nil
 	default:
 		return nil, fmt.Errorf("unsupported package manager: %s", pm)
 	}
 }
 
-// CreateUpdater creates an updater for the specified package manager.
-func CreateUpdater(pm detector.PackageManager, workDir string) (updater.Updater, error) {
+// UpdaterOptions configures the updater made by CreateUpdater.
+type UpdaterOptions struct {
+	Backup          bool // Copy manifests and lockfiles to <file>.faro.bak before updating
+	VerifyChecksums bool // Run go mod verify after updating Go modules
+}
+
+// CreateUpdater creates an updater for the specified package manager, preferring
+// one registered with RegisterUpdater, and applies opts to it.
+func CreateUpdater(pm detector.PackageManager, workDir string, opts UpdaterOptions) (updater.Updater, error) {
+	u, err := buildUpdater(pm, workDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	if b, ok := u.(updater.BackupUpdater); ok {
+		b.SetBackups(opts.Backup)
+	}
+	return u, nil
+}
+
+// buildUpdater creates the updater for pm with the manager-specific options
+// in opts.
+func buildUpdater(pm detector.PackageManager, workDir string, opts UpdaterOptions) (updater.Updater, error) {
+	if newUpdater, ok := customUpdater(pm); ok {
+		return newUpdater(workDir), nil
+	}
 	switch pm {
 	case detector.Go:
-		return gomodUpdater.NewUpdater(workDir), nil
+		u := gomodUpdater.NewUpdater(workDir)
+		u.VerifyChecksums = opts.VerifyChecksums
+		return u, nil
 	case detector.Npm:
 		return npmUpdater.NewUpdater(workDir), nil
 	case detector.Yarn:
@@ -63,18 +109,55 @@ func CreateUpdater(pm detector.PackageManager, workDir string) (updater.Updater,
 		return poetryUpdater.NewUpdater(workDir), nil
 	case detector.Uv:
 		return uvUpdater.NewUpdater(workDir), nil
+	case detector.Maven:
+		return mavenUpdater.NewUpdater(workDir), nil
+	case detector.Gradle:
+		return gradleUpdater.NewUpdater(workDir), nil
+	case detector.Bundler:
+		return bundlerUpdater.NewUpdater(workDir), nil
+	case detector.Conda:
+		return condaUpdater.NewUpdater(workDir), nil
 	default:
 		return nil, fmt.Errorf("unsupported package manager: %s", pm)
 	}
 }
 
-// CreateVulnClient creates a vulnerability client for the specified package manager.
-func CreateVulnClient(pm detector.PackageManager) vuln.Client {
+// VulnOptions configures the vulnerability client made by CreateVulnClient.
+type VulnOptions struct {
+	Source      vuln.Source   // Advisory database; empty uses OSV
+	NoCache     bool          // Skip the on-disk response cache
+	CacheTTL    time.Duration // How long cached responses stay fresh; zero uses vuln.DefaultCacheTTL
+	OSVURL      string        // OSV API endpoint; empty uses the public API
+	GitHubToken string        // Token for the GitHub Advisory Database
+}
+
+// CreateVulnClient creates a vulnerability client for the specified package manager
+// backed by the advisory database opts selects.
+// Responses are cached on disk unless NoCache is set or no cache dir is available.
+func CreateVulnClient(pm detector.PackageManager, opts VulnOptions) vuln.Client {
 	ecosystem := getEcosystem(pm)
-	return vuln.NewClientForEcosystem(ecosystem)
+
+	var cache *vuln.DiskCache
+	if !opts.NoCache {
+		if dir, err := vuln.DefaultCacheDir(); err == nil {
+			cache = vuln.NewDiskCache(dir, opts.CacheTTL, nil)
+		}
+	}
+
+	if opts.Source == vuln.SourceGHSA {
+		return vuln.NewGHSAClient(ecosystem, "", opts.GitHubToken, cache)
+	}
+	return vuln.NewCachedClient(ecosystem, opts.OSVURL, cache)
+}
+
+// SupportsVulnerabilities reports whether the advisory databases cover pm's
+// packages. Conda and custom packages have no OSV ecosystem.
+func SupportsVulnerabilities(pm detector.PackageManager) bool {
+	return getEcosystem(pm) != ""
 }
 
-// getEcosystem maps package managers to OSV ecosystem names.
+// getEcosystem maps package managers to OSV ecosystem names, or "" for
+// managers OSV doesn't cover, including registered custom ones.
 func getEcosystem(pm detector.PackageManager) string {
 	switch pm {
 	case detector.Go:
@@ -83,7 +166,16 @@ func getEcosystem(pm detector.PackageManager) string {
 		return "npm"
 	case detector.Pip, detector.Poetry, detector.Uv:
 		return "PyPI"
+	case detector.Maven, detector.Gradle:
+		return "Maven"
+	case detector.Bundler:
+		return "RubyGems"
+	case detector.Conda:
+		return ""
 	default:
+		if detector.IsCustom(pm) {
+			return ""
+		}
 		return "Go"
 	}
 }
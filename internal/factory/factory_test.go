@@ -1,9 +1,14 @@
 package factory
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pragmaticivan/faro/internal/detector"
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+	"github.com/pragmaticivan/faro/internal/vuln"
 )
 
 func TestCreateScanner(t *testing.T) {
@@ -19,6 +24,10 @@ func TestCreateScanner(t *testing.T) {
 		{"pip", detector.Pip, false},
 		{"poetry", detector.Poetry, false},
 		{"uv", detector.Uv, false},
+		{"maven", detector.Maven, false},
+		{"gradle", detector.Gradle, false},
+		{"bundler", detector.Bundler, false},
+		{"conda", detector.Conda, false},
 		{"invalid", "invalid", true},
 	}
 
@@ -49,19 +58,66 @@ func TestCreateUpdater(t *testing.T) {
 		{"pip", detector.Pip, false},
 		{"poetry", detector.Poetry, false},
 		{"uv", detector.Uv, false},
+		{"maven", detector.Maven, false},
+		{"gradle", detector.Gradle, false},
+		{"bundler", detector.Bundler, false},
+		{"conda", detector.Conda, false},
 		{"invalid", "invalid", true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			updater, err := CreateUpdater(tt.pm, "/tmp")
+			u, err := CreateUpdater(tt.pm, "/tmp", UpdaterOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateUpdater() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !tt.wantErr && updater == nil {
+			if !tt.wantErr && u == nil {
 				t.Errorf("CreateUpdater() returned nil updater")
 			}
+			if _, ok := u.(updater.BackupUpdater); !tt.wantErr && !ok {
+				t.Errorf("CreateUpdater() returned an updater that can't take backups")
+			}
 		})
 	}
 }
+
+func TestCreateVulnClient_DispatchesOnSource(t *testing.T) {
+	if _, ok := CreateVulnClient(detector.Npm, VulnOptions{NoCache: true}).(*vuln.RealClient); !ok {
+		t.Error("expected the OSV client by default")
+	}
+	client := CreateVulnClient(detector.Npm, VulnOptions{Source: vuln.SourceGHSA, NoCache: true, GitHubToken: "token"})
+	if _, ok := client.(*vuln.GHSAClient); !ok {
+		t.Errorf("expected the GHSA client for --vuln-source ghsa, got %T", client)
+	}
+}
+
+func TestSupportsVulnerabilities(t *testing.T) {
+	if !SupportsVulnerabilities(detector.Pip) {
+		t.Error("expected pip packages to be covered by OSV")
+	}
+	if SupportsVulnerabilities(detector.Conda) {
+		t.Error("expected conda packages to have no OSV ecosystem")
+	}
+}
+
+func TestCreateUpdater_Backup(t *testing.T) {
+	dir := t.TempDir()
+	original := "dependencies {\n    implementation 'com.squareup.okhttp3:okhttp:4.10.0'\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "build.gradle"), []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := CreateUpdater(detector.Gradle, dir, UpdaterOptions{Backup: true})
+	if err != nil {
+		t.Fatalf("CreateUpdater() error: %v", err)
+	}
+	if err := u.UpdatePackages([]scanner.Module{
+		{Name: "com.squareup.okhttp3:okhttp", Update: &scanner.UpdateInfo{Version: "4.12.0"}},
+	}); err != nil {
+		t.Fatalf("UpdatePackages() error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "build.gradle"+updater.BackupSuffix)); err != nil || string(data) != original {
+		t.Errorf("expected a backup of build.gradle, got %q, %v", data, err)
+	}
+}
@@ -0,0 +1,64 @@
+package factory
+
+import (
+	"sync"
+
+	"github.com/pragmaticivan/faro/internal/detector"
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+// ScannerConstructor creates a scanner for the project in workDir.
+type ScannerConstructor func(workDir string) scanner.Scanner
+
+// UpdaterConstructor creates an updater for the project in workDir.
+type UpdaterConstructor func(workDir string) updater.Updater
+
+// Scanners and updaters registered at runtime, keyed by package manager.
+var (
+	pluginsMu      sync.RWMutex
+	customScanners = make(map[detector.PackageManager]ScannerConstructor)
+	customUpdaters = make(map[detector.PackageManager]UpdaterConstructor)
+)
+
+// RegisterScanner makes CreateScanner use newScanner for the package manager
+// name, ahead of the built-in scanners, so package managers faro doesn't
+// know about can be added without forking it. A new name is also accepted by
+// --manager; it has no detection rule, so it must be selected explicitly.
+func RegisterScanner(name string, newScanner ScannerConstructor) {
+	pm := detector.PackageManager(name)
+	pluginsMu.Lock()
+	customScanners[pm] = newScanner
+	pluginsMu.Unlock()
+	if _, err := detector.Validate(name); err != nil {
+		detector.RegisterManager(pm)
+	}
+}
+
+// RegisterUpdater makes CreateUpdater use newUpdater for the package manager
+// name, ahead of the built-in updaters.
+func RegisterUpdater(name string, newUpdater UpdaterConstructor) {
+	pm := detector.PackageManager(name)
+	pluginsMu.Lock()
+	customUpdaters[pm] = newUpdater
+	pluginsMu.Unlock()
+	if _, err := detector.Validate(name); err != nil {
+		detector.RegisterManager(pm)
+	}
+}
+
+// customScanner returns the scanner constructor registered for pm, if any.
+func customScanner(pm detector.PackageManager) (ScannerConstructor, bool) {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	newScanner, ok := customScanners[pm]
+	return newScanner, ok
+}
+
+// customUpdater returns the updater constructor registered for pm, if any.
+func customUpdater(pm detector.PackageManager) (UpdaterConstructor, bool) {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	newUpdater, ok := customUpdaters[pm]
+	return newUpdater, ok
+}
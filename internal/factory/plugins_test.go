@@ -0,0 +1,76 @@
+package factory
+
+import (
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/detector"
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+type fakeScanner struct{ workDir string }
+
+func (s *fakeScanner) GetUpdates(scanner.Options) ([]scanner.Module, error) { return nil, nil }
+
+func (s *fakeScanner) GetDependencyIndex() (scanner.DependencyIndex, error) { return nil, nil }
+
+type fakeUpdater struct{ workDir string }
+
+func (u *fakeUpdater) UpdatePackages([]scanner.Module) error { return nil }
+
+func (u *fakeUpdater) UpdateSinglePackage(scanner.Module) error { return nil }
+
+func TestRegisterScannerAndUpdater(t *testing.T) {
+	t.Cleanup(func() {
+		delete(customScanners, "acme")
+		delete(customUpdaters, "acme")
+	})
+
+	if _, err := CreateScanner("acme", "/tmp"); err == nil {
+		t.Fatal("expected acme to be unsupported before registering it")
+	}
+
+	RegisterScanner("acme", func(workDir string) scanner.Scanner { return &fakeScanner{workDir: workDir} })
+	RegisterUpdater("acme", func(workDir string) updater.Updater { return &fakeUpdater{workDir: workDir} })
+
+	pm, err := detector.Validate("acme")
+	if err != nil {
+		t.Fatalf("expected --manager acme to be accepted, got %v", err)
+	}
+
+	s, err := CreateScanner(pm, "/project")
+	if err != nil {
+		t.Fatalf("CreateScanner() error: %v", err)
+	}
+	if fake, ok := s.(*fakeScanner); !ok || fake.workDir != "/project" {
+		t.Errorf("expected the registered scanner for /project, got %#v", s)
+	}
+
+	u, err := CreateUpdater(pm, "/project", UpdaterOptions{})
+	if err != nil {
+		t.Fatalf("CreateUpdater() error: %v", err)
+	}
+	if fake, ok := u.(*fakeUpdater); !ok || fake.workDir != "/project" {
+		t.Errorf("expected the registered updater for /project, got %#v", u)
+	}
+
+	if SupportsVulnerabilities(pm) {
+		t.Error("expected no OSV ecosystem for a custom manager")
+	}
+}
+
+func TestRegisterScanner_OverridesBuiltin(t *testing.T) {
+	t.Cleanup(func() { delete(customScanners, detector.Npm) })
+
+	RegisterScanner("npm", func(workDir string) scanner.Scanner { return &fakeScanner{workDir: workDir} })
+	s, err := CreateScanner(detector.Npm, "/project")
+	if err != nil {
+		t.Fatalf("CreateScanner() error: %v", err)
+	}
+	if _, ok := s.(*fakeScanner); !ok {
+		t.Errorf("expected the registered scanner to take precedence, got %T", s)
+	}
+	if !SupportsVulnerabilities(detector.Npm) {
+		t.Error("expected npm to keep its OSV ecosystem")
+	}
+}
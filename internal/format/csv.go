@@ -0,0 +1,44 @@
+package format
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// WriteCSV writes one row per module, preceded by a header row. Modules
+// without an update (vulnerable ones with no fix) leave the update columns
+// empty. Vulnerability totals for the current and update versions are
+// included when showVulns is set.
+func WriteCSV(w io.Writer, modules []scanner.Module, showVulns bool) error {
+	header := []string{"name", "current", "update", "dependency_type", "direct"}
+	if showVulns {
+		header = append(header, "vulns_current", "vulns_update")
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, m := range modules {
+		name := m.Name
+		if name == "" {
+			name = m.Path
+		}
+		update, vulnsUpdate := "", ""
+		if m.Update != nil {
+			update, vulnsUpdate = m.Update.Version, strconv.Itoa(m.VulnUpdate.Total)
+		}
+		row := []string{name, m.Version, update, m.DependencyType, strconv.FormatBool(m.Direct)}
+		if showVulns {
+			row = append(row, strconv.Itoa(m.VulnCurrent.Total), vulnsUpdate)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,149 @@
+package format
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Delta counts the releases published between a current version and its
+// update, by the component each release bumped over the one before it.
+type Delta struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Total returns the number of releases in the delta.
+func (d Delta) Total() int {
+	return d.Major + d.Minor + d.Patch
+}
+
+// String renders the delta, e.g. "3 majors, 12 minors, 1 patch behind",
+// omitting empty counts. It returns "" for an empty delta.
+func (d Delta) String() string {
+	var parts []string
+	if d.Major > 0 {
+		parts = append(parts, plural(d.Major, "major"))
+	}
+	if d.Minor > 0 {
+		parts = append(parts, plural(d.Minor, "minor"))
+	}
+	if d.Patch > 0 {
+		parts = append(parts, plural(d.Patch, "patch"))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ", ") + " behind"
+}
+
+// VersionDelta counts the stable releases in versions that are newer than
+// current and no newer than target. Releases are walked in version order and
+// each counts as a major, minor or patch release by what it bumped over the
+// previous one, starting from current. Prereleases and versions that aren't
+// MAJOR.MINOR.PATCH are skipped. ok is false if current or target can't be
+// parsed.
+func VersionDelta(current, target string, versions []string) (d Delta, ok bool) {
+	from, ok := parseRelease(current)
+	if !ok {
+		return Delta{}, false
+	}
+	to, ok := parseRelease(target)
+	if !ok {
+		return Delta{}, false
+	}
+
+	var releases []release
+	for _, v := range versions {
+		if strings.Contains(strings.SplitN(v, "+", 2)[0], "-") {
+			continue // Prerelease or Go pseudo-version
+		}
+		r, ok := parseRelease(v)
+		if !ok || r.compare(from) <= 0 || r.compare(to) > 0 {
+			continue
+		}
+		releases = append(releases, r)
+	}
+	sort.Slice(releases, func(i, j int) bool { return releases[i].compare(releases[j]) < 0 })
+
+	prev := from
+	for _, r := range releases {
+		if r == prev {
+			continue // Same release listed twice, e.g. with build metadata
+		}
+		switch {
+		case r.major != prev.major:
+			d.Major++
+		case r.minor != prev.minor:
+			d.Minor++
+		default:
+			d.Patch++
+		}
+		prev = r
+	}
+	return d, true
+}
+
+// NewestPatch returns the newest stable release in versions with the same
+// major and minor version as current and a higher patch, or "" if there is
+// none or current can't be parsed.
+func NewestPatch(current string, versions []string) string {
+	from, ok := parseRelease(current)
+	if !ok {
+		return ""
+	}
+	var newest string
+	best := from
+	for _, v := range versions {
+		if strings.Contains(strings.SplitN(v, "+", 2)[0], "-") {
+			continue // Prerelease or Go pseudo-version
+		}
+		r, ok := parseRelease(v)
+		if !ok || r.major != from.major || r.minor != from.minor || r.compare(best) <= 0 {
+			continue
+		}
+		best, newest = r, v
+	}
+	return newest
+}
+
+// release is the MAJOR.MINOR.PATCH core of a version.
+type release struct {
+	major, minor, patch int
+}
+
+// compare returns -1, 0 or 1 as r is older than, equal to or newer than o.
+func (r release) compare(o release) int {
+	for _, c := range [][2]int{{r.major, o.major}, {r.minor, o.minor}, {r.patch, o.patch}} {
+		if c[0] != c[1] {
+			if c[0] < c[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseRelease parses the core of a version such as "1.2.3", "v1.2.3" or
+// "1.2.3-beta.1+build", ignoring any prerelease and build suffix.
+func parseRelease(v string) (release, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return release{}, false
+	}
+	var nums [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return release{}, false
+		}
+		nums[i] = n
+	}
+	return release{major: nums[0], minor: nums[1], patch: nums[2]}, true
+}
@@ -0,0 +1,90 @@
+package format
+
+import "testing"
+
+func TestVersionDelta(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		target   string
+		versions []string
+		want     Delta
+		wantStr  string
+	}{
+		{
+			name:     "npm releases across majors",
+			current:  "1.2.3",
+			target:   "3.0.0",
+			versions: []string{"3.0.0", "1.2.3", "1.2.4", "2.0.0", "1.3.0", "2.1.0", "2.1.1", "1.0.0", "4.0.0"},
+			want:     Delta{Major: 2, Minor: 2, Patch: 2},
+			wantStr:  "2 majors, 2 minors, 2 patches behind",
+		},
+		{
+			name:     "go versions skip prereleases and pseudo-versions",
+			current:  "v0.4.0",
+			target:   "v0.5.1",
+			versions: []string{"v0.4.0", "v0.5.0-rc.1", "v0.0.0-20240101000000-abcdef123456", "v0.5.0", "v0.5.1"},
+			want:     Delta{Minor: 1, Patch: 1},
+			wantStr:  "1 minor, 1 patch behind",
+		},
+		{
+			name:     "single patch",
+			current:  "4.18.0",
+			target:   "4.18.1",
+			versions: []string{"4.18.0", "4.18.1"},
+			want:     Delta{Patch: 1},
+			wantStr:  "1 patch behind",
+		},
+		{
+			name:     "no listed releases in range",
+			current:  "1.0.0",
+			target:   "1.1.0",
+			versions: []string{"0.9.0", "1.0.0"},
+			want:     Delta{},
+			wantStr:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := VersionDelta(tt.current, tt.target, tt.versions)
+			if !ok {
+				t.Fatal("expected the versions to parse")
+			}
+			if got != tt.want {
+				t.Errorf("VersionDelta() = %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.wantStr {
+				t.Errorf("String() = %q, want %q", got.String(), tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestVersionDelta_Unparseable(t *testing.T) {
+	if _, ok := VersionDelta("latest", "1.0.0", []string{"1.0.0"}); ok {
+		t.Error("expected a non-semver current version to be rejected")
+	}
+	if _, ok := VersionDelta("1.0.0", "2024.1", []string{"2024.1"}); ok {
+		t.Error("expected a non-semver target version to be rejected")
+	}
+}
+
+func TestNewestPatch(t *testing.T) {
+	versions := []string{"v1.2.0", "v1.2.1", "v1.2.3", "v1.2.4-rc.1", "v1.3.0", "v2.0.0", "v1.2.2"}
+	tests := []struct {
+		current string
+		want    string
+	}{
+		{"v1.2.0", "v1.2.3"},
+		{"v1.2.3", ""},
+		{"v1.3.0", ""},
+		{"v0.0.0-20240101000000-abcdef123456", ""},
+		{"latest", ""},
+	}
+	for _, tt := range tests {
+		if got := NewestPatch(tt.current, versions); got != tt.want {
+			t.Errorf("NewestPatch(%q) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package format
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// DiffEntry is a module that changed between two reports.
+type DiffEntry struct {
+	Dir     string
+	Manager string
+
+	// Module is the module as the newer report lists it, or as the older
+	// one did when the newer report no longer lists it
+	Module scanner.Module
+
+	// Vulns is how many vulnerabilities of the current version were fixed
+	// or introduced; zero for update entries
+	Vulns int
+}
+
+// ReportDiff lists what changed between an older and a newer report.
+// Vulnerability changes are only meaningful when both reports were written
+// with vulnerability checks enabled.
+type ReportDiff struct {
+	NewUpdates      []DiffEntry // Updates the older report didn't have, including newer targets
+	RemovedUpdates  []DiffEntry // Updates the newer report no longer lists, e.g. applied ones
+	FixedVulns      []DiffEntry // Modules whose current version has fewer vulnerabilities
+	IntroducedVulns []DiffEntry // Modules whose current version has more vulnerabilities
+}
+
+// Empty reports whether nothing changed.
+func (d ReportDiff) Empty() bool {
+	return len(d.NewUpdates) == 0 && len(d.RemovedUpdates) == 0 && len(d.FixedVulns) == 0 && len(d.IntroducedVulns) == 0
+}
+
+// diffKey identifies a module across reports.
+type diffKey struct {
+	dir, manager, workspace, name string
+}
+
+// DiffReports compares an older report with a newer one. Entries follow the
+// order of the report they are taken from.
+func DiffReports(older, newer Report) ReportDiff {
+	oldModules := indexReport(older)
+	newModules := indexReport(newer)
+
+	var d ReportDiff
+	for _, p := range newer.Projects {
+		for _, m := range p.Updates {
+			entry := DiffEntry{Dir: p.Dir, Manager: p.Manager, Module: m}
+			prev, seen := oldModules[keyFor(p, m)]
+			if m.Update != nil && (!seen || prev.Update == nil || prev.Update.Version != m.Update.Version) {
+				d.NewUpdates = append(d.NewUpdates, entry)
+			}
+			switch delta := m.VulnCurrent.Total - prev.VulnCurrent.Total; {
+			case delta > 0:
+				entry.Vulns = delta
+				d.IntroducedVulns = append(d.IntroducedVulns, entry)
+			case delta < 0:
+				entry.Vulns = -delta
+				d.FixedVulns = append(d.FixedVulns, entry)
+			}
+		}
+	}
+	for _, p := range older.Projects {
+		for _, m := range p.Updates {
+			next, seen := newModules[keyFor(p, m)]
+			if m.Update != nil && (!seen || next.Update == nil) {
+				d.RemovedUpdates = append(d.RemovedUpdates, DiffEntry{Dir: p.Dir, Manager: p.Manager, Module: m})
+			}
+			// Modules the newer report dropped no longer have vulnerabilities
+			if !seen && m.VulnCurrent.Total > 0 {
+				d.FixedVulns = append(d.FixedVulns, DiffEntry{Dir: p.Dir, Manager: p.Manager, Module: m, Vulns: m.VulnCurrent.Total})
+			}
+		}
+	}
+	return d
+}
+
+func indexReport(r Report) map[diffKey]scanner.Module {
+	idx := make(map[diffKey]scanner.Module)
+	for _, p := range r.Projects {
+		for _, m := range p.Updates {
+			idx[keyFor(p, m)] = m
+		}
+	}
+	return idx
+}
+
+func keyFor(p ProjectReport, m scanner.Module) diffKey {
+	name := m.Name
+	if name == "" {
+		name = m.Path // Fallback
+	}
+	return diffKey{dir: p.Dir, manager: p.Manager, workspace: m.Workspace, name: name}
+}
+
+// WriteDiff prints d as sections of "dir manager: name old → new" lines, or
+// a single line saying nothing changed.
+func WriteDiff(w io.Writer, d ReportDiff) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+	sections := []struct {
+		title   string
+		entries []DiffEntry
+		line    func(DiffEntry) string
+	}{
+		{"New updates", d.NewUpdates, func(e DiffEntry) string {
+			return fmt.Sprintf("%s  %s → %s", diffName(e), e.Module.Version, e.Module.Update.Version)
+		}},
+		{"No longer listed", d.RemovedUpdates, func(e DiffEntry) string {
+			return fmt.Sprintf("%s  %s → %s", diffName(e), e.Module.Version, e.Module.Update.Version)
+		}},
+		{"Fixed vulnerabilities", d.FixedVulns, func(e DiffEntry) string {
+			return fmt.Sprintf("%s  %s (-%d)", diffName(e), e.Module.Version, e.Vulns)
+		}},
+		{"New vulnerabilities", d.IntroducedVulns, func(e DiffEntry) string {
+			return fmt.Sprintf("%s  %s (+%d)", diffName(e), e.Module.Version, e.Vulns)
+		}},
+	}
+
+	first := true
+	for _, s := range sections {
+		if len(s.entries) == 0 {
+			continue
+		}
+		if !first {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := fmt.Fprintf(w, "%s (%d):\n", s.title, len(s.entries)); err != nil {
+			return err
+		}
+		for _, e := range s.entries {
+			if _, err := fmt.Fprintf(w, " %s\n", s.line(e)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// diffName renders an entry's module with its project, e.g.
+// "frontend npm: express".
+func diffName(e DiffEntry) string {
+	name := e.Module.Name
+	if name == "" {
+		name = e.Module.Path
+	}
+	return fmt.Sprintf("%s %s: %s", e.Dir, e.Manager, name)
+}
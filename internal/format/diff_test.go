@@ -0,0 +1,84 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestDiffReports(t *testing.T) {
+	var older, newer Report
+	older.AddProject(".", "go", []scanner.Module{
+		{Name: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}},
+		{Name: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.2.0"}, VulnCurrent: scanner.VulnInfo{High: 1, Total: 1}},
+		{Name: "c", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}},
+	})
+	newer.AddProject(".", "go", []scanner.Module{
+		{Name: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}},
+		{Name: "c", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.1.0"}, VulnCurrent: scanner.VulnInfo{Low: 2, Total: 2}},
+		{Name: "d", Version: "v0.1.0", Update: &scanner.UpdateInfo{Version: "v0.2.0"}},
+	})
+
+	d := DiffReports(older, newer)
+	if got := diffNames(d.NewUpdates); got != "c,d" {
+		t.Errorf("NewUpdates = %s, want c (newer target) and d", got)
+	}
+	if got := diffNames(d.RemovedUpdates); got != "b" {
+		t.Errorf("RemovedUpdates = %s, want b", got)
+	}
+	if got := diffNames(d.FixedVulns); got != "b" || d.FixedVulns[0].Vulns != 1 {
+		t.Errorf("FixedVulns = %+v, want b fixing 1", d.FixedVulns)
+	}
+	if got := diffNames(d.IntroducedVulns); got != "c" || d.IntroducedVulns[0].Vulns != 2 {
+		t.Errorf("IntroducedVulns = %+v, want c introducing 2", d.IntroducedVulns)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDiff(&buf, d); err != nil {
+		t.Fatalf("WriteDiff() error = %v", err)
+	}
+	for _, want := range []string{
+		"New updates (2):\n . go: c  v1.0.0 → v2.1.0\n . go: d  v0.1.0 → v0.2.0\n",
+		"No longer listed (1):\n . go: b  v1.0.0 → v1.2.0\n",
+		"Fixed vulnerabilities (1):\n . go: b  v1.0.0 (-1)\n",
+		"New vulnerabilities (1):\n . go: c  v1.0.0 (+2)\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDiffReports_SeparatesProjects(t *testing.T) {
+	var older, newer Report
+	older.AddProject(".", "npm", []scanner.Module{{Name: "express", Version: "4.18.0", Update: &scanner.UpdateInfo{Version: "4.19.0"}}})
+	newer.AddProject("web", "npm", []scanner.Module{{Name: "express", Version: "4.18.0", Update: &scanner.UpdateInfo{Version: "4.19.0"}}})
+
+	d := DiffReports(older, newer)
+	if len(d.NewUpdates) != 1 || len(d.RemovedUpdates) != 1 {
+		t.Errorf("expected the same package in another project to count as changed, got %+v", d)
+	}
+}
+
+func TestWriteDiff_NoChanges(t *testing.T) {
+	var r Report
+	r.AddProject(".", "go", []scanner.Module{{Name: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}}})
+
+	var buf bytes.Buffer
+	if err := WriteDiff(&buf, DiffReports(r, r)); err != nil {
+		t.Fatalf("WriteDiff() error = %v", err)
+	}
+	if got := buf.String(); got != "No changes\n" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+func diffNames(entries []DiffEntry) string {
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Module.Name
+	}
+	return strings.Join(names, ",")
+}
@@ -3,6 +3,7 @@ package format
 import (
 	"fmt"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
@@ -10,9 +11,33 @@ import (
 )
 
 type Options struct {
-	Group bool
-	Lines bool
-	Time  bool
+	Group    bool
+	Lines    bool
+	Time     bool
+	JSON     bool
+	NDJSON   bool // One JSON object per module per line, written as each project finishes
+	CSV      bool
+	Releases bool
+	Delta    bool
+
+	// ManagerPrefix prefixes lines output with the package manager, as in
+	// npm:express@4.18.2; set for runs spanning several managers rather
+	// than by a --format modifier
+	ManagerPrefix bool
+
+	// Template renders each module with a user-supplied text/template; set
+	// from --template rather than by a --format modifier
+	Template *template.Template
+
+	// MinSeverity hides vulnerability counts below this style.Severity*
+	// level in human-readable output; set from --min-severity
+	MinSeverity int
+}
+
+// MachineReadable reports whether the output is meant for other programs,
+// in which case banners and progress messages are suppressed.
+func (o Options) MachineReadable() bool {
+	return o.Lines || o.JSON || o.NDJSON || o.CSV || o.Template != nil
 }
 
 func ParseFlag(s string) (Options, error) {
@@ -33,10 +58,29 @@ func ParseFlag(s string) (Options, error) {
 			out.Lines = true
 		case "time":
 			out.Time = true
+		case "json":
+			out.JSON = true
+		case "ndjson":
+			out.NDJSON = true
+		case "csv":
+			out.CSV = true
+		case "releases":
+			out.Releases = true
+		case "delta":
+			out.Delta = true
 		default:
-			return out, fmt.Errorf("unsupported --format value: %q (supported: group, lines, time)", v)
+			return out, fmt.Errorf("unsupported --format value: %q (supported: group, lines, time, json, ndjson, csv, releases, delta)", v)
 		}
 	}
+	if out.JSON && out.Lines {
+		return out, fmt.Errorf("--format json cannot be combined with lines")
+	}
+	if out.CSV && (out.JSON || out.Lines) {
+		return out, fmt.Errorf("--format csv cannot be combined with json or lines")
+	}
+	if out.NDJSON && (out.JSON || out.Lines || out.CSV) {
+		return out, fmt.Errorf("--format ndjson cannot be combined with json, lines or csv")
+	}
 	return out, nil
 }
 
@@ -65,6 +109,23 @@ func PublishTime(updateTime string, now time.Time) string {
 	return fmt.Sprintf("%s (%dd ago)", t.Format("2006-01-02"), days)
 }
 
+// CurrentAge describes how long ago the current version was published, e.g.
+// "current is 412 days old", or "" if currentTime can't be parsed.
+func CurrentAge(currentTime string, now time.Time) string {
+	t, ok := ParseRFC3339ish(currentTime)
+	if !ok {
+		return ""
+	}
+	days := int(now.Sub(t).Hours() / 24)
+	if days < 0 {
+		days = 0
+	}
+	if days == 1 {
+		return "current is 1 day old"
+	}
+	return fmt.Sprintf("current is %d days old", days)
+}
+
 type DiffGroup int
 
 const (
@@ -84,7 +145,7 @@ func GroupForModule(m scanner.Module) DiffGroup {
 		return GroupMajor
 	case style.DiffMinor:
 		// ncu-style behavior: v0 minor bumps are treated as major-ish risk
-		if strings.HasPrefix(m.Version, "v0.") && strings.HasPrefix(m.Update.Version, "v0.") {
+		if isPreOne(m.Version) && isPreOne(m.Update.Version) {
 			return GroupMajor
 		}
 		return GroupMinor
@@ -95,7 +156,77 @@ func GroupForModule(m scanner.Module) DiffGroup {
 	}
 }
 
-func GroupLabel(m scanner.Module) string {
+// IsMajorUpdate reports whether m's update raises the major version. Minor
+// bumps of 0.x versions count as major, as in GroupForModule.
+func IsMajorUpdate(m scanner.Module) bool {
+	return GroupForModule(m) == GroupMajor
+}
+
+// isPreOne reports whether v is a 0.x version, with or without a "v" prefix.
+func isPreOne(v string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(v, "v"), "0.")
+}
+
+// GroupBy names a strategy for bucketing modules in grouped output.
+type GroupBy string
+
+const (
+	GroupByBump      GroupBy = "bump"      // Semver bump: major, minor, patch (default)
+	GroupByType      GroupBy = "type"      // Dependency type, e.g. devDependencies
+	GroupByScope     GroupBy = "scope"     // npm scope, Maven group or Go path prefix
+	GroupByWorkspace GroupBy = "workspace" // Workspace member, root first
+	GroupByManager   GroupBy = "manager"   // Package manager
+)
+
+// ParseGroupBy validates a --group-by value; empty selects GroupByBump.
+func ParseGroupBy(s string) (GroupBy, error) {
+	by := GroupBy(strings.ToLower(strings.TrimSpace(s)))
+	switch by {
+	case "":
+		return GroupByBump, nil
+	case GroupByBump, GroupByType, GroupByScope, GroupByWorkspace, GroupByManager:
+		return by, nil
+	default:
+		return "", fmt.Errorf("unsupported --group-by value: %q (supported: bump, type, scope, workspace, manager)", s)
+	}
+}
+
+// Grouping is the strategy GroupLabel and GroupSortKey bucket modules by.
+// The zero value groups by semver bump.
+type Grouping struct {
+	By      GroupBy
+	Manager string // Package manager of the modules, for GroupByManager
+}
+
+// GroupLabel returns the heading of the bucket m falls in under g.
+func GroupLabel(m scanner.Module, g Grouping) string {
+	switch g.By {
+	case GroupByType:
+		if m.DependencyType != "" {
+			return m.DependencyType
+		}
+		return "Unknown"
+	case GroupByScope:
+		if scope := moduleScope(moduleName(m)); scope != "" {
+			return scope
+		}
+		return "Unscoped"
+	case GroupByWorkspace:
+		if m.Workspace != "" {
+			return m.Workspace
+		}
+		return "Root"
+	case GroupByManager:
+		if g.Manager != "" {
+			return g.Manager
+		}
+		return "Unknown"
+	}
+	return bumpLabel(m)
+}
+
+// bumpLabel labels m by the semver bump of its update.
+func bumpLabel(m scanner.Module) string {
 	if m.Update == nil {
 		return "Unknown"
 	}
@@ -104,7 +235,7 @@ func GroupLabel(m scanner.Module) string {
 		return "Major"
 	}
 	if diff == style.DiffMinor {
-		if strings.HasPrefix(m.Version, "v0.") && strings.HasPrefix(m.Update.Version, "v0.") {
+		if isPreOne(m.Version) && isPreOne(m.Update.Version) {
 			return "Major (v0)"
 		}
 		return "Minor"
@@ -115,7 +246,26 @@ func GroupLabel(m scanner.Module) string {
 	return "Unknown"
 }
 
-func GroupSortKey(m scanner.Module) int {
+// GroupSortKey orders buckets under g: lower keys are listed first, and
+// buckets with equal keys are ordered by label.
+func GroupSortKey(m scanner.Module, g Grouping) int {
+	switch g.By {
+	case GroupByType, GroupByManager:
+		return 0
+	case GroupByScope:
+		// Unscoped packages come after every scope
+		if moduleScope(moduleName(m)) == "" {
+			return 1
+		}
+		return 0
+	case GroupByWorkspace:
+		// The root project comes before workspace members
+		if m.Workspace == "" {
+			return 0
+		}
+		return 1
+	}
+
 	switch GroupForModule(m) {
 	case GroupMajor:
 		return 0
@@ -127,3 +277,31 @@ func GroupSortKey(m scanner.Module) int {
 		return 3
 	}
 }
+
+// moduleScope returns the namespace a package name belongs to: the npm scope
+// of "@aws-sdk/client-s3", the Maven group of "com.amazonaws:aws-java-sdk",
+// or the host and owner of "github.com/aws/aws-sdk-go". It returns "" for
+// unscoped names.
+func moduleScope(name string) string {
+	if strings.HasPrefix(name, "@") {
+		scope, _, ok := strings.Cut(name, "/")
+		if !ok {
+			return ""
+		}
+		return scope
+	}
+	if group, _, ok := strings.Cut(name, ":"); ok {
+		return group
+	}
+
+	parts := strings.Split(name, "/")
+	switch {
+	case len(parts) == 1:
+		return ""
+	case len(parts) == 2 || !strings.Contains(parts[0], "."):
+		// gopkg.in/yaml.v3, or a path without a host
+		return parts[0]
+	default:
+		return parts[0] + "/" + parts[1]
+	}
+}
@@ -20,6 +20,43 @@ func TestParseFlag(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected error for unsupported format")
 	}
+
+	opts, err = ParseFlag("json")
+	if err != nil || !opts.JSON || !opts.MachineReadable() {
+		t.Fatalf("unexpected json opts: %+v, err: %v", opts, err)
+	}
+
+	opts, err = ParseFlag("time,releases")
+	if err != nil || !opts.Releases || !opts.Time {
+		t.Fatalf("unexpected releases opts: %+v, err: %v", opts, err)
+	}
+
+	opts, err = ParseFlag("delta")
+	if err != nil || !opts.Delta || opts.MachineReadable() {
+		t.Fatalf("unexpected delta opts: %+v, err: %v", opts, err)
+	}
+
+	if _, err = ParseFlag("json,lines"); err == nil {
+		t.Fatalf("expected error combining json and lines")
+	}
+
+	opts, err = ParseFlag("csv")
+	if err != nil || !opts.CSV || !opts.MachineReadable() {
+		t.Fatalf("unexpected csv opts: %+v, err: %v", opts, err)
+	}
+
+	if _, err = ParseFlag("csv,json"); err == nil {
+		t.Fatalf("expected error combining csv and json")
+	}
+
+	opts, err = ParseFlag("ndjson")
+	if err != nil || !opts.NDJSON || !opts.MachineReadable() {
+		t.Fatalf("unexpected ndjson opts: %+v, err: %v", opts, err)
+	}
+
+	if _, err = ParseFlag("ndjson,json"); err == nil {
+		t.Fatalf("expected error combining ndjson and json")
+	}
 }
 
 func TestPublishTime(t *testing.T) {
@@ -31,6 +68,24 @@ func TestPublishTime(t *testing.T) {
 	}
 }
 
+func TestCurrentAge(t *testing.T) {
+	now := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339), "current is 412 days old"},
+		{time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC).Format(time.RFC3339), "current is 1 day old"},
+		{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339), "current is 0 days old"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := CurrentAge(tt.in, now); got != tt.want {
+			t.Errorf("CurrentAge(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
 func TestParseRFC3339ish(t *testing.T) {
 	if _, ok := ParseRFC3339ish("2026-01-17T00:00:00.123456789Z"); !ok {
 		t.Fatalf("expected RFC3339Nano to parse")
@@ -46,16 +101,83 @@ func TestGroupLabelAndSortKey(t *testing.T) {
 	mPatch := scanner.Module{Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}}
 	mV0Minor := scanner.Module{Version: "v0.1.0", Update: &scanner.UpdateInfo{Version: "v0.2.0"}}
 
-	if GroupLabel(mMajor) != "Major" || GroupSortKey(mMajor) != 0 {
+	if GroupLabel(mMajor, Grouping{}) != "Major" || GroupSortKey(mMajor, Grouping{}) != 0 {
 		t.Fatalf("unexpected major label/sort")
 	}
-	if GroupLabel(mMinor) != "Minor" || GroupSortKey(mMinor) != 1 {
+	if GroupLabel(mMinor, Grouping{}) != "Minor" || GroupSortKey(mMinor, Grouping{}) != 1 {
 		t.Fatalf("unexpected minor label/sort")
 	}
-	if GroupLabel(mPatch) != "Patch" || GroupSortKey(mPatch) != 2 {
+	if GroupLabel(mPatch, Grouping{}) != "Patch" || GroupSortKey(mPatch, Grouping{}) != 2 {
 		t.Fatalf("unexpected patch label/sort")
 	}
-	if GroupLabel(mV0Minor) != "Major (v0)" || GroupSortKey(mV0Minor) != 0 {
+	if GroupLabel(mV0Minor, Grouping{}) != "Major (v0)" || GroupSortKey(mV0Minor, Grouping{}) != 0 {
 		t.Fatalf("unexpected v0 label/sort")
 	}
 }
+
+func TestGroupLabel_Scope(t *testing.T) {
+	g := Grouping{By: GroupByScope}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"github.com/aws/aws-sdk-go", "github.com/aws"},
+		{"github.com/aws/aws-sdk-go-v2/service/s3", "github.com/aws"},
+		{"github.com/aws/smithy-go", "github.com/aws"},
+		{"github.com/spf13/cobra", "github.com/spf13"},
+		{"golang.org/x/text", "golang.org/x"},
+		{"gopkg.in/yaml.v3", "gopkg.in"},
+		{"@aws-sdk/client-s3", "@aws-sdk"},
+		{"com.amazonaws:aws-java-sdk-s3", "com.amazonaws"},
+		{"requests", "Unscoped"},
+	}
+	for _, tt := range tests {
+		m := scanner.Module{Name: tt.name, Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.1.0"}}
+		if got := GroupLabel(m, g); got != tt.want {
+			t.Errorf("GroupLabel(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	scoped := scanner.Module{Name: "github.com/aws/smithy-go"}
+	unscoped := scanner.Module{Name: "requests"}
+	if GroupSortKey(scoped, g) >= GroupSortKey(unscoped, g) {
+		t.Error("expected unscoped packages to sort after scopes")
+	}
+}
+
+func TestParseGroupBy(t *testing.T) {
+	for in, want := range map[string]GroupBy{"": GroupByBump, "scope": GroupByScope, " Type ": GroupByType, "manager": GroupByManager} {
+		if got, err := ParseGroupBy(in); err != nil || got != want {
+			t.Errorf("ParseGroupBy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseGroupBy("license"); err == nil {
+		t.Error("expected an error for an unsupported grouping")
+	}
+}
+
+func TestIsMajorUpdate(t *testing.T) {
+	tests := []struct {
+		current, update string
+		want            bool
+	}{
+		{"v1.4.0", "v2.0.0", true},
+		{"1.4.0", "2.0.0", true},
+		{"v1.4.0", "v1.5.0", false},
+		{"1.4.0", "1.4.1", false},
+		{"v0.3.0", "v0.4.0", true},
+		{"0.3.0", "0.4.0", true},
+		{"0.3.0", "0.3.1", false},
+		{"0.9.0", "1.0.0", true},
+		{"latest", "next", false},
+	}
+	for _, tt := range tests {
+		m := scanner.Module{Version: tt.current, Update: &scanner.UpdateInfo{Version: tt.update}}
+		if got := IsMajorUpdate(m); got != tt.want {
+			t.Errorf("IsMajorUpdate(%s -> %s) = %v, want %v", tt.current, tt.update, got, tt.want)
+		}
+	}
+	if IsMajorUpdate(scanner.Module{Version: "v1.0.0"}) {
+		t.Errorf("expected a module without an update not to be major")
+	}
+}
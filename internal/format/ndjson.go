@@ -0,0 +1,29 @@
+package format
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// ndjsonLine is one line of `--format ndjson`: a module's fields, alongside
+// the project directory and manager that reported it, so the line decodes
+// into a scanner.Module as well.
+type ndjsonLine struct {
+	Dir     string `json:"dir"`
+	Manager string `json:"manager"`
+	scanner.Module
+}
+
+// WriteNDJSON writes each module as a compact JSON object on its own line,
+// so consumers can process a project's updates as soon as they are written.
+func WriteNDJSON(w io.Writer, dir, manager string, modules []scanner.Module) error {
+	enc := json.NewEncoder(w)
+	for _, m := range modules {
+		if err := enc.Encode(ndjsonLine{Dir: dir, Manager: manager, Module: m}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestWriteNDJSON_OneModulePerLine(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "express", Version: "4.17.0", Update: &scanner.UpdateInfo{Version: "4.18.2"}, Direct: true, DependencyType: "dependencies"},
+		{Name: "jest", Version: "29.0.0", Update: &scanner.UpdateInfo{Version: "29.7.0"}, DependencyType: "devDependencies", Workspace: "web"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteNDJSON(&buf, "frontend", "npm", modules); err != nil {
+		t.Fatalf("WriteNDJSON() error = %v", err)
+	}
+
+	var got []scanner.Module
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var m scanner.Module
+		if err := json.Unmarshal(sc.Bytes(), &m); err != nil {
+			t.Fatalf("line %q is not a module: %v", sc.Text(), err)
+		}
+		var project struct{ Dir, Manager string }
+		if err := json.Unmarshal(sc.Bytes(), &project); err != nil || project.Dir != "frontend" || project.Manager != "npm" {
+			t.Fatalf("expected dir and manager on line %q, got %+v (%v)", sc.Text(), project, err)
+		}
+		got = append(got, m)
+	}
+	if len(got) != len(modules) {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(modules), len(got), buf.String())
+	}
+	for i, m := range got {
+		want := modules[i]
+		if m.Name != want.Name || m.Version != want.Version || m.Update == nil || m.Update.Version != want.Update.Version ||
+			m.DependencyType != want.DependencyType || m.Direct != want.Direct || m.Workspace != want.Workspace {
+			t.Errorf("line %d = %+v, want %+v", i, m, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/detector"
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// ReleaseURL returns a best-effort link to the release notes of the module's
+// update version, or "" if none can be derived.
+func ReleaseURL(pm detector.PackageManager, m scanner.Module) string {
+	name := m.Name
+	if name == "" {
+		name = m.Path
+	}
+	if name == "" {
+		return ""
+	}
+	version := m.Version
+	if m.Update != nil {
+		version = m.Update.Version
+	}
+
+	switch pm {
+	case detector.Go:
+		return goReleaseURL(name, version)
+	case detector.Npm, detector.Yarn, detector.Pnpm:
+		return fmt.Sprintf("https://www.npmjs.com/package/%s/v/%s", name, version)
+	case detector.Pip, detector.Poetry, detector.Uv:
+		return fmt.Sprintf("https://pypi.org/project/%s/%s/", name, version)
+	case detector.Maven, detector.Gradle:
+		return fmt.Sprintf("https://central.sonatype.com/artifact/%s/%s", strings.Replace(name, ":", "/", 1), version)
+	case detector.Bundler:
+		return fmt.Sprintf("https://rubygems.org/gems/%s/versions/%s", name, version)
+	default:
+		return ""
+	}
+}
+
+// goReleaseURL links GitHub and GitLab hosted modules to their releases page
+// and everything else to pkg.go.dev.
+func goReleaseURL(path, version string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) >= 3 {
+		repo := strings.Join(parts[:3], "/")
+		switch parts[0] {
+		case "github.com":
+			return "https://" + repo + "/releases"
+		case "gitlab.com":
+			return "https://" + repo + "/-/releases"
+		}
+	}
+
+	if version == "" {
+		return "https://pkg.go.dev/" + path
+	}
+	return fmt.Sprintf("https://pkg.go.dev/%s@%s", path, version)
+}
@@ -0,0 +1,42 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/detector"
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestReleaseURL(t *testing.T) {
+	update := func(name, version string) scanner.Module {
+		return scanner.Module{Name: name, Version: "1.0.0", Update: &scanner.UpdateInfo{Version: version}}
+	}
+
+	tests := []struct {
+		name string
+		pm   detector.PackageManager
+		mod  scanner.Module
+		want string
+	}{
+		{"go github", detector.Go, update("github.com/spf13/cobra", "v1.9.0"), "https://github.com/spf13/cobra/releases"},
+		{"go github major suffix", detector.Go, update("github.com/go-chi/chi/v5", "v5.1.0"), "https://github.com/go-chi/chi/releases"},
+		{"go gitlab", detector.Go, update("gitlab.com/group/project", "v0.2.0"), "https://gitlab.com/group/project/-/releases"},
+		{"go vanity path", detector.Go, update("golang.org/x/mod", "v0.20.0"), "https://pkg.go.dev/golang.org/x/mod@v0.20.0"},
+		{"go legacy path field", detector.Go, scanner.Module{Path: "gopkg.in/yaml.v3", Update: &scanner.UpdateInfo{Version: "v3.0.1"}}, "https://pkg.go.dev/gopkg.in/yaml.v3@v3.0.1"},
+		{"npm", detector.Npm, update("react", "19.0.0"), "https://www.npmjs.com/package/react/v/19.0.0"},
+		{"npm scoped via pnpm", detector.Pnpm, update("@types/node", "22.1.0"), "https://www.npmjs.com/package/@types/node/v/22.1.0"},
+		{"pypi", detector.Poetry, update("requests", "2.32.3"), "https://pypi.org/project/requests/2.32.3/"},
+		{"maven", detector.Maven, update("com.google.guava:guava", "33.0.0-jre"), "https://central.sonatype.com/artifact/com.google.guava/guava/33.0.0-jre"},
+		{"gradle", detector.Gradle, update("com.google.guava:guava", "33.0.0-jre"), "https://central.sonatype.com/artifact/com.google.guava/guava/33.0.0-jre"},
+		{"rubygems", detector.Bundler, update("rails", "7.1.2"), "https://rubygems.org/gems/rails/versions/7.1.2"},
+		{"unknown manager", detector.PackageManager("cargo"), update("serde", "1.0.0"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReleaseURL(tt.pm, tt.mod); got != tt.want {
+				t.Fatalf("ReleaseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
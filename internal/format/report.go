@@ -0,0 +1,60 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// SchemaVersion identifies the shape of Report. It is only incremented on
+// breaking changes; new optional fields keep the same version.
+const SchemaVersion = 1
+
+// Report is the machine-readable output of `--format json`. Updates are nested
+// per project so consumers can attribute each one to a directory and manager.
+type Report struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Projects      []ProjectReport `json:"projects"`
+}
+
+// ProjectReport holds the updates found by one package manager in one directory.
+type ProjectReport struct {
+	Dir     string           `json:"dir"`
+	Manager string           `json:"manager"`
+	Updates []scanner.Module `json:"updates"`
+}
+
+// AddProject appends the updates for a project, normalizing a nil slice so
+// that projects without updates encode as an empty array.
+func (r *Report) AddProject(dir, manager string, updates []scanner.Module) {
+	if updates == nil {
+		updates = []scanner.Module{}
+	}
+	r.Projects = append(r.Projects, ProjectReport{Dir: dir, Manager: manager, Updates: updates})
+}
+
+// WriteJSON encodes the report as indented JSON, stamped with SchemaVersion.
+func WriteJSON(w io.Writer, r Report) error {
+	r.SchemaVersion = SchemaVersion
+	if r.Projects == nil {
+		r.Projects = []ProjectReport{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// ReadJSON decodes a report written by WriteJSON, rejecting reports from a
+// newer, incompatible schema.
+func ReadJSON(r io.Reader) (Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return Report{}, fmt.Errorf("invalid JSON report: %w", err)
+	}
+	if report.SchemaVersion < 1 || report.SchemaVersion > SchemaVersion {
+		return Report{}, fmt.Errorf("unsupported report schemaVersion %d (supported: %d)", report.SchemaVersion, SchemaVersion)
+	}
+	return report, nil
+}
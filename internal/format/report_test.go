@@ -0,0 +1,78 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestWriteJSON_NestsUpdatesPerProject(t *testing.T) {
+	var report Report
+	report.AddProject(".", "go", []scanner.Module{{Name: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}}})
+	report.AddProject("frontend", "npm", nil)
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, report); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var decoded struct {
+		SchemaVersion *int `json:"schemaVersion"`
+		Projects      []struct {
+			Dir     string            `json:"dir"`
+			Manager string            `json:"manager"`
+			Updates []json.RawMessage `json:"updates"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded.SchemaVersion == nil || *decoded.SchemaVersion != SchemaVersion {
+		t.Fatalf("expected schemaVersion %d, got %s", SchemaVersion, buf.String())
+	}
+	if len(decoded.Projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(decoded.Projects))
+	}
+	if decoded.Projects[0].Manager != "go" || len(decoded.Projects[0].Updates) != 1 {
+		t.Errorf("unexpected first project: %+v", decoded.Projects[0])
+	}
+	if decoded.Projects[1].Dir != "frontend" || decoded.Projects[1].Updates == nil {
+		t.Errorf("expected empty updates array for second project: %s", buf.String())
+	}
+}
+
+func TestWriteJSON_EmptyReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, Report{}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if got := buf.String(); got != "{\n  \"schemaVersion\": 1,\n  \"projects\": []\n}\n" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+func TestReadJSON_RoundTrip(t *testing.T) {
+	var report Report
+	report.AddProject(".", "go", []scanner.Module{{Name: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}, VulnCurrent: scanner.VulnInfo{High: 1, Total: 1}}})
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, report); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	got, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	if len(got.Projects) != 1 || got.Projects[0].Updates[0].VulnCurrent.High != 1 {
+		t.Errorf("expected the report and its vulnerability counts back, got %+v", got)
+	}
+
+	if _, err := ReadJSON(bytes.NewBufferString(`{"schemaVersion":2,"projects":[]}`)); err == nil {
+		t.Error("expected a newer schemaVersion to be rejected")
+	}
+	if _, err := ReadJSON(bytes.NewBufferString(`[]`)); err == nil {
+		t.Error("expected a non-report to be rejected")
+	}
+}
@@ -0,0 +1,106 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// SortKey selects the order in which SortModules lists modules.
+type SortKey string
+
+const (
+	SortNone     SortKey = ""         // Keep the scanner's order
+	SortName     SortKey = "name"     // Alphabetical by package name
+	SortBump     SortKey = "bump"     // Major, then minor, then patch updates
+	SortAge      SortKey = "age"      // Oldest current version first
+	SortSeverity SortKey = "severity" // Most severe current vulnerabilities first
+)
+
+// ParseSortKey validates a --sort value.
+func ParseSortKey(s string) (SortKey, error) {
+	key := SortKey(strings.ToLower(strings.TrimSpace(s)))
+	switch key {
+	case SortNone, SortName, SortBump, SortAge, SortSeverity:
+		return key, nil
+	default:
+		return "", fmt.Errorf("unsupported --sort value: %q (supported: name, bump, age, severity)", s)
+	}
+}
+
+// SortModules orders modules in place by key. Ties, and every module under
+// SortName, are ordered by name and then workspace so the result doesn't
+// depend on the scanner's order. SortNone leaves modules untouched.
+func SortModules(modules []scanner.Module, key SortKey) {
+	var before func(a, b scanner.Module) int
+	switch key {
+	case SortName:
+		before = func(a, b scanner.Module) int { return 0 }
+	case SortBump:
+		before = func(a, b scanner.Module) int {
+			return GroupSortKey(a, Grouping{}) - GroupSortKey(b, Grouping{})
+		}
+	case SortAge:
+		before = compareAge
+	case SortSeverity:
+		before = func(a, b scanner.Module) int {
+			return compareSeverity(b.VulnCurrent, a.VulnCurrent)
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(modules, func(i, j int) bool {
+		a, b := modules[i], modules[j]
+		if c := before(a, b); c != 0 {
+			return c < 0
+		}
+		if an, bn := moduleName(a), moduleName(b); an != bn {
+			return an < bn
+		}
+		return a.Workspace < b.Workspace
+	})
+}
+
+// compareAge orders modules by the publish time of their current version,
+// oldest first; modules without a known time come last.
+func compareAge(a, b scanner.Module) int {
+	at, aok := ParseRFC3339ish(a.Time)
+	bt, bok := ParseRFC3339ish(b.Time)
+	switch {
+	case !aok && !bok:
+		return 0
+	case !aok:
+		return 1
+	case !bok:
+		return -1
+	}
+	return at.Compare(bt)
+}
+
+// compareSeverity compares vulnerability counts bucket by bucket, from
+// critical down to low, and then by total.
+func compareSeverity(a, b scanner.VulnInfo) int {
+	for _, c := range [][2]int{
+		{a.Critical, b.Critical},
+		{a.High, b.High},
+		{a.Medium, b.Medium},
+		{a.Low, b.Low},
+		{a.Total, b.Total},
+	} {
+		if c[0] != c[1] {
+			return c[0] - c[1]
+		}
+	}
+	return 0
+}
+
+// moduleName returns the module's name, falling back to the legacy Go Path.
+func moduleName(m scanner.Module) string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.Path
+}
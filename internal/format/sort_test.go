@@ -0,0 +1,81 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func sortFixture() []scanner.Module {
+	return []scanner.Module{
+		{Name: "zod", Version: "3.22.0", Time: "2023-08-01T00:00:00Z", Update: &scanner.UpdateInfo{Version: "3.22.4"}},
+		{Name: "axios", Version: "0.27.0", Time: "2022-04-25T00:00:00Z", Update: &scanner.UpdateInfo{Version: "1.6.0"},
+			VulnCurrent: scanner.VulnInfo{Medium: 2, Total: 2}},
+		{Name: "lodash", Version: "4.17.20", Update: &scanner.UpdateInfo{Version: "4.17.21"},
+			VulnCurrent: scanner.VulnInfo{High: 1, Total: 1}},
+		{Name: "express", Version: "4.17.0", Time: "2019-05-16T00:00:00Z", Update: &scanner.UpdateInfo{Version: "4.18.2"},
+			VulnCurrent: scanner.VulnInfo{High: 1, Low: 1, Total: 2}},
+		{Name: "react", Version: "17.0.2", Time: "2021-03-22T00:00:00Z", Update: &scanner.UpdateInfo{Version: "18.2.0"}},
+	}
+}
+
+func names(modules []scanner.Module) string {
+	out := make([]string, len(modules))
+	for i, m := range modules {
+		out[i] = m.Name
+	}
+	return strings.Join(out, " ")
+}
+
+func TestSortModules(t *testing.T) {
+	tests := []struct {
+		key  SortKey
+		want string
+	}{
+		{SortNone, "zod axios lodash express react"},
+		{SortName, "axios express lodash react zod"},
+		// axios is a v0 bump, so it counts as major
+		{SortBump, "axios react express lodash zod"},
+		// lodash has no publish time and goes last
+		{SortAge, "express react axios zod lodash"},
+		{SortSeverity, "express lodash axios react zod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.key), func(t *testing.T) {
+			modules := sortFixture()
+			SortModules(modules, tt.key)
+			if got := names(modules); got != tt.want {
+				t.Errorf("SortModules(%q) = %s, want %s", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortModules_WorkspaceTieBreak(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "react", Workspace: "packages/web"},
+		{Name: "react", Workspace: ""},
+		{Name: "react", Workspace: "packages/admin"},
+	}
+	SortModules(modules, SortName)
+	var got []string
+	for _, m := range modules {
+		got = append(got, m.Workspace)
+	}
+	if strings.Join(got, ",") != ",packages/admin,packages/web" {
+		t.Errorf("unexpected workspace order: %q", got)
+	}
+}
+
+func TestParseSortKey(t *testing.T) {
+	for _, in := range []string{"", "name", "Bump", " age ", "severity"} {
+		if _, err := ParseSortKey(in); err != nil {
+			t.Errorf("ParseSortKey(%q) error: %v", in, err)
+		}
+	}
+	if _, err := ParseSortKey("size"); err == nil {
+		t.Error("expected an error for an unsupported key")
+	}
+}
@@ -0,0 +1,82 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// Summary counts available updates by the size of the version bump.
+type Summary struct {
+	Total int
+	Major int // Includes v0 minor bumps, matching GroupForModule
+	Minor int
+	Patch int
+	Other int // Non-semver versions whose bump size can't be determined
+
+	// FixedVulns is how many known vulnerabilities the updates resolve
+	FixedVulns int
+}
+
+// Summarize counts the updates in modules by bump size. Modules without an
+// update are ignored.
+func Summarize(modules []scanner.Module) Summary {
+	var s Summary
+	for _, m := range modules {
+		if m.Update == nil {
+			continue
+		}
+		s.Total++
+		switch GroupForModule(m) {
+		case GroupMajor:
+			s.Major++
+		case GroupMinor:
+			s.Minor++
+		case GroupPatch:
+			s.Patch++
+		default:
+			s.Other++
+		}
+		if fixed := m.VulnCurrent.Total - m.VulnUpdate.Total; fixed > 0 {
+			s.FixedVulns += fixed
+		}
+	}
+	return s
+}
+
+// Line renders the summary as a single sentence, e.g.
+// "12 updates available (4 major, 6 minor, 2 patch) across 3 groups".
+func (s Summary) Line(groups int, showVulns bool) string {
+	counts := []string{
+		fmt.Sprintf("%d major", s.Major),
+		fmt.Sprintf("%d minor", s.Minor),
+		fmt.Sprintf("%d patch", s.Patch),
+	}
+	if s.Other > 0 {
+		counts = append(counts, fmt.Sprintf("%d other", s.Other))
+	}
+
+	line := fmt.Sprintf("%s available (%s)", plural(s.Total, "update"), strings.Join(counts, ", "))
+	if groups > 0 {
+		line += " across " + plural(groups, "group")
+	}
+	if showVulns {
+		line += ", fixes " + plural(s.FixedVulns, "vulnerability")
+	}
+	return line
+}
+
+// plural formats n with the singular or plural form of noun.
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	if strings.HasSuffix(noun, "ch") {
+		return fmt.Sprintf("%d %ses", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,63 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestSummarize(t *testing.T) {
+	mod := func(from, to string) scanner.Module {
+		return scanner.Module{Version: from, Update: &scanner.UpdateInfo{Version: to}}
+	}
+
+	modules := []scanner.Module{
+		mod("v1.0.0", "v2.0.0"),
+		mod("v0.1.0", "v0.2.0"), // v0 minor counts as major
+		mod("1.2.0", "1.3.0"),
+		mod("v1.0.0", "v1.0.1"),
+		mod("v0.0.0-20240101000000-abcdefabcdef", "v0.0.0-20250101000000-abcdefabcdef"),
+		mod("2024.1", "latest"),
+		{Version: "v1.0.0"}, // no update
+	}
+
+	got := Summarize(modules)
+	want := Summary{Total: 6, Major: 2, Minor: 1, Patch: 1, Other: 2}
+	if got != want {
+		t.Fatalf("Summarize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummarize_FixedVulns(t *testing.T) {
+	fixes := scanner.Module{
+		Version:     "v1.0.0",
+		Update:      &scanner.UpdateInfo{Version: "v1.0.1"},
+		VulnCurrent: scanner.VulnInfo{High: 2, Total: 2},
+	}
+	regresses := scanner.Module{
+		Version:    "v1.0.0",
+		Update:     &scanner.UpdateInfo{Version: "v1.1.0"},
+		VulnUpdate: scanner.VulnInfo{Low: 1, Total: 1},
+	}
+
+	if got := Summarize([]scanner.Module{fixes, regresses}).FixedVulns; got != 2 {
+		t.Fatalf("expected 2 fixed vulnerabilities, got %d", got)
+	}
+}
+
+func TestSummaryLine(t *testing.T) {
+	s := Summary{Total: 12, Major: 4, Minor: 6, Patch: 2}
+	if got := s.Line(3, false); got != "12 updates available (4 major, 6 minor, 2 patch) across 3 groups" {
+		t.Fatalf("unexpected line: %q", got)
+	}
+
+	s = Summary{Total: 1, Patch: 1, FixedVulns: 1}
+	if got := s.Line(1, true); got != "1 update available (0 major, 0 minor, 1 patch) across 1 group, fixes 1 vulnerability" {
+		t.Fatalf("unexpected line: %q", got)
+	}
+
+	s = Summary{Total: 2, Minor: 1, Other: 1}
+	if got := s.Line(0, true); got != "2 updates available (0 major, 1 minor, 0 patch, 1 other), fixes 0 vulnerabilities" {
+		t.Fatalf("unexpected line: %q", got)
+	}
+}
@@ -0,0 +1,38 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// ParseTemplate parses a --template value, a text/template executed once per
+// update with the scanner.Module as data (.Name, .Version, .Update.Version,
+// .DependencyType, .VulnCurrent.Total, ...).
+func ParseTemplate(s string) (*template.Template, error) {
+	tmpl, err := template.New("module").Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// WriteTemplate renders each module with an update with tmpl, ending every
+// rendering with a newline. Modules listed only for their vulnerabilities
+// have no update and are skipped, so templates can use .Update freely.
+func WriteTemplate(w io.Writer, tmpl *template.Template, modules []scanner.Module) error {
+	for _, m := range modules {
+		if m.Update == nil {
+			continue
+		}
+		if err := tmpl.Execute(w, m); err != nil {
+			return fmt.Errorf("failed to render --template for %s: %w", m.Name, err)
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
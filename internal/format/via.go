@@ -0,0 +1,21 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxVia is how many of the dependencies pulling a module in Via names.
+const maxVia = 3
+
+// Via renders the direct dependencies that pull in a transitive module, e.g.
+// "via express" or "via a, b, c and 2 more". It returns "" when there are none.
+func Via(requiredBy []string) string {
+	if len(requiredBy) == 0 {
+		return ""
+	}
+	if len(requiredBy) <= maxVia {
+		return "via " + strings.Join(requiredBy, ", ")
+	}
+	return fmt.Sprintf("via %s and %d more", strings.Join(requiredBy[:maxVia], ", "), len(requiredBy)-maxVia)
+}
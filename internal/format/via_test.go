@@ -0,0 +1,20 @@
+package format
+
+import "testing"
+
+func TestVia(t *testing.T) {
+	tests := []struct {
+		requiredBy []string
+		want       string
+	}{
+		{nil, ""},
+		{[]string{"express"}, "via express"},
+		{[]string{"a", "b", "c"}, "via a, b, c"},
+		{[]string{"a", "b", "c", "d", "e"}, "via a, b, c and 2 more"},
+	}
+	for _, tt := range tests {
+		if got := Via(tt.requiredBy); got != tt.want {
+			t.Errorf("Via(%v) = %q, want %q", tt.requiredBy, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package gomod
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -22,54 +23,69 @@ func ReadRequireIndex(goModPath string) (RequireIndex, error) {
 
 func ParseRequireIndex(goModContents string) RequireIndex {
 	idx := make(RequireIndex)
+	block := "" // Directive of the enclosing "verb (" block, if any
 
-	lines := strings.Split(goModContents, "\n")
-	inRequireBlock := false
-
-	for _, rawLine := range lines {
-		line := strings.TrimSpace(rawLine)
+	for _, rawLine := range strings.Split(goModContents, "\n") {
+		line, comment := splitComment(rawLine)
 		if line == "" {
 			continue
 		}
 
-		if strings.HasPrefix(line, "require (") {
-			inRequireBlock = true
-			continue
-		}
-		if inRequireBlock && line == ")" {
-			inRequireBlock = false
+		if block != "" {
+			if line == ")" {
+				block = ""
+			} else if block == "require" {
+				parseRequireLine(idx, line, comment)
+			}
 			continue
 		}
 
-		if strings.HasPrefix(line, "require ") {
-			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
-			parseRequireLine(idx, line)
+		// Only require lines matter; go, toolchain, exclude, retract,
+		// replace, tool and godebug directives are skipped
+		verb, rest, _ := strings.Cut(line, " ")
+		if v, ok := strings.CutSuffix(verb, "("); ok && rest == "" {
+			// "require(" without a space before the parenthesis
+			verb, rest = v, "("
+		}
+		rest = strings.TrimSpace(rest)
+		if rest == "(" {
+			block = verb
 			continue
 		}
-
-		if inRequireBlock {
-			parseRequireLine(idx, line)
+		if verb == "require" {
+			parseRequireLine(idx, rest, comment)
 		}
 	}
-
 	return idx
 }
 
-func parseRequireLine(dst RequireIndex, line string) {
-	comment := ""
+// splitComment returns line without its trailing // comment, and the comment
+// text, both trimmed.
+func splitComment(line string) (code, comment string) {
 	if i := strings.Index(line, "//"); i >= 0 {
-		comment = line[i+2:]
-		line = strings.TrimSpace(line[:i])
+		return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+2:])
 	}
+	return strings.TrimSpace(line), ""
+}
+
+// isIndirectComment reports whether a require line's comment marks it
+// indirect: the comment is "indirect" or starts with "indirect;", as written
+// by the go command.
+func isIndirectComment(comment string) bool {
+	return comment == "indirect" || strings.HasPrefix(comment, "indirect;")
+}
 
+func parseRequireLine(dst RequireIndex, line, comment string) {
 	fields := strings.Fields(line)
 	if len(fields) < 2 {
 		return
 	}
-
 	path := fields[0]
-	indirect := strings.Contains(comment, "indirect")
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		path = unquoted
+	}
 
+	indirect := isIndirectComment(comment)
 	if existingIndirect, ok := dst[path]; ok {
 		if !existingIndirect {
 			// already direct; keep direct
@@ -80,6 +96,152 @@ func parseRequireLine(dst RequireIndex, line string) {
 		dst[path] = indirect
 		return
 	}
-
 	dst[path] = indirect
 }
+
+// ReplaceIndex maps a replaced module path to the version the replace
+// applies to; an empty version means every version is replaced.
+type ReplaceIndex map[string]string
+
+// Replaces reports whether the module at path and version is replaced.
+func (r ReplaceIndex) Replaces(path, version string) bool {
+	v, ok := r[path]
+	return ok && (v == "" || v == version)
+}
+
+// ReadReplaces returns the replace directives of the go.mod at goModPath.
+func ReadReplaces(goModPath string) (ReplaceIndex, error) {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", goModPath, err)
+	}
+	return ParseReplaces(string(data)), nil
+}
+
+// ParseReplaces returns the modules named on the left-hand side of replace
+// directives, in both the single-line and block forms.
+func ParseReplaces(goModContents string) ReplaceIndex {
+	idx := make(ReplaceIndex)
+	inBlock := false
+
+	for _, rawLine := range strings.Split(goModContents, "\n") {
+		line := rawLine
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(line, "replace ("), line == "replace(":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "replace "):
+			line = strings.TrimPrefix(line, "replace ")
+		case !inBlock:
+			continue
+		}
+
+		old, _, ok := strings.Cut(line, "=>")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(old)
+		switch len(fields) {
+		case 1:
+			idx[fields[0]] = ""
+		case 2:
+			// A version-less replace of the same module wins
+			if v, seen := idx[fields[0]]; !seen || v != "" {
+				idx[fields[0]] = fields[1]
+			}
+		}
+	}
+
+	return idx
+}
+
+// ReadGoDirective returns the version from the `go` directive of the go.mod at goModPath.
+func ReadGoDirective(goModPath string) (string, error) {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", goModPath, err)
+	}
+	return ParseGoDirective(string(data)), nil
+}
+
+// ParseGoDirective returns the version from the `go` directive, or "" if absent.
+func ParseGoDirective(goModContents string) string {
+	return parseDirective(goModContents, "go")
+}
+
+// ParseToolchainDirective returns the value of the `toolchain` directive, or "" if absent.
+func ParseToolchainDirective(goModContents string) string {
+	return parseDirective(goModContents, "toolchain")
+}
+
+func parseDirective(goModContents, name string) string {
+	for _, rawLine := range strings.Split(goModContents, "\n") {
+		line := rawLine
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == name {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// CompareGoVersions compares Go versions such as "1.21", "1.21.3" or "1.22rc1"
+// (an optional "go" prefix is ignored). It returns -1, 0 or 1. A missing patch
+// component is treated as zero and prereleases sort before the release.
+func CompareGoVersions(a, b string) int {
+	an, apre := parseGoVersion(a)
+	bn, bpre := parseGoVersion(b)
+	for i := range an {
+		if an[i] != bn[i] {
+			if an[i] < bn[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case apre == bpre:
+		return 0
+	case apre == "":
+		return 1
+	case bpre == "":
+		return -1
+	case apre < bpre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// ExceedsGoFloor reports whether a module requiring Go version required would
+// force raising a project whose go directive is floor. Empty values never exceed.
+func ExceedsGoFloor(floor, required string) bool {
+	if floor == "" || required == "" {
+		return false
+	}
+	return CompareGoVersions(required, floor) > 0
+}
+
+func parseGoVersion(v string) (nums [3]int, pre string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "go")
+	if i := strings.IndexFunc(v, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); i >= 0 {
+		pre = v[i:]
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	for i := 0; i < len(parts) && i < len(nums); i++ {
+		nums[i], _ = strconv.Atoi(parts[i])
+	}
+	return nums, pre
+}
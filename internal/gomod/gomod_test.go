@@ -48,6 +48,58 @@ require (
 	}
 }
 
+func TestParseRequireIndex_ToolchainBlocksAndExclude(t *testing.T) {
+	contents := `module example.com/foo
+
+go 1.21.0
+
+toolchain go1.22.0
+
+require (
+	github.com/a/b v1.2.3
+	"github.com/quoted/mod" v0.3.0
+)
+
+require(
+	github.com/c/d v0.1.0 // indirect
+	github.com/g/h v0.2.0 // indirectly pinned for a bug
+)
+
+exclude (
+	github.com/excluded/block v1.0.0
+)
+
+exclude github.com/excluded/line v1.1.0
+
+retract [v0.9.0, v0.9.5] // broken release
+
+tool example.com/tool/cmd
+`
+	idx := ParseRequireIndex(contents)
+
+	want := RequireIndex{
+		"github.com/a/b":        false,
+		"github.com/quoted/mod": false,
+		"github.com/c/d":        true,
+		"github.com/g/h":        false,
+	}
+	if len(idx) != len(want) {
+		t.Fatalf("expected %d requires, got %v", len(want), idx)
+	}
+	for path, indirect := range want {
+		got, ok := idx[path]
+		if !ok || got != indirect {
+			t.Errorf("%s: got indirect=%v (present %v), want %v", path, got, ok, indirect)
+		}
+	}
+	if got := ParseGoDirective(contents); got != "1.21.0" {
+		t.Errorf("expected go 1.21.0, got %q", got)
+	}
+	if got := ParseToolchainDirective(contents); got != "go1.22.0" {
+		t.Errorf("expected toolchain go1.22.0, got %q", got)
+	}
+}
+
 func TestReadRequireIndex(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "go.mod")
@@ -64,3 +116,92 @@ func TestReadRequireIndex(t *testing.T) {
 		t.Fatalf("expected direct require")
 	}
 }
+
+func TestParseReplaces(t *testing.T) {
+	contents := `module example.com/foo
+
+go 1.25
+
+require (
+	github.com/a/b v1.2.3
+	github.com/c/d v0.1.0
+	github.com/e/f v1.0.0
+)
+
+replace github.com/a/b => ../b // local checkout
+
+replace (
+	github.com/c/d v0.1.0 => github.com/fork/d v0.1.1
+)
+`
+
+	idx := ParseReplaces(contents)
+	if !idx.Replaces("github.com/a/b", "v1.2.3") {
+		t.Errorf("expected github.com/a/b to be replaced at any version")
+	}
+	if !idx.Replaces("github.com/c/d", "v0.1.0") || idx.Replaces("github.com/c/d", "v0.2.0") {
+		t.Errorf("expected github.com/c/d to be replaced only at v0.1.0")
+	}
+	if idx.Replaces("github.com/e/f", "v1.0.0") {
+		t.Errorf("expected github.com/e/f not to be replaced")
+	}
+}
+
+func TestParseGoDirective(t *testing.T) {
+	contents := `module example.com/foo
+
+go 1.21 // language version
+
+toolchain go1.22.3
+
+require github.com/a/b v1.2.3
+`
+	if got := ParseGoDirective(contents); got != "1.21" {
+		t.Fatalf("ParseGoDirective() = %q, want 1.21", got)
+	}
+	if got := ParseToolchainDirective(contents); got != "go1.22.3" {
+		t.Fatalf("ParseToolchainDirective() = %q, want go1.22.3", got)
+	}
+	if got := ParseGoDirective("module example.com/foo\n"); got != "" {
+		t.Fatalf("expected empty go directive, got %q", got)
+	}
+}
+
+func TestCompareGoVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.21", "1.21.0", 0},
+		{"1.21", "1.22", -1},
+		{"1.22.1", "1.22", 1},
+		{"1.22rc1", "1.22", -1},
+		{"1.22rc1", "1.22rc2", -1},
+		{"go1.23", "1.22.5", 1},
+		{"1.9", "1.10", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareGoVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareGoVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestExceedsGoFloor(t *testing.T) {
+	tests := []struct {
+		floor, required string
+		want            bool
+	}{
+		{"1.21", "1.22", true},
+		{"1.21", "1.21.5", true},
+		{"1.22", "1.21", false},
+		{"1.21", "1.21", false},
+		{"", "1.22", false},
+		{"1.21", "", false},
+	}
+	for _, tt := range tests {
+		if got := ExceedsGoFloor(tt.floor, tt.required); got != tt.want {
+			t.Errorf("ExceedsGoFloor(%q, %q) = %v, want %v", tt.floor, tt.required, got, tt.want)
+		}
+	}
+}
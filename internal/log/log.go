@@ -0,0 +1,118 @@
+// Package log is a small leveled logger for diagnostics, written to stderr so
+// they never mix with the update list on stdout.
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is the severity of a log message. Loggers print messages at their
+// level and the more severe ones.
+type Level int
+
+const (
+	LevelError Level = iota
+	LevelWarn
+	LevelInfo
+	LevelDebug
+)
+
+// levelNames lists the --log-level values in Level order.
+var levelNames = []string{"error", "warn", "info", "debug"}
+
+func (l Level) String() string {
+	if l < LevelError || l > LevelDebug {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel validates a --log-level value; empty selects LevelWarn.
+func ParseLevel(s string) (Level, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return LevelWarn, nil
+	}
+	for i, name := range levelNames {
+		if s == name {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported --log-level value: %q (supported: %s)", s, strings.Join(levelNames, ", "))
+}
+
+// Logger writes messages at or above its level to w, one per line, prefixed
+// with the level, e.g. "warn: skipping ...". It is safe for concurrent use.
+type Logger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level Level
+}
+
+// New creates a logger writing messages up to level to w.
+func New(w io.Writer, level Level) *Logger {
+	return &Logger{w: w, level: level}
+}
+
+// Enabled reports whether messages at level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return level <= l.level
+}
+
+func (l *Logger) logf(level Level, format string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+	msg := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = fmt.Fprintf(l.w, "%s: %s\n", level, msg)
+}
+
+// Errorf logs a message at LevelError.
+func (l *Logger) Errorf(format string, args ...any) { l.logf(LevelError, format, args...) }
+
+// Warnf logs a message at LevelWarn.
+func (l *Logger) Warnf(format string, args ...any) { l.logf(LevelWarn, format, args...) }
+
+// Infof logs a message at LevelInfo.
+func (l *Logger) Infof(format string, args ...any) { l.logf(LevelInfo, format, args...) }
+
+// Debugf logs a message at LevelDebug.
+func (l *Logger) Debugf(format string, args ...any) { l.logf(LevelDebug, format, args...) }
+
+var (
+	defaultMu sync.Mutex
+	std       = New(os.Stderr, LevelWarn)
+)
+
+// Default returns the logger used by the package-level functions: warnings
+// and errors to stderr unless replaced with SetDefault.
+func Default() *Logger {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return std
+}
+
+// SetDefault replaces the logger used by the package-level functions.
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	std = l
+}
+
+// Errorf logs a message at LevelError with the default logger.
+func Errorf(format string, args ...any) { Default().Errorf(format, args...) }
+
+// Warnf logs a message at LevelWarn with the default logger.
+func Warnf(format string, args ...any) { Default().Warnf(format, args...) }
+
+// Infof logs a message at LevelInfo with the default logger.
+func Infof(format string, args ...any) { Default().Infof(format, args...) }
+
+// Debugf logs a message at LevelDebug with the default logger.
+func Debugf(format string, args ...any) { Default().Debugf(format, args...) }
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLogger_Levels(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, LevelInfo)
+
+	l.Errorf("broken %d", 1)
+	l.Warnf("skipping %s", "x")
+	l.Infof("scanning\n")
+	l.Debugf("hidden")
+
+	want := "error: broken 1\nwarn: skipping x\ninfo: scanning\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{"": LevelWarn, "error": LevelError, "WARN": LevelWarn, " info ": LevelInfo, "debug": LevelDebug}
+	for in, want := range tests {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("trace"); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	prev := Default()
+	defer SetDefault(prev)
+
+	var buf bytes.Buffer
+	SetDefault(New(&buf, LevelError))
+	Warnf("dropped")
+	Errorf("kept")
+	if buf.String() != "error: kept\n" {
+		t.Errorf("unexpected default logger output %q", buf.String())
+	}
+}
@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// NpmClient looks up publish times, versions and deprecations with
+// `npm view`, so the registries, scopes and credentials configured in .npmrc
+// apply as they do to the package manager itself. Each package's times are
+// fetched at most once per client.
+type NpmClient struct {
+	workDir  string
+	registry string
+	env      []string
+	runNpm   func(ctx context.Context, args ...string) ([]byte, error)
+	times    timeCache
+}
+
+// NewNpmClient creates a client that runs npm in workDir, with env appended
+// to the inherited environment. A non-empty registryURL replaces the
+// registry .npmrc configures, as --registry does.
+func NewNpmClient(workDir, registryURL string, env []string) *NpmClient {
+	c := &NpmClient{workDir: workDir, registry: registryURL, env: env}
+	c.runNpm = func(ctx context.Context, args ...string) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "npm", args...)
+		cmd.Dir = c.workDir
+		cmd.Env = append(os.Environ(), c.env...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := scanner.Output(cmd)
+		if err != nil {
+			if authErr := scanner.RegistryAuthError("npm", stderr.Bytes()); authErr != nil {
+				return nil, authErr
+			}
+			return nil, fmt.Errorf("npm %s failed: %w: %s", args[0], err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return out, nil
+	}
+	return c
+}
+
+// Registry returns the registry URL the client passes to npm, or "" for the
+// one .npmrc configures.
+func (c *NpmClient) Registry() string {
+	return c.registry
+}
+
+// PublishTime returns when version of the named package was published.
+func (c *NpmClient) PublishTime(ctx context.Context, name, version string) (string, error) {
+	return c.times.lookup(name, version, func(name string) (map[string]string, error) {
+		return c.fetchTimes(ctx, name)
+	})
+}
+
+// Versions returns every version of the named package listed on the
+// registry, from the same lookup as PublishTime.
+func (c *NpmClient) Versions(ctx context.Context, name string) ([]string, error) {
+	times, err := c.times.get(name, func(name string) (map[string]string, error) {
+		return c.fetchTimes(ctx, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(times))
+	for v := range times {
+		// The time map also records when the package was created and modified
+		if v != "created" && v != "modified" {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// Deprecation returns the deprecation message of version of the named
+// package, or "" if it isn't deprecated.
+func (c *NpmClient) Deprecation(ctx context.Context, name, version string) (string, error) {
+	out, err := c.view(ctx, name+"@"+version, "deprecated", "--json")
+	if err != nil {
+		return "", err
+	}
+	// npm prints nothing for a version that isn't deprecated
+	if len(bytes.TrimSpace(out)) == 0 {
+		return "", nil
+	}
+	// Usually a message, but some old packages set it to a boolean
+	var deprecated any
+	if err := json.Unmarshal(out, &deprecated); err != nil {
+		return "", fmt.Errorf("failed to decode npm view output: %w", err)
+	}
+	switch d := deprecated.(type) {
+	case string:
+		return d, nil
+	case bool:
+		if d {
+			return "deprecated", nil
+		}
+	}
+	return "", nil
+}
+
+// fetchTimes reads the "time" map of a package with `npm view <name> time`.
+func (c *NpmClient) fetchTimes(ctx context.Context, name string) (map[string]string, error) {
+	out, err := c.view(ctx, name, "time", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var times map[string]string
+	if err := json.Unmarshal(out, &times); err != nil {
+		return nil, fmt.Errorf("failed to decode npm view output: %w", err)
+	}
+	return times, nil
+}
+
+// view runs `npm view` with args against the client's registry.
+func (c *NpmClient) view(ctx context.Context, args ...string) ([]byte, error) {
+	args = append([]string{"view"}, args...)
+	if c.registry != "" {
+		args = append(args, "--registry="+c.registry)
+	}
+	return c.runNpm(ctx, args...)
+}
@@ -0,0 +1,143 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeNpm replaces c's npm command with one that answers `npm view` from
+// outputs, keyed by the space-joined arguments, and records the calls.
+func fakeNpm(c *NpmClient, outputs map[string]string) *[]string {
+	var mu sync.Mutex
+	var calls []string
+	c.runNpm = func(_ context.Context, args ...string) ([]byte, error) {
+		key := strings.Join(args, " ")
+		mu.Lock()
+		calls = append(calls, key)
+		mu.Unlock()
+		out, ok := outputs[key]
+		if !ok {
+			return nil, errors.New("npm view failed: E404")
+		}
+		return []byte(out), nil
+	}
+	return &calls
+}
+
+func TestNpmClient_PublishTime(t *testing.T) {
+	c := NewNpmClient(t.TempDir(), "", nil)
+	calls := fakeNpm(c, map[string]string{
+		"view @types/node time --json": `{"created":"2016-05-17T18:00:00.000Z","20.11.5":"2024-01-18T12:00:00.000Z","20.11.6":"2024-01-20T08:30:00.000Z"}`,
+	})
+	ctx := context.Background()
+
+	got, err := c.PublishTime(ctx, "@types/node", "20.11.5")
+	if err != nil {
+		t.Fatalf("PublishTime failed: %v", err)
+	}
+	if got != "2024-01-18T12:00:00.000Z" {
+		t.Errorf("unexpected publish time %q", got)
+	}
+
+	if got, _ := c.PublishTime(ctx, "@types/node", "20.11.6"); got != "2024-01-20T08:30:00.000Z" {
+		t.Errorf("unexpected publish time %q", got)
+	}
+	if got, _ := c.PublishTime(ctx, "@types/node", "99.0.0"); got != "" {
+		t.Errorf("expected no time for an unknown version, got %q", got)
+	}
+
+	if len(*calls) != 1 {
+		t.Errorf("expected the times to be fetched once, got %v", *calls)
+	}
+}
+
+func TestNpmClient_PublishTime_Error(t *testing.T) {
+	c := NewNpmClient(t.TempDir(), "", nil)
+	calls := fakeNpm(c, nil)
+	ctx := context.Background()
+
+	if _, err := c.PublishTime(ctx, "missing", "1.0.0"); err == nil {
+		t.Fatal("expected an error for a missing package")
+	}
+	if _, err := c.PublishTime(ctx, "missing", "1.0.1"); err == nil {
+		t.Fatal("expected the cached error on the second lookup")
+	}
+	if len(*calls) != 1 {
+		t.Errorf("expected failed lookups to be cached, got %v", *calls)
+	}
+}
+
+func TestNpmClient_Versions(t *testing.T) {
+	c := NewNpmClient(t.TempDir(), "", nil)
+	calls := fakeNpm(c, map[string]string{
+		"view express time --json": `{"created":"2010-12-29T19:38:25.450Z","modified":"2024-09-10T00:00:00.000Z","4.18.2":"2022-10-08T00:00:00.000Z","5.0.0":"2024-09-10T00:00:00.000Z"}`,
+	})
+	ctx := context.Background()
+
+	if _, err := c.PublishTime(ctx, "express", "5.0.0"); err != nil {
+		t.Fatalf("PublishTime failed: %v", err)
+	}
+	versions, err := c.Versions(ctx, "express")
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	slices.Sort(versions)
+	if !slices.Equal(versions, []string{"4.18.2", "5.0.0"}) {
+		t.Errorf("unexpected versions %v", versions)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("expected the cached times to be reused, got %v", *calls)
+	}
+}
+
+func TestNpmClient_Deprecation(t *testing.T) {
+	c := NewNpmClient(t.TempDir(), "", nil)
+	fakeNpm(c, map[string]string{
+		"view request@2.88.2 deprecated --json": `"request has been deprecated, see https://github.com/request/request/issues/3142"` + "\n",
+		"view request@2.88.0 deprecated --json": "",
+		"view request@1.0.0 deprecated --json":  "true\n",
+	})
+	ctx := context.Background()
+
+	got, err := c.Deprecation(ctx, "request", "2.88.2")
+	if err != nil {
+		t.Fatalf("Deprecation failed: %v", err)
+	}
+	if !strings.HasPrefix(got, "request has been deprecated") {
+		t.Errorf("unexpected deprecation message %q", got)
+	}
+	if got, err := c.Deprecation(ctx, "request", "2.88.0"); err != nil || got != "" {
+		t.Errorf("expected 2.88.0 not to be deprecated, got %q, %v", got, err)
+	}
+	if got, _ := c.Deprecation(ctx, "request", "1.0.0"); got != "deprecated" {
+		t.Errorf("expected a placeholder for a boolean deprecation, got %q", got)
+	}
+	if _, err := c.Deprecation(ctx, "missing", "1.0.0"); err == nil {
+		t.Error("expected an error for a missing package")
+	}
+}
+
+func TestNpmClient_Registry(t *testing.T) {
+	c := NewNpmClient(t.TempDir(), "https://npm.corp.example.com/", nil)
+	if got := c.Registry(); got != "https://npm.corp.example.com/" {
+		t.Errorf("Registry() = %q, want the --registry URL", got)
+	}
+	calls := fakeNpm(c, map[string]string{
+		"view react time --json --registry=https://npm.corp.example.com/": `{"18.2.0":"2022-06-14T19:46:38.369Z"}`,
+	})
+
+	got, err := c.PublishTime(context.Background(), "react", "18.2.0")
+	if err != nil {
+		t.Fatalf("PublishTime failed: %v", err)
+	}
+	if got != "2022-06-14T19:46:38.369Z" {
+		t.Errorf("unexpected publish time %q", got)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("expected one npm view call against the registry, got %v", *calls)
+	}
+}
@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultPyPI is the public Python Package Index.
+const DefaultPyPI = "https://pypi.org"
+
+// PyPIClient looks up release upload times from the JSON API of PyPI or of
+// an index that mirrors it. Each project's metadata is fetched at most once
+// per client.
+type PyPIClient struct {
+	baseURL    string
+	httpClient *http.Client
+	times      timeCache
+}
+
+// NewPyPIClient creates a client for the package index at indexURL, as given
+// to pip's --index-url, e.g. https://pypi.org/simple or an Artifactory or
+// Nexus repository; "" is the public PyPI. A nil httpClient uses one with a
+// 30 second timeout.
+func NewPyPIClient(indexURL string, httpClient *http.Client) *PyPIClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &PyPIClient{
+		baseURL:    pypiBaseURL(indexURL),
+		httpClient: httpClient,
+	}
+}
+
+// pypiBaseURL maps a PEP 503 simple index URL to the base of the JSON API
+// served beside it, which is where PyPI, Artifactory and Nexus put it:
+// https://pypi.org/simple/ becomes https://pypi.org.
+func pypiBaseURL(indexURL string) string {
+	base := strings.TrimRight(strings.TrimSpace(indexURL), "/")
+	if base == "" {
+		return DefaultPyPI
+	}
+	return strings.TrimSuffix(base, "/simple")
+}
+
+// BaseURL returns the base of the JSON API the client queries.
+func (c *PyPIClient) BaseURL() string {
+	return c.baseURL
+}
+
+// PublishTime returns when version of the named project was first uploaded.
+func (c *PyPIClient) PublishTime(ctx context.Context, name, version string) (string, error) {
+	return c.times.lookup(name, version, func(name string) (map[string]string, error) {
+		return c.fetchTimes(ctx, name)
+	})
+}
+
+// fetchTimes maps each release of a project to the upload time of its
+// earliest file, read from the JSON API.
+func (c *PyPIClient) fetchTimes(ctx context.Context, name string) (map[string]string, error) {
+	endpoint := fmt.Sprintf("%s/pypi/%s/json", c.baseURL, url.PathEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build PyPI request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PyPI: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PyPI returned status %d for %s", resp.StatusCode, name)
+	}
+
+	var doc struct {
+		Releases map[string][]struct {
+			UploadTime string `json:"upload_time_iso_8601"`
+		} `json:"releases"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode PyPI response: %w", err)
+	}
+
+	times := make(map[string]string, len(doc.Releases))
+	for version, files := range doc.Releases {
+		var earliest time.Time
+		for _, f := range files {
+			t, err := time.Parse(time.RFC3339Nano, f.UploadTime)
+			if err != nil {
+				continue
+			}
+			if earliest.IsZero() || t.Before(earliest) {
+				earliest = t
+			}
+		}
+		if !earliest.IsZero() {
+			times[version] = earliest.UTC().Format(time.RFC3339)
+		}
+	}
+	return times, nil
+}
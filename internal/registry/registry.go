@@ -0,0 +1,179 @@
+// Package registry looks up release metadata from package registries.
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/cooldown"
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// TimeLookup returns the RFC 3339 publish time of version of the named
+// package, or "" if the registry doesn't report one.
+type TimeLookup func(ctx context.Context, name, version string) (string, error)
+
+// maxConcurrentLookups bounds the number of registry requests in flight.
+const maxConcurrentLookups = 10
+
+// FillUpdateTimes sets Update.Time, and the current version's Time, on
+// modules that don't have them, querying lookup concurrently, and then drops
+// updates published within the last cooldownDays. Updates whose publish time
+// can't be determined are kept. A nil lookup leaves the times untouched.
+func FillUpdateTimes(ctx context.Context, modules []scanner.Module, lookup TimeLookup, cooldownDays int, now time.Time) []scanner.Module {
+	if lookup != nil {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, maxConcurrentLookups)
+		for i := range modules {
+			u := modules[i].Update
+			if u == nil || u.Version == "" {
+				continue
+			}
+			needUpdate := u.Time == ""
+			needCurrent := modules[i].Time == "" && modules[i].Version != ""
+			if !needUpdate && !needCurrent {
+				continue
+			}
+			wg.Add(1)
+			go func(m *scanner.Module) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				if needUpdate {
+					if t, err := lookup(ctx, m.Name, m.Update.Version); err == nil {
+						m.Update.Time = t
+					}
+				}
+				if needCurrent {
+					if t, err := lookup(ctx, m.Name, m.Version); err == nil {
+						m.Time = t
+					}
+				}
+			}(&modules[i])
+		}
+		wg.Wait()
+	}
+
+	if cooldownDays <= 0 {
+		return modules
+	}
+	kept := modules[:0]
+	for _, m := range modules {
+		if m.Update != nil && m.Update.Time != "" && !cooldown.Eligible(m.Update.Time, cooldownDays, now) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// FillTimesFor runs FillUpdateTimes under opts' context and cooldown, but
+// only when opts needs publish times; otherwise modules are returned as they
+// are, without querying the registry.
+func FillTimesFor(opts scanner.Options, modules []scanner.Module, lookup TimeLookup) []scanner.Module {
+	if !opts.NeedsPublishTimes() {
+		return modules
+	}
+	return FillUpdateTimes(opts.Ctx(), modules, lookup, opts.CooldownDays, time.Now())
+}
+
+// VersionsLookup returns every published version of the named package.
+type VersionsLookup func(ctx context.Context, name string) ([]string, error)
+
+// FillVersions sets Update.Versions on modules with an update, querying
+// lookup concurrently. Packages whose versions can't be fetched are left
+// without them.
+func FillVersions(ctx context.Context, modules []scanner.Module, lookup VersionsLookup) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentLookups)
+	for i := range modules {
+		if modules[i].Update == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(m *scanner.Module) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if versions, err := lookup(ctx, m.Name); err == nil {
+				m.Update.Versions = versions
+			}
+		}(&modules[i])
+	}
+	wg.Wait()
+}
+
+// DeprecationLookup returns the deprecation message of version of the named
+// package, or "" if it isn't deprecated.
+type DeprecationLookup func(ctx context.Context, name, version string) (string, error)
+
+// FillDeprecations sets Update.Deprecated on modules whose update the
+// registry marks as deprecated, querying lookup concurrently. Updates whose
+// status can't be determined are left unmarked.
+func FillDeprecations(ctx context.Context, modules []scanner.Module, lookup DeprecationLookup) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentLookups)
+	for i := range modules {
+		if modules[i].Update == nil || modules[i].Update.Version == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(m *scanner.Module) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if msg, err := lookup(ctx, m.Name, m.Update.Version); err == nil {
+				m.Update.Deprecated = msg
+			}
+		}(&modules[i])
+	}
+	wg.Wait()
+}
+
+// timeCache memoizes the per-version publish times of packages so each
+// package is fetched at most once, even by concurrent lookups.
+type timeCache struct {
+	mu       sync.Mutex
+	packages map[string]*packageTimes
+}
+
+// packageTimes holds the publish times of one package, keyed by version.
+type packageTimes struct {
+	once  sync.Once
+	times map[string]string
+	err   error
+}
+
+// lookup returns the publish time of version, calling fetch for the package's
+// times on first use.
+func (c *timeCache) lookup(name, version string, fetch func(name string) (map[string]string, error)) (string, error) {
+	times, err := c.get(name, fetch)
+	if err != nil {
+		return "", err
+	}
+	return times[version], nil
+}
+
+// get returns the publish times of the named package, calling fetch on first
+// use.
+func (c *timeCache) get(name string, fetch func(name string) (map[string]string, error)) (map[string]string, error) {
+	c.mu.Lock()
+	if c.packages == nil {
+		c.packages = make(map[string]*packageTimes)
+	}
+	pkg, ok := c.packages[name]
+	if !ok {
+		pkg = &packageTimes{}
+		c.packages[name] = pkg
+	}
+	c.mu.Unlock()
+
+	pkg.once.Do(func() {
+		pkg.times, pkg.err = fetch(name)
+	})
+	return pkg.times, pkg.err
+}
@@ -0,0 +1,75 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestFillUpdateTimes(t *testing.T) {
+	now := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
+	times := map[string]string{
+		"fresh": now.Add(-2 * 24 * time.Hour).Format(time.RFC3339),
+		"old":   now.Add(-30 * 24 * time.Hour).Format(time.RFC3339),
+	}
+	lookup := func(_ context.Context, name, version string) (string, error) {
+		if name == "broken" {
+			return "", errors.New("registry unavailable")
+		}
+		return times[name], nil
+	}
+	modules := func() []scanner.Module {
+		var mods []scanner.Module
+		for _, name := range []string{"fresh", "old", "unknown", "broken"} {
+			mods = append(mods, scanner.Module{Name: name, Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "2.0.0"}})
+		}
+		return mods
+	}
+
+	got := FillUpdateTimes(context.Background(), modules(), lookup, 0, now)
+	if len(got) != 4 {
+		t.Fatalf("expected all modules without a cooldown, got %d", len(got))
+	}
+	if got[0].Update.Time != times["fresh"] || got[1].Update.Time != times["old"] {
+		t.Errorf("expected update times to be filled, got %+v %+v", got[0].Update, got[1].Update)
+	}
+
+	got = FillUpdateTimes(context.Background(), modules(), lookup, 7, now)
+	var names []string
+	for _, m := range got {
+		names = append(names, m.Name)
+	}
+	if len(names) != 3 || names[0] != "old" || names[1] != "unknown" || names[2] != "broken" {
+		t.Errorf("expected only the fresh update to be dropped, got %v", names)
+	}
+}
+
+func TestFillUpdateTimes_CurrentVersionTime(t *testing.T) {
+	times := map[string]string{
+		"1.0.0": "2024-12-01T00:00:00Z",
+		"2.0.0": "2026-01-10T00:00:00Z",
+	}
+	lookup := func(_ context.Context, _, version string) (string, error) { return times[version], nil }
+
+	mods := []scanner.Module{
+		{Name: "a", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "2.0.0"}},
+		{Name: "b", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "2.0.0", Time: "2026-01-11T00:00:00Z"}},
+	}
+	got := FillUpdateTimes(context.Background(), mods, lookup, 0, time.Now())
+	if got[0].Time != times["1.0.0"] || got[0].Update.Time != times["2.0.0"] {
+		t.Errorf("expected both times to be filled, got %+v %+v", got[0], got[0].Update)
+	}
+	if got[1].Time != times["1.0.0"] || got[1].Update.Time != "2026-01-11T00:00:00Z" {
+		t.Errorf("expected only the current time to be filled, got %+v %+v", got[1], got[1].Update)
+	}
+}
+
+func TestFillUpdateTimes_NilLookup(t *testing.T) {
+	mods := []scanner.Module{{Name: "a", Update: &scanner.UpdateInfo{Version: "2.0.0"}}}
+	if got := FillUpdateTimes(context.Background(), mods, nil, 7, time.Now()); len(got) != 1 || got[0].Update.Time != "" {
+		t.Fatalf("expected modules to be untouched, got %+v", got)
+	}
+}
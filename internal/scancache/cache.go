@@ -0,0 +1,228 @@
+// Package scancache stores scan results on disk so repeated runs against an
+// unchanged project can skip the package manager.
+package scancache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/log"
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// DefaultTTL is how long a cached scan is reused.
+const DefaultTTL = 15 * time.Minute
+
+// Cache stores scan results on disk, keyed by the project's files and the
+// scan options.
+type Cache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// entry is a cached scan. It is gob encoded, which unlike the JSON encoding
+// of scanner.Module keeps fields such as FromGoMod and UpdateInfo.Versions.
+type entry struct {
+	ScannedAt time.Time
+	Modules   []scanner.Module
+	Warnings  []string
+}
+
+// DefaultDir returns the directory used for the scan cache
+// (os.UserCacheDir()/faro/scan).
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache dir: %w", err)
+	}
+	return filepath.Join(base, "faro", "scan"), nil
+}
+
+// New creates a cache rooted at dir. A non-positive ttl uses DefaultTTL and a
+// nil now uses time.Now.
+func New(dir string, ttl time.Duration, now func() time.Time) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &Cache{dir: dir, ttl: ttl, now: now}
+}
+
+// Key identifies a scan of the project in workDir by manager pm: the
+// contents of its files (manifests and lockfiles, missing ones included as
+// such) and the options that change what the scanner reports.
+func Key(pm, workDir string, files []string, opts scanner.Options) (string, error) {
+	abs, err := filepath.Abs(workDir)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, part := range []string{
+		pm, abs, opts.Manifest, opts.Filter,
+		strconv.FormatBool(opts.IncludeDev),
+		strconv.FormatBool(opts.IncludeTransitive),
+		strconv.Itoa(opts.CooldownDays),
+		strconv.FormatBool(opts.IncludeVulnScan),
+		strconv.FormatBool(opts.AllowGoBump),
+		strconv.FormatBool(opts.InRange),
+		strconv.FormatBool(opts.ListVersions),
+		strconv.FormatBool(opts.PublishTimes),
+		strconv.FormatBool(opts.Deprecations),
+		strings.Join(opts.Env, "\x00"),
+	} {
+		_, _ = fmt.Fprintf(h, "%s\x00", part)
+	}
+	for _, f := range files {
+		path := f
+		if !filepath.IsAbs(f) {
+			path = filepath.Join(workDir, f)
+		}
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			_, _ = fmt.Fprintf(h, "%s\x00%d\x00", f, len(data))
+			_, _ = h.Write(data)
+		case errors.Is(err, fs.ErrNotExist):
+			_, _ = fmt.Fprintf(h, "%s\x00-\x00", f)
+		default:
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Get returns the scan cached under key if there is one and it has not
+// expired.
+func (c *Cache) Get(key string) (modules []scanner.Module, warnings []string, ok bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, nil, false
+	}
+	var e entry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return nil, nil, false
+	}
+	if c.now().Sub(e.ScannedAt) > c.ttl {
+		return nil, nil, false
+	}
+	return e.Modules, e.Warnings, true
+}
+
+// Put stores a scan under key.
+func (c *Cache) Put(key string, modules []scanner.Module, warnings []string) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry{ScannedAt: c.now(), Modules: modules, Warnings: warnings}); err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	// Write atomically so concurrent runs never observe a partial entry.
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Clear removes every cached scan.
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// Dir returns the cache directory.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".gob")
+}
+
+// Scanner serves GetUpdates from a Cache, running the wrapped scanner only
+// when there is no fresh entry for the project and options.
+type Scanner struct {
+	inner    scanner.Scanner
+	cache    *Cache
+	pm       string
+	workDir  string
+	files    []string
+	warnings []string
+}
+
+// Wrap returns s with its scans cached in c. files are the manifests and
+// lockfiles of the pm project in workDir whose contents key the cache.
+func (c *Cache) Wrap(s scanner.Scanner, pm, workDir string, files []string) *Scanner {
+	return &Scanner{inner: s, cache: c, pm: pm, workDir: workDir, files: files}
+}
+
+// GetUpdates returns the cached scan for opts if the project files are
+// unchanged, and otherwise scans and caches the result.
+func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	s.warnings = nil
+	key, err := Key(s.pm, s.workDir, s.files, opts)
+	if err != nil {
+		log.Debugf("not caching the %s scan: %v", s.pm, err)
+		return s.scan(opts)
+	}
+	if modules, warnings, ok := s.cache.Get(key); ok {
+		log.Infof("using the cached %s scan of %s", s.pm, s.workDir)
+		s.warnings = warnings
+		return modules, nil
+	}
+
+	modules, err := s.scan(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Put(key, modules, s.warnings); err != nil {
+		log.Debugf("could not cache the %s scan: %v", s.pm, err)
+	}
+	return modules, nil
+}
+
+// scan runs the wrapped scanner, keeping its warnings.
+func (s *Scanner) scan(opts scanner.Options) ([]scanner.Module, error) {
+	modules, err := s.inner.GetUpdates(opts)
+	if reporter, ok := s.inner.(scanner.WarningReporter); ok {
+		s.warnings = reporter.Warnings()
+	}
+	return modules, err
+}
+
+// GetDependencyIndex returns the wrapped scanner's index; it is not cached.
+func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
+	return s.inner.GetDependencyIndex()
+}
+
+// Warnings returns the warnings of the last scan, cached or not.
+func (s *Scanner) Warnings() []string {
+	return s.warnings
+}
@@ -0,0 +1,117 @@
+package scancache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// countingScanner counts the scans it runs.
+type countingScanner struct {
+	modules []scanner.Module
+	calls   int
+}
+
+func (s *countingScanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	s.calls++
+	return s.modules, nil
+}
+
+func (s *countingScanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
+	return nil, nil
+}
+
+func (s *countingScanner) Warnings() []string {
+	return []string{"skipped a line"}
+}
+
+func TestScanner_CachesUntilFilesChange(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", "module example.com/app\n")
+	now := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
+	c := New(t.TempDir(), time.Hour, func() time.Time { return now })
+
+	inner := &countingScanner{modules: []scanner.Module{{
+		Name:      "github.com/pkg/errors",
+		Version:   "v0.8.0",
+		Update:    &scanner.UpdateInfo{Version: "v0.9.1", Versions: []string{"v0.8.0", "v0.9.1"}},
+		FromGoMod: true,
+	}}}
+	s := c.Wrap(inner, "go", dir, []string{"go.mod", "go.sum"})
+
+	for i := 0; i < 2; i++ {
+		modules, err := s.GetUpdates(scanner.Options{})
+		if err != nil {
+			t.Fatalf("GetUpdates failed: %v", err)
+		}
+		if len(modules) != 1 || !modules[0].FromGoMod || len(modules[0].Update.Versions) != 2 {
+			t.Fatalf("unexpected modules %+v", modules)
+		}
+		if got := s.Warnings(); len(got) != 1 {
+			t.Errorf("expected the scan's warning, got %v", got)
+		}
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected the second scan to be served from the cache, got %d scans", inner.calls)
+	}
+
+	// Different options scan again
+	if _, err := s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true}); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 2 {
+		t.Errorf("expected --all to miss the cache, got %d scans", inner.calls)
+	}
+
+	// Asking for publish times scans again, as the cached scan has none
+	if _, err := s.GetUpdates(scanner.Options{PublishTimes: true}); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 3 {
+		t.Errorf("expected publish times to miss the cache, got %d scans", inner.calls)
+	}
+
+	// So does creating a lockfile
+	writeFile(t, dir, "go.sum", "github.com/pkg/errors v0.8.0 h1:abc=\n")
+	if _, err := s.GetUpdates(scanner.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 4 {
+		t.Errorf("expected a changed lockfile to miss the cache, got %d scans", inner.calls)
+	}
+
+	// And expiry
+	now = now.Add(2 * time.Hour)
+	if _, err := s.GetUpdates(scanner.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if inner.calls != 5 {
+		t.Errorf("expected an expired entry to miss the cache, got %d scans", inner.calls)
+	}
+}
+
+func TestCache_Clear(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "scan"), 0, nil)
+	if err := c.Put("key", []scanner.Module{{Name: "left-pad"}}, nil); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, _, ok := c.Get("key"); !ok {
+		t.Fatal("expected a hit after put")
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if _, _, ok := c.Get("key"); ok {
+		t.Error("expected a miss after clear")
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,207 @@
+// Package bundler provides RubyGems (Bundler) package manager scanning functionality.
+package bundler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// Scanner implements scanner.Scanner for Bundler.
+type Scanner struct {
+	workDir      string
+	runBundleCmd func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// NewScanner creates a new Bundler scanner.
+func NewScanner(workDir string) *Scanner {
+	return &Scanner{
+		workDir: workDir,
+		runBundleCmd: func(ctx context.Context, args ...string) ([]byte, error) {
+			cmd := exec.CommandContext(ctx, "bundle", args...)
+			cmd.Dir = workDir
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+
+			out, err := scanner.Output(cmd)
+			if err != nil {
+				// bundle outdated exits with 1 when there are outdated gems
+				if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 && len(out) > 0 {
+					return out, nil
+				}
+				if stderr.Len() > 0 {
+					return nil, fmt.Errorf("%w, stderr: %s", err, stderr.String())
+				}
+				return nil, err
+			}
+			return out, nil
+		},
+	}
+}
+
+// outdatedGem is one line of `bundle outdated --parseable` output.
+type outdatedGem struct {
+	name      string
+	installed string
+	newest    string
+}
+
+// GetUpdates returns all gems that have available updates.
+func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	match, err := scanner.CompileFilter(opts.Filter, false)
+	if err != nil {
+		return nil, err
+	}
+
+	depIdx, err := s.GetDependencyIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Gemfile: %w", err)
+	}
+
+	ctx := opts.Ctx()
+	output, err := s.runBundleCmd(ctx, "outdated", "--parseable")
+	if err != nil {
+		return nil, scanner.CommandError(ctx, "bundle", fmt.Errorf("failed to run bundle outdated: %w", err))
+	}
+
+	modules := []scanner.Module{}
+	for _, g := range parseOutdated(string(output)) {
+		depInfo, ok := depIdx[g.name]
+		if !ok {
+			depInfo = scanner.DependencyInfo{Direct: false, Type: "transitive"}
+		}
+
+		// Gems not listed in the Gemfile need IncludeTransitive
+		if !opts.IncludeTransitive && !depInfo.Direct {
+			continue
+		}
+
+		// Apply filter
+		if !match(g.name) {
+			continue
+		}
+
+		modules = append(modules, scanner.Module{
+			Name:           g.name,
+			Version:        g.installed,
+			Direct:         depInfo.Direct,
+			DependencyType: depInfo.Type,
+			Update: &scanner.UpdateInfo{
+				Version: g.newest,
+			},
+		})
+	}
+
+	return modules, nil
+}
+
+// GetDependencyIndex returns a map of gems declared in the Gemfile to their
+// groups: "default" for gems outside any group, otherwise the comma-separated
+// group names (e.g. "development,test").
+func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
+	data, err := os.ReadFile(filepath.Join(s.workDir, "Gemfile"))
+	if err != nil {
+		return nil, err
+	}
+	return parseGemfile(string(data)), nil
+}
+
+var (
+	// outdatedLine matches "rack (newest 3.0.8, installed 2.2.7, requested ~> 2.2)".
+	outdatedLine = regexp.MustCompile(`^(\S+) \(newest ([^,)]+), installed ([^,)]+)`)
+
+	gemLine    = regexp.MustCompile(`^gem\s*\(?\s*["']([^"']+)["']`)
+	groupBlock = regexp.MustCompile(`^group\s*\(?\s*(.+?)\)?\s+do\b`)
+	groupsOpt  = regexp.MustCompile(`(?:\bgroups?:|:groups?\s*=>)\s*(\[[^\]]*\]|:\w+|["']\w+["'])`)
+	symbol     = regexp.MustCompile(`^(?::|["'])(\w+)["']?$`)
+	blockStart = regexp.MustCompile(`(\bdo\s*(\|[^|]*\|)?\s*$)|^(if|unless|case|begin)\b`)
+)
+
+// parseOutdated parses the output of `bundle outdated --parseable`. Git
+// sources report a revision after the version, which is dropped.
+func parseOutdated(output string) []outdatedGem {
+	var gems []outdatedGem
+	sc := bufio.NewScanner(strings.NewReader(output))
+	for sc.Scan() {
+		m := outdatedLine.FindStringSubmatch(strings.TrimSpace(sc.Text()))
+		if m == nil {
+			continue
+		}
+		gems = append(gems, outdatedGem{
+			name:      m[1],
+			newest:    strings.Fields(m[2])[0],
+			installed: strings.Fields(m[3])[0],
+		})
+	}
+	return gems
+}
+
+// parseGemfile returns the gems declared in a Gemfile, tracking
+// `group ... do` blocks and `group:`/`groups:` options.
+func parseGemfile(contents string) scanner.DependencyIndex {
+	idx := make(scanner.DependencyIndex)
+	// Each open block records its groups; non-group blocks record nil
+	var blocks [][]string
+
+	sc := bufio.NewScanner(strings.NewReader(contents))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		if line == "end" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+		if m := groupBlock.FindStringSubmatch(line); m != nil {
+			blocks = append(blocks, symbols(m[1]))
+			continue
+		}
+
+		if m := gemLine.FindStringSubmatch(line); m != nil {
+			var groups []string
+			for _, b := range blocks {
+				groups = append(groups, b...)
+			}
+			if opt := groupsOpt.FindStringSubmatch(line); opt != nil {
+				groups = append(groups, symbols(opt[1])...)
+			}
+			typ := "default"
+			if len(groups) > 0 {
+				typ = strings.Join(groups, ",")
+			}
+			idx[m[1]] = scanner.DependencyInfo{Direct: true, Type: typ}
+		}
+
+		if blockStart.MatchString(line) {
+			blocks = append(blocks, nil)
+		}
+	}
+	return idx
+}
+
+// symbols returns the names in a list like `:development, :test` or
+// `[:development, "test"]`, ignoring options such as `optional: true`.
+func symbols(list string) []string {
+	var names []string
+	for _, part := range strings.Split(strings.Trim(list, "[]"), ",") {
+		if m := symbol.FindStringSubmatch(strings.TrimSpace(part)); m != nil {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
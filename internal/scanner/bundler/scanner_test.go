@@ -0,0 +1,142 @@
+package bundler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+const sampleGemfile = `source "https://rubygems.org"
+
+ruby "3.2.2"
+
+gem "rails", "~> 7.0.4"
+gem 'pg', '~> 1.1'
+gem "bootsnap", require: false
+gem "sidekiq", group: :jobs
+
+platforms :mri, :windows do
+  gem "debug"
+end
+
+group :development, :test do
+  gem "rspec-rails" # test framework
+  if ENV["CI"]
+    gem "simplecov"
+  end
+end
+
+group :development do
+  gem "web-console"
+end
+
+gem "puma", ">= 5.0"
+`
+
+const sampleOutput = `Fetching gem metadata from https://rubygems.org/.........
+Resolving dependencies...
+
+rails (newest 7.1.2, installed 7.0.4.3, requested ~> 7.0.4)
+rack (newest 3.0.8, installed 2.2.7)
+rspec-rails (newest 6.1.0, installed 6.0.1)
+web-console (newest 4.2.1, installed 4.2.0)
+puma (newest 6.4.0, installed 5.6.5, requested >= 5.0)
+sidekiq (newest 7.2.0 1a2b3c4, installed 7.1.0 5d6e7f8)
+`
+
+func TestParseOutdated(t *testing.T) {
+	got := parseOutdated(sampleOutput)
+	want := []outdatedGem{
+		{name: "rails", installed: "7.0.4.3", newest: "7.1.2"},
+		{name: "rack", installed: "2.2.7", newest: "3.0.8"},
+		{name: "rspec-rails", installed: "6.0.1", newest: "6.1.0"},
+		{name: "web-console", installed: "4.2.0", newest: "4.2.1"},
+		{name: "puma", installed: "5.6.5", newest: "6.4.0"},
+		{name: "sidekiq", installed: "7.1.0", newest: "7.2.0"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseOutdated() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("gem %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseGemfile(t *testing.T) {
+	idx := parseGemfile(sampleGemfile)
+	want := map[string]string{
+		"rails":       "default",
+		"pg":          "default",
+		"bootsnap":    "default",
+		"sidekiq":     "jobs",
+		"debug":       "default",
+		"rspec-rails": "development,test",
+		"simplecov":   "development,test",
+		"web-console": "development",
+		"puma":        "default",
+	}
+	if len(idx) != len(want) {
+		t.Errorf("parseGemfile() = %+v, want %d gems", idx, len(want))
+	}
+	for name, typ := range want {
+		info, ok := idx[name]
+		if !ok || !info.Direct || info.Type != typ {
+			t.Errorf("idx[%q] = %+v, want direct %q", name, info, typ)
+		}
+	}
+}
+
+func TestGetUpdates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Gemfile"), []byte(sampleGemfile), 0644); err != nil {
+		t.Fatalf("failed to write Gemfile: %v", err)
+	}
+
+	var gotArgs []string
+	s := &Scanner{
+		workDir: dir,
+		runBundleCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			gotArgs = args
+			return []byte(sampleOutput), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if strings.Join(gotArgs, " ") != "outdated --parseable" {
+		t.Errorf("unexpected bundle args: %v", gotArgs)
+	}
+
+	var names []string
+	for _, m := range modules {
+		names = append(names, m.Name+"@"+m.Version+"->"+m.Update.Version+":"+m.DependencyType)
+	}
+	want := "rails@7.0.4.3->7.1.2:default rspec-rails@6.0.1->6.1.0:development,test web-console@4.2.0->4.2.1:development puma@5.6.5->6.4.0:default sidekiq@7.1.0->7.2.0:jobs"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("GetUpdates() = %s, want %s", got, want)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeTransitive: true, Filter: "rack"})
+	if err != nil {
+		t.Fatalf("GetUpdates(IncludeTransitive) failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Direct || modules[0].DependencyType != "transitive" {
+		t.Errorf("expected rack as a transitive gem, got %+v", modules)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{Filter: "^rails$|puma"})
+	if err != nil {
+		t.Fatalf("GetUpdates(Filter) failed: %v", err)
+	}
+	if len(modules) != 2 || modules[0].Name != "rails" || modules[1].Name != "puma" {
+		t.Errorf("expected rails and puma for '^rails$|puma', got %+v", modules)
+	}
+}
@@ -0,0 +1,385 @@
+// Package conda provides conda (environment.yml) package manager scanning functionality.
+package conda
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// EnvironmentFile is the conda environment file the scanner reads.
+const EnvironmentFile = "environment.yml"
+
+// maxConcurrentSearches bounds the number of `conda search` processes
+// running at once.
+const maxConcurrentSearches = 4
+
+// Scanner implements scanner.Scanner for conda environment files.
+type Scanner struct {
+	workDir     string
+	runCondaCmd func(ctx context.Context, args ...string) ([]byte, error)
+	warnings    []string
+}
+
+// NewScanner creates a new conda scanner.
+func NewScanner(workDir string) *Scanner {
+	return &Scanner{
+		workDir: workDir,
+		runCondaCmd: func(ctx context.Context, args ...string) ([]byte, error) {
+			cmd := exec.CommandContext(ctx, "conda", args...)
+			cmd.Dir = workDir
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+
+			out, err := scanner.Output(cmd)
+			if err != nil {
+				if stderr.Len() > 0 {
+					return nil, fmt.Errorf("%w, stderr: %s", err, stderr.String())
+				}
+				// conda search reports errors such as PackagesNotFoundError
+				// as JSON on stdout
+				if msg := searchError(out); msg != "" {
+					return nil, fmt.Errorf("%w: %s", err, msg)
+				}
+				return nil, err
+			}
+			return out, nil
+		},
+	}
+}
+
+// Spec is one entry of the dependencies list of an environment file.
+type Spec struct {
+	Channel string // Channel from a "channel::name" prefix, if any
+	Name    string
+	Version string // Exact version of a "name=1.2" or "name==1.2" pin; empty otherwise
+}
+
+// Environment holds the parts of an environment file the scanner uses.
+type Environment struct {
+	Channels     []string
+	Dependencies []Spec
+}
+
+// GetUpdates returns the pinned conda packages that have a newer version in
+// the environment's channels. Unpinned and range specs like "numpy>=1.20"
+// already resolve to the newest allowed version, so they are not reported;
+// packages under the pip: subsection are left to pip.
+func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	s.warnings = nil
+
+	match, err := scanner.CompileFilter(opts.Filter, true)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := s.readEnvironment()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", EnvironmentFile, err)
+	}
+
+	var specs []Spec
+	for _, spec := range env.Dependencies {
+		if spec.Version == "" || !match(spec.Name) {
+			continue
+		}
+		specs = append(specs, spec)
+	}
+
+	ctx := opts.Ctx()
+	latest := make([]string, len(specs))
+	errs := make([]error, len(specs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentSearches)
+	for i, spec := range specs {
+		wg.Add(1)
+		go func(i int, spec Spec) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			latest[i], errs[i] = s.latestVersion(ctx, env.Channels, spec)
+		}(i, spec)
+	}
+	wg.Wait()
+
+	modules := []scanner.Module{}
+	for i, spec := range specs {
+		if errs[i] != nil {
+			// A missing conda or an expired timeout affects every package
+			if ctx.Err() != nil || errors.Is(errs[i], exec.ErrNotFound) {
+				return nil, scanner.CommandError(ctx, "conda", fmt.Errorf("failed to run conda search: %w", errs[i]))
+			}
+			s.warnings = append(s.warnings, fmt.Sprintf("skipping %s: %v", spec.Name, errs[i]))
+			continue
+		}
+		if latest[i] == "" || compareVersions(latest[i], spec.Version) <= 0 {
+			continue
+		}
+		modules = append(modules, scanner.Module{
+			Name:           spec.Name,
+			Version:        spec.Version,
+			Direct:         true,
+			DependencyType: "main",
+			Update: &scanner.UpdateInfo{
+				Version: latest[i],
+			},
+		})
+	}
+
+	return modules, nil
+}
+
+// Warnings returns non-fatal problems from the last GetUpdates call.
+func (s *Scanner) Warnings() []string {
+	return s.warnings
+}
+
+// GetDependencyIndex returns the conda packages listed in the environment
+// file, all classified as "main".
+func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
+	env, err := s.readEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	idx := make(scanner.DependencyIndex, len(env.Dependencies))
+	for _, spec := range env.Dependencies {
+		idx[spec.Name] = scanner.DependencyInfo{Direct: true, Type: "main"}
+	}
+	return idx, nil
+}
+
+// readEnvironment parses the project's environment file.
+func (s *Scanner) readEnvironment() (Environment, error) {
+	data, err := os.ReadFile(filepath.Join(s.workDir, EnvironmentFile))
+	if err != nil {
+		return Environment{}, err
+	}
+	return ParseEnvironment(string(data)), nil
+}
+
+// latestVersion returns the newest release of spec's package, searching the
+// spec's own channel or else the environment's channels.
+func (s *Scanner) latestVersion(ctx context.Context, channels []string, spec Spec) (string, error) {
+	args := []string{"search", "--json"}
+	if spec.Channel != "" {
+		channels = []string{spec.Channel}
+	}
+	if len(channels) > 0 {
+		args = append(args, "--override-channels")
+		for _, c := range channels {
+			args = append(args, "-c", c)
+		}
+	}
+	args = append(args, spec.Name)
+
+	out, err := s.runCondaCmd(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	versions, err := parseSearch(out, spec.Name)
+	if err != nil {
+		return "", err
+	}
+	return newestStable(versions), nil
+}
+
+var (
+	// topLevelKey matches an unindented "key:" line.
+	topLevelKey = regexp.MustCompile(`^([\w-]+)\s*:`)
+
+	// listItem matches a "- item" line, capturing its indentation and item.
+	listItem = regexp.MustCompile(`^(\s*)-\s*(.*)$`)
+
+	// matchSpec splits a conda match spec such as "conda-forge::numpy=1.26.0=py311_0",
+	// "numpy==1.26.0", "numpy>=1.20" or "numpy 1.26.0 py311_0" into channel,
+	// name, operator and version.
+	matchSpec = regexp.MustCompile(`^(?:([\w./:-]+)::)?([A-Za-z0-9_.-]+)\s*(==|=|>=|<=|!=|~=|>|<|\s)?\s*([^\s=,|]*)`)
+
+	// exactVersion matches a version without wildcards.
+	exactVersion = regexp.MustCompile(`^[\w.+!]+$`)
+)
+
+// ParseEnvironment returns the channels and conda dependencies of an
+// environment file. Entries under the pip: subsection are skipped.
+func ParseEnvironment(contents string) Environment {
+	var env Environment
+	var section string
+	pipIndent := -1 // indentation of the "- pip:" item while inside it
+
+	sc := bufio.NewScanner(strings.NewReader(contents))
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		} else if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if m := topLevelKey.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			pipIndent = -1
+			continue
+		}
+
+		m := listItem.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent, item := len(m[1]), unquote(strings.TrimSpace(m[2]))
+		if pipIndent >= 0 {
+			if indent > pipIndent {
+				continue
+			}
+			pipIndent = -1
+		}
+
+		switch section {
+		case "channels":
+			env.Channels = append(env.Channels, item)
+		case "dependencies":
+			if strings.HasSuffix(item, ":") {
+				// A nested list such as "- pip:"
+				pipIndent = indent
+				continue
+			}
+			if spec, ok := parseSpec(item); ok {
+				env.Dependencies = append(env.Dependencies, spec)
+			}
+		}
+	}
+	return env
+}
+
+// parseSpec parses a conda match spec. Version is only set for exact pins.
+func parseSpec(item string) (Spec, bool) {
+	m := matchSpec.FindStringSubmatch(item)
+	if m == nil {
+		return Spec{}, false
+	}
+	spec := Spec{Channel: m[1], Name: m[2]}
+	op, version := strings.TrimSpace(m[3]), m[4]
+	rest := item[len(m[0]):]
+	if (op == "" || op == "=" || op == "==") && version != "" && exactVersion.MatchString(version) &&
+		!strings.ContainsAny(rest, ",|") {
+		spec.Version = version
+	}
+	return spec, true
+}
+
+// unquote strips YAML quotes around a list item.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// parseSearch returns the versions `conda search --json` lists for name.
+func parseSearch(output []byte, name string) ([]string, error) {
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse conda search output: %w", err)
+	}
+	if msg := searchError(output); msg != "" {
+		return nil, fmt.Errorf("%s", msg)
+	}
+
+	var builds []struct {
+		Version string `json:"version"`
+	}
+	if raw, ok := result[name]; ok {
+		if err := json.Unmarshal(raw, &builds); err != nil {
+			return nil, fmt.Errorf("failed to parse conda search output: %w", err)
+		}
+	}
+	versions := make([]string, 0, len(builds))
+	for _, b := range builds {
+		versions = append(versions, b.Version)
+	}
+	return versions, nil
+}
+
+// searchError returns the message of a JSON error reported by conda, if any.
+func searchError(output []byte) string {
+	var e struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(output, &e) != nil || e.Error == "" {
+		return ""
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Error
+}
+
+// newestStable returns the highest version that isn't a pre-release such as
+// "2.0.0rc1" or "1.0.0.dev0".
+func newestStable(versions []string) string {
+	var newest string
+	for _, v := range versions {
+		if isPrerelease(v) {
+			continue
+		}
+		if newest == "" || compareVersions(v, newest) > 0 {
+			newest = v
+		}
+	}
+	return newest
+}
+
+// prerelease matches the pre-release and development markers conda packages use.
+var prerelease = regexp.MustCompile(`(?i)(a|b|rc|alpha|beta|dev|pre)\d*$`)
+
+// isPrerelease reports whether any segment of v is a pre-release marker.
+func isPrerelease(v string) bool {
+	for _, seg := range strings.Split(v, ".") {
+		if prerelease.MatchString(seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareVersions compares dotted versions segment by segment, numerically
+// where both segments are numbers.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, errX := strconv.Atoi(x)
+		yn, errY := strconv.Atoi(y)
+		if errX == nil && errY == nil {
+			if xn != yn {
+				return xn - yn
+			}
+			continue
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
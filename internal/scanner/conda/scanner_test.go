@@ -0,0 +1,182 @@
+package conda
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+const sampleEnvironment = `name: analysis
+channels:
+  - conda-forge
+  - defaults
+dependencies:
+  - python=3.11
+  - numpy=1.26.0=py311h64a7726_0
+  - "pandas==2.1.0"
+  - scipy>=1.10
+  - matplotlib
+  - scikit-learn=1.3.*
+  - bioconda::samtools=1.17
+  - libblas 3.9.0 20_linux64_openblas
+  # - requests=2.0
+  - pip
+  - pip:
+    - requests==2.31.0
+    - black
+  - jupyterlab=4.0.5 # notebooks
+`
+
+func TestParseEnvironment(t *testing.T) {
+	env := ParseEnvironment(sampleEnvironment)
+
+	if got := strings.Join(env.Channels, ","); got != "conda-forge,defaults" {
+		t.Errorf("Channels = %s, want conda-forge,defaults", got)
+	}
+
+	want := []Spec{
+		{Name: "python", Version: "3.11"},
+		{Name: "numpy", Version: "1.26.0"},
+		{Name: "pandas", Version: "2.1.0"},
+		{Name: "scipy"},
+		{Name: "matplotlib"},
+		{Name: "scikit-learn"},
+		{Channel: "bioconda", Name: "samtools", Version: "1.17"},
+		{Name: "libblas", Version: "3.9.0"},
+		{Name: "pip"},
+		{Name: "jupyterlab", Version: "4.0.5"},
+	}
+	if len(env.Dependencies) != len(want) {
+		t.Fatalf("Dependencies = %+v, want %+v", env.Dependencies, want)
+	}
+	for i := range want {
+		if env.Dependencies[i] != want[i] {
+			t.Errorf("dependency %d = %+v, want %+v", i, env.Dependencies[i], want[i])
+		}
+	}
+}
+
+// searchOutput returns `conda search --json` output listing versions of name.
+func searchOutput(name string, versions ...string) []byte {
+	var entries []string
+	for _, v := range versions {
+		entries = append(entries, `{"name":"`+name+`","version":"`+v+`","build":"0"}`)
+	}
+	return []byte(`{"` + name + `":[` + strings.Join(entries, ",") + `]}`)
+}
+
+func TestGetUpdates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, EnvironmentFile), []byte(sampleEnvironment), 0644); err != nil {
+		t.Fatalf("failed to write environment.yml: %v", err)
+	}
+
+	available := map[string][]string{
+		"python":     {"3.10.0", "3.11.0", "3.12.1", "3.13.0rc1"},
+		"numpy":      {"1.25.2", "1.26.0", "1.26.4"},
+		"pandas":     {"2.1.0"},
+		"samtools":   {"1.17", "1.18"},
+		"libblas":    {"3.9.0"},
+		"jupyterlab": {"4.0.5", "4.0.10", "4.1.0a1"},
+	}
+	var searched []string
+	s := &Scanner{
+		workDir: dir,
+		runCondaCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			name := args[len(args)-1]
+			if name == "samtools" {
+				searched = append(searched, strings.Join(args, " "))
+			}
+			return searchOutput(name, available[name]...), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+
+	var got []string
+	for _, m := range modules {
+		got = append(got, m.Name+"@"+m.Version+"->"+m.Update.Version+":"+m.DependencyType)
+	}
+	want := "python@3.11->3.12.1:main numpy@1.26.0->1.26.4:main samtools@1.17->1.18:main jupyterlab@4.0.5->4.0.10:main"
+	if strings.Join(got, " ") != want {
+		t.Errorf("GetUpdates() = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	if len(searched) != 1 || searched[0] != "search --json --override-channels -c bioconda samtools" {
+		t.Errorf("expected samtools to be searched in its own channel, got %v", searched)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{Filter: "NUMPY"})
+	if err != nil {
+		t.Fatalf("GetUpdates(Filter) failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "numpy" {
+		t.Errorf("expected only numpy for filter NUMPY, got %+v", modules)
+	}
+}
+
+func TestGetUpdates_SearchErrors(t *testing.T) {
+	dir := t.TempDir()
+	env := "dependencies:\n  - numpy=1.26.0\n  - private-pkg=1.0\n"
+	if err := os.WriteFile(filepath.Join(dir, EnvironmentFile), []byte(env), 0644); err != nil {
+		t.Fatalf("failed to write environment.yml: %v", err)
+	}
+
+	s := &Scanner{
+		workDir: dir,
+		runCondaCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			if args[len(args)-1] == "private-pkg" {
+				return nil, errors.New("PackagesNotFoundError")
+			}
+			return searchOutput("numpy", "1.26.4"), nil
+		},
+	}
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "numpy" {
+		t.Errorf("expected numpy to still be reported, got %+v", modules)
+	}
+	if w := s.Warnings(); len(w) != 1 || !strings.Contains(w[0], "skipping private-pkg") {
+		t.Errorf("expected a warning for private-pkg, got %v", w)
+	}
+
+	s.runCondaCmd = func(context.Context, ...string) ([]byte, error) {
+		return nil, &exec.Error{Name: "conda", Err: exec.ErrNotFound}
+	}
+	var missing *scanner.MissingToolError
+	if _, err := s.GetUpdates(scanner.Options{}); !errors.As(err, &missing) {
+		t.Errorf("expected a MissingToolError without conda, got %v", err)
+	}
+}
+
+func TestGetDependencyIndex(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, EnvironmentFile), []byte(sampleEnvironment), 0644); err != nil {
+		t.Fatalf("failed to write environment.yml: %v", err)
+	}
+
+	idx, err := NewScanner(dir).GetDependencyIndex()
+	if err != nil {
+		t.Fatalf("GetDependencyIndex failed: %v", err)
+	}
+	if len(idx) != 10 {
+		t.Errorf("expected 10 conda packages, got %+v", idx)
+	}
+	if info := idx["matplotlib"]; !info.Direct || info.Type != "main" {
+		t.Errorf("idx[matplotlib] = %+v, want direct main", info)
+	}
+	if _, ok := idx["requests"]; ok {
+		t.Error("expected pip packages to be left out")
+	}
+}
@@ -0,0 +1,179 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// MissingToolError reports that a package manager's executable isn't installed.
+type MissingToolError struct {
+	Tool string
+	Err  error
+}
+
+func (e *MissingToolError) Error() string {
+	return fmt.Sprintf("%s not found in PATH; install it or use --manager", e.Tool)
+}
+
+func (e *MissingToolError) Unwrap() error {
+	return e.Err
+}
+
+// ToolError replaces err with a MissingToolError when it was caused by tool
+// not being found in PATH, and returns it unchanged otherwise. Scanners and
+// updaters use it on errors from running package manager commands.
+func ToolError(tool string, err error) error {
+	if err != nil && errors.Is(err, exec.ErrNotFound) {
+		return &MissingToolError{Tool: tool, Err: err}
+	}
+	return err
+}
+
+// CommandError wraps err from running tool under ctx. An expired or canceled
+// ctx is reported as such, since the command was killed rather than failing
+// on its own; other errors go through ToolError.
+func CommandError(ctx context.Context, tool string, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%s did not finish before the timeout; raise it with --timeout: %w", tool, ctx.Err())
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%s was canceled: %w", tool, ctx.Err())
+	}
+	return ToolError(tool, err)
+}
+
+// AuthError reports that a registry refused a Node package manager for lack
+// of credentials, typically a missing or expired token in .npmrc.
+type AuthError struct {
+	Tool   string
+	Detail string
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("%s could not authenticate with the package registry (%s); check the registry auth token in the project or user .npmrc", e.Tool, e.Detail)
+}
+
+// authMarkers are substrings npm, yarn and pnpm print when a registry
+// rejects a request for lack of credentials.
+var authMarkers = []string{
+	"E401", "E403", "ENEEDAUTH",
+	"ERR_PNPM_FETCH_401", "ERR_PNPM_FETCH_403",
+	"401 Unauthorized", "403 Forbidden",
+}
+
+// RegistryAuthError returns an *AuthError when output from tool reports a
+// registry authentication failure, and nil otherwise.
+func RegistryAuthError(tool string, output []byte) error {
+	for _, line := range strings.Split(string(output), "\n") {
+		for _, marker := range authMarkers {
+			if strings.Contains(line, marker) {
+				return &AuthError{Tool: tool, Detail: strings.TrimSpace(line)}
+			}
+		}
+	}
+	return nil
+}
+
+// commandLog receives a line for every command run through Output,
+// CombinedOutput and OutputStream; nil disables logging.
+var (
+	commandLogMu sync.Mutex
+	commandLog   io.Writer
+)
+
+// SetCommandLog directs a log of every package manager command run by the
+// scanners and updaters, with its directory and exit status, to w. A nil w
+// disables the log.
+func SetCommandLog(w io.Writer) {
+	commandLogMu.Lock()
+	defer commandLogMu.Unlock()
+	commandLog = w
+}
+
+// CommandLog returns the writer set by SetCommandLog, or nil.
+func CommandLog() io.Writer {
+	commandLogMu.Lock()
+	defer commandLogMu.Unlock()
+	return commandLog
+}
+
+// Output runs cmd like cmd.Output, logging it if a command log is set.
+func Output(cmd *exec.Cmd) ([]byte, error) {
+	out, err := cmd.Output()
+	logCommand(cmd, err)
+	return out, err
+}
+
+// CombinedOutput runs cmd like cmd.CombinedOutput, logging it if a command
+// log is set.
+func CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	out, err := cmd.CombinedOutput()
+	logCommand(cmd, err)
+	return out, err
+}
+
+// OutputStream starts cmd and returns its standard output to be read while
+// it runs, rather than buffered whole as by Output. Closing the stream waits
+// for cmd to exit and returns its error, an *exec.ExitError carrying stderr
+// as with cmd.Output; cmd is logged then.
+func OutputStream(cmd *exec.Cmd) (io.ReadCloser, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stream := &commandStream{ReadCloser: stdout, cmd: cmd}
+	cmd.Stderr = &stream.stderr
+	if err := cmd.Start(); err != nil {
+		logCommand(cmd, err)
+		return nil, err
+	}
+	return stream, nil
+}
+
+// commandStream is the stdout of a command started by OutputStream.
+type commandStream struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr bytes.Buffer
+}
+
+func (s *commandStream) Close() error {
+	// Wait must not run before the pipe is read to the end
+	_, _ = io.Copy(io.Discard, s.ReadCloser)
+	err := s.cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitErr.Stderr = s.stderr.Bytes()
+	}
+	logCommand(s.cmd, err)
+	return err
+}
+
+// logCommand writes cmd and how it ended to the command log, e.g.
+// "$ npm outdated --json (in /app): exit status 1".
+func logCommand(cmd *exec.Cmd, err error) {
+	commandLogMu.Lock()
+	defer commandLogMu.Unlock()
+	if commandLog == nil {
+		return
+	}
+
+	status := "exit status 0"
+	if err != nil {
+		status = err.Error()
+	}
+	line := "$ " + strings.Join(cmd.Args, " ")
+	if cmd.Dir != "" {
+		line += " (in " + cmd.Dir + ")"
+	}
+	_, _ = fmt.Fprintf(commandLog, "%s: %s\n", line, status)
+}
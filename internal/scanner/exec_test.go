@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCommandLog(t *testing.T) {
+	var log bytes.Buffer
+	SetCommandLog(&log)
+	defer SetCommandLog(nil)
+
+	dir := t.TempDir()
+	cmd := exec.Command("go", "env", "GOOS")
+	cmd.Dir = dir
+	if _, err := Output(cmd); err != nil {
+		t.Fatalf("go env failed: %v", err)
+	}
+	if _, err := CombinedOutput(exec.Command("go", "no-such-command")); err == nil {
+		t.Fatal("expected an unknown go command to fail")
+	}
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 logged commands, got %q", log.String())
+	}
+	if want := "$ go env GOOS (in " + dir + "): exit status 0"; lines[0] != want {
+		t.Errorf("got %q, want %q", lines[0], want)
+	}
+	if !strings.HasPrefix(lines[1], "$ go no-such-command: exit status ") || strings.HasSuffix(lines[1], " 0") {
+		t.Errorf("expected the failing exit status, got %q", lines[1])
+	}
+
+	// Nothing is logged once the log is unset
+	SetCommandLog(nil)
+	log.Reset()
+	if _, err := Output(exec.Command("go", "env", "GOOS")); err != nil {
+		t.Fatalf("go env failed: %v", err)
+	}
+	if log.Len() != 0 {
+		t.Errorf("expected no log without a command log, got %q", log.String())
+	}
+}
+
+func TestOutputStream(t *testing.T) {
+	var log bytes.Buffer
+	SetCommandLog(&log)
+	defer SetCommandLog(nil)
+
+	stream, err := OutputStream(exec.Command("go", "env", "GOOS", "GOARCH"))
+	if err != nil {
+		t.Fatalf("OutputStream failed: %v", err)
+	}
+	out, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("reading the stream failed: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("go env failed: %v", err)
+	}
+	if lines := strings.Fields(string(out)); len(lines) != 2 {
+		t.Errorf("expected GOOS and GOARCH, got %q", out)
+	}
+
+	// Closing without reading still waits for the command and reports how it ended
+	stream, err = OutputStream(exec.Command("go", "no-such-command"))
+	if err != nil {
+		t.Fatalf("OutputStream failed: %v", err)
+	}
+	var exitErr *exec.ExitError
+	if err := stream.Close(); !errors.As(err, &exitErr) || len(exitErr.Stderr) == 0 {
+		t.Errorf("expected an exit error carrying stderr, got %v", err)
+	}
+
+	if got := strings.Count(log.String(), "\n"); got != 2 {
+		t.Errorf("expected both commands to be logged, got %q", log.String())
+	}
+}
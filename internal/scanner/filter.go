@@ -0,0 +1,60 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NameMatcher reports whether a package name is selected by Options.Filter.
+type NameMatcher func(name string) bool
+
+// CompileFilter returns a NameMatcher for an Options.Filter pattern. A name
+// matches when it contains the pattern as a substring or matches it as a
+// regular expression, so both "react" and "react|vue" work. With ignoreCase,
+// used by ecosystems whose package names are case-insensitive (npm, PyPI),
+// both comparisons ignore case. An empty pattern matches every name; a
+// pattern that isn't a valid regular expression is an error.
+//
+// A pattern ending in "/" without other regex syntax than dots, such as
+// "golang.org/x/" or "@types/", is a path prefix instead: it matches only
+// names that start with it, so "golang.org/x/" selects golang.org/x/net but
+// neither google.golang.org/protobuf nor example.com/golang.org/x/y.
+func CompileFilter(pattern string, ignoreCase bool) (NameMatcher, error) {
+	if pattern == "" {
+		return func(string) bool { return true }, nil
+	}
+
+	if strings.HasSuffix(pattern, "/") && !strings.ContainsAny(pattern, `\^$|()[]{}*+?`) {
+		prefix := pattern
+		if ignoreCase {
+			prefix = strings.ToLower(prefix)
+		}
+		return func(name string) bool {
+			if ignoreCase {
+				name = strings.ToLower(name)
+			}
+			return strings.HasPrefix(name, prefix)
+		}, nil
+	}
+
+	expr := pattern
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter pattern: %w", err)
+	}
+
+	return func(name string) bool {
+		if ignoreCase {
+			if strings.Contains(strings.ToLower(name), strings.ToLower(pattern)) {
+				return true
+			}
+		} else if strings.Contains(name, pattern) {
+			return true
+		}
+		return re.MatchString(name)
+	}, nil
+}
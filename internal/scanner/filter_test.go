@@ -0,0 +1,52 @@
+package scanner
+
+import "testing"
+
+func TestCompileFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		ignoreCase bool
+		matches    []string
+		rejects    []string
+	}{
+		{"empty", "", false, []string{"anything"}, nil},
+		{"substring", "react", false, []string{"react", "react-dom", "@types/react"}, []string{"vue"}},
+		{"alternation", "react|vue", false, []string{"react-dom", "vue"}, []string{"svelte"}},
+		{"anchored", "^github.com/spf13/", false, []string{"github.com/spf13/cobra"}, []string{"golang.org/x/github.com/spf13/"}},
+		{"case sensitive", "django", false, []string{"django"}, []string{"Django"}},
+		{"ignore case", "django|FLASK", true, []string{"Django", "flask"}, []string{"requests"}},
+		// Substring matches still work when the pattern has regex metacharacters
+		{"literal dots", "golang.org/x", false, []string{"golang.org/x/text"}, []string{"example.com/x"}},
+		// A trailing slash selects a path prefix
+		{"path prefix", "golang.org/x/", false,
+			[]string{"golang.org/x/net", "golang.org/x/tools/gopls"},
+			[]string{"google.golang.org/protobuf", "example.com/golang.org/x/y", "golangXorg/x/net", "golang.org/x"}},
+		{"scope prefix", "@Types/", true, []string{"@types/node"}, []string{"types/node", "@typescript-eslint/parser"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := CompileFilter(tt.pattern, tt.ignoreCase)
+			if err != nil {
+				t.Fatalf("CompileFilter(%q) error: %v", tt.pattern, err)
+			}
+			for _, name := range tt.matches {
+				if !match(name) {
+					t.Errorf("%q should match %q", tt.pattern, name)
+				}
+			}
+			for _, name := range tt.rejects {
+				if match(name) {
+					t.Errorf("%q should not match %q", tt.pattern, name)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileFilter_InvalidPattern(t *testing.T) {
+	if _, err := CompileFilter("react(", false); err == nil {
+		t.Fatal("expected an error for an invalid regex")
+	}
+}
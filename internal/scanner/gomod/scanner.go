@@ -3,11 +3,15 @@ package gomod
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,60 +22,300 @@ import (
 
 // Scanner implements scanner.Scanner for Go modules.
 type Scanner struct {
-	workDir        string
-	goModPath      string
-	listAllModules func() ([]byte, error)
+	workDir         string
+	goModPath       string
+	listAllModules  func(ctx context.Context) (io.ReadCloser, error)
+	queryGoVersions func(ctx context.Context, specs []string) ([]byte, error)
+	listVersions    func(ctx context.Context, paths []string) ([]byte, error)
+	modGraph        func(ctx context.Context) ([]byte, error)
+	warnings        []string
+	env             []string // Extra KEY=VALUE environment for go commands
 }
 
 // goModule is the internal representation from `go list` output.
 type goModule struct {
-	Path     string    `json:"Path"`
-	Version  string    `json:"Version"`
-	Time     string    `json:"Time"`
-	Update   *goModule `json:"Update"`
-	Indirect bool      `json:"Indirect"`
+	Path      string         `json:"Path"`
+	Version   string         `json:"Version"`
+	Time      string         `json:"Time"`
+	Update    *goModule      `json:"Update"`
+	Indirect  bool           `json:"Indirect"`
+	GoVersion string         `json:"GoVersion"`
+	Versions  []string       `json:"Versions"`
+	Retracted []string       `json:"Retracted"` // Rationale of a retraction, if the version is retracted
+	Error     *goModuleError `json:"Error"`
+}
+
+// goModuleError is the error go list reports for a module it can't resolve.
+type goModuleError struct {
+	Err string `json:"Err"`
 }
 
 // NewScanner creates a new Go module scanner.
 func NewScanner(workDir string) *Scanner {
-	return &Scanner{
+	s := &Scanner{
 		workDir:   workDir,
 		goModPath: filepath.Join(workDir, "go.mod"),
-		listAllModules: func() ([]byte, error) {
-			cmd := exec.Command("go", "list", "-m", "-u", "-json", "all")
-			cmd.Dir = workDir
-			return cmd.Output()
-		},
 	}
+	s.listAllModules = func(ctx context.Context) (io.ReadCloser, error) {
+		return scanner.OutputStream(s.command(ctx, []string{"list", "-m", "-u", "-e", "-json"}, "all"))
+	}
+	s.queryGoVersions = func(ctx context.Context, specs []string) ([]byte, error) {
+		return scanner.Output(s.command(ctx, []string{"list", "-m", "-e", "-json"}, specs...))
+	}
+	s.listVersions = func(ctx context.Context, paths []string) ([]byte, error) {
+		return scanner.Output(s.command(ctx, []string{"list", "-m", "-e", "-versions", "-json"}, paths...))
+	}
+	s.modGraph = func(ctx context.Context) ([]byte, error) {
+		return scanner.Output(s.command(ctx, []string{"mod", "graph"}))
+	}
+	return s
+}
+
+// command builds a go command for the scanned go.mod: it runs in the
+// go.mod's directory, with -modfile after the subcommand flags when the
+// file has another name, followed by args.
+func (s *Scanner) command(ctx context.Context, subcommand []string, args ...string) *exec.Cmd {
+	full := slices.Clone(subcommand)
+	if name := filepath.Base(s.goModPath); name != "go.mod" {
+		full = append(full, "-modfile="+name)
+	}
+	return goCommand(ctx, filepath.Dir(s.goModPath), s.env, append(full, args...)...)
+}
+
+// goCommand builds a go command run in workDir. It inherits the parent
+// environment (GOPROXY, GOFLAGS, GONOSUMDB, GOPRIVATE, netrc auth, ...) with
+// env appended, so entries in env override inherited ones.
+func goCommand(ctx context.Context, workDir string, env []string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
 }
 
 // GetUpdates returns all Go modules that have available updates.
+// With IncludeVulnScan, modules without an update are returned too.
 func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	s.warnings = nil
+	s.env = opts.Env
+	s.goModPath = filepath.Join(s.workDir, "go.mod")
+	if opts.Manifest != "" {
+		s.goModPath = opts.Manifest
+		if !filepath.IsAbs(opts.Manifest) {
+			s.goModPath = filepath.Join(s.workDir, opts.Manifest)
+		}
+	}
+
 	idx, err := gomod.ReadRequireIndex(s.goModPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read go.mod: %w", err)
 	}
 
-	var filterRegex *regexp.Regexp
-	if opts.Filter != "" {
-		compiled, err := regexp.Compile(opts.Filter)
+	match, err := scanner.CompileFilter(opts.Filter, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := opts.Ctx()
+	// The module graph can be large, so it is decoded as go list prints it
+	output, err := s.listAllModules(ctx)
+	if err != nil {
+		return nil, scanner.CommandError(ctx, "go", fmt.Errorf("failed to run go list: %w", err))
+	}
+	goModules, decodeErr := decodeGoListModules(output)
+	if err := output.Close(); err != nil {
+		return nil, scanner.CommandError(ctx, "go", fmt.Errorf("failed to run go list: %w", err))
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	goModules = s.dropBroken(goModules)
+	s.dropRetractedUpdates(goModules)
+
+	// Replaced modules (local paths, forks) aren't upgraded through go get
+	replaces, err := gomod.ReadReplaces(s.goModPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	goModules = dropReplaced(goModules, replaces)
+
+	modules := s.annotateAndFilter(goModules, idx, opts, match, time.Now())
+	if !opts.AllowGoBump && len(modules) > 0 {
+		floor, err := gomod.ReadGoDirective(s.goModPath)
 		if err != nil {
-			return nil, fmt.Errorf("invalid filter pattern: %w", err)
+			return nil, fmt.Errorf("failed to read go.mod: %w", err)
+		}
+		modules = s.enforceGoFloor(ctx, modules, floor)
+	}
+	if opts.ListVersions {
+		s.fillVersions(ctx, modules)
+	}
+	if opts.IncludeTransitive {
+		s.fillRequiredBy(ctx, modules, idx)
+	}
+	return modules, nil
+}
+
+// fillRequiredBy sets RequiredBy on indirect and transitive modules to the
+// direct requirements that lead to them in `go mod graph`. It only runs with
+// IncludeTransitive, which lists the transitive modules this is meant to explain.
+func (s *Scanner) fillRequiredBy(ctx context.Context, modules []scanner.Module, idx gomod.RequireIndex) {
+	needed := false
+	for _, m := range modules {
+		needed = needed || !m.Direct
+	}
+	if !needed {
+		return
+	}
+
+	output, err := s.modGraph(ctx)
+	if err != nil {
+		s.warnings = append(s.warnings, fmt.Sprintf("could not read the module graph: %v", err))
+		return
+	}
+	parents := reverseModGraph(output)
+	for i := range modules {
+		if !modules[i].Direct {
+			modules[i].RequiredBy = requiredBy(parents, modules[i].Name, idx)
+		}
+	}
+}
+
+// reverseModGraph parses `go mod graph` output, one "from to" edge per line,
+// into a map from each module path to the paths that require it. Versions
+// are dropped; the main module is listed under "".
+func reverseModGraph(data []byte) map[string][]string {
+	parents := make(map[string][]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		from, to, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		fromPath, fromVersion, _ := strings.Cut(from, "@")
+		if fromVersion == "" {
+			fromPath = "" // The main module
+		}
+		toPath, _, _ := strings.Cut(to, "@")
+		if !slices.Contains(parents[toPath], fromPath) {
+			parents[toPath] = append(parents[toPath], fromPath)
+		}
+	}
+	return parents
+}
+
+// requiredBy walks parents up from path and returns, sorted, the direct
+// requirements of go.mod it passes through.
+func requiredBy(parents map[string][]string, path string, idx gomod.RequireIndex) []string {
+	var direct []string
+	seen := map[string]bool{path: true}
+	queue := []string{path}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, p := range parents[cur] {
+			if p == "" || seen[p] {
+				continue
+			}
+			seen[p] = true
+			if indirect, ok := idx[p]; ok && !indirect {
+				direct = append(direct, p)
+				continue
+			}
+			queue = append(queue, p)
+		}
+	}
+	sort.Strings(direct)
+	return direct
+}
+
+// fillVersions sets Update.Versions from the module proxy's version lists
+// (the @v/list endpoint, as read by `go list -m -versions`).
+func (s *Scanner) fillVersions(ctx context.Context, modules []scanner.Module) {
+	var paths []string
+	for _, m := range modules {
+		if m.Update != nil {
+			paths = append(paths, m.Name)
 		}
-		filterRegex = compiled
+	}
+	if len(paths) == 0 {
+		return
 	}
 
-	output, err := s.listAllModules()
+	output, err := s.listVersions(ctx, paths)
+	if err != nil {
+		s.warnings = append(s.warnings, fmt.Sprintf("could not list module versions: %v", err))
+		return
+	}
+	listed, err := decodeGoListModules(bytes.NewReader(output))
 	if err != nil {
-		return nil, fmt.Errorf("failed to run go list: %w", err)
+		s.warnings = append(s.warnings, fmt.Sprintf("could not list module versions: %v", err))
+		return
 	}
 
-	goModules, err := decodeGoListModules(output)
+	versions := make(map[string][]string, len(listed))
+	for _, l := range listed {
+		versions[l.Path] = l.Versions
+	}
+	for i := range modules {
+		if modules[i].Update != nil {
+			modules[i].Update.Versions = versions[modules[i].Name]
+		}
+	}
+}
+
+// Warnings returns non-fatal problems from the last GetUpdates call.
+func (s *Scanner) Warnings() []string {
+	return s.warnings
+}
+
+// enforceGoFloor drops updates whose go.mod requires a newer Go version than
+// floor, since `go get` would raise the project's go directive to match.
+func (s *Scanner) enforceGoFloor(ctx context.Context, modules []scanner.Module, floor string) []scanner.Module {
+	if floor == "" {
+		return modules
+	}
+
+	specs := make([]string, 0, len(modules))
+	for _, m := range modules {
+		if m.Update != nil {
+			specs = append(specs, m.Name+"@"+m.Update.Version)
+		}
+	}
+	if len(specs) == 0 {
+		return modules
+	}
+
+	output, err := s.queryGoVersions(ctx, specs)
 	if err != nil {
-		return nil, err
+		s.warnings = append(s.warnings, fmt.Sprintf("could not check Go version requirements of updates: %v", err))
+		return modules
+	}
+	queried, err := decodeGoListModules(bytes.NewReader(output))
+	if err != nil {
+		s.warnings = append(s.warnings, fmt.Sprintf("could not check Go version requirements of updates: %v", err))
+		return modules
 	}
 
-	return s.annotateAndFilter(goModules, idx, opts, filterRegex, time.Now()), nil
+	required := make(map[string]string, len(queried))
+	for _, q := range queried {
+		required[q.Path+"@"+q.Version] = q.GoVersion
+	}
+
+	kept := make([]scanner.Module, 0, len(modules))
+	for _, m := range modules {
+		if m.Update == nil {
+			kept = append(kept, m)
+			continue
+		}
+		goVersion := required[m.Name+"@"+m.Update.Version]
+		if gomod.ExceedsGoFloor(floor, goVersion) {
+			s.warnings = append(s.warnings, fmt.Sprintf(
+				"skipping %s %s: requires go %s (project uses go %s); use --allow-go-bump to include it",
+				m.Name, m.Update.Version, goVersion, floor))
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
 }
 
 // GetDependencyIndex returns a map of Go module paths to their dependency information.
@@ -95,9 +339,55 @@ func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
 	return depIdx, nil
 }
 
-// decodeGoListModules decodes the JSON stream output from `go list -m -u -json all`.
-func decodeGoListModules(data []byte) ([]goModule, error) {
-	decoder := json.NewDecoder(bytes.NewReader(data))
+// dropBroken removes modules that go list failed to resolve, recording a
+// warning for each, so one bad module doesn't hide the rest of the graph.
+func (s *Scanner) dropBroken(modules []goModule) []goModule {
+	kept := modules[:0]
+	for _, m := range modules {
+		if m.Error == nil && m.Update != nil && m.Update.Error != nil {
+			m.Error = m.Update.Error
+		}
+		if m.Error != nil {
+			s.warnings = append(s.warnings, fmt.Sprintf("skipping %s: %s", m.Path, m.Error.Err))
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// dropRetractedUpdates clears updates to versions their authors retracted,
+// recording a warning for each. go list skips retracted versions when looking
+// for updates, but a proxy or GOFLAGS=-retracted can still surface them.
+func (s *Scanner) dropRetractedUpdates(modules []goModule) {
+	for i, m := range modules {
+		if m.Update == nil || len(m.Update.Retracted) == 0 {
+			continue
+		}
+		s.warnings = append(s.warnings, fmt.Sprintf("ignoring update of %s to %s: retracted (%s)",
+			m.Path, m.Update.Version, strings.Join(m.Update.Retracted, "; ")))
+		modules[i].Update = nil
+	}
+}
+
+// dropReplaced removes modules covered by a replace directive.
+func dropReplaced(modules []goModule, replaces gomod.ReplaceIndex) []goModule {
+	if len(replaces) == 0 {
+		return modules
+	}
+	kept := modules[:0]
+	for _, m := range modules {
+		if !replaces.Replaces(m.Path, m.Version) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+// decodeGoListModules decodes the JSON stream output from `go list -m -u -json all`,
+// one module at a time as r yields it.
+func decodeGoListModules(r io.Reader) ([]goModule, error) {
+	decoder := json.NewDecoder(r)
 	var modules []goModule
 	for decoder.More() {
 		var m goModule
@@ -114,16 +404,20 @@ func (s *Scanner) annotateAndFilter(
 	modules []goModule,
 	idx gomod.RequireIndex,
 	opts scanner.Options,
-	filterRegex *regexp.Regexp,
+	match scanner.NameMatcher,
 	now time.Time,
 ) []scanner.Module {
 	out := make([]scanner.Module, 0, len(modules))
 	for _, m := range modules {
-		if m.Update == nil {
+		// Up-to-date modules are only kept for vulnerability scans; the
+		// main module has no version and is never kept
+		if m.Update == nil && (!opts.IncludeVulnScan || m.Version == "") {
 			continue
 		}
 
-		// Override classification based on go.mod
+		// Override classification based on go.mod. Paths are matched exactly,
+		// /vN suffix included: github.com/foo/bar and github.com/foo/bar/v2
+		// are different modules and may both be required
 		fromGoMod := false
 		indirect := m.Indirect
 		depType := "transitive"
@@ -137,24 +431,18 @@ func (s *Scanner) annotateAndFilter(
 			}
 		}
 
-		// Filter out transitive dependencies if not including all
-		if !opts.IncludeAll && !fromGoMod {
+		// Filter out transitive dependencies unless asked for
+		if !opts.IncludeTransitive && !fromGoMod {
 			continue
 		}
 
 		// Apply filter
-		if opts.Filter != "" {
-			match := strings.Contains(m.Path, opts.Filter)
-			if !match && filterRegex != nil {
-				match = filterRegex.MatchString(m.Path)
-			}
-			if !match {
-				continue
-			}
+		if !match(m.Path) {
+			continue
 		}
 
 		// Apply cooldown
-		if opts.CooldownDays > 0 {
+		if opts.CooldownDays > 0 && m.Update != nil {
 			if !cooldown.Eligible(m.Update.Time, opts.CooldownDays, now) {
 				continue
 			}
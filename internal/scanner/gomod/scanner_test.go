@@ -1,15 +1,34 @@
 package gomod
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/pragmaticivan/faro/internal/gomod"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
+// stream adapts fake go list output to the listAllModules seam.
+func stream(output func(context.Context) ([]byte, error)) func(context.Context) (io.ReadCloser, error) {
+	return func(ctx context.Context) (io.ReadCloser, error) {
+		data, err := output(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
 func TestGetUpdates(t *testing.T) {
 	// 1. Setup go.mod
 	tmpDir := t.TempDir()
@@ -63,7 +82,7 @@ require (
 
 	// 3. Initialize Scanner
 	s := NewScanner(tmpDir)
-	s.listAllModules = func() ([]byte, error) {
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
 		// go list -json output is a stream of JSON objects, not an array
 		var buf []byte
 		for _, m := range mockOutput {
@@ -71,7 +90,8 @@ require (
 			buf = append(buf, b...)
 		}
 		return buf, nil
-	}
+	})
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, nil }
 
 	// 4. Test Case: Default options (Direct + Indirect in go.mod, no transitive that aren't in go.mod)
 	// Wait, the logic is:
@@ -81,7 +101,7 @@ require (
 	// transitive is NOT in go.mod
 
 	opts := scanner.Options{
-		IncludeAll: false,
+		IncludeTransitive: false,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -120,14 +140,156 @@ require (
 		t.Error("example.com/direct not found")
 	}
 
-	// 5. Test Case: IncludeAll = true
-	opts.IncludeAll = true
+	// 5. Test Case: IncludeTransitive
+	s.modGraph = func(context.Context) ([]byte, error) {
+		return []byte("example.com/foo example.com/direct@v1.0.0\n" +
+			"example.com/foo example.com/indirect@v1.0.0\n" +
+			"example.com/direct@v1.0.0 example.com/indirect@v1.0.0\n" +
+			"example.com/indirect@v1.0.0 example.com/transitive@v0.5.0\n"), nil
+	}
+	opts.IncludeTransitive = true
 	modules, err = s.GetUpdates(opts)
 	if err != nil {
-		t.Fatalf("GetUpdates(IncludeAll) failed: %v", err)
+		t.Fatalf("GetUpdates(IncludeTransitive) failed: %v", err)
 	}
 	if len(modules) != 3 {
-		t.Errorf("expected 3 modules with IncludeAll, got %d", len(modules))
+		t.Errorf("expected 3 modules with IncludeTransitive, got %d", len(modules))
+	}
+	for _, m := range modules {
+		want := []string{"example.com/direct"}
+		if m.Direct {
+			want = nil
+		}
+		if !slices.Equal(m.RequiredBy, want) {
+			t.Errorf("%s: RequiredBy = %v, want %v", m.Name, m.RequiredBy, want)
+		}
+	}
+}
+
+func TestGetUpdates_MajorVersionSuffix(t *testing.T) {
+	tmpDir := t.TempDir()
+	goModContent := `module example.com/foo
+
+go 1.21
+
+require (
+	github.com/foo/bar/v2 v2.1.0
+	github.com/foo/baz/v3 v3.0.0 // indirect
+)
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	// github.com/foo/bar v1 is only pulled in transitively, next to the
+	// required /v2 major
+	mockOutput := []goModule{
+		{Path: "github.com/foo/bar/v2", Version: "v2.1.0", Update: &goModule{Path: "github.com/foo/bar/v2", Version: "v2.3.0"}},
+		{Path: "github.com/foo/baz/v3", Version: "v3.0.0", Indirect: true, Update: &goModule{Path: "github.com/foo/baz/v3", Version: "v3.0.1"}},
+		{Path: "github.com/foo/bar", Version: "v1.4.0", Indirect: true, Update: &goModule{Path: "github.com/foo/bar", Version: "v1.5.0"}},
+	}
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
+		var buf []byte
+		for _, m := range mockOutput {
+			b, _ := json.Marshal(m)
+			buf = append(buf, b...)
+		}
+		return buf, nil
+	})
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, nil }
+	s.modGraph = func(context.Context) ([]byte, error) { return nil, nil }
+
+	modules, err := s.GetUpdates(scanner.Options{IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, m := range modules {
+		got[m.Name] = m.DependencyType
+	}
+	want := map[string]string{
+		"github.com/foo/bar/v2": "direct",
+		"github.com/foo/baz/v3": "indirect",
+		"github.com/foo/bar":    "transitive",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("dependency types = %v, want %v", got, want)
+	}
+}
+
+func TestRequiredBy(t *testing.T) {
+	graph := `example.com/app example.com/a@v1.0.0
+example.com/app example.com/b@v1.0.0
+example.com/app example.com/shared@v1.1.0
+example.com/a@v1.0.0 example.com/shared@v1.0.0
+example.com/b@v1.0.0 example.com/mid@v0.2.0
+example.com/mid@v0.2.0 example.com/shared@v1.1.0
+example.com/mid@v0.2.0 example.com/leaf@v0.1.0
+example.com/leaf@v0.1.0 example.com/mid@v0.2.0
+`
+	parents := reverseModGraph([]byte(graph))
+	if got := parents["example.com/shared"]; !slices.Equal(got, []string{"", "example.com/a", "example.com/mid"}) {
+		t.Errorf("parents[shared] = %q", got)
+	}
+
+	idx := gomod.RequireIndex{
+		"example.com/a":      false,
+		"example.com/b":      false,
+		"example.com/shared": true, // indirect
+	}
+	tests := map[string][]string{
+		"example.com/shared": {"example.com/a", "example.com/b"},
+		"example.com/leaf":   {"example.com/b"},
+		"example.com/a":      nil,
+		"example.com/orphan": nil,
+	}
+	for path, want := range tests {
+		if got := requiredBy(parents, path, idx); !slices.Equal(got, want) {
+			t.Errorf("requiredBy(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestGetUpdates_PathPrefixFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	goMod := `module example.com/foo
+
+go 1.21
+
+require (
+	golang.org/x/net v0.20.0
+	golang.org/x/text v0.14.0
+	google.golang.org/protobuf v1.32.0
+	github.com/acme/golang.org/x/shim v1.0.0
+)
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
+		var buf []byte
+		for _, path := range []string{"golang.org/x/net", "golang.org/x/text", "google.golang.org/protobuf", "github.com/acme/golang.org/x/shim"} {
+			b, _ := json.Marshal(goModule{Path: path, Version: "v1.0.0", Update: &goModule{Path: path, Version: "v1.1.0"}})
+			buf = append(buf, b...)
+		}
+		return buf, nil
+	})
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, nil }
+
+	modules, err := s.GetUpdates(scanner.Options{Filter: "golang.org/x/"})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	var got []string
+	for _, m := range modules {
+		got = append(got, m.Name)
+	}
+	slices.Sort(got)
+	if want := []string{"golang.org/x/net", "golang.org/x/text"}; !slices.Equal(got, want) {
+		t.Fatalf("expected only golang.org/x modules %v, got %v", want, got)
 	}
 }
 
@@ -153,8 +315,8 @@ require example.com/pkg v1.0.0
 		{
 			Path: "example.com/old", Version: "v1.0.0", Indirect: true, // Treated as direct because go.mod? No, go.mod only has pkg.
 			// Wait, let's add checking logic.
-			// If not in go.mod, it's skipped unless IncludeAll is true.
-			// Let's add "example.com/old" to go.mod to make it simpler, or use IncludeAll.
+			// If not in go.mod, it's skipped unless IncludeTransitive is true.
+			// Let's add "example.com/old" to go.mod to make it simpler, or use IncludeTransitive.
 			Update: &goModule{Path: "example.com/old", Version: "v1.1.0", Time: oldTime},
 		},
 	}
@@ -162,24 +324,25 @@ require example.com/pkg v1.0.0
 
 	// Create scanner
 	s := NewScanner(tmpDir)
-	s.listAllModules = func() ([]byte, error) {
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
 		var buf []byte
 		for _, m := range mockOutput {
 			b, _ := json.Marshal(m)
 			buf = append(buf, b...)
 		}
 		return buf, nil
-	}
+	})
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, nil }
 
 	// Case 1: Cooldown 1 day. Fresh should be skipped. Old (48h) should pass.
 	// But "example.com/old" is not in go.mod, so it's skipped by default.
-	// Let's rely on IncludeAll for the second package or assume it's in go.mod?
+	// Let's rely on IncludeTransitive for the second package or assume it's in go.mod?
 	// The test setup only put pkg in go.mod. So 'old' is not in go.mod.
-	// Let's use IncludeAll = true to test cooldown on both.
+	// Let's use IncludeTransitive to test cooldown on both.
 
 	opts := scanner.Options{
-		CooldownDays: 1,
-		IncludeAll:   true,
+		CooldownDays:      1,
+		IncludeTransitive: true,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -196,6 +359,183 @@ require example.com/pkg v1.0.0
 	}
 }
 
+func TestGetUpdates_GoFloor(t *testing.T) {
+	tmpDir := t.TempDir()
+	goModContent := `module test
+
+go 1.21
+
+require (
+	example.com/ok v1.0.0
+	example.com/newgo v1.0.0
+)
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	mockOutput := []goModule{
+		{Path: "example.com/ok", Version: "v1.0.0", Update: &goModule{Path: "example.com/ok", Version: "v1.1.0"}},
+		{Path: "example.com/newgo", Version: "v1.0.0", Update: &goModule{Path: "example.com/newgo", Version: "v1.2.0"}},
+	}
+	versions := []goModule{
+		{Path: "example.com/ok", Version: "v1.1.0", GoVersion: "1.20"},
+		{Path: "example.com/newgo", Version: "v1.2.0", GoVersion: "1.23"},
+	}
+
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
+		var buf []byte
+		for _, m := range mockOutput {
+			b, _ := json.Marshal(m)
+			buf = append(buf, b...)
+		}
+		return buf, nil
+	})
+	var gotSpecs []string
+	s.queryGoVersions = func(_ context.Context, specs []string) ([]byte, error) {
+		gotSpecs = specs
+		var buf []byte
+		for _, m := range versions {
+			b, _ := json.Marshal(m)
+			buf = append(buf, b...)
+		}
+		return buf, nil
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gotSpecs) != 2 || gotSpecs[0] != "example.com/ok@v1.1.0" {
+		t.Errorf("unexpected query specs: %v", gotSpecs)
+	}
+	if len(modules) != 1 || modules[0].Name != "example.com/ok" {
+		t.Fatalf("expected only example.com/ok, got %+v", modules)
+	}
+	warnings := s.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "example.com/newgo") || !strings.Contains(warnings[0], "go 1.23") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	// With AllowGoBump the update is kept and no query is made.
+	gotSpecs = nil
+	modules, err = s.GetUpdates(scanner.Options{AllowGoBump: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 2 || gotSpecs != nil || len(s.Warnings()) != 0 {
+		t.Errorf("expected both modules without a query, got %d modules, specs %v", len(modules), gotSpecs)
+	}
+}
+
+func TestGetUpdates_GoFloorQueryFailureKeepsUpdates(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module test\ngo 1.21\nrequire example.com/pkg v1.0.0\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
+		return json.Marshal(goModule{Path: "example.com/pkg", Version: "v1.0.0", Update: &goModule{Path: "example.com/pkg", Version: "v1.1.0"}})
+	})
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, errors.New("offline") }
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 1 {
+		t.Fatalf("expected update to be kept, got %d", len(modules))
+	}
+	if len(s.Warnings()) != 1 {
+		t.Errorf("expected a warning, got %v", s.Warnings())
+	}
+}
+
+func TestGetUpdates_ListVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module test\ngo 1.21\nrequire example.com/pkg v1.0.0\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
+		return json.Marshal(goModule{Path: "example.com/pkg", Version: "v1.0.0", Update: &goModule{Path: "example.com/pkg", Version: "v1.2.0"}})
+	})
+	var listed []string
+	s.listVersions = func(_ context.Context, paths []string) ([]byte, error) {
+		listed = paths
+		return json.Marshal(goModule{Path: "example.com/pkg", Version: "v1.0.0", Versions: []string{"v1.0.0", "v1.1.0", "v1.2.0"}})
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{AllowGoBump: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listed != nil || modules[0].Update.Versions != nil {
+		t.Errorf("expected versions to be listed only on request, got %v", listed)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{AllowGoBump: true, ListVersions: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(listed, []string{"example.com/pkg"}) {
+		t.Errorf("unexpected listed paths %v", listed)
+	}
+	if got := modules[0].Update.Versions; len(got) != 3 || got[1] != "v1.1.0" {
+		t.Errorf("unexpected versions %v", got)
+	}
+}
+
+func TestGetUpdates_SkipsRetractedUpdate(t *testing.T) {
+	tmpDir := t.TempDir()
+	goMod := "module test\ngo 1.21\nrequire (\n\texample.com/retracted v1.0.0\n\texample.com/ok v1.0.0\n)\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	mockOutput := []goModule{
+		{Path: "example.com/retracted", Version: "v1.0.0", Update: &goModule{
+			Path: "example.com/retracted", Version: "v1.1.0", Retracted: []string{"published by mistake"},
+		}},
+		{Path: "example.com/ok", Version: "v1.0.0", Update: &goModule{Path: "example.com/ok", Version: "v1.0.1"}},
+	}
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
+		var buf []byte
+		for _, m := range mockOutput {
+			b, _ := json.Marshal(m)
+			buf = append(buf, b...)
+		}
+		return buf, nil
+	})
+
+	modules, err := s.GetUpdates(scanner.Options{AllowGoBump: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 1 || modules[0].Name != "example.com/ok" {
+		t.Fatalf("expected only example.com/ok, got %+v", modules)
+	}
+	warnings := s.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "example.com/retracted to v1.1.0") || !strings.Contains(warnings[0], "published by mistake") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	// The current version is still listed for vulnerability scans
+	modules, err = s.GetUpdates(scanner.Options{AllowGoBump: true, IncludeVulnScan: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range modules {
+		if m.Name == "example.com/retracted" && m.Update != nil {
+			t.Errorf("expected no update for the retracted version, got %+v", m.Update)
+		}
+	}
+}
+
 func TestDecodeGoListModules(t *testing.T) {
 	input := `
 {
@@ -207,7 +547,7 @@ func TestDecodeGoListModules(t *testing.T) {
 	"Version": "v2.0.0"
 }
 `
-	modules, err := decodeGoListModules([]byte(input))
+	modules, err := decodeGoListModules(strings.NewReader(input))
 	if err != nil {
 		t.Fatalf("failed to decode: %v", err)
 	}
@@ -223,6 +563,251 @@ func TestDecodeGoListModules(t *testing.T) {
 	}
 }
 
+func TestDecodeGoListModules_Pipe(t *testing.T) {
+	r, w := io.Pipe()
+	go func() {
+		// Modules arrive in pieces, split mid-object, as go list prints them
+		for _, chunk := range []string{`{"Path": "example.com/a", "Ver`, `sion": "v1.0.0"}` + "\n", `{"Path": "example.com/b", "Version": "v2.0.0"}`} {
+			_, _ = io.WriteString(w, chunk)
+		}
+		_ = w.Close()
+	}()
+
+	modules, err := decodeGoListModules(r)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if len(modules) != 2 || modules[0].Version != "v1.0.0" || modules[1].Path != "example.com/b" {
+		t.Errorf("unexpected modules %+v", modules)
+	}
+}
+
+// failingStream is go list output whose command fails once it is read.
+type failingStream struct{ io.Reader }
+
+func (failingStream) Close() error { return errors.New("exit status 1") }
+
+func TestGetUpdates_GoListFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module test\ngo 1.21\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	s := NewScanner(tmpDir)
+	s.listAllModules = func(context.Context) (io.ReadCloser, error) {
+		return failingStream{strings.NewReader(`{"Path": "example.com/a"`)}, nil
+	}
+	_, err := s.GetUpdates(scanner.Options{})
+	if err == nil || !strings.Contains(err.Error(), "failed to run go list: exit status 1") {
+		t.Errorf("expected the go list failure to be reported over the truncated output, got %v", err)
+	}
+}
+
+func TestGetUpdates_SkipsModulesWithErrors(t *testing.T) {
+	tmpDir := t.TempDir()
+	goModContent := `module test
+
+go 1.21
+
+require (
+	example.com/a v1.0.0
+	example.com/broken v1.0.0
+	example.com/c v1.0.0
+)
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	output := `
+{"Path": "example.com/a", "Version": "v1.0.0", "Update": {"Path": "example.com/a", "Version": "v1.1.0"}}
+{"Path": "example.com/broken", "Version": "v1.0.0", "Error": {"Err": "module example.com/broken: reading https://proxy.golang.org/example.com/broken/@v/list: 404 Not Found"}}
+{"Path": "example.com/c", "Version": "v1.0.0", "Update": {"Path": "example.com/c", "Version": "v1.2.0"}}
+`
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) { return []byte(output), nil })
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, nil }
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 2 || modules[0].Name != "example.com/a" || modules[1].Name != "example.com/c" {
+		t.Fatalf("expected the modules around the broken one, got %+v", modules)
+	}
+	warnings := s.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "skipping example.com/broken") || !strings.Contains(warnings[0], "404 Not Found") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestGetUpdates_SkipsReplacedModules(t *testing.T) {
+	tmpDir := t.TempDir()
+	goModContent := `module test
+
+go 1.21
+
+require (
+	example.com/kept v1.0.0
+	example.com/local v1.0.0
+	example.com/forked v1.0.0
+)
+
+replace example.com/local => ../local
+
+replace example.com/forked v1.0.0 => github.com/me/forked v1.0.1
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	output := `
+{"Path": "example.com/kept", "Version": "v1.0.0", "Update": {"Path": "example.com/kept", "Version": "v1.1.0"}}
+{"Path": "example.com/local", "Version": "v1.0.0", "Update": {"Path": "example.com/local", "Version": "v1.3.0"}, "Replace": {"Path": "../local"}}
+{"Path": "example.com/forked", "Version": "v1.0.0", "Update": {"Path": "example.com/forked", "Version": "v2.0.0"}, "Replace": {"Path": "github.com/me/forked", "Version": "v1.0.1"}}
+`
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) { return []byte(output), nil })
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, nil }
+
+	modules, err := s.GetUpdates(scanner.Options{IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "example.com/kept" {
+		t.Fatalf("expected replaced modules to be skipped, got %+v", modules)
+	}
+}
+
 // Helper struct field need 'Refresh' was a typo in my mind?
 // No, goModule struct in scanner.go doesn't have Refresh. I added it in the test mock struct init but it's not in the type definition in scanner.go.
 // I need to be careful. The mock is creating goModule structs.
+
+func TestGetUpdates_IncludeVulnScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	goModContent := `module example.com/foo
+
+go 1.21
+
+require (
+	example.com/outdated v1.0.0
+	example.com/pinned v2.3.0
+)
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goModContent), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	mockOutput := []goModule{
+		{Path: "example.com/foo"}, // main module
+		{Path: "example.com/outdated", Version: "v1.0.0", Update: &goModule{Path: "example.com/outdated", Version: "v1.1.0", Time: "2023-01-01T00:00:00Z"}},
+		{Path: "example.com/pinned", Version: "v2.3.0"},
+	}
+	s := NewScanner(tmpDir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) {
+		var buf []byte
+		for _, m := range mockOutput {
+			b, _ := json.Marshal(m)
+			buf = append(buf, b...)
+		}
+		return buf, nil
+	})
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, nil }
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "example.com/outdated" {
+		t.Fatalf("expected only the outdated module by default, got %+v", modules)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeVulnScan: true, CooldownDays: 7})
+	if err != nil {
+		t.Fatalf("GetUpdates(IncludeVulnScan) failed: %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("expected the outdated and pinned modules, got %+v", modules)
+	}
+	if pinned := modules[1]; pinned.Name != "example.com/pinned" || pinned.Update != nil || !pinned.Direct {
+		t.Errorf("expected pinned as a direct module without an update, got %+v", pinned)
+	}
+}
+
+func TestGetUpdates_ManifestInSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	goModContent := `module example.com/api
+
+go 1.21
+
+require example.com/direct v1.0.0
+`
+	if err := os.WriteFile(filepath.Join(sub, "go.mod"), []byte(goModContent), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	output := `{"Path": "example.com/direct", "Version": "v1.0.0", "Update": {"Path": "example.com/direct", "Version": "v1.1.0"}}`
+	s := NewScanner(root)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) { return []byte(output), nil })
+	s.queryGoVersions = func(context.Context, []string) ([]byte, error) { return nil, nil }
+
+	// The root has no go.mod of its own
+	if _, err := s.GetUpdates(scanner.Options{}); err == nil {
+		t.Fatal("expected a missing go.mod at the root to fail")
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{Manifest: filepath.Join("services", "api", "go.mod")})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || !modules[0].Direct || !modules[0].FromGoMod {
+		t.Fatalf("expected the submodule's direct requirement, got %+v", modules)
+	}
+
+	// go commands run next to the manifest
+	cmd := s.command(context.Background(), []string{"list", "-m"}, "all")
+	if cmd.Dir != sub || !slices.Equal(cmd.Args, []string{"go", "list", "-m", "all"}) {
+		t.Errorf("unexpected command %v in %s", cmd.Args, cmd.Dir)
+	}
+}
+
+func TestCommand_AlternateModFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.tools.mod"), []byte("module example.com/tools\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewScanner(dir)
+	s.listAllModules = stream(func(context.Context) ([]byte, error) { return nil, nil })
+
+	if _, err := s.GetUpdates(scanner.Options{Manifest: "go.tools.mod"}); err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	cmd := s.command(context.Background(), []string{"mod", "graph"})
+	if want := []string{"go", "mod", "graph", "-modfile=go.tools.mod"}; cmd.Dir != dir || !slices.Equal(cmd.Args, want) {
+		t.Errorf("got %v in %s, want %v in %s", cmd.Args, cmd.Dir, want, dir)
+	}
+}
+
+func TestGoCommand_Env(t *testing.T) {
+	t.Setenv("GOPROXY", "https://proxy.golang.org")
+	t.Setenv("GONOSUMDB", "git.example.com")
+
+	cmd := goCommand(context.Background(), t.TempDir(), []string{"GOPROXY=https://goproxy.example.com"}, "list")
+
+	// The override is appended after the inherited value, so it wins
+	last := map[string]string{}
+	for _, kv := range cmd.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		last[k] = v
+	}
+	if last["GOPROXY"] != "https://goproxy.example.com" {
+		t.Errorf("GOPROXY = %q, want the override", last["GOPROXY"])
+	}
+	if last["GONOSUMDB"] != "git.example.com" {
+		t.Errorf("expected GONOSUMDB to be inherited, got %q", last["GONOSUMDB"])
+	}
+}
@@ -0,0 +1,110 @@
+package gradle
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// declaration is a dependency declared in a build file.
+type declaration struct {
+	configuration string
+	module        string // group:name
+}
+
+var (
+	// stringNotation matches `implementation "group:name:version"` in Groovy
+	// and `implementation("group:name:version")` in Kotlin.
+	stringNotation = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*["']([\w.-]+):([\w.-]+)(?::[^"']*)?["']`)
+
+	// mapNotation matches `implementation group: 'g', name: 'n', version: 'v'`.
+	mapNotation = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*group\s*[:=]\s*["']([\w.-]+)["']\s*,\s*name\s*[:=]\s*["']([\w.-]+)["']`)
+
+	// catalogAccessor matches `implementation(libs.some.alias)`.
+	catalogAccessor = regexp.MustCompile(`^\s*(\w+)\s*\(?\s*libs\.([\w.]+)`)
+
+	// catalogModule matches the module of a [libraries] entry, in either the
+	// "group:name:version" string or the table notation.
+	catalogModule = regexp.MustCompile(`^([\w.-]+)\s*=\s*(?:["']([\w.-]+):([\w.-]+)(?::[^"']*)?["']|\{(.*)\})`)
+
+	// tableField matches `key = "value"` inside an inline table.
+	tableField = regexp.MustCompile(`([\w.]+)\s*=\s*["']([^"']*)["']`)
+)
+
+// parseDeclarations returns the dependencies declared in a build file.
+// catalog maps normalized version catalog aliases to their modules.
+func parseDeclarations(contents string, catalog map[string]string) []declaration {
+	var decls []declaration
+	for _, line := range strings.Split(contents, "\n") {
+		if m := stringNotation.FindStringSubmatch(line); m != nil {
+			decls = append(decls, declaration{configuration: m[1], module: m[2] + ":" + m[3]})
+			continue
+		}
+		if m := mapNotation.FindStringSubmatch(line); m != nil {
+			decls = append(decls, declaration{configuration: m[1], module: m[2] + ":" + m[3]})
+			continue
+		}
+		if m := catalogAccessor.FindStringSubmatch(line); m != nil {
+			if module, ok := catalog[normalizeAlias(m[2])]; ok {
+				decls = append(decls, declaration{configuration: m[1], module: module})
+			}
+		}
+	}
+	return decls
+}
+
+// normalizeAlias maps a catalog alias such as "junit-jupiter" to the form of
+// its accessor, "junit.jupiter".
+func normalizeAlias(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
+
+// readCatalog reads the [libraries] of a version catalog and maps each
+// normalized alias to its group:name module. A missing catalog is empty.
+func readCatalog(path string) (map[string]string, error) {
+	catalog := make(map[string]string)
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return catalog, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var section string
+	sc := bufio.NewScanner(file)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
+			continue
+		}
+		if section != "libraries" {
+			continue
+		}
+
+		m := catalogModule.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		alias := normalizeAlias(m[1])
+		if m[2] != "" {
+			catalog[alias] = m[2] + ":" + m[3]
+			continue
+		}
+
+		fields := make(map[string]string)
+		for _, f := range tableField.FindAllStringSubmatch(m[4], -1) {
+			fields[f[1]] = f[2]
+		}
+		switch {
+		case fields["module"] != "":
+			catalog[alias] = fields["module"]
+		case fields["group"] != "" && fields["name"] != "":
+			catalog[alias] = fields["group"] + ":" + fields["name"]
+		}
+	}
+	return catalog, sc.Err()
+}
@@ -0,0 +1,166 @@
+// Package gradle provides Gradle package manager scanning functionality.
+package gradle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// reportPath is where the versions plugin writes its JSON report.
+var reportPath = filepath.Join("build", "dependencyUpdates", "report.json")
+
+// Scanner implements scanner.Scanner for Gradle.
+type Scanner struct {
+	workDir      string
+	runGradleCmd func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// NewScanner creates a new Gradle scanner.
+func NewScanner(workDir string) *Scanner {
+	return &Scanner{
+		workDir: workDir,
+		runGradleCmd: func(ctx context.Context, args ...string) ([]byte, error) {
+			cmd := exec.CommandContext(ctx, gradleCommand(workDir), args...)
+			cmd.Dir = workDir
+			return scanner.Output(cmd)
+		},
+	}
+}
+
+// gradleCommand returns the project's Gradle wrapper if it has one, and
+// gradle from PATH otherwise.
+func gradleCommand(workDir string) string {
+	if _, err := os.Stat(filepath.Join(workDir, "gradlew")); err == nil {
+		return filepath.Join(workDir, "gradlew")
+	}
+	return "gradle"
+}
+
+// dependencyReport is the subset of the JSON report written by the
+// com.github.ben-manes.versions plugin's dependencyUpdates task.
+type dependencyReport struct {
+	Outdated struct {
+		Dependencies []reportDependency `json:"dependencies"`
+	} `json:"outdated"`
+}
+
+type reportDependency struct {
+	Group     string `json:"group"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Available struct {
+		Release     string `json:"release"`
+		Milestone   string `json:"milestone"`
+		Integration string `json:"integration"`
+	} `json:"available"`
+}
+
+// latest returns the newest available version, preferring releases.
+func (d reportDependency) latest() string {
+	switch {
+	case d.Available.Release != "":
+		return d.Available.Release
+	case d.Available.Milestone != "":
+		return d.Available.Milestone
+	default:
+		return d.Available.Integration
+	}
+}
+
+// GetUpdates returns all Gradle dependencies that have available updates.
+func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	match, err := scanner.CompileFilter(opts.Filter, false)
+	if err != nil {
+		return nil, err
+	}
+
+	depIdx, err := s.GetDependencyIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Gradle build files: %w", err)
+	}
+
+	ctx := opts.Ctx()
+	_, err = s.runGradleCmd(ctx, "dependencyUpdates", "-DoutputFormatter=json", "-Drevision=release", "--quiet")
+	if err != nil {
+		return nil, scanner.CommandError(ctx, "gradle", fmt.Errorf("failed to run gradle dependencyUpdates: %w", err))
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.workDir, reportPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dependencyUpdates report (is the com.github.ben-manes.versions plugin applied?): %w", err)
+	}
+	var report dependencyReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse dependencyUpdates report: %w", err)
+	}
+
+	modules := []scanner.Module{}
+	for _, d := range report.Outdated.Dependencies {
+		name := d.Group + ":" + d.Name
+		latest := d.latest()
+		if latest == "" || latest == d.Version {
+			continue
+		}
+
+		// Apply filter
+		if !match(name) {
+			continue
+		}
+
+		// The report only lists declared dependencies; ones declared outside
+		// the root build files get an empty configuration
+		modules = append(modules, scanner.Module{
+			Name:           name,
+			Version:        d.Version,
+			Direct:         true,
+			DependencyType: depIdx[name].Type,
+			Update: &scanner.UpdateInfo{
+				Version: latest,
+			},
+		})
+	}
+
+	return modules, nil
+}
+
+// GetDependencyIndex returns a map of group:name coordinates declared in the
+// root build.gradle(.kts) to the configuration declaring them, such as
+// "implementation" or "testImplementation".
+func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
+	catalog, err := readCatalog(filepath.Join(s.workDir, "gradle", "libs.versions.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	idx := make(scanner.DependencyIndex)
+	for _, file := range []string{"build.gradle", "build.gradle.kts"} {
+		data, err := os.ReadFile(filepath.Join(s.workDir, file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range parseDeclarations(string(data), catalog) {
+			// A production configuration wins over test or tooling ones
+			if prev, ok := idx[decl.module]; ok && !isTestConfiguration(prev.Type) {
+				continue
+			}
+			idx[decl.module] = scanner.DependencyInfo{Direct: true, Type: decl.configuration}
+		}
+	}
+	return idx, nil
+}
+
+// isTestConfiguration reports whether configuration only affects tests,
+// e.g. testImplementation or androidTestRuntimeOnly.
+func isTestConfiguration(configuration string) bool {
+	return strings.HasPrefix(configuration, "test") || strings.Contains(configuration, "Test")
+}
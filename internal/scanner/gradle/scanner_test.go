@@ -0,0 +1,182 @@
+package gradle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+const sampleReport = `{
+  "current": {
+    "dependencies": [
+      {"group": "org.slf4j", "name": "slf4j-api", "version": "2.0.9", "projectUrl": "http://www.slf4j.org"}
+    ],
+    "count": 1
+  },
+  "gradle": {"enabled": true, "running": {"version": "8.5", "isUpdateAvailable": false}},
+  "exceeded": {"dependencies": [], "count": 0},
+  "outdated": {
+    "dependencies": [
+      {
+        "group": "com.google.guava",
+        "name": "guava",
+        "version": "31.1-jre",
+        "available": {"release": "33.0.0-jre", "milestone": null, "integration": null},
+        "projectUrl": "https://github.com/google/guava"
+      },
+      {
+        "group": "org.junit.jupiter",
+        "name": "junit-jupiter",
+        "version": "5.9.0",
+        "available": {"release": null, "milestone": "5.10.0-M1", "integration": null}
+      },
+      {
+        "group": "com.squareup.okhttp3",
+        "name": "okhttp",
+        "version": "4.10.0",
+        "available": {"release": "4.12.0", "milestone": null, "integration": null}
+      },
+      {
+        "group": "org.mockito",
+        "name": "mockito-core",
+        "version": "4.0.0",
+        "available": {"release": "5.8.0", "milestone": null, "integration": null}
+      }
+    ],
+    "count": 4
+  },
+  "unresolved": {"dependencies": [], "count": 0},
+  "count": 5
+}`
+
+const sampleBuildFile = `plugins {
+    id("java")
+    id("com.github.ben-manes.versions") version "0.50.0"
+}
+
+dependencies {
+    implementation(libs.guava)
+    implementation("com.squareup.okhttp3:okhttp:4.10.0")
+    testImplementation(libs.junit.jupiter)
+    testImplementation("org.mockito:mockito-core:4.0.0")
+}
+`
+
+const sampleCatalog = `[versions]
+guava = "31.1-jre"
+
+[libraries]
+guava = { module = "com.google.guava:guava", version.ref = "guava" }
+junit-jupiter = { group = "org.junit.jupiter", name = "junit-jupiter", version = "5.9.0" }
+`
+
+// writeProject writes a Gradle project with the sample build files and
+// dependencyUpdates report.
+func writeProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"build.gradle.kts":          sampleBuildFile,
+		"gradle/libs.versions.toml": sampleCatalog,
+		reportPath:                  sampleReport,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestGetUpdates(t *testing.T) {
+	dir := writeProject(t)
+	var gotArgs []string
+	s := &Scanner{
+		workDir: dir,
+		runGradleCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			gotArgs = args
+			return nil, nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if strings.Join(gotArgs, " ") != "dependencyUpdates -DoutputFormatter=json -Drevision=release --quiet" {
+		t.Errorf("unexpected gradle args: %v", gotArgs)
+	}
+
+	want := []scanner.Module{
+		{Name: "com.google.guava:guava", Version: "31.1-jre", DependencyType: "implementation", Update: &scanner.UpdateInfo{Version: "33.0.0-jre"}},
+		{Name: "org.junit.jupiter:junit-jupiter", Version: "5.9.0", DependencyType: "testImplementation", Update: &scanner.UpdateInfo{Version: "5.10.0-M1"}},
+		{Name: "com.squareup.okhttp3:okhttp", Version: "4.10.0", DependencyType: "implementation", Update: &scanner.UpdateInfo{Version: "4.12.0"}},
+		{Name: "org.mockito:mockito-core", Version: "4.0.0", DependencyType: "testImplementation", Update: &scanner.UpdateInfo{Version: "5.8.0"}},
+	}
+	if len(modules) != len(want) {
+		t.Fatalf("expected %d modules, got %+v", len(want), modules)
+	}
+	for i, w := range want {
+		m := modules[i]
+		if m.Name != w.Name || m.Version != w.Version || m.DependencyType != w.DependencyType || !m.Direct || m.Update.Version != w.Update.Version {
+			t.Errorf("module %d = %+v (update %s), want %+v (update %s)", i, m, m.Update.Version, w, w.Update.Version)
+		}
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{Filter: "okhttp"})
+	if err != nil {
+		t.Fatalf("GetUpdates(Filter) failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "com.squareup.okhttp3:okhttp" {
+		t.Errorf("expected only okhttp, got %+v", modules)
+	}
+}
+
+func TestGetUpdates_MissingReport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "build.gradle"), []byte(""), 0644); err != nil {
+		t.Fatalf("failed to write build.gradle: %v", err)
+	}
+	s := &Scanner{
+		workDir:      dir,
+		runGradleCmd: func(context.Context, ...string) ([]byte, error) { return nil, nil },
+	}
+
+	_, err := s.GetUpdates(scanner.Options{})
+	if err == nil || !strings.Contains(err.Error(), "com.github.ben-manes.versions") {
+		t.Fatalf("expected a hint about the versions plugin, got %v", err)
+	}
+}
+
+func TestParseDeclarations_Groovy(t *testing.T) {
+	contents := `dependencies {
+    implementation 'com.google.guava:guava:31.1-jre'
+    api "org.apache.commons:commons-lang3:3.12.0"
+    testImplementation group: 'junit', name: 'junit', version: '4.13.2'
+    compileOnly libs.lombok
+}
+`
+	decls := parseDeclarations(contents, map[string]string{"lombok": "org.projectlombok:lombok"})
+	want := []declaration{
+		{configuration: "implementation", module: "com.google.guava:guava"},
+		{configuration: "api", module: "org.apache.commons:commons-lang3"},
+		{configuration: "testImplementation", module: "junit:junit"},
+		{configuration: "compileOnly", module: "org.projectlombok:lombok"},
+	}
+	if len(decls) != len(want) {
+		t.Fatalf("parseDeclarations() = %+v, want %+v", decls, want)
+	}
+	for i := range want {
+		if decls[i] != want[i] {
+			t.Errorf("declaration %d = %+v, want %+v", i, decls[i], want[i])
+		}
+	}
+}
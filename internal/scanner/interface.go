@@ -1,7 +1,11 @@
 // Package scanner provides interfaces and types for dependency scanning across different package managers.
 package scanner
 
-import "time"
+import (
+	"context"
+	"strings"
+	"time"
+)
 
 // Scanner is the interface that all package manager scanners must implement.
 type Scanner interface {
@@ -12,6 +16,12 @@ type Scanner interface {
 	GetDependencyIndex() (DependencyIndex, error)
 }
 
+// WarningReporter is implemented by scanners that can report non-fatal
+// problems (such as skipped updates) from their last GetUpdates call.
+type WarningReporter interface {
+	Warnings() []string
+}
+
 // DependencyIndex maps package names to their classification.
 type DependencyIndex map[string]DependencyInfo
 
@@ -44,11 +54,25 @@ type Module struct {
 	// Python: "main", "dev", "optional"
 	DependencyType string `json:"dependencyType"`
 
-	// VulnCurrent holds vulnerability counts for the current version
-	VulnCurrent VulnInfo `json:"-"`
+	// Workspace is the workspace package that declares the dependency
+	// (npm/pnpm monorepos); empty for the root project
+	Workspace string `json:"workspace,omitempty"`
+
+	// RequiredBy lists the direct dependencies that pull in an indirect or
+	// transitive module, from the dependency graph (Go only)
+	RequiredBy []string `json:"requiredBy,omitempty"`
+
+	// RangeBlocked marks a Node dependency whose declared range keeps it at
+	// its current version although a newer one exists, e.g. ^4.0.0 with
+	// 5.0.0 published (npm, yarn and pnpm only)
+	RangeBlocked bool `json:"rangeBlocked,omitempty"`
+
+	// VulnCurrent holds vulnerability counts for the current version;
+	// omitted from JSON unless vulnerabilities were checked and found
+	VulnCurrent VulnInfo `json:"vulnCurrent,omitzero"`
 
 	// VulnUpdate holds vulnerability counts for the update version
-	VulnUpdate VulnInfo `json:"-"`
+	VulnUpdate VulnInfo `json:"vulnUpdate,omitzero"`
 
 	// Legacy fields for backward compatibility with Go scanner
 	Path      string `json:"Path,omitempty"`     // Alias for Name (Go compatibility)
@@ -60,6 +84,18 @@ type Module struct {
 type UpdateInfo struct {
 	Version string `json:"version"`
 	Time    string `json:"time,omitempty"`
+
+	// Wanted is the newest version the declared range allows, as reported
+	// by npm, yarn and pnpm; empty for other managers
+	Wanted string `json:"wanted,omitempty"`
+
+	// Versions lists every published version of the package, in no
+	// particular order; only filled with Options.ListVersions
+	Versions []string `json:"-"`
+
+	// Deprecated is the registry's deprecation message for the update
+	// version, if any (npm only)
+	Deprecated string `json:"deprecated,omitempty"`
 }
 
 // VulnInfo contains vulnerability information for a module version.
@@ -73,20 +109,124 @@ type VulnInfo struct {
 
 // Options configures dependency discovery across all scanners.
 type Options struct {
-	// Filter is a substring or regex pattern to filter package names
+	// Filter is a substring or regex pattern to filter package names; see
+	// CompileFilter
 	Filter string
 
-	// IncludeAll determines what additional dependencies to include:
-	// - Go: include transitive dependencies not in go.mod
-	// - npm/yarn/pnpm: include devDependencies
-	// - Python: include all dependency groups
-	IncludeAll bool
+	// IncludeDev includes declared dependencies outside the default set:
+	// - npm/yarn/pnpm: peer and optional dependencies
+	// - Poetry/uv: dependency groups and extras besides main
+	IncludeDev bool
+
+	// IncludeTransitive includes dependencies the project doesn't declare,
+	// e.g. Go modules not in go.mod
+	IncludeTransitive bool
 
 	// CooldownDays filters out versions published within the last N days
 	CooldownDays int
 
+	// PublishTimes looks up when the current and update versions were
+	// published, for output that shows or sorts by it. Scanners whose
+	// package manager doesn't report times only query the registry for them
+	// with PublishTimes or a cooldown (npm, yarn, pnpm, pip, Poetry and uv)
+	PublishTimes bool
+
 	// WorkDir is the working directory for the scanner
 	WorkDir string
+
+	// Manifest is the path of the project's manifest when it isn't the
+	// default one in the scanner's directory, relative to that directory,
+	// e.g. a submodule's "services/api/go.mod" or an alternate "go.tools.mod"
+	// (Go only). Package manager commands run in the manifest's directory.
+	Manifest string
+
+	// IncludeVulnScan also returns dependencies that are already up to date,
+	// with a nil Update, so their current versions can be checked for
+	// vulnerabilities. Supported by the Go, pip and uv scanners; the others
+	// only list packages they know an update for.
+	IncludeVulnScan bool
+
+	// AllowGoBump keeps Go updates that require a newer Go version than the
+	// project's go directive (Go only)
+	AllowGoBump bool
+
+	// InRange makes the Node scanners report the wanted version, the newest
+	// one the declared range allows, as the update instead of the latest, so
+	// applying it only changes the lockfile (npm, yarn and pnpm only)
+	InRange bool
+
+	// ListVersions also fetches every published version of packages with an
+	// update into UpdateInfo.Versions, to show how far behind they are (npm
+	// and Go only)
+	ListVersions bool
+
+	// Deprecations looks up whether each update's version is deprecated on
+	// the registry, for output that flags it (npm only)
+	Deprecations bool
+
+	// Registry is the --registry URL that registry lookups use instead of
+	// the configured one (npm, yarn and pnpm only; Go takes GOPROXY in Env)
+	Registry string
+
+	// Env holds extra KEY=VALUE environment variables for the package
+	// manager commands, overriding inherited ones, e.g. GOPROXY for private
+	// modules or npm_config_registry (Go, npm, yarn and pnpm only)
+	Env []string
+
+	// Context bounds the package manager commands run by the scanner; nil
+	// means no deadline
+	Context context.Context
+}
+
+// Ctx returns the options' Context, or context.Background() if it is nil.
+func (o Options) Ctx() context.Context {
+	if o.Context == nil {
+		return context.Background()
+	}
+	return o.Context
+}
+
+// NeedsPublishTimes reports whether publish times have to be looked up,
+// either for output or to apply CooldownDays.
+func (o Options) NeedsPublishTimes() bool {
+	return o.PublishTimes || o.CooldownDays > 0
+}
+
+// Includes reports whether a dependency of depType is listed with these
+// options: transitive ones need IncludeTransitive, and the others need
+// IncludeDev unless byDefault says the scanner always lists them.
+func (o Options) Includes(depType string, byDefault bool) bool {
+	if depType == "transitive" {
+		return o.IncludeTransitive
+	}
+	return byDefault || o.IncludeDev
+}
+
+// IsDefaultNodeDependencyType reports whether Node (npm/yarn/pnpm) dependencies
+// of the given DependencyType are listed without IncludeDev. Peer and optional
+// dependencies need IncludeDev, transitive ones IncludeTransitive.
+func IsDefaultNodeDependencyType(depType string) bool {
+	return depType == "dependencies" || depType == "devDependencies"
+}
+
+// NodeUpdate builds the update of a Node package from the versions its
+// package manager reports. With inRange the wanted version is the target and
+// nil is returned when it is unknown or already installed.
+func NodeUpdate(current, wanted, latest string, inRange bool) *UpdateInfo {
+	if !inRange {
+		return &UpdateInfo{Version: latest, Wanted: wanted}
+	}
+	if wanted == "" || wanted == current {
+		return nil
+	}
+	return &UpdateInfo{Version: wanted, Wanted: wanted}
+}
+
+// RangeBlocked reports whether a Node package's declared range stops it from
+// reaching latest: the newest version the range allows is the installed one.
+// It is false when the package manager doesn't report wanted (Yarn Berry).
+func RangeBlocked(current, wanted, latest string) bool {
+	return wanted != "" && wanted == current && latest != "" && latest != current
 }
 
 // MaxPathLength calculates the maximum name length for formatting.
@@ -110,17 +250,21 @@ func FilterModules(modules []Module, filter string, cooldownDays int, now time.T
 		return modules
 	}
 
+	match, err := CompileFilter(filter, false)
+	if err != nil {
+		// Not a valid regex; fall back to a plain substring match
+		match = func(name string) bool { return strings.Contains(name, filter) }
+	}
+
 	result := make([]Module, 0, len(modules))
 	for _, m := range modules {
 		// Apply filter
-		if filter != "" {
-			name := m.Name
-			if name == "" {
-				name = m.Path
-			}
-			if !contains(name, filter) {
-				continue
-			}
+		name := m.Name
+		if name == "" {
+			name = m.Path
+		}
+		if !match(name) {
+			continue
 		}
 
 		// Apply cooldown
@@ -138,16 +282,3 @@ func FilterModules(modules []Module, filter string, cooldownDays int, now time.T
 	}
 	return result
 }
-
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && (s == substr || containsHelper(s, substr))
-}
-
-func containsHelper(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
-}
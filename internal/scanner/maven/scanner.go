@@ -0,0 +1,187 @@
+// Package maven provides Maven package manager scanning functionality.
+package maven
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// Scanner implements scanner.Scanner for Maven.
+type Scanner struct {
+	workDir     string
+	runMavenCmd func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// NewScanner creates a new Maven scanner.
+func NewScanner(workDir string) *Scanner {
+	return &Scanner{
+		workDir: workDir,
+		runMavenCmd: func(ctx context.Context, args ...string) ([]byte, error) {
+			cmd := exec.CommandContext(ctx, "mvn", args...)
+			cmd.Dir = workDir
+			return scanner.Output(cmd)
+		},
+	}
+}
+
+// pomProject is the subset of pom.xml needed to classify dependencies.
+type pomProject struct {
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+	DependencyManagement struct {
+		Dependencies []pomDependency `xml:"dependencies>dependency"`
+	} `xml:"dependencyManagement"`
+}
+
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Scope      string `xml:"scope"`
+}
+
+// mavenUpdate is one entry of `mvn versions:display-dependency-updates` output.
+type mavenUpdate struct {
+	name    string // groupId:artifactId
+	current string
+	latest  string
+}
+
+// GetUpdates returns all Maven dependencies that have available updates.
+func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	match, err := scanner.CompileFilter(opts.Filter, false)
+	if err != nil {
+		return nil, err
+	}
+
+	depIdx, err := s.GetDependencyIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+
+	ctx := opts.Ctx()
+	output, err := s.runMavenCmd(ctx, "-B", "versions:display-dependency-updates")
+	if err != nil {
+		return nil, scanner.CommandError(ctx, "mvn", fmt.Errorf("failed to run mvn versions:display-dependency-updates: %w", err))
+	}
+
+	modules := []scanner.Module{}
+	for _, u := range parseDependencyUpdates(string(output)) {
+		depInfo, ok := depIdx[u.name]
+		if !ok {
+			depInfo = scanner.DependencyInfo{Direct: false, Type: "transitive"}
+		}
+
+		// Dependencies inherited from a parent POM need IncludeTransitive
+		if !opts.IncludeTransitive && !depInfo.Direct {
+			continue
+		}
+
+		// Apply filter
+		if !match(u.name) {
+			continue
+		}
+
+		modules = append(modules, scanner.Module{
+			Name:           u.name,
+			Version:        u.current,
+			Direct:         depInfo.Direct,
+			DependencyType: depInfo.Type,
+			Update: &scanner.UpdateInfo{
+				Version: u.latest,
+			},
+		})
+	}
+
+	return modules, nil
+}
+
+// GetDependencyIndex returns a map of groupId:artifactId coordinates declared
+// in pom.xml to their scope ("compile" when unset).
+func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
+	data, err := os.ReadFile(filepath.Join(s.workDir, "pom.xml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var pom pomProject
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, err
+	}
+
+	idx := make(scanner.DependencyIndex)
+	// Managed versions are visited first so a scope on the dependency itself wins
+	deps := append(pom.DependencyManagement.Dependencies, pom.Dependencies...)
+	for _, d := range deps {
+		scope := strings.TrimSpace(d.Scope)
+		if scope == "" {
+			scope = "compile"
+		}
+		name := strings.TrimSpace(d.GroupID) + ":" + strings.TrimSpace(d.ArtifactID)
+		idx[name] = scanner.DependencyInfo{Direct: true, Type: scope}
+	}
+	return idx, nil
+}
+
+var (
+	// logPrefix matches Maven's "[INFO] " style line prefixes.
+	logPrefix = regexp.MustCompile(`^\[[A-Z]+\]\s?`)
+
+	// updateLine matches "group:artifact ..... 1.0 -> 2.0", where the name
+	// may have been wrapped onto the previous line.
+	updateLine = regexp.MustCompile(`^(?:([\w.-]+:[\w.-]+)\s*)?(?:\.+\s*)?(\S+)\s+->\s+(\S+)$`)
+
+	// wrappedName matches a name whose versions continue on the next line.
+	wrappedName = regexp.MustCompile(`^([\w.-]+:[\w.-]+)\s*\.*$`)
+)
+
+// parseDependencyUpdates parses the output of
+// `mvn versions:display-dependency-updates`:
+//
+//	[INFO] The following dependencies in Dependencies have newer versions:
+//	[INFO]   com.google.guava:guava ................... 31.1-jre -> 33.0.0-jre
+//	[INFO]   org.springframework.boot:spring-boot-starter-web ...
+//	[INFO]                                               2.7.0 -> 3.2.1
+//
+// Coordinates listed in several sections are reported once.
+func parseDependencyUpdates(output string) []mavenUpdate {
+	var updates []mavenUpdate
+	seen := make(map[string]bool)
+	pending := ""
+
+	sc := bufio.NewScanner(strings.NewReader(output))
+	for sc.Scan() {
+		line := strings.TrimSpace(logPrefix.ReplaceAllString(sc.Text(), ""))
+		if line == "" {
+			pending = ""
+			continue
+		}
+
+		if m := updateLine.FindStringSubmatch(line); m != nil {
+			name := m[1]
+			if name == "" {
+				name = pending
+			}
+			pending = ""
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			updates = append(updates, mavenUpdate{name: name, current: m[2], latest: m[3]})
+			continue
+		}
+
+		pending = ""
+		if m := wrappedName.FindStringSubmatch(line); m != nil {
+			pending = m[1]
+		}
+	}
+	return updates
+}
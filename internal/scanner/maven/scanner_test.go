@@ -0,0 +1,155 @@
+package maven
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+const samplePom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0.0</version>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.springframework.boot</groupId>
+        <artifactId>spring-boot-dependencies</artifactId>
+        <version>2.7.0</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>31.1-jre</version>
+    </dependency>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-web</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.junit.jupiter</groupId>
+      <artifactId>junit-jupiter</artifactId>
+      <version>5.9.0</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+</project>
+`
+
+const sampleOutput = `[INFO] Scanning for projects...
+[INFO]
+[INFO] --------------------------< com.example:app >---------------------------
+[INFO] Building app 1.0.0
+[INFO] --------------------------------[ jar ]---------------------------------
+[INFO]
+[INFO] --- versions-maven-plugin:2.16.2:display-dependency-updates (default-cli) @ app ---
+[INFO] The following dependencies in Dependency Management have newer versions:
+[INFO]   org.springframework.boot:spring-boot-dependencies ...
+[INFO]                                                          2.7.0 -> 3.2.1
+[INFO]
+[INFO] The following dependencies in Dependencies have newer versions:
+[INFO]   com.google.guava:guava ............................ 31.1-jre -> 33.0.0-jre
+[INFO]   org.junit.jupiter:junit-jupiter ........................ 5.9.0 -> 5.10.1
+[INFO]   org.slf4j:slf4j-api .................................... 1.7.36 -> 2.0.9
+[INFO]   org.springframework.boot:spring-boot-starter-web ...
+[INFO]                                                          2.7.0 -> 3.2.1
+[INFO]
+[INFO] ------------------------------------------------------------------------
+[INFO] BUILD SUCCESS
+[INFO] ------------------------------------------------------------------------
+`
+
+func TestParseDependencyUpdates(t *testing.T) {
+	got := parseDependencyUpdates(sampleOutput)
+	want := []mavenUpdate{
+		{name: "org.springframework.boot:spring-boot-dependencies", current: "2.7.0", latest: "3.2.1"},
+		{name: "com.google.guava:guava", current: "31.1-jre", latest: "33.0.0-jre"},
+		{name: "org.junit.jupiter:junit-jupiter", current: "5.9.0", latest: "5.10.1"},
+		{name: "org.slf4j:slf4j-api", current: "1.7.36", latest: "2.0.9"},
+		{name: "org.springframework.boot:spring-boot-starter-web", current: "2.7.0", latest: "3.2.1"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("parseDependencyUpdates() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("update %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGetUpdates(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "pom.xml"), []byte(samplePom), 0644); err != nil {
+		t.Fatalf("failed to write pom.xml: %v", err)
+	}
+
+	var gotArgs []string
+	s := &Scanner{
+		workDir: tmpDir,
+		runMavenCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			gotArgs = args
+			return []byte(sampleOutput), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(gotArgs) != 2 || gotArgs[0] != "-B" || gotArgs[1] != "versions:display-dependency-updates" {
+		t.Errorf("unexpected mvn args: %v", gotArgs)
+	}
+
+	wantTypes := map[string]string{
+		"org.springframework.boot:spring-boot-dependencies": "import",
+		"com.google.guava:guava":                            "compile",
+		"org.junit.jupiter:junit-jupiter":                   "test",
+		"org.springframework.boot:spring-boot-starter-web":  "compile",
+	}
+	if len(modules) != len(wantTypes) {
+		t.Fatalf("expected %d declared dependencies, got %+v", len(wantTypes), modules)
+	}
+	for _, m := range modules {
+		if !m.Direct || m.DependencyType != wantTypes[m.Name] {
+			t.Errorf("unexpected classification for %s: direct=%v type=%s", m.Name, m.Direct, m.DependencyType)
+		}
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeTransitive: true, Filter: "slf4j"})
+	if err != nil {
+		t.Fatalf("GetUpdates(IncludeTransitive) failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Direct || modules[0].DependencyType != "transitive" || modules[0].Update.Version != "2.0.9" {
+		t.Errorf("expected the inherited slf4j-api with IncludeTransitive, got %+v", modules)
+	}
+}
+
+func TestGetUpdates_MissingBinary(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "pom.xml"), []byte(samplePom), 0644); err != nil {
+		t.Fatalf("failed to write pom.xml: %v", err)
+	}
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runMavenCmd: func(context.Context, ...string) ([]byte, error) {
+			return nil, &exec.Error{Name: "mvn", Err: exec.ErrNotFound}
+		},
+	}
+	_, err := s.GetUpdates(scanner.Options{})
+	if err == nil || err.Error() != "mvn not found in PATH; install it or use --manager" {
+		t.Fatalf("expected a friendly missing binary error, got %v", err)
+	}
+}
@@ -3,242 +3,441 @@ package npm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
-	"sync"
-	"time"
 
-	"github.com/pragmaticivan/faro/internal/cooldown"
+	"github.com/pragmaticivan/faro/internal/log"
+	"github.com/pragmaticivan/faro/internal/registry"
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/workspace"
 )
 
 // Scanner implements scanner.Scanner for npm.
 type Scanner struct {
-	workDir          string
-	runNpmOutdated   func() ([]byte, error)
-	fetchPackageTime func(name, version string) (string, error)
+	workDir                  string
+	runNpmOutdated           func(ctx context.Context) ([]byte, error)
+	runNpmOutdatedWorkspaces func(ctx context.Context) ([]byte, error)
+	fetchPackageTime         func(ctx context.Context, name, version string) (string, error)
+	fetchVersions            func(ctx context.Context, name string) ([]string, error)
+	fetchDeprecation         func(ctx context.Context, name, version string) (string, error)
+	env                      []string            // Extra environment for npm, from Options.Env
+	npm                      *registry.NpmClient // Registry lookups of the current GetUpdates call
+	warnings                 []string
 }
 
 // packageJSON represents the structure of package.json.
 type packageJSON struct {
-	Dependencies    map[string]string `json:"dependencies"`
-	DevDependencies map[string]string `json:"devDependencies"`
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// section returns the dependency map for a package.json field name.
+func (p *packageJSON) section(name string) map[string]string {
+	switch name {
+	case "dependencies":
+		return p.Dependencies
+	case "devDependencies":
+		return p.DevDependencies
+	case "peerDependencies":
+		return p.PeerDependencies
+	case "optionalDependencies":
+		return p.OptionalDependencies
+	default:
+		return nil
+	}
+}
+
+// dependencyType returns the package.json section that declares name, or
+// "transitive" if it isn't declared.
+func (p *packageJSON) dependencyType(name string) string {
+	for _, sec := range []string{"dependencies", "devDependencies", "optionalDependencies", "peerDependencies"} {
+		if _, ok := p.section(sec)[name]; ok {
+			return sec
+		}
+	}
+	return "transitive"
+}
+
+// nonRegistrySpec describes a package.json dependency spec that doesn't
+// refer to a registry release of the package under its declared name, such
+// as "alias of other-pkg" for "npm:other-pkg@^1.0.0" or "git dependency". It
+// returns "" for version ranges and tags.
+func nonRegistrySpec(spec string) string {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case strings.HasPrefix(spec, "npm:"):
+		target := strings.TrimPrefix(spec, "npm:")
+		// Scoped targets keep their leading @: npm:@scope/pkg@^1.0.0
+		if i := strings.LastIndex(target, "@"); i > 0 {
+			target = target[:i]
+		}
+		return "alias of " + target
+	case strings.HasPrefix(spec, "file:"), strings.HasPrefix(spec, "link:"):
+		return "local dependency"
+	case strings.HasPrefix(spec, "workspace:"):
+		return "workspace dependency"
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return "tarball dependency"
+	case strings.HasPrefix(spec, "git+"), strings.HasPrefix(spec, "git:"),
+		strings.HasPrefix(spec, "github:"), strings.HasPrefix(spec, "gitlab:"),
+		strings.HasPrefix(spec, "bitbucket:"), strings.HasPrefix(spec, "gist:"),
+		strings.Contains(spec, "/"):
+		// git+ssh://, git://, github:org/repo and the org/repo shorthand
+		return "git dependency"
+	}
+	return ""
+}
+
+// project holds the root manifest and the manifests of any workspace members.
+type project struct {
+	root       *packageJSON
+	workspaces map[string]*packageJSON // keyed by workspace package name
+}
+
+// manifestFor returns the manifest of the workspace named dependent, falling
+// back to the root manifest. The returned name is empty for the root.
+func (p *project) manifestFor(dependent string) (*packageJSON, string) {
+	if ws, ok := p.workspaces[dependent]; ok {
+		return ws, dependent
+	}
+	return p.root, ""
 }
 
 // npmOutdated represents the structure of `npm outdated --json` output.
 type npmOutdated map[string]npmPackageInfo
 
 type npmPackageInfo struct {
-	Current  string `json:"current"`
-	Wanted   string `json:"wanted"`
-	Latest   string `json:"latest"`
-	Location string `json:"location"`
-	Type     string `json:"type"` // "dependencies" or "devDependencies"
+	Current   string `json:"current"`
+	Wanted    string `json:"wanted"`
+	Latest    string `json:"latest"`
+	Location  string `json:"location"`
+	Type      string `json:"type"`      // "dependencies" or "devDependencies"
+	Dependent string `json:"dependent"` // Workspace (or root) package that depends on it
+}
+
+// npmError is the error object npm prints with --json when a command fails.
+type npmError struct {
+	Code    string `json:"code"`
+	Summary string `json:"summary"`
+	Detail  string `json:"detail"`
+}
+
+func (e *npmError) Error() string {
+	return fmt.Sprintf("npm outdated failed: %s: %s", e.Code, e.Summary)
+}
+
+// toolError returns an *scanner.AuthError for registry auth failures and e
+// otherwise.
+func (e *npmError) toolError() error {
+	if slices.Contains([]string{"E401", "E403", "ENEEDAUTH"}, e.Code) {
+		return &scanner.AuthError{Tool: "npm", Detail: strings.TrimSpace(e.Code + " " + e.Summary)}
+	}
+	return e
 }
 
 // NewScanner creates a new npm scanner.
 func NewScanner(workDir string) *Scanner {
 	s := &Scanner{
 		workDir: workDir,
-		runNpmOutdated: func() ([]byte, error) {
-			cmd := exec.Command("npm", "outdated", "--json")
-			cmd.Dir = workDir
-			var stderr bytes.Buffer
-			cmd.Stderr = &stderr
-
-			// npm outdated returns exit code 1 when there are outdated packages.
-			// However, if the command fails for other reasons (e.g. executable not found),
-			// we should return the error.
-			out, err := cmd.Output()
-			if err != nil {
-				// If exit code is 1, it just means there are outdated packages, which is expected.
-				if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-					return out, nil
-				}
-				// For other errors, return error with stderr info
-				if stderr.Len() > 0 {
-					return nil, fmt.Errorf("npm outdated failed: %w, stderr: %s", err, stderr.String())
-				}
-				return nil, err
-			}
-			return out, nil
-		},
-	}
-	s.fetchPackageTime = func(name, version string) (string, error) {
-		// npm view package time --json
-		// Note: 'npm view' returns the full time map even if we ask for a specific version,
-		// so we ask for the package time map and extract the specific version.
-		cmd := exec.Command("npm", "view", name, "time", "--json")
-		cmd.Dir = workDir
-		out, err := cmd.Output()
-		if err != nil {
-			return "", err
-		}
+	}
+	s.runNpmOutdated = func(ctx context.Context) ([]byte, error) {
+		return runOutdated(outdatedCommand(ctx, workDir, s.env))
+	}
+	s.runNpmOutdatedWorkspaces = func(ctx context.Context) ([]byte, error) {
+		return runOutdated(outdatedCommand(ctx, workDir, s.env, "--workspaces", "--include-workspace-root"))
+	}
+	s.fetchPackageTime = func(ctx context.Context, name, version string) (string, error) {
+		return s.npm.PublishTime(ctx, name, version)
+	}
+	s.fetchVersions = func(ctx context.Context, name string) ([]string, error) {
+		return s.npm.Versions(ctx, name)
+	}
+	s.fetchDeprecation = func(ctx context.Context, name, version string) (string, error) {
+		return s.npm.Deprecation(ctx, name, version)
+	}
+	return s
+}
 
-		var timeMap map[string]string
-		if err := json.Unmarshal(out, &timeMap); err != nil {
-			return "", err
-		}
+// outdatedCommand builds `npm outdated --json` with any extra args, run in
+// workDir with env appended to the inherited environment, so entries such as
+// npm_config_registry override inherited ones.
+func outdatedCommand(ctx context.Context, workDir string, env []string, extraArgs ...string) *exec.Cmd {
+	args := append([]string{"outdated", "--json"}, extraArgs...)
+	cmd := exec.CommandContext(ctx, "npm", args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
+}
 
-		if t, ok := timeMap[version]; ok {
-			return t, nil
+// runOutdated runs an `npm outdated` command built by outdatedCommand.
+func runOutdated(cmd *exec.Cmd) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// npm outdated returns exit code 1 when there are outdated packages.
+	// However, if the command fails for other reasons (e.g. executable not found),
+	// we should return the error.
+	out, err := scanner.Output(cmd)
+	if err != nil {
+		// Registry auth failures also exit with 1, so check for them first
+		if authErr := scanner.RegistryAuthError("npm", stderr.Bytes()); authErr != nil {
+			return nil, authErr
+		}
+		// If exit code is 1, it just means there are outdated packages, which is expected.
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return out, nil
+		}
+		// For other errors, return error with stderr info
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("npm outdated failed: %w, stderr: %s", err, stderr.String())
 		}
-		return "", nil
+		return nil, err
 	}
-	return s
+	return out, nil
 }
 
 // GetUpdates returns all npm packages that have available updates.
 func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
-	// Read package.json to determine dependency types
-	pkgJSON, err := s.readPackageJSON()
+	s.warnings = nil
+	s.env = opts.Env
+	s.npm = registry.NewNpmClient(s.workDir, opts.Registry, opts.Env)
+
+	match, err := scanner.CompileFilter(opts.Filter, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read package.json: %w", err)
+		return nil, err
 	}
 
-	// Get outdated packages from npm
-	output, err := s.runNpmOutdated()
+	// Read package.json (and workspace members) to determine dependency types
+	proj, err := s.readProject()
 	if err != nil {
-		return nil, fmt.Errorf("failed to run npm outdated: %w", err)
+		return nil, fmt.Errorf("failed to read package.json: %w", err)
 	}
 
-	if len(output) == 0 {
-		return []scanner.Module{}, nil
+	// Get outdated packages from npm
+	outdatedCmd := s.runNpmOutdated
+	if len(proj.workspaces) > 0 {
+		outdatedCmd = s.runNpmOutdatedWorkspaces
 	}
-
-	var outdated npmOutdated
-	if err := json.Unmarshal(output, &outdated); err != nil {
-		return nil, fmt.Errorf("failed to parse npm outdated output: %w", err)
+	ctx := opts.Ctx()
+	output, err := outdatedCmd(ctx)
+	if err != nil {
+		return nil, scanner.CommandError(ctx, "npm", fmt.Errorf("failed to run npm outdated: %w", err))
 	}
 
-	type candidate struct {
-		Name   string
-		Info   npmPackageInfo
-		Direct bool
-		Type   string
+	// Up-to-date projects print nothing or {}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return []scanner.Module{}, nil
 	}
-	var candidates []candidate
 
-	for name, info := range outdated {
-		// If current version matches latest, it's not an update we care about
-		if info.Current == info.Latest {
-			continue
+	outdated, err := decodeOutdated(output)
+	if err != nil {
+		var npmErr *npmError
+		if errors.As(err, &npmErr) {
+			return nil, npmErr.toolError()
 		}
+		// Output that isn't the expected JSON, such as a truncated report,
+		// shouldn't abort runs that scan other projects too
+		log.Warnf("ignoring unparseable npm outdated output: %v", err)
+		return []scanner.Module{}, nil
+	}
 
-		// Determine if it's a direct dependency
-		_, isDirect := pkgJSON.Dependencies[name]
-		_, isDevDirect := pkgJSON.DevDependencies[name]
-
-		depType := info.Type
-		if depType == "" {
-			if isDirect {
-				depType = "dependencies"
-			} else if isDevDirect {
-				depType = "devDependencies"
-			} else {
-				depType = "transitive"
+	// Visit packages by name so results don't depend on map order
+	var modules []scanner.Module
+	for _, name := range slices.Sorted(maps.Keys(outdated)) {
+		for _, info := range outdated[name] {
+			// If current version matches latest, it's not an update we care about
+			if info.Current == info.Latest {
+				continue
 			}
-		}
-
-		// Filter transitive if not including all
-		if !opts.IncludeAll && depType == "transitive" {
-			continue
-		}
 
-		// Apply filter
-		if opts.Filter != "" && !strings.Contains(name, opts.Filter) {
-			continue
-		}
+			// Determine if it's a direct dependency of the declaring package
+			pkgJSON, ws := proj.manifestFor(info.Dependent)
+			declared := pkgJSON.dependencyType(name)
 
-		candidates = append(candidates, candidate{name, info, isDirect || isDevDirect, depType})
-	}
-
-	// Fetch update times concurrently
-	modules := make([]scanner.Module, 0, len(candidates))
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, 10) // Limit concurrency
+			// Aliases, git, local and tarball dependencies aren't registry
+			// releases of name, so installing name@latest would be wrong
+			if kind := nonRegistrySpec(pkgJSON.section(declared)[name]); kind != "" {
+				if strings.HasPrefix(kind, "alias") {
+					s.warnings = append(s.warnings, fmt.Sprintf("skipping %s: %s; update it in package.json manually", name, kind))
+				}
+				continue
+			}
 
-	for _, c := range candidates {
-		wg.Add(1)
-		go func(c candidate) {
-			defer wg.Done()
+			// npm omits current for packages that aren't installed; fall back
+			// to the range declared in package.json, or skip the entry
+			current := info.Current
+			if current == "" {
+				current = pkgJSON.section(declared)[name]
+				if current == "" {
+					continue
+				}
+			}
 
-			sem <- struct{}{}        // Acquire token
-			defer func() { <-sem }() // Release token
+			depType := info.Type
+			if depType == "" {
+				depType = declared
+			}
 
-			var updateTime string
-			// Only fetch time if we have a latest version
-			if c.Info.Latest != "" {
-				t, err := s.fetchPackageTime(c.Name, c.Info.Latest)
-				if err == nil {
-					updateTime = t
-				}
+			// Peer and optional dependencies need --dev, transitive ones --transitive
+			if !opts.Includes(depType, scanner.IsDefaultNodeDependencyType(depType)) {
+				continue
 			}
 
-			// Apply cooldown if requested and we have a time
-			if opts.CooldownDays > 0 && updateTime != "" {
-				if !cooldown.Eligible(updateTime, opts.CooldownDays, time.Now()) {
-					return
-				}
+			// Apply filter
+			if !match(name) {
+				continue
 			}
 
-			module := scanner.Module{
-				Name:           c.Name,
-				Version:        c.Info.Current,
-				Direct:         c.Direct,
-				DependencyType: c.Type,
-				Update: &scanner.UpdateInfo{
-					Version: c.Info.Latest,
-					Time:    updateTime,
-				},
+			update := scanner.NodeUpdate(current, info.Wanted, info.Latest, opts.InRange)
+			if update == nil {
+				continue
 			}
 
-			mu.Lock()
-			modules = append(modules, module)
-			mu.Unlock()
-		}(c)
+			modules = append(modules, scanner.Module{
+				Name:           name,
+				Version:        current,
+				Direct:         declared != "transitive",
+				DependencyType: depType,
+				Workspace:      ws,
+				Update:         update,
+				RangeBlocked:   scanner.RangeBlocked(current, info.Wanted, info.Latest),
+			})
+		}
 	}
 
-	wg.Wait()
+	// Look up publish times on the registry and apply the cooldown
+	modules = registry.FillTimesFor(opts, modules, s.fetchPackageTime)
+	if opts.ListVersions && s.fetchVersions != nil {
+		registry.FillVersions(ctx, modules, s.fetchVersions)
+	}
+	if opts.Deprecations && s.fetchDeprecation != nil {
+		registry.FillDeprecations(ctx, modules, s.fetchDeprecation)
+	}
+	if modules == nil {
+		return []scanner.Module{}, nil
+	}
 	return modules, nil
 }
 
+// Warnings returns non-fatal problems from the last GetUpdates call.
+func (s *Scanner) Warnings() []string {
+	return s.warnings
+}
+
 // GetDependencyIndex returns a map of npm package names to their dependency information.
+// Workspace member dependencies are merged in; a production classification wins,
+// followed by dev, optional and peer.
 func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
-	pkgJSON, err := s.readPackageJSON()
+	proj, err := s.readProject()
 	if err != nil {
 		return nil, err
 	}
 
 	idx := make(scanner.DependencyIndex)
-	for name := range pkgJSON.Dependencies {
-		idx[name] = scanner.DependencyInfo{
-			Direct: true,
-			Type:   "dependencies",
-		}
+	manifests := []*packageJSON{proj.root}
+	for _, ws := range proj.workspaces {
+		manifests = append(manifests, ws)
 	}
-	for name := range pkgJSON.DevDependencies {
-		idx[name] = scanner.DependencyInfo{
-			Direct: true,
-			Type:   "devDependencies",
+	// Sections are visited in increasing precedence, so later ones win
+	for _, sec := range []string{"peerDependencies", "optionalDependencies", "devDependencies", "dependencies"} {
+		for _, pkgJSON := range manifests {
+			for name := range pkgJSON.section(sec) {
+				idx[name] = scanner.DependencyInfo{
+					Direct: true,
+					Type:   sec,
+				}
+			}
 		}
 	}
 	return idx, nil
 }
 
-// readPackageJSON reads and parses package.json.
-func (s *Scanner) readPackageJSON() (*packageJSON, error) {
-	path := filepath.Join(s.workDir, "package.json")
-	data, err := os.ReadFile(path)
+// decodeOutdated parses `npm outdated --json` output. In workspace mode npm
+// reports a list of entries for packages outdated in several workspaces.
+func decodeOutdated(data []byte) (map[string][]npmPackageInfo, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	// A failed run prints {"error": {...}} instead of the outdated packages
+	if msg, ok := raw["error"]; ok {
+		var npmErr npmError
+		if err := json.Unmarshal(msg, &npmErr); err == nil && npmErr.Code != "" {
+			return nil, &npmErr
+		}
+	}
+
+	out := make(map[string][]npmPackageInfo, len(raw))
+	for name, msg := range raw {
+		var list []npmPackageInfo
+		if err := json.Unmarshal(msg, &list); err == nil {
+			out[name] = list
+			continue
+		}
+		var info npmPackageInfo
+		if err := json.Unmarshal(msg, &info); err != nil {
+			return nil, err
+		}
+		out[name] = []npmPackageInfo{info}
+	}
+	return out, nil
+}
+
+// readProject reads the root package.json and the package.json of every
+// workspace member it declares.
+func (s *Scanner) readProject() (*project, error) {
+	data, err := os.ReadFile(filepath.Join(s.workDir, "package.json"))
 	if err != nil {
 		return nil, err
 	}
 
+	root, err := parsePackageJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	proj := &project{root: root}
+
+	patterns, err := workspace.NpmPatterns(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(patterns) == 0 {
+		return proj, nil
+	}
+
+	members, err := workspace.Resolve(s.workDir, patterns)
+	if err != nil {
+		return nil, err
+	}
+	proj.workspaces = make(map[string]*packageJSON, len(members))
+	for _, member := range members {
+		memberData, err := os.ReadFile(filepath.Join(s.workDir, filepath.FromSlash(member.Dir), "package.json"))
+		if err != nil {
+			return nil, err
+		}
+		pkg, err := parsePackageJSON(memberData)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", member.Dir, err)
+		}
+		proj.workspaces[member.Name] = pkg
+	}
+	return proj, nil
+}
+
+// parsePackageJSON parses package.json contents.
+func parsePackageJSON(data []byte) (*packageJSON, error) {
 	var pkg packageJSON
 	if err := json.Unmarshal(data, &pkg); err != nil {
 		return nil, err
@@ -1,12 +1,19 @@
 package npm
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/pragmaticivan/faro/internal/log"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
@@ -36,10 +43,10 @@ func TestGetUpdates_WithTime(t *testing.T) {
 		// However, we can mock runNpmOutdated.
 		// For readPackageJSON, we might need to rely on a file or refactor separation.
 		// Wait, NewScanner takes workDir. We can create a temp dir and write package.json there.
-		runNpmOutdated: func() ([]byte, error) {
+		runNpmOutdated: func(context.Context) ([]byte, error) {
 			return outdatedBytes, nil
 		},
-		fetchPackageTime: func(name, version string) (string, error) {
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
 			if name == "react" && version == "18.2.0" {
 				return "2023-05-01T12:00:00.000Z", nil
 			}
@@ -57,7 +64,7 @@ func TestGetUpdates_WithTime(t *testing.T) {
 	}
 
 	opts := scanner.Options{
-		CooldownDays: 0,
+		PublishTimes: true,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -78,6 +85,62 @@ func TestGetUpdates_WithTime(t *testing.T) {
 	}
 }
 
+func TestGetUpdates_NoTimeLookupByDefault(t *testing.T) {
+	outdated := npmOutdated{
+		"react": {Current: "18.0.0", Wanted: "18.0.0", Latest: "18.2.0"},
+	}
+	outdatedBytes, _ := json.Marshal(outdated)
+
+	tmpDir := t.TempDir()
+	if err := writePackageJSON(tmpDir, []byte(`{"dependencies":{"react":"^18.0.0"}}`)); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	lookups := 0
+	s := &Scanner{
+		workDir: tmpDir,
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			lookups++
+			return "2023-05-01T12:00:00.000Z", nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 {
+		t.Fatalf("expected 1 module, got %d", len(modules))
+	}
+	if lookups != 0 {
+		t.Errorf("expected no publish time lookups without a cooldown or time output, got %d", lookups)
+	}
+	if modules[0].Update.Time != "" {
+		t.Errorf("expected no update time, got %q", modules[0].Update.Time)
+	}
+}
+
+func TestGetUpdates_Registry(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := writePackageJSON(tmpDir, []byte(`{"dependencies":{"react":"^18.0.0"}}`)); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+	s := NewScanner(tmpDir)
+	s.runNpmOutdated = func(context.Context) ([]byte, error) {
+		return []byte("{}"), nil
+	}
+
+	if _, err := s.GetUpdates(scanner.Options{Registry: "https://npm.corp.example.com/"}); err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if got := s.npm.Registry(); got != "https://npm.corp.example.com/" {
+		t.Errorf("expected registry lookups against --registry, got %q", got)
+	}
+}
+
 func TestGetUpdates_Cooldown(t *testing.T) {
 	mockPkgJSON := packageJSON{
 		Dependencies: map[string]string{
@@ -94,10 +157,10 @@ func TestGetUpdates_Cooldown(t *testing.T) {
 	outdatedBytes, _ := json.Marshal(mockOutdated)
 
 	s := &Scanner{
-		runNpmOutdated: func() ([]byte, error) {
+		runNpmOutdated: func(context.Context) ([]byte, error) {
 			return outdatedBytes, nil
 		},
-		fetchPackageTime: func(name, version string) (string, error) {
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
 			now := time.Now()
 			if name == "fresh-pkg" {
 				return now.Add(-24 * time.Hour).Format(time.RFC3339), nil // 1 day old
@@ -150,10 +213,10 @@ func TestGetUpdates_SkipSameVersion(t *testing.T) {
 	outdatedBytes, _ := json.Marshal(mockOutdated)
 
 	s := &Scanner{
-		runNpmOutdated: func() ([]byte, error) {
+		runNpmOutdated: func(context.Context) ([]byte, error) {
 			return outdatedBytes, nil
 		},
-		fetchPackageTime: func(name, version string) (string, error) {
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
 			return "", nil
 		},
 	}
@@ -177,6 +240,269 @@ func TestGetUpdates_SkipSameVersion(t *testing.T) {
 	}
 }
 
+func TestGetUpdates_InRange(t *testing.T) {
+	mockPkgJSON := packageJSON{
+		Dependencies: map[string]string{
+			"express": "^4.18.0",
+			"lodash":  "~4.17.0",
+		},
+	}
+	pkgJSONBytes, _ := json.Marshal(mockPkgJSON)
+
+	mockOutdated := npmOutdated{
+		"express": {Current: "4.18.0", Wanted: "4.18.2", Latest: "5.0.0", Type: "dependencies"},
+		"lodash":  {Current: "4.17.21", Wanted: "4.17.21", Latest: "5.0.0", Type: "dependencies"},
+	}
+	outdatedBytes, _ := json.Marshal(mockOutdated)
+
+	s := &Scanner{
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return "", nil
+		},
+	}
+
+	tmpDir := t.TempDir()
+	s.workDir = tmpDir
+	if err := writePackageJSON(tmpDir, pkgJSONBytes); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("expected 2 modules without --in-range, got %d", len(modules))
+	}
+	for _, m := range modules {
+		if m.Name == "express" && (m.Update.Version != "5.0.0" || m.Update.Wanted != "4.18.2") {
+			t.Errorf("expected express latest 5.0.0 wanted 4.18.2, got %+v", m.Update)
+		}
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{InRange: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	// lodash is already at its wanted version, so only express moves
+	if len(modules) != 1 || modules[0].Name != "express" {
+		t.Fatalf("expected only express in range, got %+v", modules)
+	}
+	if modules[0].Update.Version != "4.18.2" {
+		t.Errorf("expected in-range update to 4.18.2, got %s", modules[0].Update.Version)
+	}
+}
+
+func TestGetUpdates_RangeBlocked(t *testing.T) {
+	mockPkgJSON := packageJSON{
+		Dependencies: map[string]string{
+			"express": "^4.0.0",
+			"lodash":  "^4.17.0",
+		},
+	}
+	pkgJSONBytes, _ := json.Marshal(mockPkgJSON)
+
+	mockOutdated := npmOutdated{
+		// ^4.0.0 allows nothing newer than what is installed, so 5.0.0 is out of reach
+		"express": {Current: "4.21.0", Wanted: "4.21.0", Latest: "5.0.0", Type: "dependencies"},
+		"lodash":  {Current: "4.17.20", Wanted: "4.17.21", Latest: "4.17.21", Type: "dependencies"},
+	}
+	outdatedBytes, _ := json.Marshal(mockOutdated)
+
+	s := &Scanner{
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return "", nil
+		},
+	}
+	s.workDir = t.TempDir()
+	if err := writePackageJSON(s.workDir, pkgJSONBytes); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("expected 2 modules, got %+v", modules)
+	}
+	for _, m := range modules {
+		if want := m.Name == "express"; m.RangeBlocked != want {
+			t.Errorf("%s: expected RangeBlocked %v, got %v", m.Name, want, m.RangeBlocked)
+		}
+	}
+}
+
+func TestGetUpdates_ListVersions(t *testing.T) {
+	pkgJSONBytes, _ := json.Marshal(packageJSON{Dependencies: map[string]string{"express": "^4.18.0"}})
+	outdatedBytes, _ := json.Marshal(npmOutdated{
+		"express": {Current: "4.18.0", Wanted: "4.18.2", Latest: "5.0.0", Type: "dependencies"},
+	})
+
+	s := &Scanner{
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return "", nil
+		},
+		fetchVersions: func(_ context.Context, name string) ([]string, error) {
+			return []string{"4.18.0", "4.18.2", "5.0.0"}, nil
+		},
+	}
+	tmpDir := t.TempDir()
+	s.workDir = tmpDir
+	if err := writePackageJSON(tmpDir, pkgJSONBytes); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if modules[0].Update.Versions != nil {
+		t.Errorf("expected versions only with ListVersions, got %v", modules[0].Update.Versions)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{ListVersions: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules[0].Update.Versions) != 3 {
+		t.Errorf("expected the registry versions, got %v", modules[0].Update.Versions)
+	}
+}
+
+func TestGetUpdates_Deprecated(t *testing.T) {
+	pkgJSONBytes, _ := json.Marshal(packageJSON{Dependencies: map[string]string{"request": "^2.88.0", "express": "^4.18.0"}})
+	outdatedBytes, _ := json.Marshal(npmOutdated{
+		"request": {Current: "2.88.0", Wanted: "2.88.2", Latest: "2.88.2", Type: "dependencies"},
+		"express": {Current: "4.18.0", Wanted: "4.18.2", Latest: "5.0.0", Type: "dependencies"},
+	})
+
+	s := &Scanner{
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return "", nil
+		},
+		fetchDeprecation: func(_ context.Context, name, version string) (string, error) {
+			if name == "request" && version == "2.88.2" {
+				return "request has been deprecated", nil
+			}
+			return "", nil
+		},
+	}
+	tmpDir := t.TempDir()
+	s.workDir = tmpDir
+	if err := writePackageJSON(tmpDir, pkgJSONBytes); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{Deprecations: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	deprecated := make(map[string]string)
+	for _, m := range modules {
+		deprecated[m.Name] = m.Update.Deprecated
+	}
+	if deprecated["request"] != "request has been deprecated" || deprecated["express"] != "" {
+		t.Errorf("expected only request to be marked deprecated, got %v", deprecated)
+	}
+
+	// Output that doesn't flag deprecations skips the lookups
+	modules, err = s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	for _, m := range modules {
+		if m.Update.Deprecated != "" {
+			t.Errorf("expected no deprecation lookup without Deprecations, got %s: %q", m.Name, m.Update.Deprecated)
+		}
+	}
+}
+
+func TestGetUpdates_SkipsAliasAndGitDependencies(t *testing.T) {
+	mockPkgJSON := packageJSON{
+		Dependencies: map[string]string{
+			"mypkg":   "npm:other-pkg@^1.0.0",
+			"lib":     "github:org/repo",
+			"express": "^4.18.0",
+		},
+	}
+	pkgJSONBytes, _ := json.Marshal(mockPkgJSON)
+
+	// npm reports aliases and git dependencies under their package.json keys
+	mockOutdated := npmOutdated{
+		"mypkg":   {Current: "1.0.0", Wanted: "1.2.0", Latest: "2.0.0", Type: "dependencies"},
+		"lib":     {Current: "git", Wanted: "git", Latest: "git", Type: "dependencies"},
+		"express": {Current: "4.18.0", Wanted: "4.18.2", Latest: "5.0.0", Type: "dependencies"},
+	}
+	outdatedBytes, _ := json.Marshal(mockOutdated)
+
+	var looked []string
+	s := &Scanner{
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			looked = append(looked, name)
+			return "", nil
+		},
+	}
+	tmpDir := t.TempDir()
+	s.workDir = tmpDir
+	if err := writePackageJSON(tmpDir, pkgJSONBytes); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "express" {
+		t.Fatalf("expected only express, got %+v", modules)
+	}
+	if slices.Contains(looked, "mypkg") || slices.Contains(looked, "lib") {
+		t.Errorf("expected no registry lookups for skipped packages, got %v", looked)
+	}
+
+	warnings := s.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "mypkg") || !strings.Contains(warnings[0], "alias of other-pkg") {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestNonRegistrySpec(t *testing.T) {
+	tests := map[string]string{
+		"^1.0.0":                           "",
+		"latest":                           "",
+		">=1.2.0 <2.0.0 || 3.x":            "",
+		"npm:other-pkg@^1.0.0":             "alias of other-pkg",
+		"npm:@scope/pkg@1.x":               "alias of @scope/pkg",
+		"github:org/repo":                  "git dependency",
+		"org/repo#v1.0.0":                  "git dependency",
+		"git+ssh://git@github.com/o/r.git": "git dependency",
+		"file:../lib":                      "local dependency",
+		"link:../lib":                      "local dependency",
+		"workspace:*":                      "workspace dependency",
+		"https://example.com/pkg.tgz":      "tarball dependency",
+	}
+	for spec, want := range tests {
+		if got := nonRegistrySpec(spec); got != want {
+			t.Errorf("nonRegistrySpec(%q) = %q, want %q", spec, got, want)
+		}
+	}
+}
+
 func TestParseNpmViewTime(t *testing.T) {
 	// Simulate the output from npm view package time --json
 	jsonOutput := `{
@@ -210,10 +536,10 @@ func TestGetUpdates_IncludeScopedDevDependenciesWhenTypeMissing(t *testing.T) {
 	outdatedBytes, _ := json.Marshal(mockOutdated)
 
 	s := &Scanner{
-		runNpmOutdated: func() ([]byte, error) {
+		runNpmOutdated: func(context.Context) ([]byte, error) {
 			return outdatedBytes, nil
 		},
-		fetchPackageTime: func(name, version string) (string, error) {
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
 			return "", nil
 		},
 	}
@@ -224,7 +550,7 @@ func TestGetUpdates_IncludeScopedDevDependenciesWhenTypeMissing(t *testing.T) {
 		t.Fatalf("failed to write package.json: %v", err)
 	}
 
-	modules, err := s.GetUpdates(scanner.Options{IncludeAll: false})
+	modules, err := s.GetUpdates(scanner.Options{})
 	if err != nil {
 		t.Fatalf("GetUpdates failed: %v", err)
 	}
@@ -236,3 +562,373 @@ func TestGetUpdates_IncludeScopedDevDependenciesWhenTypeMissing(t *testing.T) {
 		t.Fatalf("expected @nestjs/common, got %s", modules[0].Name)
 	}
 }
+
+func TestGetUpdates_Workspaces(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"package.json":            `{"name": "root", "workspaces": ["packages/*"], "devDependencies": {"typescript": "^5.0.0"}}`,
+		"packages/a/package.json": `{"name": "pkg-a", "dependencies": {"lodash": "^4.17.0"}}`,
+		"packages/b/package.json": `{"name": "pkg-b", "dependencies": {"react": "^17.0.0"}, "devDependencies": {"lodash": "^4.17.0"}}`,
+	}
+	for rel, contents := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+
+	// npm reports an array when a package is outdated in several workspaces.
+	outdated := `{
+		"lodash": [
+			{"current": "4.17.0", "wanted": "4.17.21", "latest": "4.17.21", "dependent": "pkg-a"},
+			{"current": "4.17.0", "wanted": "4.17.21", "latest": "4.17.21", "dependent": "pkg-b"}
+		],
+		"react": {"current": "17.0.2", "wanted": "17.0.2", "latest": "18.2.0", "dependent": "pkg-b"},
+		"typescript": {"current": "5.0.0", "wanted": "5.4.0", "latest": "5.4.0", "dependent": "root"}
+	}`
+
+	rootCalled := false
+	s := &Scanner{
+		workDir: tmpDir,
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			rootCalled = true
+			return nil, nil
+		},
+		runNpmOutdatedWorkspaces: func(context.Context) ([]byte, error) {
+			return []byte(outdated), nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return "", nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if rootCalled {
+		t.Errorf("expected workspace-aware npm outdated to be used")
+	}
+	if len(modules) != 4 {
+		t.Fatalf("expected 4 modules, got %d: %+v", len(modules), modules)
+	}
+
+	got := make(map[string]scanner.Module)
+	for _, m := range modules {
+		got[m.Name+"|"+m.Workspace] = m
+	}
+	if m, ok := got["lodash|pkg-a"]; !ok || m.DependencyType != "dependencies" {
+		t.Errorf("expected lodash as dependency of pkg-a, got %+v", m)
+	}
+	if m, ok := got["lodash|pkg-b"]; !ok || m.DependencyType != "devDependencies" {
+		t.Errorf("expected lodash as devDependency of pkg-b, got %+v", m)
+	}
+	if _, ok := got["react|pkg-b"]; !ok {
+		t.Errorf("expected react from pkg-b")
+	}
+	if m, ok := got["typescript|"]; !ok || m.DependencyType != "devDependencies" {
+		t.Errorf("expected typescript from root, got %+v", m)
+	}
+
+	idx, err := s.GetDependencyIndex()
+	if err != nil {
+		t.Fatalf("GetDependencyIndex failed: %v", err)
+	}
+	if idx["lodash"].Type != "dependencies" || idx["react"].Type != "dependencies" || idx["typescript"].Type != "devDependencies" {
+		t.Errorf("unexpected merged index: %+v", idx)
+	}
+}
+
+func TestGetUpdates_PeerAndOptionalDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSON := `{
+		"dependencies": {"react": "^18.0.0"},
+		"peerDependencies": {"react-dom": "^18.0.0"},
+		"optionalDependencies": {"fsevents": "^2.0.0"}
+	}`
+	if err := writePackageJSON(tmpDir, []byte(pkgJSON)); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	// npm reports the declaring section in "type"; older versions may omit it
+	outdated := `{
+		"react": {"current": "18.0.0", "wanted": "18.2.0", "latest": "18.2.0", "type": "dependencies"},
+		"react-dom": {"current": "18.0.0", "wanted": "18.2.0", "latest": "19.0.0", "type": "peerDependencies"},
+		"fsevents": {"current": "2.0.0", "wanted": "2.3.3", "latest": "2.3.3"}
+	}`
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return []byte(outdated), nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return "", nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "react" {
+		t.Fatalf("expected only react by default, got %+v", modules)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	got := make(map[string]scanner.Module)
+	for _, m := range modules {
+		got[m.Name] = m
+	}
+	if m := got["react-dom"]; m.DependencyType != "peerDependencies" || !m.Direct {
+		t.Errorf("expected react-dom as direct peerDependency, got %+v", m)
+	}
+	if m := got["fsevents"]; m.DependencyType != "optionalDependencies" || !m.Direct {
+		t.Errorf("expected fsevents as direct optionalDependency, got %+v", m)
+	}
+
+	idx, err := s.GetDependencyIndex()
+	if err != nil {
+		t.Fatalf("GetDependencyIndex failed: %v", err)
+	}
+	if idx["react-dom"].Type != "peerDependencies" || idx["fsevents"].Type != "optionalDependencies" {
+		t.Errorf("unexpected index: %+v", idx)
+	}
+}
+
+func TestGetUpdates_DevWithoutTransitive(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSON := `{
+		"dependencies": {"react": "^18.0.0"},
+		"devDependencies": {"jest": "^29.0.0"},
+		"peerDependencies": {"react-dom": "^18.0.0"}
+	}`
+	if err := writePackageJSON(tmpDir, []byte(pkgJSON)); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	// loose-envify isn't declared in package.json, so it's transitive
+	outdated := `{
+		"react": {"current": "18.0.0", "wanted": "18.2.0", "latest": "18.2.0", "type": "dependencies"},
+		"jest": {"current": "29.0.0", "wanted": "29.7.0", "latest": "29.7.0", "type": "devDependencies"},
+		"react-dom": {"current": "18.0.0", "wanted": "18.2.0", "latest": "18.2.0", "type": "peerDependencies"},
+		"loose-envify": {"current": "1.3.0", "wanted": "1.4.0", "latest": "1.4.0"}
+	}`
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return []byte(outdated), nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return "", nil
+		},
+	}
+
+	names := func(opts scanner.Options) []string {
+		modules, err := s.GetUpdates(opts)
+		if err != nil {
+			t.Fatalf("GetUpdates failed: %v", err)
+		}
+		var names []string
+		for _, m := range modules {
+			names = append(names, m.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	if got, want := names(scanner.Options{IncludeDev: true}), []string{"jest", "react", "react-dom"}; !slices.Equal(got, want) {
+		t.Errorf("IncludeDev = %v, want %v", got, want)
+	}
+	if got, want := names(scanner.Options{IncludeTransitive: true}), []string{"jest", "loose-envify", "react"}; !slices.Equal(got, want) {
+		t.Errorf("IncludeTransitive = %v, want %v", got, want)
+	}
+}
+
+func TestGetUpdates_MissingCurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSON := `{"dependencies": {"express": "^4.18.2"}}`
+	if err := writePackageJSON(tmpDir, []byte(pkgJSON)); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	// Neither package is installed, so npm omits "current"
+	outdated := `{
+		"express": {"wanted": "4.21.2", "latest": "5.1.0", "type": "dependencies"},
+		"debug": {"wanted": "4.4.0", "latest": "4.4.0"}
+	}`
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return []byte(outdated), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 {
+		t.Fatalf("expected only the declared package, got %+v", modules)
+	}
+	if modules[0].Name != "express" || modules[0].Version != "^4.18.2" || modules[0].Update.Version != "5.1.0" {
+		t.Errorf("expected express to fall back to its declared range, got %+v", modules[0])
+	}
+}
+
+func TestGetUpdates_EmptyAndMalformedOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{"dependencies": {"react": "^18.0.0"}}`), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		warns  bool
+	}{
+		{"empty", "", false},
+		{"whitespace", " \n\t\n", false},
+		{"empty object", "{}\n", false},
+		{"garbage", "npm WARN config something\n", true},
+		{"truncated", `{"react": {"current": "18.0.0", "wan`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged bytes.Buffer
+			prev := log.Default()
+			log.SetDefault(log.New(&logged, log.LevelWarn))
+			defer log.SetDefault(prev)
+
+			s := &Scanner{
+				workDir: tmpDir,
+				runNpmOutdated: func(context.Context) ([]byte, error) {
+					return []byte(tt.output), nil
+				},
+			}
+			modules, err := s.GetUpdates(scanner.Options{})
+			if err != nil {
+				t.Fatalf("GetUpdates failed: %v", err)
+			}
+			if len(modules) != 0 {
+				t.Errorf("expected no modules, got %+v", modules)
+			}
+			if warned := strings.HasPrefix(logged.String(), "warn: ignoring unparseable npm outdated output"); warned != tt.warns {
+				t.Errorf("expected warning %v, got %q", tt.warns, logged.String())
+			}
+		})
+	}
+}
+
+func TestGetUpdates_DeterministicOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	deps := map[string]string{}
+	mockOutdated := npmOutdated{}
+	for _, name := range []string{"vue", "axios", "react", "lodash", "express", "zod", "chalk", "dayjs"} {
+		deps[name] = "^1.0.0"
+		mockOutdated[name] = npmPackageInfo{Current: "1.0.0", Latest: "2.0.0", Type: "dependencies"}
+	}
+	pkgJSONBytes, _ := json.Marshal(packageJSON{Dependencies: deps})
+	if err := writePackageJSON(tmpDir, pkgJSONBytes); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+	outdatedBytes, _ := json.Marshal(mockOutdated)
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+	}
+
+	order := func() []string {
+		modules, err := s.GetUpdates(scanner.Options{})
+		if err != nil {
+			t.Fatalf("GetUpdates failed: %v", err)
+		}
+		names := make([]string, len(modules))
+		for i, m := range modules {
+			names[i] = m.Name
+		}
+		return names
+	}
+
+	first := order()
+	want := []string{"axios", "chalk", "dayjs", "express", "lodash", "react", "vue", "zod"}
+	if !slices.Equal(first, want) {
+		t.Fatalf("expected modules sorted by name, got %v", first)
+	}
+	if second := order(); !slices.Equal(first, second) {
+		t.Errorf("order changed between runs: %v vs %v", first, second)
+	}
+}
+
+func TestGetUpdates_AuthErrorOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := writePackageJSON(tmpDir, []byte(`{"dependencies":{"@myorg/ui":"^1.0.0"}}`)); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	// npm outdated --json exits 1 with this on stdout when the registry
+	// rejects the token, which used to read as "no updates"
+	s := &Scanner{
+		workDir: tmpDir,
+		runNpmOutdated: func(context.Context) ([]byte, error) {
+			return []byte(`{"error":{"code":"E401","summary":"Unable to authenticate, need: Basic realm=\"GitHub Package Registry\"","detail":""}}`), nil
+		},
+	}
+
+	_, err := s.GetUpdates(scanner.Options{})
+	var authErr *scanner.AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("expected an AuthError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "E401") || !strings.Contains(err.Error(), ".npmrc") {
+		t.Errorf("expected the npm code and an .npmrc hint, got %q", err)
+	}
+}
+
+func TestOutdatedCommand_Env(t *testing.T) {
+	cmd := outdatedCommand(context.Background(), "/project", []string{"npm_config_registry=https://npm.example.com/"}, "--workspaces")
+
+	if got := strings.Join(cmd.Args[1:], " "); got != "outdated --json --workspaces" {
+		t.Errorf("args = %q", got)
+	}
+	if cmd.Dir != "/project" {
+		t.Errorf("Dir = %q, want /project", cmd.Dir)
+	}
+	if !slices.Contains(cmd.Env, "npm_config_registry=https://npm.example.com/") {
+		t.Errorf("expected npm_config_registry in the command environment, got %v", cmd.Env)
+	}
+}
+
+func TestRunOutdated_AuthErrorExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake npm")
+	}
+
+	// A fake npm that fails the way npm does on a private registry without auth
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho 'npm error code E401' >&2\necho 'npm error 401 Unauthorized - GET https://npm.pkg.github.com/@myorg%2fui' >&2\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(binDir, "npm"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake npm: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+
+	_, err := runOutdated(outdatedCommand(context.Background(), t.TempDir(), nil))
+	var authErr *scanner.AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("expected an AuthError, got %v", err)
+	}
+	if authErr.Detail != "npm error code E401" {
+		t.Errorf("Detail = %q", authErr.Detail)
+	}
+}
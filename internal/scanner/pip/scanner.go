@@ -3,20 +3,25 @@ package pip
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/pragmaticivan/faro/internal/registry"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
 // Scanner implements scanner.Scanner for pip.
 type Scanner struct {
-	workDir   string
-	runPipCmd func(args ...string) ([]byte, error)
+	workDir          string
+	runPipCmd        func(ctx context.Context, args ...string) ([]byte, error)
+	fetchPackageTime func(ctx context.Context, name, version string) (string, error)
+	pypi             *registry.PyPIClient // Index lookups of the current GetUpdates call
 }
 
 // pipOutdated represents the structure of `pip list --outdated --format json` output.
@@ -31,28 +36,39 @@ type pipPackageInfo struct {
 
 // NewScanner creates a new pip scanner.
 func NewScanner(workDir string) *Scanner {
-	return &Scanner{
+	s := &Scanner{
 		workDir: workDir,
-		runPipCmd: func(args ...string) ([]byte, error) {
-			cmd := exec.Command("pip", args...)
+		runPipCmd: func(ctx context.Context, args ...string) ([]byte, error) {
+			cmd := exec.CommandContext(ctx, "pip", args...)
 			cmd.Dir = workDir
-			return cmd.Output()
+			return scanner.Output(cmd)
 		},
 	}
+	s.fetchPackageTime = func(ctx context.Context, name, version string) (string, error) {
+		return s.pypi.PublishTime(ctx, name, version)
+	}
+	return s
 }
 
 // GetUpdates returns all pip packages that have available updates.
+// With IncludeVulnScan, installed packages without an update are returned too.
 func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
-	// Read requirements.txt to determine direct dependencies
-	directDeps, err := s.readRequirementsTxt()
+	match, err := scanner.CompileFilter(opts.Filter, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the requirements files to determine direct dependencies
+	directDeps, err := s.readDirectDeps()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read requirements.txt: %w", err)
+		return nil, err
 	}
 
 	// Get outdated packages from pip
-	output, err := s.runPipCmd("list", "--outdated", "--format", "json")
+	ctx := opts.Ctx()
+	output, err := s.runPipCmd(ctx, "list", "--outdated", "--format", "json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to run pip list --outdated: %w", err)
+		return nil, scanner.CommandError(ctx, "pip", fmt.Errorf("failed to run pip list --outdated: %w", err))
 	}
 
 	var outdated pipOutdated
@@ -60,17 +76,29 @@ func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
 		return nil, fmt.Errorf("failed to parse pip output: %w", err)
 	}
 
+	if opts.IncludeVulnScan {
+		output, err := s.runPipCmd(ctx, "list", "--format", "json")
+		if err != nil {
+			return nil, scanner.CommandError(ctx, "pip", fmt.Errorf("failed to run pip list: %w", err))
+		}
+		var installed pipOutdated
+		if err := json.Unmarshal(output, &installed); err != nil {
+			return nil, fmt.Errorf("failed to parse pip output: %w", err)
+		}
+		outdated = withUpToDate(outdated, installed)
+	}
+
 	var modules []scanner.Module
 	for _, info := range outdated {
-		_, isDirect := directDeps[strings.ToLower(info.Name)]
+		_, isDirect := directDeps[normalizeName(info.Name)]
 
-		// Filter transitive if not including all
-		if !opts.IncludeAll && !isDirect {
+		// Filter transitive unless asked for
+		if !opts.IncludeTransitive && !isDirect {
 			continue
 		}
 
 		// Apply filter
-		if opts.Filter != "" && !strings.Contains(strings.ToLower(info.Name), strings.ToLower(opts.Filter)) {
+		if !match(info.Name) {
 			continue
 		}
 
@@ -84,33 +112,72 @@ func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
 			Version:        info.Version,
 			Direct:         isDirect,
 			DependencyType: depType,
-			Update: &scanner.UpdateInfo{
-				Version: info.Latest,
-			},
+		}
+		if info.Latest != "" {
+			module.Update = &scanner.UpdateInfo{Version: info.Latest}
 		}
 		modules = append(modules, module)
 	}
 
-	return modules, nil
+	s.pypi = registry.NewPyPIClient(s.indexURL(), nil)
+	return registry.FillTimesFor(opts, modules, s.fetchPackageTime), nil
+}
+
+// indexURL returns the package index pip resolves against, from
+// PIP_INDEX_URL; "" is the public PyPI.
+func (s *Scanner) indexURL() string {
+	return os.Getenv("PIP_INDEX_URL")
 }
 
 // GetDependencyIndex returns a map of pip package names to their dependency information.
+// With pip-tools, packages pinned only in the compiled requirements.txt are
+// transitive.
 func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
-	directDeps, err := s.readRequirementsTxt()
+	directDeps, err := s.readDirectDeps()
 	if err != nil {
 		return nil, err
 	}
 
 	idx := make(scanner.DependencyIndex)
+	if s.usesPipTools() {
+		pinned, err := readRequirements(filepath.Join(s.workDir, "requirements.txt"))
+		if err != nil {
+			return nil, err
+		}
+		for name := range pinned {
+			idx[name] = scanner.DependencyInfo{Direct: false, Type: "transitive"}
+		}
+	}
 	for name := range directDeps {
 		idx[name] = scanner.DependencyInfo{Direct: true, Type: "main"}
 	}
 	return idx, nil
 }
 
-// readRequirementsTxt reads requirements.txt and returns a map of package names.
-func (s *Scanner) readRequirementsTxt() (map[string]bool, error) {
-	path := filepath.Join(s.workDir, "requirements.txt")
+// usesPipTools reports whether the project keeps its direct dependencies in a
+// pip-tools requirements.in next to the compiled requirements.txt.
+func (s *Scanner) usesPipTools() bool {
+	_, err := os.Stat(filepath.Join(s.workDir, "requirements.in"))
+	return err == nil
+}
+
+// readDirectDeps returns the direct dependencies: those in requirements.in
+// for pip-tools projects, otherwise those in requirements.txt.
+func (s *Scanner) readDirectDeps() (map[string]bool, error) {
+	file := "requirements.txt"
+	if s.usesPipTools() {
+		file = "requirements.in"
+	}
+	deps, err := readRequirements(filepath.Join(s.workDir, file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return deps, nil
+}
+
+// readRequirements reads a requirements file and returns a map of PEP 503
+// normalized package names. A missing file yields no packages.
+func readRequirements(path string) (map[string]bool, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -125,20 +192,55 @@ func (s *Scanner) readRequirementsTxt() (map[string]bool, error) {
 	deps := make(map[string]bool)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		// Parse package name (handle version specs like package==1.0.0, package>=1.0.0, etc.)
-		parts := strings.FieldsFunc(line, func(r rune) bool {
-			return r == '=' || r == '>' || r == '<' || r == '~' || r == '!'
-		})
-		if len(parts) > 0 {
-			pkgName := strings.TrimSpace(parts[0])
-			deps[strings.ToLower(pkgName)] = true
+		if name := requirementName(scanner.Text()); name != "" {
+			deps[normalizeName(name)] = true
 		}
 	}
 
 	return deps, scanner.Err()
 }
+
+// requirementName returns the package name of a requirements file line, or ""
+// for blank lines, comments and options such as -r, -c or --hash.
+func requirementName(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "-") {
+		return ""
+	}
+
+	// Parse package name (handle version specs like package==1.0.0, package>=1.0.0,
+	// extras like package[extra] and markers like package; python_version<"3.9")
+	end := strings.IndexAny(line, "=><~!;[ \t\\")
+	if end >= 0 {
+		line = line[:end]
+	}
+	return strings.TrimSpace(line)
+}
+
+// nameSeparators matches runs of characters PEP 503 treats as equivalent.
+var nameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizeName returns the PEP 503 normalized form of a package name, so
+// Flask_Login in requirements.txt matches Flask-Login reported by pip.
+func normalizeName(name string) string {
+	return strings.ToLower(nameSeparators.ReplaceAllString(name, "-"))
+}
+
+// withUpToDate appends the installed packages missing from outdated, which
+// are up to date, with no latest version.
+func withUpToDate(outdated, installed pipOutdated) pipOutdated {
+	seen := make(map[string]bool, len(outdated))
+	for _, info := range outdated {
+		seen[normalizeName(info.Name)] = true
+	}
+	for _, info := range installed {
+		if !seen[normalizeName(info.Name)] {
+			info.Latest = ""
+			outdated = append(outdated, info)
+		}
+	}
+	return outdated
+}
@@ -1,11 +1,19 @@
 package pip
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pragmaticivan/faro/internal/registry"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
@@ -29,14 +37,14 @@ flask==2.2.0
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPipCmd: func(args ...string) ([]byte, error) {
+		runPipCmd: func(_ context.Context, args ...string) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
 
 	// Test Case 1: Default options (only direct dependencies)
 	opts := scanner.Options{
-		IncludeAll: false,
+		IncludeTransitive: false,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -74,7 +82,7 @@ flask==2.2.0
 			}
 		}
 		if m.Name == "werkzeug" {
-			t.Error("werkzeug should not be included when IncludeAll=false")
+			t.Error("werkzeug should not be included without IncludeTransitive")
 		}
 	}
 
@@ -85,15 +93,15 @@ flask==2.2.0
 		t.Error("flask not found")
 	}
 
-	// Test Case 2: IncludeAll = true
-	opts.IncludeAll = true
+	// Test Case 2: IncludeTransitive
+	opts.IncludeTransitive = true
 	modules, err = s.GetUpdates(opts)
 	if err != nil {
-		t.Fatalf("GetUpdates(IncludeAll) failed: %v", err)
+		t.Fatalf("GetUpdates(IncludeTransitive) failed: %v", err)
 	}
 
 	if len(modules) != 3 {
-		t.Errorf("expected 3 modules with IncludeAll, got %d", len(modules))
+		t.Errorf("expected 3 modules with IncludeTransitive, got %d", len(modules))
 	}
 
 	// Verify werkzeug is now included
@@ -110,7 +118,7 @@ flask==2.2.0
 		}
 	}
 	if !foundWerkzeug {
-		t.Error("werkzeug not found with IncludeAll=true")
+		t.Error("werkzeug not found with IncludeTransitive")
 	}
 }
 
@@ -131,7 +139,7 @@ django==4.0.0
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPipCmd: func(args ...string) ([]byte, error) {
+		runPipCmd: func(_ context.Context, args ...string) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
@@ -154,6 +162,83 @@ django==4.0.0
 	}
 }
 
+func TestGetUpdates_FilterAlternation(t *testing.T) {
+	tmpDir := t.TempDir()
+	requirementsTxt := `requests==2.28.0
+django==4.0.0
+flask==2.0.0
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte(requirementsTxt), 0644); err != nil {
+		t.Fatalf("failed to write requirements.txt: %v", err)
+	}
+
+	mockOutdated := pipOutdated{
+		{Name: "requests", Version: "2.28.0", Latest: "2.31.0", Type: "wheel"},
+		{Name: "Django", Version: "4.0.0", Latest: "5.0.0", Type: "wheel"},
+		{Name: "Flask", Version: "2.0.0", Latest: "3.0.0", Type: "wheel"},
+	}
+	outdatedBytes, _ := json.Marshal(mockOutdated)
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPipCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+	}
+
+	// PyPI names are case-insensitive, so the pattern is too
+	modules, err := s.GetUpdates(scanner.Options{Filter: "django|flask"})
+	if err != nil {
+		t.Fatalf("GetUpdates with filter failed: %v", err)
+	}
+
+	if len(modules) != 2 || modules[0].Name != "Django" || modules[1].Name != "Flask" {
+		t.Errorf("expected Django and Flask for 'django|flask', got %+v", modules)
+	}
+}
+
+func TestGetUpdates_IncludeVulnScan(t *testing.T) {
+	tmpDir := t.TempDir()
+	requirementsTxt := `requests==2.28.0
+pyyaml==5.3
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte(requirementsTxt), 0644); err != nil {
+		t.Fatalf("failed to write requirements.txt: %v", err)
+	}
+
+	outdatedBytes, _ := json.Marshal(pipOutdated{
+		{Name: "requests", Version: "2.28.0", Latest: "2.31.0", Type: "wheel"},
+	})
+	installedBytes, _ := json.Marshal(pipOutdated{
+		{Name: "requests", Version: "2.28.0"},
+		{Name: "PyYAML", Version: "5.3"},
+	})
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPipCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			if slices.Contains(args, "--outdated") {
+				return outdatedBytes, nil
+			}
+			return installedBytes, nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{IncludeVulnScan: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("expected requests and the pinned PyYAML, got %+v", modules)
+	}
+	if modules[0].Name != "requests" || modules[0].Update == nil || modules[0].Update.Version != "2.31.0" {
+		t.Errorf("unexpected outdated module: %+v", modules[0])
+	}
+	if modules[1].Name != "PyYAML" || modules[1].Update != nil || modules[1].Version != "5.3" || !modules[1].Direct {
+		t.Errorf("expected PyYAML as a direct module without an update, got %+v", modules[1])
+	}
+}
+
 func TestGetUpdates_EmptyRequirements(t *testing.T) {
 	tmpDir := t.TempDir()
 	requirementsTxt := ``
@@ -168,13 +253,13 @@ func TestGetUpdates_EmptyRequirements(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPipCmd: func(args ...string) ([]byte, error) {
+		runPipCmd: func(_ context.Context, args ...string) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
 
 	opts := scanner.Options{
-		IncludeAll: true,
+		IncludeTransitive: true,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -191,6 +276,62 @@ func TestGetUpdates_EmptyRequirements(t *testing.T) {
 	}
 }
 
+func TestGetUpdates_NormalizesNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	requirementsTxt := `flask_login==0.6.0
+Zope.Interface==5.0
+python-DATEUTIL==2.8.0
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte(requirementsTxt), 0644); err != nil {
+		t.Fatalf("failed to write requirements.txt: %v", err)
+	}
+
+	mockOutdated := pipOutdated{
+		{Name: "Flask-Login", Version: "0.6.0", Latest: "0.6.3", Type: "wheel"},
+		{Name: "zope-interface", Version: "5.0", Latest: "6.1", Type: "wheel"},
+		{Name: "python_dateutil", Version: "2.8.0", Latest: "2.9.0", Type: "wheel"},
+		{Name: "six", Version: "1.15.0", Latest: "1.16.0", Type: "wheel"},
+	}
+	outdatedBytes, _ := json.Marshal(mockOutdated)
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPipCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	got := make(map[string]bool)
+	for _, m := range modules {
+		got[m.Name] = m.Direct
+	}
+	want := map[string]bool{"Flask-Login": true, "zope-interface": true, "python_dateutil": true, "six": false}
+	for name, direct := range want {
+		if d, ok := got[name]; !ok || d != direct {
+			t.Errorf("%s: direct = %v (listed %v), want %v", name, d, ok, direct)
+		}
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"Flask":           "flask",
+		"Flask_Login":     "flask-login",
+		"flask-login":     "flask-login",
+		"zope.interface":  "zope-interface",
+		"Some__Weird.-Pk": "some-weird-pk",
+	}
+	for in, want := range tests {
+		if got := normalizeName(in); got != want {
+			t.Errorf("normalizeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
 func TestGetDependencyIndex(t *testing.T) {
 	tmpDir := t.TempDir()
 	requirementsTxt := `requests==2.28.0
@@ -223,3 +364,182 @@ django>=4.0.0
 		}
 	}
 }
+
+// copyFixture copies the files of testdata/<name> into a temporary directory.
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+	dir := t.TempDir()
+	entries, err := os.ReadDir(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join("testdata", name, e.Name()))
+		if err != nil {
+			t.Fatalf("failed to read fixture: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, e.Name()), data, 0644); err != nil {
+			t.Fatalf("failed to copy fixture: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestGetUpdates_PipTools(t *testing.T) {
+	tmpDir := copyFixture(t, "pip-tools")
+
+	outdated := pipOutdated{
+		{Name: "Flask", Version: "2.2.0", Latest: "3.0.0"},
+		{Name: "requests", Version: "2.28.0", Latest: "2.31.0"},
+		{Name: "werkzeug", Version: "2.2.0", Latest: "3.0.0"},
+		{Name: "certifi", Version: "2023.7.22", Latest: "2024.2.2"},
+	}
+	outdatedBytes, _ := json.Marshal(outdated)
+	s := &Scanner{
+		workDir: tmpDir,
+		runPipCmd: func(context.Context, ...string) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 2 || modules[0].Name != "Flask" || modules[1].Name != "requests" {
+		t.Fatalf("expected only requirements.in packages, got %+v", modules)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates(IncludeTransitive) failed: %v", err)
+	}
+	for _, m := range modules {
+		wantDirect := m.Name == "Flask" || m.Name == "requests"
+		if m.Direct != wantDirect || (m.DependencyType == "transitive") == wantDirect {
+			t.Errorf("unexpected classification for %s: direct=%v type=%s", m.Name, m.Direct, m.DependencyType)
+		}
+	}
+}
+
+func TestGetDependencyIndex_PipTools(t *testing.T) {
+	s := NewScanner(copyFixture(t, "pip-tools"))
+
+	idx, err := s.GetDependencyIndex()
+	if err != nil {
+		t.Fatalf("GetDependencyIndex failed: %v", err)
+	}
+	want := map[string]scanner.DependencyInfo{
+		"flask":    {Direct: true, Type: "main"},
+		"requests": {Direct: true, Type: "main"},
+		"blinker":  {Direct: false, Type: "transitive"},
+		"certifi":  {Direct: false, Type: "transitive"},
+		"werkzeug": {Direct: false, Type: "transitive"},
+	}
+	if len(idx) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), idx)
+	}
+	for name, info := range want {
+		if idx[name] != info {
+			t.Errorf("%s = %+v, want %+v", name, idx[name], info)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestGetUpdates_Cooldown(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte("requests==2.28.0\nflask==2.2.0\n"), 0644); err != nil {
+		t.Fatalf("failed to write requirements.txt: %v", err)
+	}
+
+	outdatedBytes, _ := json.Marshal(pipOutdated{
+		{Name: "requests", Version: "2.28.0", Latest: "2.31.0", Type: "wheel"},
+		{Name: "flask", Version: "2.2.0", Latest: "3.0.0", Type: "wheel"},
+	})
+
+	// flask 3.0.0 was uploaded yesterday, requests 2.31.0 a month ago
+	uploaded := map[string]string{
+		"/pypi/requests/json": fmt.Sprintf(`{"releases":{"2.31.0":[{"upload_time_iso_8601":%q}]}}`, time.Now().AddDate(0, -1, 0).UTC().Format(time.RFC3339)),
+		"/pypi/flask/json":    fmt.Sprintf(`{"releases":{"3.0.0":[{"upload_time_iso_8601":%q}]}}`, time.Now().AddDate(0, 0, -1).UTC().Format(time.RFC3339)),
+	}
+	pypi := registry.NewPyPIClient("", &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body, ok := uploaded[r.URL.Path]
+		status := http.StatusOK
+		if !ok {
+			status = http.StatusNotFound
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})})
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPipCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+		fetchPackageTime: pypi.PublishTime,
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{CooldownDays: 7})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "requests" {
+		t.Fatalf("expected flask to be held back by the cooldown, got %+v", modules)
+	}
+	if modules[0].Update.Time == "" {
+		t.Error("expected the update time to be populated from PyPI")
+	}
+}
+
+func TestGetUpdates_NoTimeLookupByDefault(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte("requests==2.28.0\n"), 0644); err != nil {
+		t.Fatalf("failed to write requirements.txt: %v", err)
+	}
+	outdatedBytes, _ := json.Marshal(pipOutdated{
+		{Name: "requests", Version: "2.28.0", Latest: "2.31.0", Type: "wheel"},
+	})
+
+	lookups := 0
+	s := &Scanner{
+		workDir: tmpDir,
+		runPipCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			lookups++
+			return "", nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 {
+		t.Fatalf("expected 1 module, got %+v", modules)
+	}
+	if lookups != 0 {
+		t.Errorf("expected no PyPI lookups without a cooldown or time output, got %d", lookups)
+	}
+}
+
+func TestGetUpdates_IndexURL(t *testing.T) {
+	t.Setenv("PIP_INDEX_URL", "https://pypi.corp.example.com/simple")
+	outdatedBytes, _ := json.Marshal(pipOutdated{})
+	s := NewScanner(t.TempDir())
+	s.runPipCmd = func(_ context.Context, args ...string) ([]byte, error) {
+		return outdatedBytes, nil
+	}
+
+	if _, err := s.GetUpdates(scanner.Options{}); err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if got := s.pypi.BaseURL(); got != "https://pypi.corp.example.com" {
+		t.Errorf("expected lookups against PIP_INDEX_URL, got base URL %q", got)
+	}
+}
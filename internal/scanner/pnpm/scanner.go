@@ -3,29 +3,54 @@ package pnpm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/pragmaticivan/faro/internal/registry"
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/workspace"
 )
 
 // Scanner implements scanner.Scanner for pnpm.
 type Scanner struct {
-	workDir         string
-	runPnpmOutdated func() ([]byte, error)
+	workDir                  string
+	runPnpmOutdated          func(ctx context.Context) ([]byte, error)
+	runPnpmOutdatedRecursive func(ctx context.Context) ([]byte, error)
+	fetchPackageTime         func(ctx context.Context, name, version string) (string, error)
+	env                      []string            // Extra environment for pnpm, from Options.Env
+	npm                      *registry.NpmClient // Registry lookups of the current GetUpdates call
 }
 
 // pnpmOutdated represents the structure of `pnpm outdated --json` output.
+// Some pnpm versions nest it per workspace instead, keyed by the workspace's
+// package name or directory.
 type pnpmOutdated map[string]pnpmPackageInfo
 
 type pnpmPackageInfo struct {
-	Current string `json:"current"`
-	Latest  string `json:"latest"`
-	Wanted  string `json:"wanted"`
+	Current           string          `json:"current"`
+	Latest            string          `json:"latest"`
+	Wanted            string          `json:"wanted"`
+	DependencyType    string          `json:"dependencyType,omitempty"` // Reported by newer pnpm versions
+	DependentPackages []pnpmDependent `json:"dependentPackages,omitempty"`
+}
+
+// isPackage reports whether info describes a package rather than being a
+// workspace's nested outdated map.
+func (info pnpmPackageInfo) isPackage() bool {
+	return info.Current != "" || info.Latest != "" || info.Wanted != ""
+}
+
+// pnpmDependent is a workspace package listed by `pnpm outdated --recursive`.
+type pnpmDependent struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
 }
 
 type pnpmOutdatedEntry struct {
@@ -34,53 +59,158 @@ type pnpmOutdatedEntry struct {
 	Latest      string `json:"latest"`
 	Wanted      string `json:"wanted"`
 	PackageType string `json:"packageType"`
+
+	// DependencyType replaces PackageType in newer pnpm versions
+	DependencyType string `json:"dependencyType"`
 }
 
 type packageJSON struct {
-	Dependencies    map[string]string `json:"dependencies"`
-	DevDependencies map[string]string `json:"devDependencies"`
+	Name                 string            `json:"name"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// section returns the dependency map for a package.json field name.
+func (p *packageJSON) section(name string) map[string]string {
+	switch name {
+	case "dependencies":
+		return p.Dependencies
+	case "devDependencies":
+		return p.DevDependencies
+	case "peerDependencies":
+		return p.PeerDependencies
+	case "optionalDependencies":
+		return p.OptionalDependencies
+	default:
+		return nil
+	}
+}
+
+// dependencyType returns the package.json section that declares name, or
+// "transitive" if it isn't declared.
+func (p *packageJSON) dependencyType(name string) string {
+	for _, sec := range []string{"dependencies", "devDependencies", "optionalDependencies", "peerDependencies"} {
+		if _, ok := p.section(sec)[name]; ok {
+			return sec
+		}
+	}
+	return "transitive"
+}
+
+// project holds the root manifest and the manifests of any workspace members.
+type project struct {
+	root       *packageJSON
+	workspaces map[string]*packageJSON // keyed by workspace package name
+	dirs       map[string]string       // workspace package names keyed by slash-separated directory
+}
+
+// manifestFor returns the manifest of the workspace named dependent, falling
+// back to the root manifest. The returned name is empty for the root.
+func (p *project) manifestFor(dependent string) (*packageJSON, string) {
+	if ws, ok := p.workspaces[dependent]; ok {
+		return ws, dependent
+	}
+	return p.root, ""
+}
+
+// workspaceFor returns the name of the workspace a key of the nested outdated
+// output refers to; pnpm keys it by package name or by directory, absolute or
+// relative to workDir. Keys that don't match a workspace member refer to the
+// root project.
+func (p *project) workspaceFor(key, workDir string) string {
+	if _, ok := p.workspaces[key]; ok {
+		return key
+	}
+	dir := key
+	if filepath.IsAbs(dir) {
+		rel, err := filepath.Rel(workDir, dir)
+		if err != nil {
+			return ""
+		}
+		dir = rel
+	}
+	return p.dirs[filepath.ToSlash(filepath.Clean(dir))]
 }
 
 // NewScanner creates a new pnpm scanner.
 func NewScanner(workDir string) *Scanner {
-	return &Scanner{
+	s := &Scanner{
 		workDir: workDir,
-		runPnpmOutdated: func() ([]byte, error) {
-			cmd := exec.Command("pnpm", "outdated", "--json")
-			cmd.Dir = workDir
-			var stderr bytes.Buffer
-			cmd.Stderr = &stderr
-
-			out, err := cmd.Output() // pnpm outdated may return non-zero when updates are available
-			if err != nil {
-				if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-					if looksLikeJSON(out) {
-						return out, nil
-					}
-				}
-				if len(strings.TrimSpace(string(out))) > 0 {
-					return nil, fmt.Errorf("pnpm outdated failed: %w, output: %s", err, strings.TrimSpace(string(out)))
-				}
-				if stderr.Len() > 0 {
-					return nil, fmt.Errorf("pnpm outdated failed: %w, stderr: %s", err, stderr.String())
-				}
-				return nil, err
+	}
+	s.fetchPackageTime = func(ctx context.Context, name, version string) (string, error) {
+		return s.npm.PublishTime(ctx, name, version)
+	}
+	s.runPnpmOutdated = func(ctx context.Context) ([]byte, error) {
+		return runOutdated(outdatedCommand(ctx, workDir, s.env))
+	}
+	s.runPnpmOutdatedRecursive = func(ctx context.Context) ([]byte, error) {
+		return runOutdated(outdatedCommand(ctx, workDir, s.env, "--recursive"))
+	}
+	return s
+}
+
+// outdatedCommand builds `pnpm outdated --json` with any extra args, run in
+// workDir with env appended to the inherited environment.
+func outdatedCommand(ctx context.Context, workDir string, env []string, extraArgs ...string) *exec.Cmd {
+	args := append([]string{"outdated", "--json"}, extraArgs...)
+	cmd := exec.CommandContext(ctx, "pnpm", args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), env...)
+	return cmd
+}
+
+// runOutdated runs a `pnpm outdated` command built by outdatedCommand.
+func runOutdated(cmd *exec.Cmd) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := scanner.Output(cmd) // pnpm outdated may return non-zero when updates are available
+	if err != nil {
+		// Registry auth failures also exit with 1, so check for them first
+		if authErr := scanner.RegistryAuthError("pnpm", append(out, stderr.Bytes()...)); authErr != nil {
+			return nil, authErr
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			if looksLikeJSON(out) {
+				return out, nil
 			}
-			return out, nil
-		},
+		}
+		if len(strings.TrimSpace(string(out))) > 0 {
+			return nil, fmt.Errorf("pnpm outdated failed: %w, output: %s", err, strings.TrimSpace(string(out)))
+		}
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("pnpm outdated failed: %w, stderr: %s", err, stderr.String())
+		}
+		return nil, err
 	}
+	return out, nil
 }
 
 // GetUpdates returns all pnpm packages that have available updates.
 func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
-	pkgJSON, err := s.readPackageJSON()
+	s.env = opts.Env
+	s.npm = registry.NewNpmClient(s.workDir, opts.Registry, opts.Env)
+
+	match, err := scanner.CompileFilter(opts.Filter, true)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, err := s.readProject()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read package.json: %w", err)
 	}
 
-	output, err := s.runPnpmOutdated()
+	outdatedCmd := s.runPnpmOutdated
+	if len(proj.workspaces) > 0 {
+		outdatedCmd = s.runPnpmOutdatedRecursive
+	}
+	ctx := opts.Ctx()
+	output, err := outdatedCmd(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to run pnpm outdated: %w", err)
+		return nil, scanner.CommandError(ctx, "pnpm", fmt.Errorf("failed to run pnpm outdated: %w", err))
 	}
 
 	if len(output) == 0 {
@@ -88,42 +218,81 @@ func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
 	}
 
 	var modules []scanner.Module
-	var outdatedMap pnpmOutdated
+	addModule := func(name, current, wanted, latest, packageType, dependent string) {
+		pkgJSON, ws := proj.manifestFor(dependent)
+		declared := pkgJSON.dependencyType(name)
+
+		depType := packageType
+		if depType == "" {
+			depType = declared
+		}
+
+		// Peer and optional dependencies need --dev, transitive ones --transitive
+		if !opts.Includes(depType, scanner.IsDefaultNodeDependencyType(depType)) {
+			return
+		}
+
+		// Apply filter
+		if !match(name) {
+			return
+		}
+
+		update := scanner.NodeUpdate(current, wanted, latest, opts.InRange)
+		if update == nil {
+			return
+		}
+
+		modules = append(modules, scanner.Module{
+			Name:           name,
+			Version:        current,
+			Direct:         declared != "transitive",
+			DependencyType: depType,
+			Workspace:      ws,
+			Update:         update,
+			RangeBlocked:   scanner.RangeBlocked(current, wanted, latest),
+		})
+	}
+
+	addPackage := func(name string, info pnpmPackageInfo, ws string) {
+		if len(info.DependentPackages) == 0 {
+			addModule(name, info.Current, info.Wanted, info.Latest, info.DependencyType, ws)
+			return
+		}
+		// A single reported dependency type can't describe several dependents,
+		// so each one's own manifest decides
+		depType := info.DependencyType
+		if len(info.DependentPackages) > 1 {
+			depType = ""
+		}
+		for _, dependent := range info.DependentPackages {
+			addModule(name, info.Current, info.Wanted, info.Latest, depType, dependent.Name)
+		}
+	}
+
+	var outdatedMap map[string]json.RawMessage
 	if err := json.Unmarshal(output, &outdatedMap); err == nil {
-		for name, info := range outdatedMap {
-			_, isDirect := pkgJSON.Dependencies[name]
-			_, isDevDirect := pkgJSON.DevDependencies[name]
-
-			depType := "dependencies"
-			if isDevDirect {
-				depType = "devDependencies"
-			} else if !isDirect {
-				depType = "transitive"
+		// Visit keys by name so results don't depend on map order
+		for _, key := range slices.Sorted(maps.Keys(outdatedMap)) {
+			var info pnpmPackageInfo
+			if err := json.Unmarshal(outdatedMap[key], &info); err != nil {
+				return nil, fmt.Errorf("failed to parse pnpm outdated output: %w", err)
 			}
-
-			// Filter transitive dependencies if not including all
-			if !opts.IncludeAll && depType == "transitive" {
+			if info.isPackage() {
+				addPackage(key, info, "")
 				continue
 			}
 
-			// Apply filter
-			if opts.Filter != "" && !strings.Contains(name, opts.Filter) {
-				continue
+			var nested pnpmOutdated
+			if err := json.Unmarshal(outdatedMap[key], &nested); err != nil {
+				return nil, fmt.Errorf("failed to parse pnpm outdated output for %s: %w", key, err)
 			}
-
-			module := scanner.Module{
-				Name:           name,
-				Version:        info.Current,
-				Direct:         isDirect || isDevDirect,
-				DependencyType: depType,
-				Update: &scanner.UpdateInfo{
-					Version: info.Latest,
-				},
+			ws := proj.workspaceFor(key, s.workDir)
+			for _, name := range slices.Sorted(maps.Keys(nested)) {
+				addPackage(name, nested[name], ws)
 			}
-			modules = append(modules, module)
 		}
 
-		return modules, nil
+		return registry.FillTimesFor(opts, modules, s.fetchPackageTime), nil
 	}
 
 	var outdatedList []pnpmOutdatedEntry
@@ -132,47 +301,17 @@ func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
 	}
 
 	for _, info := range outdatedList {
-		name := info.Name
-		if name == "" {
+		if info.Name == "" {
 			continue
 		}
-
-		_, isDirect := pkgJSON.Dependencies[name]
-		_, isDevDirect := pkgJSON.DevDependencies[name]
-
 		depType := info.PackageType
 		if depType == "" {
-			depType = "dependencies"
-			if isDevDirect {
-				depType = "devDependencies"
-			} else if !isDirect {
-				depType = "transitive"
-			}
-		}
-
-		// Filter transitive dependencies if not including all
-		if !opts.IncludeAll && depType == "transitive" {
-			continue
+			depType = info.DependencyType
 		}
-
-		// Apply filter
-		if opts.Filter != "" && !strings.Contains(name, opts.Filter) {
-			continue
-		}
-
-		module := scanner.Module{
-			Name:           name,
-			Version:        info.Current,
-			Direct:         isDirect || isDevDirect,
-			DependencyType: depType,
-			Update: &scanner.UpdateInfo{
-				Version: info.Latest,
-			},
-		}
-		modules = append(modules, module)
+		addModule(info.Name, info.Current, info.Wanted, info.Latest, depType, "")
 	}
 
-	return modules, nil
+	return registry.FillTimesFor(opts, modules, s.fetchPackageTime), nil
 }
 
 func looksLikeJSON(b []byte) bool {
@@ -184,24 +323,65 @@ func looksLikeJSON(b []byte) bool {
 }
 
 // GetDependencyIndex returns a map of pnpm package names to their dependency information.
+// Workspace member dependencies are merged in; a production classification wins,
+// followed by dev, optional and peer.
 func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
-	pkgJSON, err := s.readPackageJSON()
+	proj, err := s.readProject()
 	if err != nil {
 		return nil, err
 	}
 
 	idx := make(scanner.DependencyIndex)
-	for name := range pkgJSON.Dependencies {
-		idx[name] = scanner.DependencyInfo{Direct: true, Type: "dependencies"}
+	manifests := []*packageJSON{proj.root}
+	for _, ws := range proj.workspaces {
+		manifests = append(manifests, ws)
 	}
-	for name := range pkgJSON.DevDependencies {
-		idx[name] = scanner.DependencyInfo{Direct: true, Type: "devDependencies"}
+	// Sections are visited in increasing precedence, so later ones win
+	for _, sec := range []string{"peerDependencies", "optionalDependencies", "devDependencies", "dependencies"} {
+		for _, pkgJSON := range manifests {
+			for name := range pkgJSON.section(sec) {
+				idx[name] = scanner.DependencyInfo{Direct: true, Type: sec}
+			}
+		}
 	}
 	return idx, nil
 }
 
-func (s *Scanner) readPackageJSON() (*packageJSON, error) {
-	path := filepath.Join(s.workDir, "package.json")
+// readProject reads the root package.json and, when a pnpm-workspace.yaml is
+// present, the package.json of every workspace member it declares.
+func (s *Scanner) readProject() (*project, error) {
+	root, err := readPackageJSON(filepath.Join(s.workDir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	proj := &project{root: root}
+
+	data, err := os.ReadFile(filepath.Join(s.workDir, "pnpm-workspace.yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return proj, nil
+		}
+		return nil, err
+	}
+
+	members, err := workspace.Resolve(s.workDir, workspace.PnpmPatterns(data))
+	if err != nil {
+		return nil, err
+	}
+	proj.workspaces = make(map[string]*packageJSON, len(members))
+	proj.dirs = make(map[string]string, len(members))
+	for _, member := range members {
+		pkg, err := readPackageJSON(filepath.Join(s.workDir, filepath.FromSlash(member.Dir), "package.json"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", member.Dir, err)
+		}
+		proj.workspaces[member.Name] = pkg
+		proj.dirs[member.Dir] = member.Name
+	}
+	return proj, nil
+}
+
+func readPackageJSON(path string) (*packageJSON, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -1,10 +1,17 @@
 package pnpm
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
@@ -53,14 +60,15 @@ func TestGetUpdates(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPnpmOutdated: func() ([]byte, error) {
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
 
 	// Test Case 1: Default options (include direct dev dependencies, exclude transitive)
 	opts := scanner.Options{
-		IncludeAll: false,
+		IncludeDev:        false,
+		IncludeTransitive: false,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -106,9 +114,12 @@ func TestGetUpdates(t *testing.T) {
 			if m.DependencyType != "devDependencies" {
 				t.Errorf("expected dependency type 'devDependencies', got %s", m.DependencyType)
 			}
+			if m.Update.Version != "1.0.0" || m.Update.Wanted != "0.34.6" {
+				t.Errorf("expected latest 1.0.0 and wanted 0.34.6, got %+v", m.Update)
+			}
 		}
 		if m.Name == "@types/node" {
-			t.Error("@types/node should not be included when IncludeAll=false")
+			t.Error("@types/node should not be included by default")
 		}
 	}
 
@@ -122,15 +133,15 @@ func TestGetUpdates(t *testing.T) {
 		t.Error("vitest not found")
 	}
 
-	// Test Case 2: IncludeAll = true
-	opts.IncludeAll = true
+	// Test Case 2: IncludeDev and IncludeTransitive
+	opts.IncludeDev, opts.IncludeTransitive = true, true
 	modules, err = s.GetUpdates(opts)
 	if err != nil {
-		t.Fatalf("GetUpdates(IncludeAll) failed: %v", err)
+		t.Fatalf("GetUpdates(IncludeDev, IncludeTransitive) failed: %v", err)
 	}
 
 	if len(modules) != 4 {
-		t.Errorf("expected 4 modules with IncludeAll, got %d", len(modules))
+		t.Errorf("expected 4 modules with IncludeDev and IncludeTransitive, got %d", len(modules))
 	}
 
 	// Verify vitest is included with correct type
@@ -147,7 +158,34 @@ func TestGetUpdates(t *testing.T) {
 		}
 	}
 	if !foundVitest {
-		t.Error("vitest not found with IncludeAll=true")
+		t.Error("vitest not found with IncludeDev and IncludeTransitive")
+	}
+}
+
+func TestGetUpdates_RangeBlocked(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSONBytes, _ := json.Marshal(packageJSON{
+		Dependencies: map[string]string{"express": "^4.0.0"},
+	})
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), pkgJSONBytes, 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+	outdatedBytes, _ := json.Marshal(pnpmOutdated{
+		"express": {Current: "4.21.0", Wanted: "4.21.0", Latest: "5.0.0"},
+	})
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+	}
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || !modules[0].RangeBlocked {
+		t.Errorf("expected ^4.0.0 to block express 5.0.0, got %+v", modules)
 	}
 }
 
@@ -174,7 +212,7 @@ func TestGetUpdates_Filter(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPnpmOutdated: func() ([]byte, error) {
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
@@ -199,6 +237,52 @@ func TestGetUpdates_Filter(t *testing.T) {
 	}
 }
 
+func TestGetUpdates_FilterAlternation(t *testing.T) {
+	tmpDir := t.TempDir()
+	mockPkgJSON := packageJSON{
+		Dependencies: map[string]string{
+			"react":     "^18.0.0",
+			"react-dom": "^18.0.0",
+			"vue":       "^3.0.0",
+		},
+	}
+	pkgJSONBytes, _ := json.Marshal(mockPkgJSON)
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), pkgJSONBytes, 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	mockOutdated := pnpmOutdated{
+		"react":     {Current: "18.0.0", Latest: "18.2.0", Wanted: "18.2.0"},
+		"react-dom": {Current: "18.0.0", Latest: "18.2.0", Wanted: "18.2.0"},
+		"vue":       {Current: "3.0.0", Latest: "3.3.0", Wanted: "3.3.0"},
+	}
+	outdatedBytes, _ := json.Marshal(mockOutdated)
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{Filter: "^react$|vue"})
+	if err != nil {
+		t.Fatalf("GetUpdates with filter failed: %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, m := range modules {
+		got[m.Name] = true
+	}
+	if len(got) != 2 || !got["react"] || !got["vue"] {
+		t.Errorf("expected react and vue for '^react$|vue', got %+v", modules)
+	}
+
+	if _, err := s.GetUpdates(scanner.Options{Filter: "react("}); err == nil {
+		t.Error("expected an error for an invalid filter pattern")
+	}
+}
+
 func TestGetUpdates_EmptyOutdated(t *testing.T) {
 	tmpDir := t.TempDir()
 	mockPkgJSON := packageJSON{
@@ -213,7 +297,7 @@ func TestGetUpdates_EmptyOutdated(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPnpmOutdated: func() ([]byte, error) {
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
 			return []byte{}, nil
 		},
 	}
@@ -254,12 +338,12 @@ func TestGetUpdates_ArrayOutputFormat(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPnpmOutdated: func() ([]byte, error) {
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
 
-	modules, err := s.GetUpdates(scanner.Options{IncludeAll: false})
+	modules, err := s.GetUpdates(scanner.Options{})
 	if err != nil {
 		t.Fatalf("GetUpdates failed: %v", err)
 	}
@@ -329,3 +413,361 @@ func TestGetDependencyIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestGetUpdates_Workspaces(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"package.json":            `{"name": "root", "devDependencies": {"typescript": "^5.0.0"}}`,
+		"pnpm-workspace.yaml":     "packages:\n  - 'packages/*'\n",
+		"packages/a/package.json": `{"name": "pkg-a", "dependencies": {"lodash": "^4.17.0"}}`,
+		"packages/b/package.json": `{"name": "pkg-b", "devDependencies": {"lodash": "^4.17.0"}}`,
+	}
+	for rel, contents := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+
+	outdated := `{
+		"lodash": {"current": "4.17.0", "latest": "4.17.21", "wanted": "4.17.21",
+			"dependentPackages": [{"name": "pkg-a", "location": "packages/a"}, {"name": "pkg-b", "location": "packages/b"}]},
+		"typescript": {"current": "5.0.0", "latest": "5.4.0", "wanted": "5.4.0",
+			"dependentPackages": [{"name": "root", "location": "."}]}
+	}`
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
+			t.Fatalf("expected recursive pnpm outdated to be used")
+			return nil, nil
+		},
+		runPnpmOutdatedRecursive: func(context.Context) ([]byte, error) {
+			return []byte(outdated), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 3 {
+		t.Fatalf("expected 3 modules, got %d: %+v", len(modules), modules)
+	}
+
+	got := make(map[string]scanner.Module)
+	for _, m := range modules {
+		got[m.Name+"|"+m.Workspace] = m
+	}
+	if m, ok := got["lodash|pkg-a"]; !ok || m.DependencyType != "dependencies" {
+		t.Errorf("expected lodash as dependency of pkg-a, got %+v", m)
+	}
+	if m, ok := got["lodash|pkg-b"]; !ok || m.DependencyType != "devDependencies" {
+		t.Errorf("expected lodash as devDependency of pkg-b, got %+v", m)
+	}
+	if m, ok := got["typescript|"]; !ok || m.DependencyType != "devDependencies" {
+		t.Errorf("expected typescript from root, got %+v", m)
+	}
+}
+
+func TestGetUpdates_DependencyTypeField(t *testing.T) {
+	tmpDir := t.TempDir()
+	// vitest isn't declared in package.json, so only pnpm's reported
+	// dependencyType can classify it
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{"name": "app"}`), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	outdated := `{
+		"vitest": {"current": "0.34.0", "latest": "1.0.0", "wanted": "0.34.6", "isDeprecated": false, "dependencyType": "devDependencies"}
+	}`
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
+			return []byte(outdated), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 {
+		t.Fatalf("expected 1 module, got %d: %+v", len(modules), modules)
+	}
+	if modules[0].DependencyType != "devDependencies" {
+		t.Errorf("expected the reported devDependencies type, got %s", modules[0].DependencyType)
+	}
+}
+
+func TestGetUpdates_WorkspaceKeyedOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"package.json":            `{"name": "root", "devDependencies": {"typescript": "^5.0.0"}}`,
+		"pnpm-workspace.yaml":     "packages:\n  - 'packages/*'\n",
+		"packages/a/package.json": `{"name": "pkg-a", "dependencies": {"lodash": "^4.17.0"}}`,
+		"packages/b/package.json": `{"name": "pkg-b", "devDependencies": {"vitest": "^0.34.0"}}`,
+	}
+	for rel, contents := range files {
+		path := filepath.Join(tmpDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", rel, err)
+		}
+	}
+
+	// Workspaces keyed by package name, relative and absolute directory
+	outdated := `{
+		"root": {
+			"typescript": {"current": "5.0.0", "latest": "5.4.0", "wanted": "5.4.0", "dependencyType": "devDependencies"}
+		},
+		"packages/a": {
+			"lodash": {"current": "4.17.0", "latest": "4.17.21", "wanted": "4.17.21", "dependencyType": "dependencies"}
+		},
+		` + strconv.Quote(filepath.Join(tmpDir, "packages", "b")) + `: {
+			"vitest": {"current": "0.34.0", "latest": "1.0.0", "wanted": "0.34.6", "dependencyType": "devDependencies"}
+		}
+	}`
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdatedRecursive: func(context.Context) ([]byte, error) {
+			return []byte(outdated), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 3 {
+		t.Fatalf("expected 3 modules, got %d: %+v", len(modules), modules)
+	}
+
+	got := make(map[string]scanner.Module)
+	for _, m := range modules {
+		got[m.Name+"|"+m.Workspace] = m
+	}
+	if m, ok := got["typescript|"]; !ok || m.DependencyType != "devDependencies" {
+		t.Errorf("expected typescript from root, got %+v", m)
+	}
+	if m, ok := got["lodash|pkg-a"]; !ok || m.DependencyType != "dependencies" || !m.Direct {
+		t.Errorf("expected lodash as dependency of pkg-a, got %+v", m)
+	}
+	if m, ok := got["vitest|pkg-b"]; !ok || m.DependencyType != "devDependencies" || m.Update.Wanted != "0.34.6" {
+		t.Errorf("expected vitest as devDependency of pkg-b, got %+v", m)
+	}
+}
+
+func TestGetUpdates_PeerAndOptionalDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSON := `{
+		"dependencies": {"react": "^18.0.0"},
+		"devDependencies": {"react-dom": "^18.0.0"},
+		"peerDependencies": {"react-dom": "^18.0.0", "vue": "^3.0.0"},
+		"optionalDependencies": {"fsevents": "^2.0.0"}
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(pkgJSON), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	outdated := `{
+		"react": {"current": "18.0.0", "latest": "18.2.0", "wanted": "18.2.0"},
+		"react-dom": {"current": "18.0.0", "latest": "18.2.0", "wanted": "18.2.0"},
+		"vue": {"current": "3.0.0", "latest": "3.4.0", "wanted": "3.4.0"},
+		"fsevents": {"current": "2.0.0", "latest": "2.3.3", "wanted": "2.3.3"}
+	}`
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
+			return []byte(outdated), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	// react-dom is also a devDependency, which takes precedence over peer
+	if len(modules) != 2 {
+		t.Fatalf("expected react and react-dom by default, got %+v", modules)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, m := range modules {
+		got[m.Name] = m.DependencyType
+	}
+	want := map[string]string{
+		"react":     "dependencies",
+		"react-dom": "devDependencies",
+		"vue":       "peerDependencies",
+		"fsevents":  "optionalDependencies",
+	}
+	for name, depType := range want {
+		if got[name] != depType {
+			t.Errorf("expected %s as %s, got %q", name, depType, got[name])
+		}
+	}
+}
+
+func TestGetUpdates_Cooldown(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSON := `{"dependencies": {"fresh": "^1.0.0", "stable": "^1.0.0"}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(pkgJSON), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	output, _ := json.Marshal(pnpmOutdated{
+		"fresh":  {Current: "1.0.0", Latest: "1.1.0"},
+		"stable": {Current: "1.0.0", Latest: "2.0.0"},
+	})
+	times := map[string]string{
+		"fresh@1.1.0":  time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+		"stable@2.0.0": time.Now().Add(-30 * 24 * time.Hour).Format(time.RFC3339),
+	}
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
+			return output, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return times[name+"@"+version], nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{CooldownDays: 7})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "stable" {
+		t.Fatalf("expected only stable to pass the cooldown, got %+v", modules)
+	}
+	if modules[0].Update.Time != times["stable@2.0.0"] {
+		t.Errorf("expected update time to be set, got %q", modules[0].Update.Time)
+	}
+}
+
+func TestGetUpdates_MissingBinary(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
+			return nil, &exec.Error{Name: "pnpm", Err: exec.ErrNotFound}
+		},
+	}
+
+	_, err := s.GetUpdates(scanner.Options{})
+	if err == nil || err.Error() != "pnpm not found in PATH; install it or use --manager" {
+		t.Fatalf("expected a friendly missing binary error, got %v", err)
+	}
+	var missing *scanner.MissingToolError
+	if !errors.As(err, &missing) || !strings.Contains(missing.Err.Error(), "executable file not found") {
+		t.Errorf("expected the exec error to be wrapped, got %#v", err)
+	}
+}
+
+func TestGetUpdates_Timeout(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(ctx context.Context) ([]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.GetUpdates(scanner.Options{Context: ctx})
+	if err == nil || !strings.Contains(err.Error(), "pnpm did not finish before the timeout") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline error to be wrapped, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = s.GetUpdates(scanner.Options{Context: ctx})
+	if err == nil || !strings.Contains(err.Error(), "pnpm was canceled") {
+		t.Fatalf("expected a cancellation error, got %v", err)
+	}
+}
+
+func TestGetUpdates_DeterministicOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	deps := map[string]string{}
+	mockOutdated := pnpmOutdated{}
+	for _, name := range []string{"vue", "axios", "react", "lodash", "express", "zod", "chalk", "dayjs"} {
+		deps[name] = "^1.0.0"
+		mockOutdated[name] = pnpmPackageInfo{Current: "1.0.0", Latest: "2.0.0", Wanted: "1.0.0"}
+	}
+	pkgJSONBytes, _ := json.Marshal(packageJSON{Dependencies: deps})
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), pkgJSONBytes, 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+	outdatedBytes, _ := json.Marshal(mockOutdated)
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPnpmOutdated: func(context.Context) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+	}
+
+	order := func() string {
+		modules, err := s.GetUpdates(scanner.Options{})
+		if err != nil {
+			t.Fatalf("GetUpdates failed: %v", err)
+		}
+		names := make([]string, len(modules))
+		for i, m := range modules {
+			names[i] = m.Name
+		}
+		return strings.Join(names, " ")
+	}
+
+	first := order()
+	if first != "axios chalk dayjs express lodash react vue zod" {
+		t.Fatalf("expected modules sorted by name, got %s", first)
+	}
+	if second := order(); first != second {
+		t.Errorf("order changed between runs: %s vs %s", first, second)
+	}
+}
+
+func TestRunOutdated_AuthErrorExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as a fake pnpm")
+	}
+
+	// pnpm reports registry auth failures as JSON on stdout with exit code 1
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho '{\"error\":{\"code\":\"ERR_PNPM_FETCH_401\",\"message\":\"GET https://npm.pkg.github.com/@myorg%2Fui: Unauthorized - 401\"}}'\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(binDir, "pnpm"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write fake pnpm: %v", err)
+	}
+	t.Setenv("PATH", binDir)
+
+	_, err := runOutdated(outdatedCommand(context.Background(), t.TempDir(), nil))
+	var authErr *scanner.AuthError
+	if !errors.As(err, &authErr) || authErr.Tool != "pnpm" {
+		t.Fatalf("expected a pnpm AuthError, got %v", err)
+	}
+}
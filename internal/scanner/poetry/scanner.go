@@ -3,95 +3,98 @@ package poetry
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/pragmaticivan/faro/internal/log"
+	"github.com/pragmaticivan/faro/internal/registry"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
 // Scanner implements scanner.Scanner for Poetry.
 type Scanner struct {
-	workDir      string
-	runPoetryCmd func(args ...string) ([]byte, error)
+	workDir          string
+	runPoetryCmd     func(ctx context.Context, args ...string) ([]byte, error)
+	fetchPackageTime func(ctx context.Context, name, version string) (string, error)
+	pypi             *registry.PyPIClient // Index lookups of the current GetUpdates call
 }
 
 // NewScanner creates a new Poetry scanner.
 func NewScanner(workDir string) *Scanner {
-	return &Scanner{
+	s := &Scanner{
 		workDir: workDir,
-		runPoetryCmd: func(args ...string) ([]byte, error) {
-			cmd := exec.Command("poetry", args...)
+		runPoetryCmd: func(ctx context.Context, args ...string) ([]byte, error) {
+			cmd := exec.CommandContext(ctx, "poetry", args...)
 			cmd.Dir = workDir
-			return cmd.Output()
+			return scanner.Output(cmd)
 		},
 	}
+	s.fetchPackageTime = func(ctx context.Context, name, version string) (string, error) {
+		return s.pypi.PublishTime(ctx, name, version)
+	}
+	return s
 }
 
 // GetUpdates returns all Poetry packages that have available updates.
 func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
-	// Read pyproject.toml to determine dependency types
-	depIdx, err := s.GetDependencyIndex()
+	match, err := scanner.CompileFilter(opts.Filter, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read pyproject.toml and poetry.lock to determine dependency types
+	depIdx, locked, err := s.dependencyIndex()
 	if err != nil {
 		return nil, err
 	}
 
 	// Run poetry show --outdated to get updates
-	output, err := s.runPoetryCmd("show", "--outdated")
+	ctx := opts.Ctx()
+	output, err := s.runPoetryCmd(ctx, "show", "--outdated", "--no-ansi")
 	// If no outdated packages, poetry show --outdated may return error
 	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) || ctx.Err() != nil {
+			return nil, scanner.CommandError(ctx, "poetry", err)
+		}
 		return []scanner.Module{}, nil
 	}
 
 	lines := strings.Split(string(output), "\n")
 	var modules []scanner.Module
 	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Parse line: "package-name (!) current latest [description]"
-		// OR: "package-name current latest [description]"
-		// The (!) indicator may or may not be present
-		fields := strings.Fields(line)
-		if len(fields) < 3 {
+		name, current, latest, ok := parseOutdatedLine(line)
+		if !ok {
+			// Poetry mixes warnings and notices into the table
+			if strings.TrimSpace(line) != "" {
+				log.Debugf("ignoring poetry show line %q", line)
+			}
 			continue
 		}
 
-		name := fields[0]
-		var current, latest string
-
-		// Check if second field is the (!) indicator
-		if fields[1] == "(!)" {
-			if len(fields) < 4 {
+		depInfo, known := depIdx[normalizeName(name)]
+		if !known {
+			// With a lock file, packages it doesn't reach are merely
+			// installed in the environment, not project dependencies
+			if locked {
+				log.Debugf("skipping %s: not in the poetry.lock dependency graph", name)
 				continue
 			}
-			current = fields[2]
-			latest = fields[3]
-		} else {
-			current = fields[1]
-			latest = fields[2]
-		}
-
-		depInfo, isDirect := depIdx[name]
-		if !isDirect {
 			depInfo = scanner.DependencyInfo{Direct: false, Type: "transitive"}
 		}
 
-		// Filter dev dependencies if not including all
-		if !opts.IncludeAll && depInfo.Type == "dev" {
-			continue
-		}
-
-		// Filter transitive if not including all
-		if !opts.IncludeAll && !depInfo.Direct {
+		// Other groups and extras need IncludeDev, transitive dependencies
+		// IncludeTransitive
+		if !opts.Includes(depInfo.Type, depInfo.Type == "main") {
 			continue
 		}
 
 		// Apply filter
-		if opts.Filter != "" && !strings.Contains(name, opts.Filter) {
+		if !match(name) {
 			continue
 		}
 
@@ -107,91 +110,375 @@ func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
 		modules = append(modules, module)
 	}
 
-	return modules, nil
+	s.pypi = registry.NewPyPIClient(s.indexURL(), nil)
+	return registry.FillTimesFor(opts, modules, s.fetchPackageTime), nil
+}
+
+// indexURL returns the package index Poetry resolves against, the primary
+// [[tool.poetry.source]] of pyproject.toml; "" is the public PyPI.
+func (s *Scanner) indexURL() string {
+	url, err := readPrimarySource(filepath.Join(s.workDir, "pyproject.toml"))
+	if err != nil {
+		log.Debugf("poetry: reading package sources: %v", err)
+		return ""
+	}
+	return url
+}
+
+// parseOutdatedLine parses a line of `poetry show --outdated` output:
+//
+//	name [(!)] current latest [description...]
+//
+// The (!) marker flags packages that aren't installed. Lines that don't
+// carry two versions, such as warnings, are rejected.
+func parseOutdatedLine(line string) (name, current, latest string, ok bool) {
+	fields := strings.Fields(ansiEscape.ReplaceAllString(line, ""))
+	if len(fields) < 3 {
+		return "", "", "", false
+	}
+
+	name = fields[0]
+	rest := fields[1:]
+	if rest[0] == "(!)" {
+		rest = rest[1:]
+	}
+	if len(rest) < 2 || !looksLikeVersion(rest[0]) || !looksLikeVersion(rest[1]) {
+		return "", "", "", false
+	}
+	return name, rest[0], rest[1], true
+}
+
+// looksLikeVersion reports whether s starts like a PEP 440 version.
+func looksLikeVersion(s string) bool {
+	s = strings.TrimPrefix(s, "v")
+	return s != "" && s[0] >= '0' && s[0] <= '9'
 }
 
 // GetDependencyIndex returns a map of Poetry package names to their dependency information.
+// Packages poetry.lock reaches from the declared dependencies are transitive.
 func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
-	deps, devDeps, err := s.readPyprojectToml()
+	idx, _, err := s.dependencyIndex()
+	return idx, err
+}
+
+// dependencyIndex builds the dependency index and reports whether it
+// includes the transitive dependencies from poetry.lock.
+func (s *Scanner) dependencyIndex() (scanner.DependencyIndex, bool, error) {
+	deps, err := s.readPyprojectToml()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	idx := make(scanner.DependencyIndex)
-
-	// Parse main dependencies
-	for name := range deps {
+	for name, depType := range deps {
 		if name == "python" {
 			continue
 		}
-		idx[name] = scanner.DependencyInfo{Direct: true, Type: "main"}
+		idx[name] = scanner.DependencyInfo{Direct: true, Type: depType}
+	}
+
+	lock, err := readPoetryLock(filepath.Join(s.workDir, "poetry.lock"))
+	if err != nil {
+		return nil, false, err
+	}
+	if lock == nil {
+		return idx, false, nil
+	}
+
+	// Walk the lock's dependency graph from the declared dependencies
+	queue := make([]string, 0, len(idx))
+	for name := range idx {
+		queue = append(queue, name)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, dep := range lock[name].Dependencies {
+			if _, seen := idx[dep]; seen {
+				continue
+			}
+			idx[dep] = scanner.DependencyInfo{Direct: false, Type: "transitive"}
+			queue = append(queue, dep)
+		}
+	}
+	return idx, true, nil
+}
+
+// lockedPackage is a [[package]] entry of poetry.lock.
+type lockedPackage struct {
+	Version      string
+	Dependencies []string // Normalized names from [package.dependencies]
+}
+
+// readPoetryLock reads poetry.lock into a map of normalized package names to
+// their locked version and dependencies. A missing file yields nil.
+// Like readPyprojectToml, it only understands the tables it needs.
+func readPoetryLock(path string) (map[string]lockedPackage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	lock := make(map[string]lockedPackage)
+	var name string
+	var pkg lockedPackage
+	flush := func() {
+		if name != "" {
+			lock[name] = pkg
+		}
+		name, pkg = "", lockedPackage{}
+	}
+
+	scanner := bufio.NewScanner(file)
+	var section string
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if line == "[[package]]" {
+				flush()
+			}
+			section = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.TrimSpace(value)
+
+		switch section {
+		case "package":
+			switch key {
+			case "name":
+				name = normalizeName(strings.Trim(value, `"'`))
+			case "version":
+				pkg.Version = strings.Trim(value, `"'`)
+			}
+		case "package.dependencies":
+			// Skip the entries of multi-line constraint arrays
+			if m := requirementName.FindStringSubmatch(key); m != nil && m[1] == key {
+				pkg.Dependencies = append(pkg.Dependencies, normalizeName(key))
+			}
+		}
 	}
+	flush()
 
-	// Parse dev dependencies
-	for name := range devDeps {
-		idx[name] = scanner.DependencyInfo{Direct: true, Type: "dev"}
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
+	return lock, nil
+}
+
+var (
+	// quotedString matches a double- or single-quoted TOML string.
+	quotedString = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+	// requirementName matches the package name at the start of a PEP 508
+	// requirement such as "requests[socks]>=2.28; python_version>'3.8'".
+	requirementName = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+	// nameSeparators matches runs of characters PEP 503 treats as equivalent.
+	nameSeparators = regexp.MustCompile(`[-_.]+`)
+
+	// groupSection matches [tool.poetry.group.<name>.dependencies] headers.
+	groupSection = regexp.MustCompile(`^tool\.poetry\.group\.("[^"]+"|'[^']+'|[^.]+)\.dependencies$`)
 
-	return idx, nil
+	// ansiEscape matches terminal color sequences, in case --no-ansi is ignored.
+	ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+)
+
+// normalizeName returns the PEP 503 normalized form of a package name.
+func normalizeName(name string) string {
+	return strings.ToLower(nameSeparators.ReplaceAllString(name, "-"))
 }
 
-// readPyprojectToml reads and parses pyproject.toml dependencies.
-// This is a simplified TOML parser that only extracts dependency names.
-func (s *Scanner) readPyprojectToml() (deps map[string]bool, devDeps map[string]bool, err error) {
+// readPyprojectToml reads pyproject.toml and maps each declared dependency
+// (by normalized name) to its type: "main", "optional", or the name of the
+// Poetry dependency group that declares it (e.g. "dev", "test", "docs").
+// This is a simplified TOML parser that understands Poetry's
+// [tool.poetry.*dependencies] tables and PEP 621 [project] dependency arrays.
+func (s *Scanner) readPyprojectToml() (map[string]string, error) {
 	path := filepath.Join(s.workDir, "pyproject.toml")
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	defer func() { _ = file.Close() }()
 
-	deps = make(map[string]bool)
-	devDeps = make(map[string]bool)
+	deps := make(map[string]string)
+	add := func(name, depType string) {
+		name = normalizeName(strings.Trim(name, `"'`))
+		// A main classification wins over groups and extras
+		if prev, ok := deps[name]; ok && prev == "main" {
+			return
+		}
+		deps[name] = depType
+	}
+	addRequirements := func(line, depType string) {
+		for _, m := range quotedString.FindAllStringSubmatch(line, -1) {
+			req := m[1] + m[2]
+			if name := requirementName.FindStringSubmatch(req); name != nil {
+				add(name[1], depType)
+			}
+		}
+	}
 
 	scanner := bufio.NewScanner(file)
-	var inDependencies, inDevDependencies bool
+	var section string
+	var arrayType string // Type of the PEP 621 array being read, if any
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 
+		// Continuation of a multi-line requirements array
+		if arrayType != "" {
+			addRequirements(line, arrayType)
+			if strings.Contains(quotedString.ReplaceAllString(line, ""), "]") {
+				arrayType = ""
+			}
+			continue
+		}
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
 		// Check for section headers
-		if strings.HasPrefix(line, "[tool.poetry.dependencies]") {
-			inDependencies = true
-			inDevDependencies = false
+		if strings.HasPrefix(line, "[") {
+			section = strings.TrimSpace(strings.Trim(line, "[]"))
 			continue
-		} else if strings.HasPrefix(line, "[tool.poetry.dev-dependencies]") || strings.HasPrefix(line, "[tool.poetry.group.dev.dependencies]") {
-			inDevDependencies = true
-			inDependencies = false
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
 			continue
-		} else if strings.HasPrefix(line, "[") {
-			// New section started
-			inDependencies = false
-			inDevDependencies = false
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if m := groupSection.FindStringSubmatch(section); m != nil {
+			add(key, strings.Trim(m[1], `"'`))
 			continue
 		}
 
-		// Skip empty lines and comments
+		var depType string
+		switch section {
+		case "tool.poetry.dependencies":
+			add(key, "main")
+			continue
+		case "tool.poetry.dev-dependencies":
+			add(key, "dev")
+			continue
+		case "project":
+			if key != "dependencies" {
+				continue
+			}
+			depType = "main"
+		case "project.optional-dependencies":
+			depType = "optional"
+		default:
+			continue
+		}
+
+		// PEP 621 arrays of requirement strings, possibly spanning lines
+		if !strings.HasPrefix(value, "[") {
+			continue
+		}
+		addRequirements(value, depType)
+		if !strings.Contains(quotedString.ReplaceAllString(value, ""), "]") {
+			arrayType = depType
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// readPrimarySource returns the URL of the package source Poetry resolves
+// against instead of PyPI: the first [[tool.poetry.source]] marked
+// priority = "default" or the legacy default = true, or else the first
+// primary one, which is what a source without a priority is. Supplemental
+// and explicit sources only serve some packages and are skipped. It returns
+// "" when no source replaces PyPI.
+func readPrimarySource(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	type source struct {
+		url      string
+		priority string
+	}
+	var sources []source
+	inSource := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
+		if strings.HasPrefix(line, "[") {
+			inSource = strings.TrimSpace(strings.Trim(line, "[]")) == "tool.poetry.source"
+			if inSource {
+				sources = append(sources, source{priority: "primary"})
+			}
+			continue
+		}
+		if !inSource {
+			continue
+		}
 
-		// Parse dependency line (format: package = "version")
-		if inDependencies || inDevDependencies {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				pkgName := strings.TrimSpace(parts[0])
-				if inDependencies {
-					deps[pkgName] = true
-				} else {
-					devDeps[pkgName] = true
-				}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if m := quotedString.FindStringSubmatch(value); m != nil {
+			value = m[1] + m[2]
+		}
+		src := &sources[len(sources)-1]
+		switch strings.TrimSpace(key) {
+		case "url":
+			src.url = value
+		case "priority":
+			src.priority = value
+		case "default":
+			if strings.HasPrefix(value, "true") {
+				src.priority = "default"
+			}
+		case "secondary":
+			if strings.HasPrefix(value, "true") {
+				src.priority = "secondary"
 			}
 		}
 	}
-
 	if err := scanner.Err(); err != nil {
-		return nil, nil, err
+		return "", err
 	}
 
-	return deps, devDeps, nil
+	for _, want := range []string{"default", "primary"} {
+		for _, src := range sources {
+			if src.priority == want && src.url != "" {
+				return src.url, nil
+			}
+		}
+	}
+	return "", nil
 }
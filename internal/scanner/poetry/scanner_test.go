@@ -1,8 +1,11 @@
 package poetry
 
 import (
+	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
@@ -35,14 +38,15 @@ pytest   7.0.0  7.4.0  Testing framework
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPoetryCmd: func(_ ...string) ([]byte, error) {
+		runPoetryCmd: func(_ context.Context, _ ...string) ([]byte, error) {
 			return []byte(mockOutput), nil
 		},
 	}
 
 	// Test Case 1: Default options (exclude dev dependencies)
 	opts := scanner.Options{
-		IncludeAll: false,
+		IncludeDev:        false,
+		IncludeTransitive: false,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -80,7 +84,7 @@ pytest   7.0.0  7.4.0  Testing framework
 			}
 		}
 		if m.Name == "pytest" {
-			t.Error("pytest should not be included when IncludeAll=false")
+			t.Error("pytest should not be included by default")
 		}
 	}
 
@@ -91,15 +95,15 @@ pytest   7.0.0  7.4.0  Testing framework
 		t.Error("flask not found")
 	}
 
-	// Test Case 2: IncludeAll = true
-	opts.IncludeAll = true
+	// Test Case 2: IncludeDev and IncludeTransitive
+	opts.IncludeDev, opts.IncludeTransitive = true, true
 	modules, err = s.GetUpdates(opts)
 	if err != nil {
-		t.Fatalf("GetUpdates(IncludeAll) failed: %v", err)
+		t.Fatalf("GetUpdates(IncludeDev, IncludeTransitive) failed: %v", err)
 	}
 
 	if len(modules) != 3 {
-		t.Errorf("expected 3 modules with IncludeAll, got %d", len(modules))
+		t.Errorf("expected 3 modules with IncludeDev and IncludeTransitive, got %d", len(modules))
 	}
 
 	// Verify pytest is included with correct type
@@ -116,7 +120,7 @@ pytest   7.0.0  7.4.0  Testing framework
 		}
 	}
 	if !foundPytest {
-		t.Error("pytest not found with IncludeAll=true")
+		t.Error("pytest not found with IncludeDev and IncludeTransitive")
 	}
 }
 
@@ -139,7 +143,7 @@ flask  2.2.0 3.0.0 Web framework
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPoetryCmd: func(_ ...string) ([]byte, error) {
+		runPoetryCmd: func(_ context.Context, _ ...string) ([]byte, error) {
 			return []byte(mockOutput), nil
 		},
 	}
@@ -176,7 +180,7 @@ requests = "^2.31.0"
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runPoetryCmd: func(_ ...string) ([]byte, error) {
+		runPoetryCmd: func(_ context.Context, _ ...string) ([]byte, error) {
 			// Simulate error when no outdated packages
 			return []byte{}, nil
 		},
@@ -254,3 +258,356 @@ black = "^23.0.0"
 		t.Error("python should not be in dependency index")
 	}
 }
+
+func TestGetUpdates_PEP621(t *testing.T) {
+	tmpDir := t.TempDir()
+	pyprojectToml := `[project]
+name = "test-project"
+version = "0.1.0"
+requires-python = ">=3.9"
+dependencies = [
+    "requests>=2.28",
+    "Flask[async] (>=2.2,<4)",  # extras and parenthesized specifiers
+    'typing_extensions; python_version < "3.11"',
+]
+
+[project.optional-dependencies]
+docs = ["mkdocs>=1.5", "mkdocs-material"]
+
+[tool.poetry.group.dev.dependencies]
+pytest = "^7.0.0"
+
+[build-system]
+requires = ["poetry-core>=2.0.0"]
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte(pyprojectToml), 0644); err != nil {
+		t.Fatalf("failed to write pyproject.toml: %v", err)
+	}
+
+	mockOutput := `requests          2.28.0 2.31.0 HTTP library
+flask             2.2.0  3.0.0  Web framework
+typing-extensions 4.0.0  4.9.0  Backported types
+mkdocs            1.5.0  1.6.0  Docs
+pytest            7.0.0  7.4.0  Testing framework
+poetry-core       2.0.0  2.1.0  Build backend
+`
+	s := &Scanner{
+		workDir: tmpDir,
+		runPoetryCmd: func(_ context.Context, _ ...string) ([]byte, error) {
+			return []byte(mockOutput), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, m := range modules {
+		got[m.Name] = m.DependencyType
+	}
+	want := map[string]string{"requests": "main", "flask": "main", "typing-extensions": "main"}
+	if len(got) != len(want) {
+		t.Fatalf("expected only main dependencies, got %+v", got)
+	}
+	for name, depType := range want {
+		if got[name] != depType {
+			t.Errorf("expected %s as %s, got %q", name, depType, got[name])
+		}
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	got = make(map[string]string)
+	for _, m := range modules {
+		got[m.Name] = m.DependencyType
+	}
+	if got["mkdocs"] != "optional" || got["pytest"] != "dev" {
+		t.Errorf("unexpected optional/dev classification: %+v", got)
+	}
+	if got["poetry-core"] != "transitive" {
+		t.Errorf("expected build requirements to be ignored, got %q", got["poetry-core"])
+	}
+
+	idx, err := s.GetDependencyIndex()
+	if err != nil {
+		t.Fatalf("GetDependencyIndex failed: %v", err)
+	}
+	if idx["mkdocs-material"].Type != "optional" {
+		t.Errorf("expected mkdocs-material as optional, got %+v", idx["mkdocs-material"])
+	}
+}
+
+func TestGetUpdates_DependencyGroups(t *testing.T) {
+	tmpDir := t.TempDir()
+	pyprojectToml := `[tool.poetry.dependencies]
+python = "^3.11"
+requests = "^2.28.0"
+
+[tool.poetry.group.dev.dependencies]
+ruff = "^0.4.0"
+
+[tool.poetry.group.test.dependencies]
+pytest = "^7.0.0"
+hypothesis = "^6.0.0"
+
+[tool.poetry.group.docs]
+optional = true
+
+[tool.poetry.group.docs.dependencies]
+mkdocs = "^1.5.0"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte(pyprojectToml), 0644); err != nil {
+		t.Fatalf("failed to write pyproject.toml: %v", err)
+	}
+
+	mockOutput := `requests   2.28.0 2.31.0 HTTP library
+ruff       0.4.0  0.5.0  Linter
+pytest     7.0.0  7.4.0  Testing framework
+hypothesis 6.0.0  6.100.0 Property testing
+mkdocs     1.5.0  1.6.0  Docs
+`
+	s := &Scanner{
+		workDir: tmpDir,
+		runPoetryCmd: func(_ context.Context, _ ...string) ([]byte, error) {
+			return []byte(mockOutput), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "requests" {
+		t.Fatalf("expected only main dependencies by default, got %+v", modules)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, m := range modules {
+		got[m.Name] = m.DependencyType
+	}
+	want := map[string]string{
+		"requests":   "main",
+		"ruff":       "dev",
+		"pytest":     "test",
+		"hypothesis": "test",
+		"mkdocs":     "docs",
+	}
+	for name, depType := range want {
+		if got[name] != depType {
+			t.Errorf("expected %s in group %q, got %q", name, depType, got[name])
+		}
+	}
+}
+
+func TestParseOutdatedLine(t *testing.T) {
+	tests := []struct {
+		line                  string
+		name, current, latest string
+		ok                    bool
+	}{
+		{"requests 2.28.0 2.31.0 Python HTTP for Humans.", "requests", "2.28.0", "2.31.0", true},
+		{"flask    (!) 2.2.0  3.0.0  A simple framework for building complex web applications.", "flask", "2.2.0", "3.0.0", true},
+		{"black 23.1.0 24.2.0", "black", "23.1.0", "24.2.0", true},
+		{"\x1b[36mrich\x1b[39m \x1b[1m13.0.0\x1b[22m \x1b[31m13.7.1\x1b[39m Render rich text", "rich", "13.0.0", "13.7.1", true},
+		{"Warning: poetry.lock is not consistent with pyproject.toml.", "", "", "", false},
+		{"requests 2.28.0", "", "", "", false},
+		{"", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		name, current, latest, ok := parseOutdatedLine(tt.line)
+		if ok != tt.ok || name != tt.name || current != tt.current || latest != tt.latest {
+			t.Errorf("parseOutdatedLine(%q) = (%q, %q, %q, %v), want (%q, %q, %q, %v)",
+				tt.line, name, current, latest, ok, tt.name, tt.current, tt.latest, tt.ok)
+		}
+	}
+}
+
+func TestGetUpdates_NotInstalledMarkerAndDescriptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	pyprojectToml := `[tool.poetry.dependencies]
+requests = "^2.28.0"
+flask = "^2.2.0"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte(pyprojectToml), 0644); err != nil {
+		t.Fatalf("failed to write pyproject.toml: %v", err)
+	}
+
+	var gotArgs []string
+	s := &Scanner{
+		workDir: tmpDir,
+		runPoetryCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			gotArgs = args
+			return []byte("requests 2.28.0 2.31.0 Python HTTP for Humans.\n" +
+				"flask    (!) 2.2.0 3.0.0 A simple framework for building complex web applications.\n"), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if strings.Join(gotArgs, " ") != "show --outdated --no-ansi" {
+		t.Errorf("unexpected poetry args: %v", gotArgs)
+	}
+
+	want := map[string][2]string{
+		"requests": {"2.28.0", "2.31.0"},
+		"flask":    {"2.2.0", "3.0.0"},
+	}
+	if len(modules) != len(want) {
+		t.Fatalf("expected %d modules, got %d", len(want), len(modules))
+	}
+	for _, m := range modules {
+		w, ok := want[m.Name]
+		if !ok {
+			t.Errorf("unexpected module %s", m.Name)
+			continue
+		}
+		if m.Version != w[0] || m.Update.Version != w[1] {
+			t.Errorf("%s: got %s -> %s, want %s -> %s", m.Name, m.Version, m.Update.Version, w[0], w[1])
+		}
+	}
+}
+
+func TestGetUpdates_MissingBinary(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte("[tool.poetry.dependencies]\nrequests = \"^2.28.0\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write pyproject.toml: %v", err)
+	}
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runPoetryCmd: func(_ context.Context, _ ...string) ([]byte, error) {
+			return nil, &exec.Error{Name: "poetry", Err: exec.ErrNotFound}
+		},
+	}
+
+	_, err := s.GetUpdates(scanner.Options{})
+	if err == nil || err.Error() != "poetry not found in PATH; install it or use --manager" {
+		t.Fatalf("expected a friendly missing binary error, got %v", err)
+	}
+}
+
+func TestReadPoetryLock(t *testing.T) {
+	lock, err := readPoetryLock(filepath.Join("testdata", "lock", "poetry.lock"))
+	if err != nil {
+		t.Fatalf("readPoetryLock failed: %v", err)
+	}
+	if len(lock) != 7 {
+		t.Errorf("expected 7 locked packages, got %d: %+v", len(lock), lock)
+	}
+
+	requests := lock["requests"]
+	if requests.Version != "2.28.0" {
+		t.Errorf("requests version = %q, want 2.28.0", requests.Version)
+	}
+	if got := strings.Join(requests.Dependencies, ","); got != "certifi,charset-normalizer,idna,urllib3" {
+		t.Errorf("requests dependencies = %s", got)
+	}
+	if _, ok := lock["charset-normalizer"]; !ok {
+		t.Error("expected locked names to be normalized")
+	}
+
+	lock, err = readPoetryLock(filepath.Join(t.TempDir(), "poetry.lock"))
+	if err != nil || lock != nil {
+		t.Errorf("expected no lock and no error for a missing file, got %v, %v", lock, err)
+	}
+}
+
+func TestGetUpdates_PoetryLock(t *testing.T) {
+	mockOutput := `requests           2.28.0 2.31.0 HTTP library
+urllib3            2.0.4  2.2.1  HTTP library
+charset-normalizer 3.2.0  3.3.2  Charset detector
+iniconfig          2.0.0  2.1.0  INI parsing
+setuptools         68.0.0 69.1.0 Installed in the venv only
+`
+	s := &Scanner{
+		workDir: filepath.Join("testdata", "lock"),
+		runPoetryCmd: func(_ context.Context, _ ...string) ([]byte, error) {
+			return []byte(mockOutput), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	var got []string
+	for _, m := range modules {
+		got = append(got, m.Name+":"+m.DependencyType)
+	}
+	want := "requests:main urllib3:transitive charset-normalizer:transitive iniconfig:transitive"
+	if strings.Join(got, " ") != want {
+		t.Errorf("GetUpdates() = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	idx, err := s.GetDependencyIndex()
+	if err != nil {
+		t.Fatalf("GetDependencyIndex failed: %v", err)
+	}
+	if info := idx["idna"]; info.Direct || info.Type != "transitive" {
+		t.Errorf("idx[idna] = %+v, want transitive", info)
+	}
+	if info := idx["pytest"]; !info.Direct || info.Type != "dev" {
+		t.Errorf("idx[pytest] = %+v, want direct dev", info)
+	}
+}
+
+func TestReadPrimarySource(t *testing.T) {
+	tests := []struct {
+		name      string
+		pyproject string
+		want      string
+	}{
+		{"no sources", "[tool.poetry.dependencies]\npython = \"^3.11\"\n", ""},
+		{"primary", `
+[[tool.poetry.source]]
+name = "extra"
+url = "https://extra.example.com/simple/"
+priority = "supplemental"
+
+[[tool.poetry.source]]
+name = "corp"
+url = "https://pypi.corp.example.com/simple/" # mirror
+`, "https://pypi.corp.example.com/simple/"},
+		{"legacy default", `
+[[tool.poetry.source]]
+name = "first"
+url = "https://first.example.com/simple/"
+
+[[tool.poetry.source]]
+name = "corp"
+url = "https://pypi.corp.example.com/simple/"
+default = true
+`, "https://pypi.corp.example.com/simple/"},
+		{"only explicit", `
+[[tool.poetry.source]]
+name = "torch"
+url = "https://download.pytorch.org/whl/cpu"
+priority = "explicit"
+`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "pyproject.toml")
+			if err := os.WriteFile(path, []byte(tt.pyproject), 0644); err != nil {
+				t.Fatalf("failed to write pyproject.toml: %v", err)
+			}
+			got, err := readPrimarySource(path)
+			if err != nil {
+				t.Fatalf("readPrimarySource failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readPrimarySource() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
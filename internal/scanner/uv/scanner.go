@@ -2,18 +2,26 @@
 package uv
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/pragmaticivan/faro/internal/registry"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
 // Scanner implements scanner.Scanner for uv.
 type Scanner struct {
-	workDir  string
-	runUvCmd func(args ...string) ([]byte, error)
+	workDir          string
+	runUvCmd         func(ctx context.Context, args ...string) ([]byte, error)
+	fetchPackageTime func(ctx context.Context, name, version string) (string, error)
+	pypi             *registry.PyPIClient // Index lookups of the current GetUpdates call
 }
 
 // uvOutdated represents the structure of `uv pip list --outdated --format json` output.
@@ -27,22 +35,33 @@ type uvPackageInfo struct {
 
 // NewScanner creates a new uv scanner.
 func NewScanner(workDir string) *Scanner {
-	return &Scanner{
+	s := &Scanner{
 		workDir: workDir,
-		runUvCmd: func(args ...string) ([]byte, error) {
-			cmd := exec.Command("uv", args...)
+		runUvCmd: func(ctx context.Context, args ...string) ([]byte, error) {
+			cmd := exec.CommandContext(ctx, "uv", args...)
 			cmd.Dir = workDir
-			return cmd.Output()
+			return scanner.Output(cmd)
 		},
 	}
+	s.fetchPackageTime = func(ctx context.Context, name, version string) (string, error) {
+		return s.pypi.PublishTime(ctx, name, version)
+	}
+	return s
 }
 
 // GetUpdates returns all uv packages that have available updates.
+// With IncludeVulnScan, installed packages without an update are returned too.
 func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	match, err := scanner.CompileFilter(opts.Filter, true)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get outdated packages from uv
-	output, err := s.runUvCmd("pip", "list", "--outdated", "--format", "json")
+	ctx := opts.Ctx()
+	output, err := s.runUvCmd(ctx, "pip", "list", "--outdated", "--format", "json")
 	if err != nil {
-		return nil, fmt.Errorf("failed to run uv pip list --outdated: %w", err)
+		return nil, scanner.CommandError(ctx, "uv", fmt.Errorf("failed to run uv pip list --outdated: %w", err))
 	}
 
 	var outdated uvOutdated
@@ -50,34 +69,91 @@ func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
 		return nil, fmt.Errorf("failed to parse uv output: %w", err)
 	}
 
+	if opts.IncludeVulnScan {
+		output, err := s.runUvCmd(ctx, "pip", "list", "--format", "json")
+		if err != nil {
+			return nil, scanner.CommandError(ctx, "uv", fmt.Errorf("failed to run uv pip list: %w", err))
+		}
+		var installed uvOutdated
+		if err := json.Unmarshal(output, &installed); err != nil {
+			return nil, fmt.Errorf("failed to parse uv output: %w", err)
+		}
+		outdated = withUpToDate(outdated, installed)
+	}
+
+	depIdx, err := s.dependencyIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pyproject.toml: %w", err)
+	}
+
 	var modules []scanner.Module
 	for _, info := range outdated {
+		depInfo, isDirect := depIdx[normalizeName(info.Name)]
+		if !isDirect {
+			depInfo = scanner.DependencyInfo{Direct: false, Type: "transitive"}
+		}
+
+		// Dependency groups and extras need IncludeDev, transitive
+		// dependencies IncludeTransitive
+		if !opts.Includes(depInfo.Type, depInfo.Type == "main") {
+			continue
+		}
+
 		// Apply filter
-		if opts.Filter != "" && !strings.Contains(strings.ToLower(info.Name), strings.ToLower(opts.Filter)) {
+		if !match(info.Name) {
 			continue
 		}
 
 		module := scanner.Module{
 			Name:           info.Name,
 			Version:        info.Version,
-			Direct:         true, // uv doesn't distinguish in list output
-			DependencyType: "main",
-			Update: &scanner.UpdateInfo{
-				Version: info.Latest,
-			},
+			Direct:         depInfo.Direct,
+			DependencyType: depInfo.Type,
+		}
+		if info.Latest != "" {
+			module.Update = &scanner.UpdateInfo{Version: info.Latest}
 		}
 		modules = append(modules, module)
 	}
 
-	return modules, nil
+	s.pypi = registry.NewPyPIClient(s.indexURL(), nil)
+	return registry.FillTimesFor(opts, modules, s.fetchPackageTime), nil
+}
+
+// indexURL returns the package index uv resolves against, from
+// UV_DEFAULT_INDEX or the older UV_INDEX_URL; "" is the public PyPI.
+func (s *Scanner) indexURL() string {
+	if url := os.Getenv("UV_DEFAULT_INDEX"); url != "" {
+		return url
+	}
+	return os.Getenv("UV_INDEX_URL")
 }
 
 // GetDependencyIndex returns a map of uv package names to their dependency information.
+// Names are PEP 503 normalized. Without a pyproject.toml every installed
+// package is treated as a main dependency.
 func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
+	return s.dependencyIndex(context.Background())
+}
+
+// dependencyIndex implements GetDependencyIndex, running uv under ctx.
+func (s *Scanner) dependencyIndex(ctx context.Context) (scanner.DependencyIndex, error) {
+	deps, err := s.readPyprojectToml()
+	if err == nil {
+		idx := make(scanner.DependencyIndex)
+		for name, depType := range deps {
+			idx[name] = scanner.DependencyInfo{Direct: true, Type: depType}
+		}
+		return idx, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
 	// uv pip list shows installed packages
-	output, err := s.runUvCmd("pip", "list", "--format", "json")
+	output, err := s.runUvCmd(ctx, "pip", "list", "--format", "json")
 	if err != nil {
-		return nil, err
+		return nil, scanner.CommandError(ctx, "uv", err)
 	}
 
 	var packages []struct {
@@ -90,7 +166,138 @@ func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
 
 	idx := make(scanner.DependencyIndex)
 	for _, pkg := range packages {
-		idx[pkg.Name] = scanner.DependencyInfo{Direct: true, Type: "main"}
+		idx[normalizeName(pkg.Name)] = scanner.DependencyInfo{Direct: true, Type: "main"}
 	}
 	return idx, nil
 }
+
+var (
+	// quotedString matches a double- or single-quoted TOML string.
+	quotedString = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+	// inlineTable matches TOML inline tables such as {include-group = "test"}.
+	inlineTable = regexp.MustCompile(`\{[^}]*\}`)
+
+	// requirementName matches the package name at the start of a PEP 508
+	// requirement such as "requests[socks]>=2.28; python_version>'3.8'".
+	requirementName = regexp.MustCompile(`^\s*([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+	// nameSeparators matches runs of characters PEP 503 treats as equivalent.
+	nameSeparators = regexp.MustCompile(`[-_.]+`)
+)
+
+// normalizeName returns the PEP 503 normalized form of a package name.
+func normalizeName(name string) string {
+	return strings.ToLower(nameSeparators.ReplaceAllString(name, "-"))
+}
+
+// readPyprojectToml reads pyproject.toml and maps each declared dependency
+// (by normalized name) to its type: "main" for [project].dependencies,
+// "optional" for extras, "dev" for [tool.uv].dev-dependencies, or the name of
+// the [dependency-groups] entry that declares it.
+// This is a simplified TOML parser that only understands requirement arrays.
+func (s *Scanner) readPyprojectToml() (map[string]string, error) {
+	path := filepath.Join(s.workDir, "pyproject.toml")
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	deps := make(map[string]string)
+	addRequirements := func(line, depType string) {
+		line = inlineTable.ReplaceAllString(line, "")
+		for _, m := range quotedString.FindAllStringSubmatch(line, -1) {
+			name := requirementName.FindStringSubmatch(m[1] + m[2])
+			if name == nil {
+				continue
+			}
+			key := normalizeName(name[1])
+			// A main classification wins over groups and extras
+			if prev, ok := deps[key]; ok && prev == "main" {
+				continue
+			}
+			deps[key] = depType
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	var section string
+	var arrayType string // Type of the requirements array being read, if any
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Continuation of a multi-line requirements array
+		if arrayType != "" {
+			addRequirements(line, arrayType)
+			if strings.Contains(quotedString.ReplaceAllString(line, ""), "]") {
+				arrayType = ""
+			}
+			continue
+		}
+
+		// Skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Check for section headers
+		if strings.HasPrefix(line, "[") {
+			section = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.TrimSpace(value)
+
+		var depType string
+		switch {
+		case section == "project" && key == "dependencies":
+			depType = "main"
+		case section == "project.optional-dependencies":
+			depType = "optional"
+		case section == "dependency-groups":
+			depType = key
+		case section == "tool.uv" && key == "dev-dependencies":
+			depType = "dev"
+		default:
+			continue
+		}
+
+		// Arrays of requirement strings, possibly spanning lines
+		if !strings.HasPrefix(value, "[") {
+			continue
+		}
+		addRequirements(value, depType)
+		if !strings.Contains(quotedString.ReplaceAllString(value, ""), "]") {
+			arrayType = depType
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return deps, nil
+}
+
+// withUpToDate appends the installed packages missing from outdated, which
+// are up to date, with no latest version.
+func withUpToDate(outdated, installed uvOutdated) uvOutdated {
+	seen := make(map[string]bool, len(outdated))
+	for _, info := range outdated {
+		seen[strings.ToLower(info.Name)] = true
+	}
+	for _, info := range installed {
+		if !seen[strings.ToLower(info.Name)] {
+			info.Latest = ""
+			outdated = append(outdated, info)
+		}
+	}
+	return outdated
+}
@@ -1,7 +1,10 @@
 package uv
 
 import (
+	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
@@ -18,14 +21,15 @@ func TestGetUpdates(t *testing.T) {
 
 	s := &Scanner{
 		workDir: ".",
-		runUvCmd: func(args ...string) ([]byte, error) {
+		runUvCmd: func(_ context.Context, args ...string) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
 
 	// Test Case 1: Default options
 	opts := scanner.Options{
-		IncludeAll: false,
+		IncludeDev:        false,
+		IncludeTransitive: false,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -79,7 +83,7 @@ func TestGetUpdates_Filter(t *testing.T) {
 
 	s := &Scanner{
 		workDir: ".",
-		runUvCmd: func(args ...string) ([]byte, error) {
+		runUvCmd: func(_ context.Context, args ...string) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
@@ -111,7 +115,7 @@ func TestGetUpdates_CaseInsensitiveFilter(t *testing.T) {
 
 	s := &Scanner{
 		workDir: ".",
-		runUvCmd: func(args ...string) ([]byte, error) {
+		runUvCmd: func(_ context.Context, args ...string) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
@@ -140,7 +144,7 @@ func TestGetUpdates_EmptyOutdated(t *testing.T) {
 
 	s := &Scanner{
 		workDir: ".",
-		runUvCmd: func(args ...string) ([]byte, error) {
+		runUvCmd: func(_ context.Context, args ...string) ([]byte, error) {
 			return outdatedBytes, nil
 		},
 	}
@@ -170,7 +174,7 @@ func TestGetDependencyIndex(t *testing.T) {
 
 	s := &Scanner{
 		workDir: ".",
-		runUvCmd: func(args ...string) ([]byte, error) {
+		runUvCmd: func(_ context.Context, args ...string) ([]byte, error) {
 			return packagesBytes, nil
 		},
 	}
@@ -195,3 +199,129 @@ func TestGetDependencyIndex(t *testing.T) {
 		}
 	}
 }
+
+const pyprojectFixture = `[project]
+name = "app"
+dependencies = [
+    "requests[socks]>=2.28",
+    "Flask_Login; python_version >= '3.8'",
+]
+
+[project.optional-dependencies]
+yaml = ["pyyaml>=6.0"]
+
+[dependency-groups]
+dev = ["pytest>=7", { include-group = "lint" }]
+lint = ["ruff"]
+
+[tool.uv]
+dev-dependencies = ["mypy"]
+`
+
+func newPyprojectScanner(t *testing.T, outdated uvOutdated) *Scanner {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "pyproject.toml"), []byte(pyprojectFixture), 0644); err != nil {
+		t.Fatalf("failed to write pyproject.toml: %v", err)
+	}
+	outdatedBytes, _ := json.Marshal(outdated)
+	return &Scanner{
+		workDir: tmpDir,
+		runUvCmd: func(_ context.Context, args ...string) ([]byte, error) {
+			return outdatedBytes, nil
+		},
+	}
+}
+
+func TestGetDependencyIndex_Pyproject(t *testing.T) {
+	s := newPyprojectScanner(t, nil)
+
+	idx, err := s.GetDependencyIndex()
+	if err != nil {
+		t.Fatalf("GetDependencyIndex failed: %v", err)
+	}
+
+	want := map[string]string{
+		"requests":    "main",
+		"flask-login": "main",
+		"pyyaml":      "optional",
+		"pytest":      "dev",
+		"ruff":        "lint",
+		"mypy":        "dev",
+	}
+	if len(idx) != len(want) {
+		t.Errorf("expected %d entries, got %d: %v", len(want), len(idx), idx)
+	}
+	for name, depType := range want {
+		info, ok := idx[name]
+		if !ok {
+			t.Errorf("expected %s in dependency index", name)
+			continue
+		}
+		if !info.Direct || info.Type != depType {
+			t.Errorf("%s: got %+v, want direct %s", name, info, depType)
+		}
+	}
+}
+
+func TestGetUpdates_Pyproject(t *testing.T) {
+	s := newPyprojectScanner(t, uvOutdated{
+		{Name: "requests", Version: "2.28.0", Latest: "2.31.0"},
+		{Name: "Flask-Login", Version: "0.6.0", Latest: "0.6.3"},
+		{Name: "pytest", Version: "7.0.0", Latest: "8.0.0"},
+		{Name: "ruff", Version: "0.1.0", Latest: "0.4.0"},
+		{Name: "urllib3", Version: "1.26.0", Latest: "2.2.0"},
+	})
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 2 {
+		t.Fatalf("expected only the 2 main dependencies, got %d", len(modules))
+	}
+	for _, m := range modules {
+		if m.Name != "requests" && m.Name != "Flask-Login" {
+			t.Errorf("unexpected module %s by default", m.Name)
+		}
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	want := map[string]struct {
+		direct  bool
+		depType string
+	}{
+		"requests":    {true, "main"},
+		"Flask-Login": {true, "main"},
+		"pytest":      {true, "dev"},
+		"ruff":        {true, "lint"},
+		"urllib3":     {false, "transitive"},
+	}
+	if len(modules) != len(want) {
+		t.Fatalf("expected %d modules, got %d", len(want), len(modules))
+	}
+	for _, m := range modules {
+		w := want[m.Name]
+		if m.Direct != w.direct || m.DependencyType != w.depType {
+			t.Errorf("%s: got direct=%v type=%s, want direct=%v type=%s", m.Name, m.Direct, m.DependencyType, w.direct, w.depType)
+		}
+	}
+}
+
+func TestIndexURL(t *testing.T) {
+	s := NewScanner(t.TempDir())
+
+	t.Setenv("UV_DEFAULT_INDEX", "")
+	t.Setenv("UV_INDEX_URL", "https://legacy.example.com/simple")
+	if got := s.indexURL(); got != "https://legacy.example.com/simple" {
+		t.Errorf("indexURL() = %q, want UV_INDEX_URL", got)
+	}
+
+	t.Setenv("UV_DEFAULT_INDEX", "https://pypi.corp.example.com/simple")
+	if got := s.indexURL(); got != "https://pypi.corp.example.com/simple" {
+		t.Errorf("indexURL() = %q, want UV_DEFAULT_INDEX to win", got)
+	}
+}
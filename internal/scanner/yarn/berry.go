@@ -0,0 +1,150 @@
+package yarn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/log"
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+// berryInfo is a line of `yarn info --json` output (Yarn 2+).
+type berryInfo struct {
+	Value    string `json:"value"`
+	Children struct {
+		Version string `json:"Version"`
+	} `json:"children"`
+}
+
+// berryNpmInfo is a line of `yarn npm info --fields name,version --json` output.
+type berryNpmInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// isBerry reports whether the project uses Yarn 2 or later. The
+// packageManager field is authoritative; otherwise a Berry lockfile or a
+// .yarnrc.yml marks the project as Berry.
+func (s *Scanner) isBerry(pkg *packageJSON) bool {
+	if version, ok := strings.CutPrefix(pkg.PackageManager, "yarn@"); ok {
+		major, _, _ := strings.Cut(version, ".")
+		n, err := strconv.Atoi(major)
+		return err == nil && n >= 2
+	}
+
+	if lock, err := os.ReadFile(filepath.Join(s.workDir, "yarn.lock")); err == nil {
+		return bytes.Contains(lock, []byte("__metadata:"))
+	}
+
+	_, err := os.Stat(filepath.Join(s.workDir, ".yarnrc.yml"))
+	return err == nil
+}
+
+// berryOutdated lists the project's direct dependencies whose installed
+// version differs from the latest version published to the registry.
+func (s *Scanner) berryOutdated(ctx context.Context) ([]outdatedPackage, error) {
+	output, err := s.runYarnInfo(ctx)
+	if err != nil {
+		return nil, scanner.CommandError(ctx, "yarn", fmt.Errorf("failed to run yarn info: %w", err))
+	}
+
+	installed := make(map[string]string)
+	var names []string
+	for _, line := range jsonLines(output) {
+		var info berryInfo
+		if err := json.Unmarshal(line, &info); err != nil {
+			log.Warnf("skipping unparseable yarn info line %q: %v", line, err)
+			continue
+		}
+		name, ok := npmLocatorName(info.Value)
+		if !ok || info.Children.Version == "" {
+			continue
+		}
+		if _, seen := installed[name]; !seen {
+			names = append(names, name)
+		}
+		installed[name] = info.Children.Version
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	output, err = s.runYarnNpmInfo(ctx, names...)
+	if err != nil {
+		return nil, scanner.CommandError(ctx, "yarn", fmt.Errorf("failed to run yarn npm info: %w", err))
+	}
+
+	var outdated []outdatedPackage
+	for _, line := range jsonLines(output) {
+		var info berryNpmInfo
+		if err := json.Unmarshal(line, &info); err != nil {
+			log.Warnf("skipping unparseable yarn npm info line %q: %v", line, err)
+			continue
+		}
+		current, ok := installed[info.Name]
+		if !ok || info.Version == "" || info.Version == current {
+			continue
+		}
+		outdated = append(outdated, outdatedPackage{name: info.Name, current: current, latest: info.Version})
+	}
+	return outdated, nil
+}
+
+// npmLocatorName returns the package name of a Berry locator such as
+// "@types/node@npm:20.1.0". Workspace, patch and other non-registry
+// locators are rejected.
+func npmLocatorName(locator string) (string, bool) {
+	if locator == "" {
+		return "", false
+	}
+	// Skip the leading @ of scoped packages when looking for the separator
+	at := strings.Index(locator[1:], "@")
+	if at < 0 {
+		return "", false
+	}
+	at++
+	if !strings.HasPrefix(locator[at+1:], "npm:") {
+		return "", false
+	}
+	return locator[:at], true
+}
+
+// jsonLines splits newline-delimited JSON output into its non-empty lines.
+func jsonLines(output []byte) [][]byte {
+	var lines [][]byte
+	sc := bufio.NewScanner(bytes.NewReader(output))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if line := bytes.TrimSpace(sc.Bytes()); len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	return lines
+}
+
+// runYarn runs a Yarn Berry command in workDir, with env appended to the
+// inherited environment, and returns its stdout.
+func runYarn(ctx context.Context, workDir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "yarn", args...)
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := scanner.Output(cmd)
+	if err != nil {
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("yarn %s failed: %w, stderr: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+	return out, nil
+}
@@ -3,6 +3,7 @@ package yarn
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,13 +11,28 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/pragmaticivan/faro/internal/log"
+	"github.com/pragmaticivan/faro/internal/registry"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
 // Scanner implements scanner.Scanner for yarn.
 type Scanner struct {
-	workDir         string
-	runYarnOutdated func() ([]byte, error)
+	workDir          string
+	runYarnOutdated  func(ctx context.Context) ([]byte, error)
+	runYarnInfo      func(ctx context.Context) ([]byte, error)
+	runYarnNpmInfo   func(ctx context.Context, names ...string) ([]byte, error)
+	fetchPackageTime func(ctx context.Context, name, version string) (string, error)
+	env              []string            // Extra environment for yarn, from Options.Env
+	npm              *registry.NpmClient // Registry lookups of the current GetUpdates call
+}
+
+// outdatedPackage is an outdated package reported by either Yarn line.
+type outdatedPackage struct {
+	name    string
+	current string
+	wanted  string // Empty for Yarn Berry, which doesn't report it
+	latest  string
 }
 
 // yarnOutdated represents the structure of `yarn outdated --json` output.
@@ -32,32 +48,47 @@ type yarnOutdatedTable struct {
 
 // NewScanner creates a new yarn scanner.
 func NewScanner(workDir string) *Scanner {
-	return &Scanner{
+	s := &Scanner{
 		workDir: workDir,
-		runYarnOutdated: func() ([]byte, error) {
-			cmd := exec.Command("yarn", "outdated", "--json")
-			cmd.Dir = workDir
-			var stderr bytes.Buffer
-			cmd.Stderr = &stderr
-
-			out, err := cmd.Output() // yarn outdated may return non-zero when updates are available
-			if err != nil {
-				if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-					if looksLikeJSON(out) {
-						return out, nil
-					}
-				}
-				if len(strings.TrimSpace(string(out))) > 0 {
-					return nil, fmt.Errorf("yarn outdated failed: %w, output: %s", err, strings.TrimSpace(string(out)))
-				}
-				if stderr.Len() > 0 {
-					return nil, fmt.Errorf("yarn outdated failed: %w, stderr: %s", err, stderr.String())
+	}
+	s.fetchPackageTime = func(ctx context.Context, name, version string) (string, error) {
+		return s.npm.PublishTime(ctx, name, version)
+	}
+	s.runYarnOutdated = func(ctx context.Context) ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "yarn", "outdated", "--json")
+		cmd.Dir = workDir
+		cmd.Env = append(os.Environ(), s.env...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		out, err := scanner.Output(cmd) // yarn outdated may return non-zero when updates are available
+		if err != nil {
+			// Registry auth failures also exit with 1, so check for them first
+			if authErr := scanner.RegistryAuthError("yarn", append(out, stderr.Bytes()...)); authErr != nil {
+				return nil, authErr
+			}
+			if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+				if looksLikeJSON(out) {
+					return out, nil
 				}
-				return nil, err
 			}
-			return out, nil
-		},
+			if len(strings.TrimSpace(string(out))) > 0 {
+				return nil, fmt.Errorf("yarn outdated failed: %w, output: %s", err, strings.TrimSpace(string(out)))
+			}
+			if stderr.Len() > 0 {
+				return nil, fmt.Errorf("yarn outdated failed: %w, stderr: %s", err, stderr.String())
+			}
+			return nil, err
+		}
+		return out, nil
+	}
+	s.runYarnInfo = func(ctx context.Context) ([]byte, error) {
+		return runYarn(ctx, workDir, s.env, "info", "--json")
 	}
+	s.runYarnNpmInfo = func(ctx context.Context, names ...string) ([]byte, error) {
+		return runYarn(ctx, workDir, s.env, append([]string{"npm", "info", "--fields", "name,version", "--json"}, names...)...)
+	}
+	return s
 }
 
 func looksLikeJSON(b []byte) bool {
@@ -70,76 +101,100 @@ func looksLikeJSON(b []byte) bool {
 
 // GetUpdates returns all yarn packages that have available updates.
 func (s *Scanner) GetUpdates(opts scanner.Options) ([]scanner.Module, error) {
+	s.env = opts.Env
+	s.npm = registry.NewNpmClient(s.workDir, opts.Registry, opts.Env)
+
+	match, err := scanner.CompileFilter(opts.Filter, true)
+	if err != nil {
+		return nil, err
+	}
+
 	pkgJSON, err := s.readPackageJSON()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read package.json: %w", err)
 	}
 
-	output, err := s.runYarnOutdated()
+	// Yarn Berry (v2+) dropped `yarn outdated`
+	var outdated []outdatedPackage
+	if s.isBerry(pkgJSON) {
+		outdated, err = s.berryOutdated(opts.Ctx())
+	} else {
+		outdated, err = s.classicOutdated(opts.Ctx())
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to run yarn outdated: %w", err)
+		return nil, err
 	}
 
-	if len(output) == 0 {
+	if len(outdated) == 0 {
 		return []scanner.Module{}, nil
 	}
 
 	var modules []scanner.Module
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if line == "" {
+	for _, pkg := range outdated {
+		depType := pkgJSON.dependencyType(pkg.name)
+
+		// Peer and optional dependencies need --dev, transitive ones --transitive
+		if !opts.Includes(depType, scanner.IsDefaultNodeDependencyType(depType)) {
 			continue
 		}
 
-		var entry yarnOutdated
-		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		if !match(pkg.name) {
 			continue
 		}
 
-		if entry.Type == "table" && len(entry.Data.Body) > 0 {
-			for _, row := range entry.Data.Body {
-				if len(row) < 4 {
-					continue
-				}
+		update := scanner.NodeUpdate(pkg.current, pkg.wanted, pkg.latest, opts.InRange)
+		if update == nil {
+			continue
+		}
 
-				name := row[0]
-				current := row[1]
-				latest := row[3]
+		module := scanner.Module{
+			Name:           pkg.name,
+			Version:        pkg.current,
+			Direct:         depType != "transitive",
+			DependencyType: depType,
+			Update:         update,
+			RangeBlocked:   scanner.RangeBlocked(pkg.current, pkg.wanted, pkg.latest),
+		}
 
-				_, isDirect := pkgJSON.Dependencies[name]
-				_, isDevDirect := pkgJSON.DevDependencies[name]
+		modules = append(modules, module)
+	}
 
-				depType := "dependencies"
-				if isDevDirect {
-					depType = "devDependencies"
-				} else if !isDirect {
-					depType = "transitive"
-				}
+	return registry.FillTimesFor(opts, modules, s.fetchPackageTime), nil
+}
 
-				if !opts.IncludeAll && depType == "transitive" {
-					continue
-				}
+// classicOutdated parses the table emitted by Yarn Classic's `yarn outdated --json`.
+func (s *Scanner) classicOutdated(ctx context.Context) ([]outdatedPackage, error) {
+	output, err := s.runYarnOutdated(ctx)
+	if err != nil {
+		return nil, scanner.CommandError(ctx, "yarn", fmt.Errorf("failed to run yarn outdated: %w", err))
+	}
 
-				if opts.Filter != "" && !strings.Contains(name, opts.Filter) {
-					continue
-				}
+	var outdated []outdatedPackage
+	lines := strings.Split(string(output), "\n")
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
 
-				module := scanner.Module{
-					Name:           name,
-					Version:        current,
-					Direct:         isDirect || isDevDirect,
-					DependencyType: depType,
-					Update: &scanner.UpdateInfo{
-						Version: latest,
-					},
-				}
+		var entry yarnOutdated
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			log.Warnf("skipping unparseable yarn outdated line %q: %v", line, err)
+			continue
+		}
 
-				modules = append(modules, module)
+		if entry.Type != "table" {
+			continue
+		}
+		for _, row := range entry.Data.Body {
+			if len(row) < 4 {
+				log.Warnf("skipping yarn outdated row with %d of 4 fields: %q", len(row), row)
+				continue
 			}
+			outdated = append(outdated, outdatedPackage{name: row[0], current: row[1], wanted: row[2], latest: row[3]})
 		}
 	}
 
-	return modules, nil
+	return outdated, nil
 }
 
 // GetDependencyIndex returns a map of yarn package names to their dependency information.
@@ -150,19 +205,48 @@ func (s *Scanner) GetDependencyIndex() (scanner.DependencyIndex, error) {
 	}
 
 	idx := make(scanner.DependencyIndex)
-	for name := range pkgJSON.Dependencies {
-		idx[name] = scanner.DependencyInfo{Direct: true, Type: "dependencies"}
-	}
-	for name := range pkgJSON.DevDependencies {
-		idx[name] = scanner.DependencyInfo{Direct: true, Type: "devDependencies"}
+	for _, sec := range []string{"peerDependencies", "optionalDependencies", "dependencies", "devDependencies"} {
+		for name := range pkgJSON.section(sec) {
+			idx[name] = scanner.DependencyInfo{Direct: true, Type: sec}
+		}
 	}
 
 	return idx, nil
 }
 
 type packageJSON struct {
-	Dependencies    map[string]string `json:"dependencies"`
-	DevDependencies map[string]string `json:"devDependencies"`
+	PackageManager       string            `json:"packageManager"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// section returns the dependency map for a package.json field name.
+func (p *packageJSON) section(name string) map[string]string {
+	switch name {
+	case "dependencies":
+		return p.Dependencies
+	case "devDependencies":
+		return p.DevDependencies
+	case "peerDependencies":
+		return p.PeerDependencies
+	case "optionalDependencies":
+		return p.OptionalDependencies
+	default:
+		return nil
+	}
+}
+
+// dependencyType returns the package.json section that declares name, or
+// "transitive" if it isn't declared.
+func (p *packageJSON) dependencyType(name string) string {
+	for _, sec := range []string{"dependencies", "devDependencies", "optionalDependencies", "peerDependencies"} {
+		if _, ok := p.section(sec)[name]; ok {
+			return sec
+		}
+	}
+	return "transitive"
 }
 
 func (s *Scanner) readPackageJSON() (*packageJSON, error) {
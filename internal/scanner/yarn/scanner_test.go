@@ -1,11 +1,16 @@
 package yarn
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/pragmaticivan/faro/internal/log"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
@@ -34,7 +39,7 @@ func TestGetUpdates(t *testing.T) {
 			Body: [][]string{
 				{"react", "18.0.0", "18.2.0", "18.2.0", "dependencies"},
 				{"axios", "1.0.0", "1.6.0", "1.6.0", "dependencies"},
-				{"jest", "29.0.0", "29.7.0", "29.7.0", "devDependencies"},
+				{"jest", "29.0.0", "29.7.0", "30.0.0", "devDependencies"},
 				{"@types/node", "18.0.0", "20.0.0", "20.0.0", "dependencies"}, // transitive
 			},
 		},
@@ -44,14 +49,15 @@ func TestGetUpdates(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runYarnOutdated: func() ([]byte, error) {
+		runYarnOutdated: func(context.Context) ([]byte, error) {
 			return mockOutputBytes, nil
 		},
 	}
 
 	// Test Case 1: Default options (include direct dev dependencies, exclude transitive)
 	opts := scanner.Options{
-		IncludeAll: false,
+		IncludeDev:        false,
+		IncludeTransitive: false,
 	}
 
 	modules, err := s.GetUpdates(opts)
@@ -97,6 +103,9 @@ func TestGetUpdates(t *testing.T) {
 			if m.DependencyType != "devDependencies" {
 				t.Errorf("expected dependency type 'devDependencies', got %s", m.DependencyType)
 			}
+			if m.Update.Version != "30.0.0" || m.Update.Wanted != "29.7.0" {
+				t.Errorf("expected latest 30.0.0 and wanted 29.7.0, got %+v", m.Update)
+			}
 		}
 		if m.Name == "@types/node" {
 			t.Error("@types/node should not be included (transitive)")
@@ -113,15 +122,15 @@ func TestGetUpdates(t *testing.T) {
 		t.Error("jest not found")
 	}
 
-	// Test Case 2: IncludeAll = true
-	opts.IncludeAll = true
+	// Test Case 2: IncludeDev and IncludeTransitive
+	opts.IncludeDev, opts.IncludeTransitive = true, true
 	modules, err = s.GetUpdates(opts)
 	if err != nil {
-		t.Fatalf("GetUpdates(IncludeAll) failed: %v", err)
+		t.Fatalf("GetUpdates(IncludeDev, IncludeTransitive) failed: %v", err)
 	}
 
 	if len(modules) != 4 {
-		t.Errorf("expected 4 modules with IncludeAll, got %d", len(modules))
+		t.Errorf("expected 4 modules with IncludeDev and IncludeTransitive, got %d", len(modules))
 	}
 
 	// Verify jest is included with correct type
@@ -138,7 +147,7 @@ func TestGetUpdates(t *testing.T) {
 		}
 	}
 	if !foundJest {
-		t.Error("jest not found with IncludeAll=true")
+		t.Error("jest not found with IncludeDev and IncludeTransitive")
 	}
 }
 
@@ -170,7 +179,7 @@ func TestGetUpdates_Filter(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runYarnOutdated: func() ([]byte, error) {
+		runYarnOutdated: func(context.Context) ([]byte, error) {
 			return append(mockOutputLine, '\n'), nil
 		},
 	}
@@ -209,7 +218,7 @@ func TestGetUpdates_EmptyOutdated(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runYarnOutdated: func() ([]byte, error) {
+		runYarnOutdated: func(context.Context) ([]byte, error) {
 			return []byte{}, nil
 		},
 	}
@@ -306,7 +315,7 @@ func TestGetUpdates_InsufficientFields(t *testing.T) {
 
 	s := &Scanner{
 		workDir: tmpDir,
-		runYarnOutdated: func() ([]byte, error) {
+		runYarnOutdated: func(context.Context) ([]byte, error) {
 			return append(mockOutputLine, '\n'), nil
 		},
 	}
@@ -322,3 +331,227 @@ func TestGetUpdates_InsufficientFields(t *testing.T) {
 		t.Errorf("expected 0 modules (invalid row), got %d", len(modules))
 	}
 }
+
+func TestGetUpdates_UnparseableLineLogsWarning(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{"dependencies": {"react": "^18.0.0"}}`), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	table, _ := json.Marshal(yarnOutdated{
+		Type: "table",
+		Data: yarnOutdatedTable{Body: [][]string{{"react", "18.0.0", "18.2.0", "18.2.0", "dependencies"}}},
+	})
+	output := append([]byte("{\"type\":\"info\",\"data\":\n"), append(table, '\n')...)
+
+	var logged bytes.Buffer
+	prev := log.Default()
+	log.SetDefault(log.New(&logged, log.LevelWarn))
+	defer log.SetDefault(prev)
+
+	s := &Scanner{
+		workDir: tmpDir,
+		runYarnOutdated: func(context.Context) ([]byte, error) {
+			return output, nil
+		},
+	}
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 {
+		t.Fatalf("expected the parseable table to be kept, got %d modules", len(modules))
+	}
+	if !strings.HasPrefix(logged.String(), "warn: skipping unparseable yarn outdated line") {
+		t.Errorf("expected a warn-level log for the broken line, got %q", logged.String())
+	}
+}
+
+func TestGetUpdates_PeerAndOptionalDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSON := `{
+		"dependencies": {"react": "^18.0.0"},
+		"peerDependencies": {"react-dom": "^18.0.0"},
+		"optionalDependencies": {"fsevents": "^2.0.0"}
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(pkgJSON), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	output, _ := json.Marshal(yarnOutdated{
+		Type: "table",
+		Data: yarnOutdatedTable{
+			Head: []string{"Package", "Current", "Wanted", "Latest", "Package Type"},
+			Body: [][]string{
+				{"react", "18.0.0", "18.2.0", "18.2.0", "dependencies"},
+				{"react-dom", "18.0.0", "18.2.0", "18.2.0", "peerDependencies"},
+				{"fsevents", "2.0.0", "2.3.3", "2.3.3", "optionalDependencies"},
+			},
+		},
+	})
+	s := &Scanner{
+		workDir: tmpDir,
+		runYarnOutdated: func(context.Context) ([]byte, error) {
+			return output, nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "react" {
+		t.Fatalf("expected only react by default, got %+v", modules)
+	}
+
+	modules, err = s.GetUpdates(scanner.Options{IncludeDev: true, IncludeTransitive: true})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	got := make(map[string]string)
+	for _, m := range modules {
+		got[m.Name] = m.DependencyType
+	}
+	if got["react-dom"] != "peerDependencies" || got["fsevents"] != "optionalDependencies" {
+		t.Errorf("unexpected classification: %+v", got)
+	}
+}
+
+func TestGetUpdates_Berry(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSON := `{
+		"packageManager": "yarn@4.1.0",
+		"dependencies": {"react": "^18.0.0", "@types/node": "^20.0.0", "lodash": "^4.17.0"},
+		"devDependencies": {"typescript": "^5.0.0"}
+	}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(pkgJSON), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	info := `{"value":"@types/node@npm:20.1.0","children":{"Version":"20.1.0","Dependencies":[]}}
+{"value":"lodash@npm:4.17.21","children":{"Version":"4.17.21"}}
+{"value":"react@npm:18.0.0","children":{"Version":"18.0.0"}}
+{"value":"typescript@npm:5.0.4","children":{"Version":"5.0.4"}}
+{"value":"app@workspace:.","children":{"Version":"0.0.0-use.local"}}
+`
+	npmInfo := `{"name":"@types/node","version":"20.11.5"}
+{"name":"lodash","version":"4.17.21"}
+{"name":"react","version":"18.2.0"}
+{"name":"typescript","version":"5.3.3"}
+`
+
+	var queried []string
+	s := &Scanner{
+		workDir: tmpDir,
+		runYarnOutdated: func(context.Context) ([]byte, error) {
+			t.Fatal("yarn outdated must not run on Yarn Berry")
+			return nil, nil
+		},
+		runYarnInfo: func(context.Context) ([]byte, error) {
+			return []byte(info), nil
+		},
+		runYarnNpmInfo: func(_ context.Context, names ...string) ([]byte, error) {
+			queried = names
+			return []byte(npmInfo), nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(queried) != 4 {
+		t.Errorf("expected 4 registry lookups, got %v", queried)
+	}
+
+	want := map[string][3]string{
+		"@types/node": {"20.1.0", "20.11.5", "dependencies"},
+		"react":       {"18.0.0", "18.2.0", "dependencies"},
+		"typescript":  {"5.0.4", "5.3.3", "devDependencies"},
+	}
+	if len(modules) != len(want) {
+		t.Fatalf("expected %d modules, got %+v", len(want), modules)
+	}
+	for _, m := range modules {
+		w, ok := want[m.Name]
+		if !ok {
+			t.Errorf("unexpected module %s", m.Name)
+			continue
+		}
+		if m.Version != w[0] || m.Update.Version != w[1] || m.DependencyType != w[2] || !m.Direct {
+			t.Errorf("%s: got %s -> %s (%s, direct=%v), want %s -> %s (%s)",
+				m.Name, m.Version, m.Update.Version, m.DependencyType, m.Direct, w[0], w[1], w[2])
+		}
+	}
+}
+
+func TestIsBerry(t *testing.T) {
+	tests := []struct {
+		name   string
+		pkg    packageJSON
+		files  map[string]string
+		expect bool
+	}{
+		{name: "classic by default", expect: false},
+		{name: "packageManager v1", pkg: packageJSON{PackageManager: "yarn@1.22.19"}, files: map[string]string{".yarnrc.yml": ""}, expect: false},
+		{name: "packageManager v3", pkg: packageJSON{PackageManager: "yarn@3.6.4+sha224.abc"}, expect: true},
+		{name: "berry lockfile", files: map[string]string{"yarn.lock": "__metadata:\n  version: 8\n"}, expect: true},
+		{name: "classic lockfile", files: map[string]string{"yarn.lock": "# yarn lockfile v1\n", ".yarnrc.yml": ""}, expect: false},
+		{name: "yarnrc.yml", files: map[string]string{".yarnrc.yml": "nodeLinker: node-modules\n"}, expect: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", name, err)
+				}
+			}
+			s := &Scanner{workDir: tmpDir}
+			if got := s.isBerry(&tt.pkg); got != tt.expect {
+				t.Errorf("isBerry() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func TestGetUpdates_Cooldown(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkgJSON := `{"dependencies": {"fresh": "^1.0.0", "stable": "^1.0.0"}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(pkgJSON), 0644); err != nil {
+		t.Fatalf("failed to write package.json: %v", err)
+	}
+
+	output, _ := json.Marshal(yarnOutdated{
+		Type: "table",
+		Data: yarnOutdatedTable{
+			Head: []string{"Package", "Current", "Wanted", "Latest", "Package Type"},
+			Body: [][]string{
+				{"fresh", "1.0.0", "1.1.0", "1.1.0", "dependencies"},
+				{"stable", "1.0.0", "2.0.0", "2.0.0", "dependencies"},
+			},
+		},
+	})
+	times := map[string]string{
+		"fresh@1.1.0":  time.Now().Add(-24 * time.Hour).Format(time.RFC3339),
+		"stable@2.0.0": time.Now().Add(-30 * 24 * time.Hour).Format(time.RFC3339),
+	}
+	s := &Scanner{
+		workDir: tmpDir,
+		runYarnOutdated: func(context.Context) ([]byte, error) {
+			return output, nil
+		},
+		fetchPackageTime: func(_ context.Context, name, version string) (string, error) {
+			return times[name+"@"+version], nil
+		},
+	}
+
+	modules, err := s.GetUpdates(scanner.Options{CooldownDays: 7})
+	if err != nil {
+		t.Fatalf("GetUpdates failed: %v", err)
+	}
+	if len(modules) != 1 || modules[0].Name != "stable" {
+		t.Fatalf("expected only stable to pass the cooldown, got %+v", modules)
+	}
+}
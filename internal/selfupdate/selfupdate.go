@@ -0,0 +1,159 @@
+// Package selfupdate checks whether a newer faro release has been
+// published.
+package selfupdate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReleasesURL is the GitHub API endpoint for faro's latest release.
+const DefaultReleasesURL = "https://api.github.com/repos/pragmaticivan/faro/releases/latest"
+
+// CheckInterval is how often the automatic check queries for a release.
+const CheckInterval = 24 * time.Hour
+
+// Checker looks up the latest faro release. The zero value is not usable;
+// create one with New.
+type Checker struct {
+	url        string
+	httpClient *http.Client
+	stampFile  string // Records when the last automatic check ran; empty disables Due
+	now        func() time.Time
+}
+
+// New creates a checker for the public releases. A nil httpClient uses one
+// with a 5 second timeout, so a slow network doesn't hold up the run.
+func New(httpClient *http.Client) *Checker {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 5 * time.Second}
+	}
+	c := &Checker{url: DefaultReleasesURL, httpClient: httpClient, now: time.Now}
+	if base, err := os.UserCacheDir(); err == nil {
+		c.stampFile = filepath.Join(base, "faro", "selfupdate-checked")
+	}
+	return c
+}
+
+// CurrentVersion returns the version faro was built at, e.g. "v1.4.0" for
+// `go install ...@v1.4.0`, or "" for development builds.
+func CurrentVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "(devel)" {
+		return ""
+	}
+	return info.Main.Version
+}
+
+// Latest returns the tag of the latest published release.
+func (c *Checker) Latest(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query faro releases: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("faro releases returned status %d", resp.StatusCode)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to decode faro releases: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("faro releases response has no tag_name")
+	}
+	return release.TagName, nil
+}
+
+// Notice returns a message announcing latest when it is newer than current,
+// or "" when current is up to date or either version can't be compared.
+func Notice(current, latest string) string {
+	if !Newer(latest, current) {
+		return ""
+	}
+	return fmt.Sprintf("A new faro release is available: %s (you have %s)\nUpdate with: go install github.com/pragmaticivan/faro/cmd/faro@latest", latest, current)
+}
+
+// Newer reports whether version a is a newer release than b. Versions that
+// aren't MAJOR.MINOR.PATCH, such as pseudo-versions, are never newer.
+func Newer(a, b string) bool {
+	ra, okA := parseRelease(a)
+	rb, okB := parseRelease(b)
+	if !okA || !okB {
+		return false
+	}
+	for i := range ra {
+		if ra[i] != rb[i] {
+			return ra[i] > rb[i]
+		}
+	}
+	// A release is newer than its own prereleases
+	return !strings.Contains(a, "-") && strings.Contains(b, "-")
+}
+
+// parseRelease parses the MAJOR.MINOR.PATCH core of a version such as
+// "v1.2.3" or "1.2.3-rc.1".
+func parseRelease(v string) ([3]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return [3]int{}, false
+	}
+	var r [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return [3]int{}, false
+		}
+		r[i] = n
+	}
+	return r, true
+}
+
+// Due reports whether the automatic check hasn't run within CheckInterval.
+func (c *Checker) Due() bool {
+	if c.stampFile == "" {
+		return false
+	}
+	info, err := os.Stat(c.stampFile)
+	if err != nil {
+		return true
+	}
+	return c.now().Sub(info.ModTime()) >= CheckInterval
+}
+
+// MarkChecked records that the automatic check ran, so Due is false for the
+// next CheckInterval.
+func (c *Checker) MarkChecked() error {
+	if c.stampFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.stampFile), 0755); err != nil {
+		return err
+	}
+	now := c.now()
+	if err := os.WriteFile(c.stampFile, nil, 0644); err != nil {
+		return err
+	}
+	return os.Chtimes(c.stampFile, now, now)
+}
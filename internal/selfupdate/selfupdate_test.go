@@ -0,0 +1,103 @@
+package selfupdate
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func fakeReleases(status int, body string, requested *string) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		*requested = r.URL.String()
+		return &http.Response{
+			StatusCode: status,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})}
+}
+
+func TestChecker_Latest(t *testing.T) {
+	var requested string
+	c := New(fakeReleases(http.StatusOK, `{"tag_name": "v1.5.0", "name": "v1.5.0", "draft": false}`, &requested))
+
+	latest, err := c.Latest(context.Background())
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if latest != "v1.5.0" {
+		t.Errorf("Latest = %q, want v1.5.0", latest)
+	}
+	if requested != DefaultReleasesURL {
+		t.Errorf("requested %q, want %q", requested, DefaultReleasesURL)
+	}
+	if got := Notice("v1.4.2", latest); !strings.Contains(got, "v1.5.0 (you have v1.4.2)") {
+		t.Errorf("unexpected notice %q", got)
+	}
+	if got := Notice("v1.5.0", latest); got != "" {
+		t.Errorf("expected no notice when up to date, got %q", got)
+	}
+
+	for _, body := range []string{`{"message": "Not Found"}`, `not json`} {
+		c = New(fakeReleases(http.StatusOK, body, &requested))
+		if _, err := c.Latest(context.Background()); err == nil {
+			t.Errorf("expected %q to fail", body)
+		}
+	}
+	c = New(fakeReleases(http.StatusForbidden, `{"message": "API rate limit exceeded"}`, &requested))
+	if _, err := c.Latest(context.Background()); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("expected the status in the error, got %v", err)
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"v1.5.0", "v1.4.9", true},
+		{"v2.0.0", "v1.10.0", true},
+		{"v1.4.0", "v1.4.0", false},
+		{"v1.3.0", "v1.4.0", false},
+		{"v1.4.0", "v1.4.0-rc.1", true},
+		{"v1.4.0-rc.1", "v1.4.0", false},
+		{"v1.5.0", "", false},
+		{"v1.5.0", "v0.0.0-20240101000000-abcdef123456", true},
+		{"nightly", "v1.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.a, tt.b); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestChecker_Due(t *testing.T) {
+	now := time.Date(2026, 1, 17, 12, 0, 0, 0, time.UTC)
+	c := New(nil)
+	c.stampFile = filepath.Join(t.TempDir(), "faro", "selfupdate-checked")
+	c.now = func() time.Time { return now }
+
+	if !c.Due() {
+		t.Fatal("expected a check to be due before the first one")
+	}
+	if err := c.MarkChecked(); err != nil {
+		t.Fatalf("MarkChecked failed: %v", err)
+	}
+	now = now.Add(23 * time.Hour)
+	if c.Due() {
+		t.Error("expected no check within a day of the last one")
+	}
+	now = now.Add(time.Hour)
+	if !c.Due() {
+		t.Error("expected a check to be due after a day")
+	}
+}
@@ -0,0 +1,39 @@
+package style
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// SpinnerFrames are drawn in turn by StartSpinner.
+var SpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinnerInterval is how long each frame is shown.
+const spinnerInterval = 100 * time.Millisecond
+
+// StartSpinner animates label on w, which should be a terminal, until the
+// returned stop function is called. Stopping erases the line so whatever is
+// printed next takes its place.
+func StartSpinner(w io.Writer, label string) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(spinnerInterval)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			_, _ = fmt.Fprintf(w, "\r%s %s", SpinnerFrames[i%len(SpinnerFrames)], label)
+			select {
+			case <-done:
+				_, _ = fmt.Fprint(w, "\r\x1b[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -2,10 +2,12 @@ package style
 
 import (
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 	"github.com/muesli/termenv"
 	"github.com/pragmaticivan/faro/internal/scanner"
 )
@@ -23,6 +25,36 @@ func init() {
 	ColorUnknown = lipgloss.NewStyle().Foreground(lipgloss.Color("13"))         // Magenta
 }
 
+// SetColorEnabled switches styled output between ANSI 256 colors and plain text.
+func SetColorEnabled(enabled bool) {
+	if enabled {
+		lipgloss.SetColorProfile(termenv.ANSI256)
+	} else {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// Colored reports whether styled output currently renders colors, as last set
+// by SetColorEnabled.
+func Colored() bool {
+	return lipgloss.ColorProfile() != termenv.Ascii
+}
+
+// ColorEnabled reports whether output written to w should be colored. Color is
+// disabled when NO_COLOR is set or w isn't a terminal.
+func ColorEnabled(w io.Writer, getenv func(string) string) bool {
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	return IsTerminal(w)
+}
+
+// IsTerminal reports whether w is a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	return ok && term.IsTerminal(f.Fd())
+}
+
 type DiffType int
 
 const (
@@ -144,6 +176,38 @@ func FormatUpdate(path, vOld, vNew string, padPath int) string {
 	)
 }
 
+// FormatCurrent returns a colored "package  v1.0.0" for a module that has no
+// update, such as a vulnerable package that is already at its latest version.
+func FormatCurrent(path, version string, padPath int) string {
+	return fmt.Sprintf("%s  %s", ColorPath.Render(fmt.Sprintf("%-*s", padPath, path)), version)
+}
+
+// Vulnerability severity levels for VisibleVulns, from least to most severe.
+const (
+	SeverityLow = iota
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// VisibleVulns drops the counts below minLevel from info (SeverityLow keeps
+// everything). Total then only counts the remaining severities, so findings
+// of unknown severity are hidden too.
+func VisibleVulns(info scanner.VulnInfo, minLevel int) scanner.VulnInfo {
+	if minLevel <= SeverityLow {
+		return info
+	}
+	counts := []*int{&info.Low, &info.Medium, &info.High, &info.Critical}
+	info.Total = 0
+	for level, n := range counts {
+		if level < minLevel {
+			*n = 0
+		}
+		info.Total += *n
+	}
+	return info
+}
+
 // FormatVulnInfo formats vulnerability information as a colored string
 // Returns "[L (1), M (2), H (1), C (1)]" with appropriate colors
 // Returns empty string if no vulnerabilities
@@ -185,6 +249,47 @@ func FormatVulnInfo(info scanner.VulnInfo) string {
 	return result
 }
 
+// FormatVulnTransition creates a compact string showing vulnerability transitions
+// e.g., "[L (1), M (2), H (2)] → [L (0)]" or just "[L (1), M (2)]" if no update info
+func FormatVulnTransition(current, update scanner.VulnInfo) string {
+	green := lipgloss.NewStyle().Foreground(lipgloss.Color("46"))
+	red := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	currentStr := FormatVulnInfo(current)
+	if currentStr == "" {
+		return ""
+	}
+
+	updateStr := FormatVulnInfo(update)
+
+	// Show transition with arrow
+	fixed := current.Total - update.Total
+
+	if fixed > 0 {
+		// Vulnerabilities were fixed
+		if updateStr == "" {
+			return fmt.Sprintf("%s → %s", currentStr, green.Render(fmt.Sprintf("✓ (fixes %d)", fixed)))
+		}
+		return fmt.Sprintf("%s → %s %s", currentStr, updateStr, green.Render(fmt.Sprintf("(fixes %d)", fixed)))
+	} else if fixed < 0 {
+		// More vulnerabilities in update
+		return fmt.Sprintf("%s → %s %s", currentStr, updateStr, red.Render(fmt.Sprintf("(+%d)", -fixed)))
+	} else if update.Total > 0 {
+		// Same count but might be different types
+		return fmt.Sprintf("%s → %s", currentStr, updateStr)
+	}
+
+	// No change or no update checked
+	return currentStr
+}
+
+// FormatDeprecated renders a warning marker for an update the registry marks
+// as deprecated, with its message on one line.
+func FormatDeprecated(message string) string {
+	yellow := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	return yellow.Render("⚠ deprecated: " + strings.Join(strings.Fields(message), " "))
+}
+
 // FormatUpdateWithVulns formats a module update line with vulnerability information
 func FormatUpdateWithVulns(path, vOld, vNew string, padPath int, vulnCurrent, vulnUpdate scanner.VulnInfo, showVulns bool) string {
 	diff := GetDiffType(vOld, vNew)
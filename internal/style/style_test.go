@@ -1,8 +1,13 @@
 package style
 
 import (
+	"bytes"
+	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
 )
 
 func TestGetDiffType_Semver(t *testing.T) {
@@ -51,3 +56,81 @@ func TestGetVersionStyle_DoesNotPanic(t *testing.T) {
 	_ = GetVersionStyle(DiffUnknown)
 	_ = GetVersionStyle(DiffSame)
 }
+
+func TestColorEnabled(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	if ColorEnabled(&bytes.Buffer{}, noEnv) {
+		t.Fatalf("expected no color for a non-terminal writer")
+	}
+
+	noColor := func(key string) string {
+		if key == "NO_COLOR" {
+			return "1"
+		}
+		return ""
+	}
+	if ColorEnabled(os.Stdout, noColor) {
+		t.Fatalf("expected NO_COLOR to disable color")
+	}
+}
+
+func TestSetColorEnabled_StripsEscapes(t *testing.T) {
+	t.Cleanup(func() { SetColorEnabled(true) })
+
+	SetColorEnabled(true)
+	if got := FormatUpdate("example.com/mod", "v1.0.0", "v2.0.0", 20); !strings.Contains(got, "\x1b[") {
+		t.Fatalf("expected escape codes with color enabled, got %q", got)
+	}
+	if !Colored() {
+		t.Error("expected Colored to report color enabled")
+	}
+
+	SetColorEnabled(false)
+	if got := FormatUpdate("example.com/mod", "v1.0.0", "v2.0.0", 20); strings.Contains(got, "\x1b[") {
+		t.Fatalf("expected no escape codes with color disabled, got %q", got)
+	}
+	if Colored() {
+		t.Error("expected Colored to report color disabled")
+	}
+}
+
+func TestVisibleVulns(t *testing.T) {
+	t.Cleanup(func() { SetColorEnabled(true) })
+	SetColorEnabled(false)
+
+	lowOnly := scanner.VulnInfo{Low: 2, Total: 2}
+	mixed := scanner.VulnInfo{Low: 1, High: 1, Critical: 1, Total: 3}
+	if got := FormatVulnInfo(VisibleVulns(lowOnly, SeverityLow)); got != "[L (2)]" {
+		t.Fatalf("expected every severity at SeverityLow, got %q", got)
+	}
+
+	if got := VisibleVulns(lowOnly, SeverityHigh); got != (scanner.VulnInfo{}) {
+		t.Errorf("expected a low-only finding to be hidden, got %+v", got)
+	}
+	if got := FormatVulnTransition(VisibleVulns(lowOnly, SeverityHigh), scanner.VulnInfo{}); got != "" {
+		t.Errorf("expected no transition for a low-only finding, got %q", got)
+	}
+	if got := FormatVulnInfo(VisibleVulns(mixed, SeverityHigh)); got != "[H (1), C (1)]" {
+		t.Errorf("expected only high and critical counts, got %q", got)
+	}
+	// The low finding that remains in the update doesn't count against the fix
+	update := VisibleVulns(scanner.VulnInfo{Low: 1, Total: 1}, SeverityHigh)
+	if got := FormatVulnTransition(VisibleVulns(mixed, SeverityHigh), update); got != "[H (1), C (1)] → ✓ (fixes 2)" {
+		t.Errorf("unexpected transition %q", got)
+	}
+}
+
+func TestStartSpinner(t *testing.T) {
+	var buf bytes.Buffer
+	stop := StartSpinner(&buf, "Checking for updates...")
+	time.Sleep(250 * time.Millisecond)
+	stop()
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "\r"+SpinnerFrames[0]+" Checking for updates...\r"+SpinnerFrames[1]+" ") {
+		t.Errorf("expected successive frames, got %q", got)
+	}
+	if !strings.HasSuffix(got, "\r\x1b[K") {
+		t.Errorf("expected the spinner line to be erased, got %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,17 +23,20 @@ var runProgram = func(m tea.Model) (tea.Model, error) {
 
 // Options configures rendering and grouping behavior for the interactive TUI.
 type Options struct {
-	FormatGroup     bool
-	FormatTime      bool
-	Updater         updater.Updater // The updater instance to use for applying updates
-	DirectLabel     string          // Label for direct dependencies
-	IndirectLabel   string          // Label for indirect/dev dependencies
-	TransitiveLabel string          // Label for transitive dependencies
+	FormatGroup         bool
+	Grouping            format.Grouping // How FormatGroup buckets rows; the zero value groups by bump
+	FormatTime          bool
+	ShowVulnerabilities bool            // Render current → update vulnerability counts on each row
+	MinSeverity         int             // Hide vulnerability counts below this style.Severity* level
+	Updater             updater.Updater // The updater instance to use for applying updates
+	DirectLabel         string          // Label for direct dependencies
+	IndirectLabel       string          // Label for indirect/dev dependencies
+	TransitiveLabel     string          // Label for transitive dependencies
 }
 
 type model struct {
-	choices  []scanner.Module
-	selected map[int]struct{}
+	choices  []scanner.Module    // Visible rows (all, narrowed by the filter)
+	selected map[string]struct{} // Keyed by moduleKey so it survives filtering
 	cursor   int
 	quitting bool
 
@@ -40,32 +44,79 @@ type model struct {
 	indirectEnd  int
 	transitiveOn bool
 
+	// Unfiltered rows and their section boundaries
+	all            []scanner.Module
+	allDirectEnd   int
+	allIndirectEnd int
+	maxPathLen     int // Name column width over all rows, so filtering keeps alignment
+
+	filter    string
+	filtering bool // Whether keystrokes currently edit the filter
+
+	height int // Terminal height from tea.WindowSizeMsg; 0 renders every row
+	offset int // First list line shown when the list is taller than the window
+
+	showHelp bool // Whether the expanded key binding list is shown
+
 	opts Options
 }
 
+// keyBindings lists every key shown in the expanded help panel.
+var keyBindings = []struct{ keys, desc string }{
+	{"↑/k", "move up"},
+	{"↓/j", "move down"},
+	{"space", "toggle selection"},
+	{"a", "select all visible"},
+	{"i/A", "invert visible selection"},
+	{"/", "filter by name (enter to apply, esc to clear)"},
+	{"enter", "update selected packages"},
+	{"?", "toggle this help"},
+	{"q/ctrl+c", "quit without updating"},
+}
+
+// vulnKeyBindings lists the extra keys available with ShowVulnerabilities.
+var vulnKeyBindings = []struct{ keys, desc string }{
+	{"s", "sort by current vulnerability severity"},
+	{"f", "select updates that fix vulnerabilities"},
+}
+
+// keyBindings returns the keys that apply to this model.
+func (m model) keyBindings() []struct{ keys, desc string } {
+	if !m.opts.ShowVulnerabilities {
+		return keyBindings
+	}
+	return append(keyBindings[:len(keyBindings):len(keyBindings)], vulnKeyBindings...)
+}
+
+// moduleKey identifies a module independently of its position in the list.
+func moduleKey(m scanner.Module) string {
+	name := m.Name
+	if name == "" {
+		name = m.Path
+	}
+	return m.Workspace + "\x00" + name + "@" + m.Version
+}
+
+// sortGrouped orders modules so each bucket of g is contiguous, with buckets
+// in GroupSortKey order.
+func sortGrouped(modules []scanner.Module, g format.Grouping) {
+	sort.SliceStable(modules, func(i, j int) bool {
+		ai, aj := format.GroupSortKey(modules[i], g), format.GroupSortKey(modules[j], g)
+		if ai != aj {
+			return ai < aj
+		}
+		if li, lj := format.GroupLabel(modules[i], g), format.GroupLabel(modules[j], g); li != lj {
+			return li < lj
+		}
+		return modules[i].Path < modules[j].Path
+	})
+}
+
 func initialModel(direct, indirect, transitive []scanner.Module, opts Options) model {
 	if opts.FormatGroup {
-		sort.Slice(direct, func(i, j int) bool {
-			ai, aj := format.GroupSortKey(direct[i]), format.GroupSortKey(direct[j])
-			if ai != aj {
-				return ai < aj
-			}
-			return direct[i].Path < direct[j].Path
-		})
-		sort.Slice(indirect, func(i, j int) bool {
-			ai, aj := format.GroupSortKey(indirect[i]), format.GroupSortKey(indirect[j])
-			if ai != aj {
-				return ai < aj
-			}
-			return indirect[i].Path < indirect[j].Path
-		})
-		sort.Slice(transitive, func(i, j int) bool {
-			ai, aj := format.GroupSortKey(transitive[i]), format.GroupSortKey(transitive[j])
-			if ai != aj {
-				return ai < aj
-			}
-			return transitive[i].Path < transitive[j].Path
-		})
+		sortGrouped(direct, opts.Grouping)
+		sortGrouped(indirect, opts.Grouping)
+		sortGrouped(transitive, opts.Grouping)
 	}
 
 	choices := make([]scanner.Module, 0, len(direct)+len(indirect)+len(transitive))
@@ -76,22 +127,107 @@ func initialModel(direct, indirect, transitive []scanner.Module, opts Options) m
 	choices = append(choices, transitive...)
 
 	return model{
-		choices:      choices,
-		selected:     make(map[int]struct{}),
-		directEnd:    directEnd,
-		indirectEnd:  indirectEnd,
-		transitiveOn: len(transitive) > 0,
-		opts:         opts,
+		choices:        choices,
+		selected:       make(map[string]struct{}),
+		directEnd:      directEnd,
+		indirectEnd:    indirectEnd,
+		transitiveOn:   len(transitive) > 0,
+		all:            choices,
+		allDirectEnd:   directEnd,
+		allIndirectEnd: indirectEnd,
+		maxPathLen:     scanner.MaxPathLength(choices),
+		opts:           opts,
 	}
 }
 
+// applyFilter narrows choices to names containing the filter
+// (case-insensitive), keeping the cursor on the same module when possible.
+func (m *model) applyFilter() {
+	var current string
+	if m.cursor >= 0 && m.cursor < len(m.choices) {
+		current = moduleKey(m.choices[m.cursor])
+	}
+
+	query := strings.ToLower(m.filter)
+	choices := make([]scanner.Module, 0, len(m.all))
+	directEnd, indirectEnd := 0, 0
+	transitiveOn := false
+	for i, c := range m.all {
+		name := c.Name
+		if name == "" {
+			name = c.Path
+		}
+		if query != "" && !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+		choices = append(choices, c)
+		if i < m.allDirectEnd {
+			directEnd++
+		}
+		if i < m.allIndirectEnd {
+			indirectEnd++
+		} else {
+			transitiveOn = true
+		}
+	}
+
+	m.choices = choices
+	m.directEnd = directEnd
+	m.indirectEnd = indirectEnd
+	m.transitiveOn = transitiveOn
+
+	m.cursor = 0
+	for i, c := range m.choices {
+		if moduleKey(c) == current {
+			m.cursor = i
+			break
+		}
+	}
+}
+
+// sortBySeverity orders each section, and each group within it under
+// FormatGroup, by the current version's vulnerabilities, most severe first.
+// The filter is reapplied so the cursor stays on the same module.
+func (m *model) sortBySeverity() {
+	// Sort a copy: choices may share the backing array and must still point
+	// at the cursor's module until the filter is reapplied
+	m.all = append([]scanner.Module(nil), m.all...)
+	for _, section := range [][]scanner.Module{
+		m.all[:m.allDirectEnd],
+		m.all[m.allDirectEnd:m.allIndirectEnd],
+		m.all[m.allIndirectEnd:],
+	} {
+		start := 0
+		for i := 1; i <= len(section); i++ {
+			if i < len(section) && (!m.opts.FormatGroup ||
+				format.GroupLabel(section[i], m.opts.Grouping) == format.GroupLabel(section[start], m.opts.Grouping)) {
+				continue
+			}
+			format.SortModules(section[start:i], format.SortSeverity)
+			start = i
+		}
+	}
+	m.applyFilter()
+}
+
+// fixesVulnerabilities reports whether updating c leaves fewer known
+// vulnerabilities than its current version has.
+func fixesVulnerabilities(c scanner.Module) bool {
+	return c.Update != nil && c.VulnUpdate.Total < c.VulnCurrent.Total
+}
+
 func (m model) Init() tea.Cmd {
 	return nil
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
 	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
 		switch msg.String() {
 		case "ctrl+c", "q":
 			m.quitting = true
@@ -106,52 +242,222 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case " ", "space":
 			if m.cursor >= 0 && m.cursor < len(m.choices) {
-				_, ok := m.selected[m.cursor]
+				key := moduleKey(m.choices[m.cursor])
+				_, ok := m.selected[key]
 				if ok {
-					delete(m.selected, m.cursor)
+					delete(m.selected, key)
+				} else {
+					m.selected[key] = struct{}{}
+				}
+			}
+		case "a":
+			for _, c := range m.choices {
+				m.selected[moduleKey(c)] = struct{}{}
+			}
+		case "A", "i":
+			for _, c := range m.choices {
+				key := moduleKey(c)
+				if _, ok := m.selected[key]; ok {
+					delete(m.selected, key)
 				} else {
-					m.selected[m.cursor] = struct{}{}
+					m.selected[key] = struct{}{}
 				}
 			}
+		case "s":
+			if m.opts.ShowVulnerabilities {
+				m.sortBySeverity()
+			}
+		case "f":
+			if m.opts.ShowVulnerabilities {
+				for _, c := range m.choices {
+					if fixesVulnerabilities(c) {
+						m.selected[moduleKey(c)] = struct{}{}
+					}
+				}
+			}
+		case "/":
+			m.filtering = true
+		case "?":
+			m.showHelp = !m.showHelp
+		case "esc":
+			if m.filter != "" {
+				m.filter = ""
+				m.applyFilter()
+			}
 		case "enter":
 			return m, tea.Quit
 		}
 	}
+	m.scrollToCursor()
 	return m, nil
 }
 
+// updateFilter handles keys while the filter input is focused.
+func (m model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.filtering = false
+		m.filter = ""
+		m.applyFilter()
+	case tea.KeyEnter:
+		m.filtering = false
+	case tea.KeyBackspace:
+		if m.filter != "" {
+			r := []rune(m.filter)
+			m.filter = string(r[:len(r)-1])
+			m.applyFilter()
+		}
+	case tea.KeyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case tea.KeyDown:
+		if m.cursor < len(m.choices)-1 {
+			m.cursor++
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filter += string(msg.Runes)
+		m.applyFilter()
+	}
+	m.scrollToCursor()
+	return m, nil
+}
+
+// listLine is one rendered line of the choice list.
+type listLine struct {
+	text    string
+	choice  int // Index into m.choices, or -1 for headings and spacing
+	heading int // Line index of the enclosing section heading, or -1
+}
+
+// listHeight returns how many list lines fit in the window, or 0 when the
+// window size is unknown.
+func (m model) listHeight() int {
+	if m.height <= 0 {
+		return 0
+	}
+	// Prompt and help text (2 lines each), plus the pinned section heading
+	// and the "more below" indicator
+	chrome := 6
+	if m.filtering || m.filter != "" {
+		chrome += 2
+	}
+	if m.showHelp {
+		chrome += len(m.keyBindings())
+	}
+	if rows := m.height - chrome; rows > 0 {
+		return rows
+	}
+	return 1
+}
+
+// scrollToCursor adjusts the offset so the cursor row stays in the window.
+func (m *model) scrollToCursor() {
+	rows := m.listHeight()
+	if rows == 0 {
+		m.offset = 0
+		return
+	}
+	lines := m.listLines()
+	for i, l := range lines {
+		if l.choice != m.cursor {
+			continue
+		}
+		if i < m.offset {
+			m.offset = i
+			// Keep the heading right above the first row of a section in view
+			if l.heading >= 0 && l.heading >= i-2 {
+				m.offset = l.heading
+			}
+		}
+		if i >= m.offset+rows {
+			m.offset = i - rows + 1
+		}
+		break
+	}
+	if limit := len(lines) - rows; m.offset > limit {
+		m.offset = limit
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
 func (m model) View() string {
 	if m.quitting {
 		return "Bye!\n"
 	}
 
 	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	heading := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
-	headingMuted := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("240"))
 
 	s := "Which packages would you like to update?\n\n"
+	if m.filtering || m.filter != "" {
+		cursor := ""
+		if m.filtering {
+			cursor = "█"
+		}
+		s += dim.Render("Filter: ") + m.filter + cursor + "\n\n"
+		if len(m.choices) == 0 {
+			s += dim.Render("No packages match the filter.") + "\n"
+		}
+	}
 
-	// Find longest path for padding
-	maxPathLen := 0
-	for _, c := range m.choices {
-		name := c.Name
-		if name == "" {
-			name = c.Path
+	lines := m.listLines()
+	first, last := 0, len(lines)
+	if rows := m.listHeight(); rows > 0 && len(lines) > rows {
+		first = m.offset
+		last = min(first+rows, len(lines))
+		// Pin the section heading when its section is scrolled into view
+		if h := lines[first].heading; h >= 0 && h < first {
+			s += lines[h].text + "\n"
+		}
+	}
+	for _, l := range lines[first:last] {
+		s += l.text + "\n"
+	}
+	if more := countChoices(lines[last:]); more > 0 {
+		s += dim.Render(fmt.Sprintf("  ↓ %d more below", more)) + "\n"
+	}
+
+	if m.showHelp {
+		s += "\n" + dim.Render("Keys:") + "\n"
+		for _, b := range m.keyBindings() {
+			s += fmt.Sprintf("  %-10s %s\n", b.keys, dim.Render(b.desc))
 		}
-		if len(name) > maxPathLen {
-			maxPathLen = len(name)
+		return s
+	}
+	s += "\nPress <space> to select, <enter> to update, <?> for more keys, <q> to quit.\n"
+	return s
+}
+
+// listLines renders the section headings and rows of the visible choices.
+func (m model) listLines() []listLine {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	heading := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	headingMuted := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("240"))
+
+	var lines []listLine
+	section := -1
+	addHeading := func(text string, spaced bool) {
+		if spaced {
+			lines = append(lines, listLine{choice: -1, heading: section})
 		}
+		section = len(lines)
+		lines = append(lines, listLine{text: text, choice: -1, heading: -1})
 	}
 
 	prevGroup := ""
 	for i, choice := range m.choices {
 		// Section headings (do not affect cursor/selection indices)
-		if i == 0 {
+		if i == 0 && m.directEnd > 0 {
 			label := m.opts.DirectLabel
 			if label == "" {
 				label = "Direct dependencies"
 			}
-			s += heading.Render(label) + "\n"
+			addHeading(heading.Render(label), false)
 			prevGroup = ""
 		}
 		if i == m.directEnd && i < len(m.choices) {
@@ -159,7 +465,7 @@ func (m model) View() string {
 			if label == "" {
 				label = "Indirect dependencies"
 			}
-			s += "\n" + headingMuted.Render(label) + "\n"
+			addHeading(headingMuted.Render(label), true)
 			prevGroup = ""
 		}
 		if m.transitiveOn && i == m.indirectEnd && i < len(m.choices) {
@@ -167,14 +473,16 @@ func (m model) View() string {
 			if label == "" {
 				label = "Transitive"
 			}
-			s += "\n" + headingMuted.Render(label) + "\n"
+			addHeading(headingMuted.Render(label), true)
 			prevGroup = ""
 		}
 
 		if m.opts.FormatGroup {
-			g := format.GroupLabel(choice)
+			g := format.GroupLabel(choice, m.opts.Grouping)
 			if g != prevGroup {
-				s += "\n" + dim.Render(g) + "\n"
+				lines = append(lines,
+					listLine{choice: -1, heading: section},
+					listLine{text: dim.Render(g), choice: -1, heading: section})
 				prevGroup = g
 			}
 		}
@@ -187,7 +495,7 @@ func (m model) View() string {
 
 		// Checkbox
 		var checked string
-		if _, ok := m.selected[i]; ok {
+		if _, ok := m.selected[moduleKey(choice)]; ok {
 			checked = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render("◉")
 		} else {
 			checked = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("◯")
@@ -198,19 +506,50 @@ func (m model) View() string {
 		if name == "" {
 			name = choice.Path
 		}
-		row := style.FormatUpdate(name, choice.Version, choice.Update.Version, maxPathLen)
-		if m.opts.FormatTime && choice.Update != nil {
-			pt := format.PublishTime(choice.Update.Time, time.Now())
-			if pt != "" {
-				row += "  " + dim.Render(pt)
+		var row string
+		if choice.Update == nil {
+			// Listed for its vulnerabilities only
+			row = style.FormatCurrent(name, choice.Version, m.maxPathLen)
+			if vulns := style.FormatVulnInfo(style.VisibleVulns(choice.VulnCurrent, m.opts.MinSeverity)); m.opts.ShowVulnerabilities && vulns != "" {
+				row += " " + vulns + "  " + dim.Render("(no update available)")
+			}
+		} else {
+			row = style.FormatUpdate(name, choice.Version, choice.Update.Version, m.maxPathLen)
+			if vulns := style.FormatVulnTransition(style.VisibleVulns(choice.VulnCurrent, m.opts.MinSeverity), style.VisibleVulns(choice.VulnUpdate, m.opts.MinSeverity)); m.opts.ShowVulnerabilities && vulns != "" {
+				row += " " + vulns
+			}
+			if choice.Update.Deprecated != "" {
+				row += "  " + style.FormatDeprecated(choice.Update.Deprecated)
+			}
+			if m.opts.FormatTime {
+				pt := format.PublishTime(choice.Update.Time, time.Now())
+				if pt != "" {
+					row += "  " + dim.Render(pt)
+				}
+				if age := format.CurrentAge(choice.Time, time.Now()); age != "" {
+					row += "  " + dim.Render("("+age+")")
+				}
 			}
 		}
 
-		s += fmt.Sprintf("%s%s %s\n", cursor, checked, row)
+		lines = append(lines, listLine{
+			text:    fmt.Sprintf("%s%s %s", cursor, checked, row),
+			choice:  i,
+			heading: section,
+		})
 	}
+	return lines
+}
 
-	s += "\nPress <space> to select, <enter> to update, <q> to quit.\n"
-	return s
+// countChoices returns how many of lines are module rows.
+func countChoices(lines []listLine) int {
+	n := 0
+	for _, l := range lines {
+		if l.choice >= 0 {
+			n++
+		}
+	}
+	return n
 }
 
 // StartInteractiveGroupedWithOptions launches the TUI with groups split by go.mod classification.
@@ -223,11 +562,11 @@ func StartInteractiveGroupedWithOptions(direct, indirect, transitive []scanner.M
 
 	// Type assertion to get back our model
 	if finalModel, ok := m.(model); ok && !finalModel.quitting {
-		// Collect selected modules
+		// Collect selected modules, including any hidden by the filter
 		var toUpdate []scanner.Module
-		for i := range finalModel.selected {
-			if i >= 0 && i < len(finalModel.choices) {
-				toUpdate = append(toUpdate, finalModel.choices[i])
+		for _, c := range finalModel.all {
+			if _, ok := finalModel.selected[moduleKey(c)]; ok {
+				toUpdate = append(toUpdate, c)
 			}
 		}
 
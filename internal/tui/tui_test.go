@@ -1,11 +1,16 @@
 package tui
 
 import (
+	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pragmaticivan/faro/internal/format"
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/style"
 )
 
 type mockUpdater struct {
@@ -38,18 +43,376 @@ func TestModelSelectionAndCursor(t *testing.T) {
 	// Toggle selection with space
 	modelAny, _ = m2.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
 	m3 := modelAny.(model)
-	if _, ok := m3.selected[1]; !ok {
+	if _, ok := m3.selected[moduleKey(indirect[0])]; !ok {
 		t.Fatalf("expected item selected")
 	}
 
 	// Toggle again
 	modelAny, _ = m3.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{' '}})
 	m4 := modelAny.(model)
-	if _, ok := m4.selected[1]; ok {
+	if _, ok := m4.selected[moduleKey(indirect[0])]; ok {
 		t.Fatalf("expected item deselected")
 	}
 }
 
+func pressKeys(t *testing.T, m model, keys ...tea.KeyMsg) model {
+	t.Helper()
+	for _, k := range keys {
+		next, _ := m.Update(k)
+		m = next.(model)
+	}
+	return m
+}
+
+func typeRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestFilter_NarrowsChoicesAndPreservesSelection(t *testing.T) {
+	direct := []scanner.Module{
+		{Name: "react", Version: "18.0.0", Update: &scanner.UpdateInfo{Version: "19.0.0"}},
+		{Name: "react-dom", Version: "18.0.0", Update: &scanner.UpdateInfo{Version: "19.0.0"}},
+		{Name: "lodash", Version: "4.0.0", Update: &scanner.UpdateInfo{Version: "4.1.0"}},
+	}
+	indirect := []scanner.Module{
+		{Name: "ReactTestUtils", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.1.0"}},
+	}
+	m := initialModel(direct, indirect, nil, Options{})
+
+	// Select lodash before filtering
+	m = pressKeys(t, m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, typeRunes(" "))
+	if _, ok := m.selected[moduleKey(direct[2])]; !ok {
+		t.Fatalf("expected lodash selected")
+	}
+
+	// Filter is case-insensitive and hides lodash
+	m = pressKeys(t, m, typeRunes("/"), typeRunes("REA"))
+	if len(m.choices) != 3 {
+		t.Fatalf("expected 3 visible choices, got %d", len(m.choices))
+	}
+	if m.directEnd != 2 || m.indirectEnd != 3 {
+		t.Fatalf("unexpected section bounds: direct=%d indirect=%d", m.directEnd, m.indirectEnd)
+	}
+	if view := m.View(); strings.Contains(view, "lodash") || !strings.Contains(view, "REA█") {
+		t.Fatalf("unexpected filtered view: %q", view)
+	}
+
+	// Select react-dom in the filtered view
+	m = pressKeys(t, m, tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyDown}, typeRunes(" "))
+	if m.quitting || m.filtering {
+		t.Fatalf("expected enter to close the filter input only")
+	}
+	if m.choices[m.cursor].Name != "react-dom" {
+		t.Fatalf("expected cursor on react-dom, got %q", m.choices[m.cursor].Name)
+	}
+
+	// Esc clears the filter; both selections survive
+	m = pressKeys(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filter != "" || len(m.choices) != 4 {
+		t.Fatalf("expected filter cleared, got %q with %d choices", m.filter, len(m.choices))
+	}
+	if len(m.selected) != 2 {
+		t.Fatalf("expected 2 selections, got %d", len(m.selected))
+	}
+	if _, ok := m.selected[moduleKey(direct[2])]; !ok {
+		t.Fatalf("expected lodash to stay selected")
+	}
+	if m.choices[m.cursor].Name != "react-dom" {
+		t.Fatalf("expected cursor to stay on react-dom, got %q", m.choices[m.cursor].Name)
+	}
+}
+
+func TestFilter_KeepsColumnAlignment(t *testing.T) {
+	direct := []scanner.Module{
+		{Name: "react", Version: "18.0.0", Update: &scanner.UpdateInfo{Version: "19.0.0"}},
+		{Name: "a-much-longer-package-name", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.1.0"}},
+	}
+	m := initialModel(direct, nil, nil, Options{})
+	row := func(view string) string {
+		for _, line := range strings.Split(view, "\n") {
+			if i := strings.Index(line, "react "); i >= 0 {
+				return line[i:]
+			}
+		}
+		t.Fatalf("no react row in %q", view)
+		return ""
+	}
+	before := row(m.View())
+
+	// Hiding the longest name must not shift react's version column
+	m = pressKeys(t, m, typeRunes("/"), typeRunes("react"))
+	if len(m.choices) != 1 {
+		t.Fatalf("expected 1 visible choice, got %d", len(m.choices))
+	}
+	if after := row(m.View()); after != before {
+		t.Errorf("alignment changed when filtering: %q, want %q", after, before)
+	}
+}
+
+func TestFilter_BackspaceAndNoMatches(t *testing.T) {
+	direct := []scanner.Module{{Path: "github.com/a/b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}}}
+	m := initialModel(direct, nil, nil, Options{})
+
+	m = pressKeys(t, m, typeRunes("/"), typeRunes("zz"))
+	if len(m.choices) != 0 || !strings.Contains(m.View(), "No packages match") {
+		t.Fatalf("expected no matches")
+	}
+
+	m = pressKeys(t, m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, typeRunes("a/b"))
+	if len(m.choices) != 1 {
+		t.Fatalf("expected 1 match after editing filter, got %d", len(m.choices))
+	}
+}
+
+func TestSelectAll(t *testing.T) {
+	direct := []scanner.Module{
+		{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}},
+		{Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}},
+	}
+	transitive := []scanner.Module{{Path: "c", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}}}
+	m := initialModel(direct, nil, transitive, Options{})
+
+	m = pressKeys(t, m, typeRunes("a"))
+	if len(m.selected) != len(m.choices) {
+		t.Fatalf("expected %d selections, got %d", len(m.choices), len(m.selected))
+	}
+
+	// Pressing again keeps everything selected
+	m = pressKeys(t, m, typeRunes("a"))
+	if len(m.selected) != len(m.choices) {
+		t.Fatalf("expected select all to be idempotent, got %d", len(m.selected))
+	}
+}
+
+func TestSelectAll_RespectsFilter(t *testing.T) {
+	direct := []scanner.Module{
+		{Path: "github.com/x/alpha", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}},
+		{Path: "github.com/x/beta", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}},
+	}
+	m := initialModel(direct, nil, nil, Options{})
+
+	m = pressKeys(t, m, typeRunes("/"), typeRunes("beta"), tea.KeyMsg{Type: tea.KeyEnter}, typeRunes("a"))
+	if len(m.selected) != 1 {
+		t.Fatalf("expected only the visible module selected, got %d", len(m.selected))
+	}
+	if _, ok := m.selected[moduleKey(direct[1])]; !ok {
+		t.Fatalf("expected beta selected")
+	}
+}
+
+func TestInvertSelection(t *testing.T) {
+	direct := []scanner.Module{
+		{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}},
+		{Path: "b", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}},
+		{Path: "c", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}},
+	}
+	m := initialModel(direct, nil, nil, Options{})
+
+	// Select "a", then invert
+	m = pressKeys(t, m, typeRunes(" "), typeRunes("i"))
+	if len(m.selected) != 2 {
+		t.Fatalf("expected 2 selections after invert, got %d", len(m.selected))
+	}
+	if _, ok := m.selected[moduleKey(direct[0])]; ok {
+		t.Fatalf("expected a to be deselected")
+	}
+
+	// "A" inverts back, and only visible rows are touched
+	m = pressKeys(t, m, typeRunes("/"), typeRunes("c"), tea.KeyMsg{Type: tea.KeyEnter}, typeRunes("A"))
+	if len(m.selected) != 1 {
+		t.Fatalf("expected 1 selection, got %d", len(m.selected))
+	}
+	if _, ok := m.selected[moduleKey(direct[1])]; !ok {
+		t.Fatalf("expected hidden selection of b to be kept")
+	}
+}
+
+func TestView_WindowsLongLists(t *testing.T) {
+	var direct, indirect []scanner.Module
+	for i := 0; i < 20; i++ {
+		direct = append(direct, scanner.Module{Path: fmt.Sprintf("prod-%02d", i), Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}})
+		indirect = append(indirect, scanner.Module{Path: fmt.Sprintf("dev-%02d", i), Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}})
+	}
+	m := initialModel(direct, indirect, nil, Options{DirectLabel: "Direct", IndirectLabel: "Indirect"})
+
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 16})
+	m = next.(model)
+
+	view := m.View()
+	if !strings.Contains(view, "prod-00") || strings.Contains(view, "prod-19") {
+		t.Fatalf("expected only the first rows to render, got: %q", view)
+	}
+	if !strings.Contains(view, "more below") {
+		t.Fatalf("expected a more-below indicator")
+	}
+	if lines := strings.Count(view, "\n"); lines > 16 {
+		t.Fatalf("expected view to fit 16 rows, got %d", lines)
+	}
+
+	// Move the cursor into the indirect section; the window follows it
+	for i := 0; i < 25; i++ {
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = next.(model)
+	}
+	view = m.View()
+	if !strings.Contains(view, "dev-05") {
+		t.Fatalf("expected cursor row to be visible, got: %q", view)
+	}
+	if strings.Contains(view, "prod-00") {
+		t.Fatalf("expected earlier rows to scroll out of view")
+	}
+	if !strings.Contains(view, "Indirect") {
+		t.Fatalf("expected the section heading to stay visible")
+	}
+	if lines := strings.Count(view, "\n"); lines > 16 {
+		t.Fatalf("expected view to fit 16 rows, got %d", lines)
+	}
+
+	// At the bottom nothing more is hidden below
+	for i := 0; i < 20; i++ {
+		next, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = next.(model)
+	}
+	view = m.View()
+	if !strings.Contains(view, "dev-19") || strings.Contains(view, "more below") {
+		t.Fatalf("expected last row without indicator, got: %q", view)
+	}
+}
+
+func TestHelpToggle(t *testing.T) {
+	direct := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}}}
+	m := initialModel(direct, nil, nil, Options{})
+
+	compact := m.View()
+	if strings.Contains(compact, "invert visible selection") {
+		t.Fatalf("expected help to be hidden by default")
+	}
+
+	m = pressKeys(t, m, typeRunes("?"))
+	expanded := m.View()
+	for _, b := range keyBindings {
+		if !strings.Contains(expanded, b.desc) {
+			t.Fatalf("expected help to list %q, got: %q", b.desc, expanded)
+		}
+	}
+
+	m = pressKeys(t, m, typeRunes("?"))
+	if m.View() != compact {
+		t.Fatalf("expected second ? to restore the compact view")
+	}
+}
+
+func TestView_VulnerableWithoutUpdate(t *testing.T) {
+	direct := []scanner.Module{{
+		Path:        "gopkg.in/yaml.v3",
+		Version:     "v3.0.1",
+		VulnCurrent: scanner.VulnInfo{High: 1, Total: 1},
+	}}
+
+	m := initialModel(direct, nil, nil, Options{ShowVulnerabilities: true, FormatTime: true, FormatGroup: true})
+	view := m.View()
+	if !strings.Contains(view, "(no update available)") {
+		t.Fatalf("expected the row to note there is no update, got: %q", view)
+	}
+	if !strings.Contains(view, style.FormatVulnInfo(direct[0].VulnCurrent)) {
+		t.Fatalf("expected the current vulnerabilities on the row, got: %q", view)
+	}
+}
+
+func TestView_ShowsVulnerabilityTransition(t *testing.T) {
+	direct := []scanner.Module{{
+		Path:        "gopkg.in/yaml.v3",
+		Version:     "v3.0.0",
+		Update:      &scanner.UpdateInfo{Version: "v3.0.1"},
+		VulnCurrent: scanner.VulnInfo{High: 1, Total: 1},
+	}}
+
+	want := style.FormatVulnTransition(direct[0].VulnCurrent, direct[0].VulnUpdate)
+	if want == "" {
+		t.Fatalf("expected a non-empty vuln string")
+	}
+
+	m := initialModel(direct, nil, nil, Options{ShowVulnerabilities: true})
+	if !strings.Contains(m.View(), want) {
+		t.Fatalf("expected row to contain %q, got: %q", want, m.View())
+	}
+
+	m = initialModel(direct, nil, nil, Options{ShowVulnerabilities: true, MinSeverity: style.SeverityCritical})
+	if strings.Contains(m.View(), want) {
+		t.Fatalf("expected the high finding to be hidden below MinSeverity, got: %q", m.View())
+	}
+
+	m = initialModel(direct, nil, nil, Options{})
+	if strings.Contains(m.View(), "fixes 1") {
+		t.Fatalf("expected no vuln string without the option")
+	}
+}
+
+func TestSelectFixes(t *testing.T) {
+	direct := []scanner.Module{
+		{Name: "fixed", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.0.1"},
+			VulnCurrent: scanner.VulnInfo{High: 1, Total: 1}},
+		{Name: "partial", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "2.0.0"},
+			VulnCurrent: scanner.VulnInfo{High: 1, Low: 1, Total: 2}, VulnUpdate: scanner.VulnInfo{Low: 1, Total: 1}},
+		{Name: "unchanged", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.1.0"},
+			VulnCurrent: scanner.VulnInfo{Low: 1, Total: 1}, VulnUpdate: scanner.VulnInfo{Low: 1, Total: 1}},
+		{Name: "clean", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.0.2"}},
+	}
+	transitive := []scanner.Module{
+		{Name: "deep", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.0.3"},
+			VulnCurrent: scanner.VulnInfo{Critical: 1, Total: 1}},
+	}
+	m := initialModel(direct, nil, transitive, Options{ShowVulnerabilities: true})
+
+	m = pressKeys(t, m, typeRunes("f"))
+	var got []string
+	for _, c := range m.all {
+		if _, ok := m.selected[moduleKey(c)]; ok {
+			got = append(got, c.Name)
+		}
+	}
+	if want := []string{"fixed", "partial", "deep"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %v selected, got %v", want, got)
+	}
+
+	// The key does nothing without vulnerability data
+	m = initialModel(direct, nil, transitive, Options{})
+	m = pressKeys(t, m, typeRunes("f"))
+	if len(m.selected) != 0 {
+		t.Fatalf("expected no selections without ShowVulnerabilities, got %d", len(m.selected))
+	}
+}
+
+func TestSortBySeverity_KeepsSectionsAndCursor(t *testing.T) {
+	direct := []scanner.Module{
+		{Name: "low", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.0.1"},
+			VulnCurrent: scanner.VulnInfo{Low: 2, Total: 2}},
+		{Name: "clean", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.0.1"}},
+		{Name: "critical", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.0.1"},
+			VulnCurrent: scanner.VulnInfo{Critical: 1, Total: 1}},
+	}
+	indirect := []scanner.Module{
+		{Name: "high", Version: "1.0.0", Update: &scanner.UpdateInfo{Version: "1.0.1"},
+			VulnCurrent: scanner.VulnInfo{High: 1, Total: 1}},
+	}
+	m := initialModel(direct, indirect, nil, Options{ShowVulnerabilities: true})
+
+	m = pressKeys(t, m, typeRunes("s"))
+	var got []string
+	for _, c := range m.choices {
+		got = append(got, c.Name)
+	}
+	if want := []string{"critical", "low", "clean", "high"}; !slices.Equal(got, want) {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+	if m.directEnd != 3 || m.allDirectEnd != 3 {
+		t.Fatalf("expected sections to keep their boundaries, got directEnd=%d", m.directEnd)
+	}
+	if m.choices[m.cursor].Name != "low" {
+		t.Fatalf("expected cursor to stay on %q, got %q", "low", m.choices[m.cursor].Name)
+	}
+}
+
 func TestInit_ReturnsNil(t *testing.T) {
 	m := initialModel(nil, nil, nil, Options{})
 	if cmd := m.Init(); cmd != nil {
@@ -73,6 +436,28 @@ func TestInitialModel_SortsWhenFormatGroup(t *testing.T) {
 	}
 }
 
+func TestInitialModel_GroupsByScope(t *testing.T) {
+	direct := []scanner.Module{
+		{Path: "github.com/aws/smithy-go", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.0.1"}},
+		{Path: "github.com/spf13/cobra", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}},
+		{Path: "github.com/aws/aws-sdk-go", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v2.0.0"}},
+	}
+	m := initialModel(direct, nil, nil, Options{FormatGroup: true, Grouping: format.Grouping{By: format.GroupByScope}})
+
+	var got []string
+	for _, c := range m.choices {
+		got = append(got, c.Path)
+	}
+	want := []string{"github.com/aws/aws-sdk-go", "github.com/aws/smithy-go", "github.com/spf13/cobra"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("choices = %v, want %v", got, want)
+	}
+	// Headings are the only place the bare scope appears
+	if view := m.View(); len(regexp.MustCompile(`github\.com/aws[^/]`).FindAllString(view, -1)) != 1 {
+		t.Errorf("expected a single github.com/aws heading, got %q", view)
+	}
+}
+
 func TestViewContainsHeadings(t *testing.T) {
 	direct := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}}}
 	m := initialModel(direct, nil, nil, Options{DirectLabel: "Direct dependencies (go.mod)"})
@@ -94,7 +479,7 @@ func TestStartInteractiveGroupedWithOptions_AppliesSelection(t *testing.T) {
 	mock := &mockUpdater{}
 	direct := []scanner.Module{{Path: "a", Version: "v1.0.0", Update: &scanner.UpdateInfo{Version: "v1.1.0"}}}
 	base := initialModel(direct, nil, nil, Options{Updater: mock})
-	base.selected[0] = struct{}{}
+	base.selected[moduleKey(direct[0])] = struct{}{}
 
 	runProgram = func(tea.Model) (tea.Model, error) {
 		return base, nil
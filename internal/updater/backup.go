@@ -0,0 +1,105 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BackupSuffix is appended to a file's name to form its backup.
+const BackupSuffix = ".faro.bak"
+
+// Backups is embedded by updaters to back up the files they modify. The zero
+// value takes no backups; enable them with SetBackups.
+type Backups struct {
+	enabled bool
+}
+
+// SetBackups enables or disables the backups taken by Backup.
+func (b *Backups) SetBackups(enabled bool) {
+	b.enabled = enabled
+}
+
+// Backup copies files, relative to dir, with the package-level Backup if
+// backups are enabled, and does nothing otherwise.
+func (b *Backups) Backup(dir string, files []string) error {
+	if !b.enabled {
+		return nil
+	}
+	return Backup(dir, files)
+}
+
+// Backup copies each of files, relative to dir, to <file>.faro.bak before an
+// updater modifies it, replacing older backups. Files that don't exist are
+// skipped.
+func Backup(dir string, files []string) error {
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to back up %s: %w", file, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to back up %s: %w", file, err)
+		}
+		if err := os.WriteFile(path+BackupSuffix, data, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// restoreSkippedDirs hold installed dependencies or VCS data, never backups
+// of the project's own files.
+var restoreSkippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	".venv":        true,
+	".git":         true,
+}
+
+// RestoreBackups moves every <file>.faro.bak in dir or its subdirectories,
+// such as the manifests of nested workspaces, back over the original file
+// and returns the restored paths relative to dir.
+func RestoreBackups(dir string) ([]string, error) {
+	var found []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && restoreSkippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), BackupSuffix) {
+			found = append(found, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var restored []string
+	for _, backup := range found {
+		path := strings.TrimSuffix(backup, BackupSuffix)
+		if err := os.Rename(backup, path); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		restored = append(restored, rel)
+	}
+	return restored, nil
+}
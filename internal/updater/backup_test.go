@@ -0,0 +1,116 @@
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBackups_DisabledByDefault(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var b Backups
+	if err := b.Backup(dir, []string{"go.mod"}); err != nil {
+		t.Fatalf("Backup() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod"+BackupSuffix)); !os.IsNotExist(err) {
+		t.Fatalf("expected no backup without SetBackups, got %v", err)
+	}
+
+	b.SetBackups(true)
+	if err := b.Backup(dir, []string{"go.mod"}); err != nil {
+		t.Fatalf("Backup() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod"+BackupSuffix)); err != nil {
+		t.Fatalf("expected a backup after SetBackups: %v", err)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                    "module a\n\nrequire b v1.0.0\n",
+		"go.sum":                    "b v1.0.0 h1:abc=\n",
+		"gradle/libs.versions.toml": "[versions]\nguava = \"31.1-jre\"\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Backup(dir, []string{"go.mod", "go.sum", "gradle/libs.versions.toml", "missing.lock"}); err != nil {
+		t.Fatalf("Backup() error: %v", err)
+	}
+	for name, contents := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name+BackupSuffix))
+		if err != nil {
+			t.Fatalf("expected a backup of %s: %v", name, err)
+		}
+		if string(data) != contents {
+			t.Fatalf("expected the backup of %s to match, got %q", name, data)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("changed\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restored, err := RestoreBackups(dir)
+	if err != nil {
+		t.Fatalf("RestoreBackups() error: %v", err)
+	}
+	slices.Sort(restored)
+	if want := []string{"go.mod", "go.sum", filepath.Join("gradle", "libs.versions.toml")}; !slices.Equal(restored, want) {
+		t.Fatalf("expected %v restored, got %v", want, restored)
+	}
+	for name, contents := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != contents {
+			t.Fatalf("expected %s to be restored, got %q", name, data)
+		}
+		if _, err := os.Stat(filepath.Join(dir, name+BackupSuffix)); !os.IsNotExist(err) {
+			t.Fatalf("expected the backup of %s to be consumed, got %v", name, err)
+		}
+	}
+}
+
+func TestRestoreBackups_NestedAndSkippedDirs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"packages/foo/bar/package.json" + BackupSuffix:      `{"name":"bar"}`,
+		"node_modules/left-pad/package.json" + BackupSuffix: `{"name":"left-pad"}`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restored, err := RestoreBackups(dir)
+	if err != nil {
+		t.Fatalf("RestoreBackups() error: %v", err)
+	}
+	if want := []string{filepath.Join("packages", "foo", "bar", "package.json")}; !slices.Equal(restored, want) {
+		t.Fatalf("expected %v restored, got %v", want, restored)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "packages", "foo", "bar", "package.json")); err != nil || string(data) != `{"name":"bar"}` {
+		t.Errorf("expected the nested manifest to be restored, got %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "node_modules", "left-pad", "package.json"+BackupSuffix)); err != nil {
+		t.Errorf("expected backups under node_modules to be left alone: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+// Package bundler provides RubyGems (Bundler) package manager update functionality.
+package bundler
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+// Updater implements updater.Updater for Bundler.
+type Updater struct {
+	updater.Backups
+
+	workDir      string
+	runBundleCmd func(args ...string) ([]byte, error)
+}
+
+// NewUpdater creates a new Bundler updater.
+func NewUpdater(workDir string) *Updater {
+	return &Updater{
+		workDir: workDir,
+		runBundleCmd: func(args ...string) ([]byte, error) {
+			cmd := exec.Command("bundle", args...)
+			cmd.Dir = workDir
+			return scanner.CombinedOutput(cmd)
+		},
+	}
+}
+
+// Files returns the Gemfile and its lockfile, which bundle update rewrites.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{"Gemfile", "Gemfile.lock"}
+}
+
+// UpdatePackages runs `bundle update --conservative` for the given gems, so
+// their shared dependencies are only updated when required.
+func (u *Updater) UpdatePackages(modules []scanner.Module) error {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgrading %d packages...\n", len(modules))
+
+	args := []string{"update"}
+	for _, m := range modules {
+		args = append(args, m.Name)
+	}
+	args = append(args, "--conservative")
+
+	if out, err := u.runBundleCmd(args...); err != nil {
+		return scanner.ToolError("bundle", fmt.Errorf("bundle update failed: %s: %w", string(out), err))
+	}
+
+	return nil
+}
+
+// UpdateSinglePackage updates a single gem.
+func (u *Updater) UpdateSinglePackage(module scanner.Module) error {
+	return u.UpdatePackages([]scanner.Module{module})
+}
@@ -0,0 +1,45 @@
+package bundler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestUpdatePackages(t *testing.T) {
+	var gotArgs []string
+	u := &Updater{
+		runBundleCmd: func(args ...string) ([]byte, error) {
+			gotArgs = args
+			return nil, nil
+		},
+	}
+
+	modules := []scanner.Module{
+		{Name: "rails", Update: &scanner.UpdateInfo{Version: "7.1.2"}},
+		{Name: "rspec", Update: &scanner.UpdateInfo{Version: "3.12.0"}},
+	}
+	if err := u.UpdatePackages(modules); err != nil {
+		t.Fatalf("UpdatePackages failed: %v", err)
+	}
+
+	want := "update rails rspec --conservative"
+	if got := strings.Join(gotArgs, " "); got != want {
+		t.Errorf("bundle args = %q, want %q", got, want)
+	}
+}
+
+func TestUpdatePackages_Fails(t *testing.T) {
+	u := &Updater{
+		runBundleCmd: func(...string) ([]byte, error) {
+			return []byte("Could not find gem 'nope'"), errors.New("exit status 7")
+		},
+	}
+
+	err := u.UpdatePackages([]scanner.Module{{Name: "nope"}})
+	if err == nil || !strings.Contains(err.Error(), "Could not find gem") {
+		t.Fatalf("expected bundle output in the error, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Package conda provides conda (environment.yml) package manager update functionality.
+package conda
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+// environmentFile is the conda environment file the updater rewrites.
+const environmentFile = "environment.yml"
+
+// Updater implements updater.Updater for conda by rewriting the version pins
+// in environment.yml. The environment itself is left for the user to
+// recreate or update with `conda env update`.
+type Updater struct {
+	updater.Backups
+
+	workDir string
+}
+
+// NewUpdater creates a new conda updater.
+func NewUpdater(workDir string) *Updater {
+	return &Updater{workDir: workDir}
+}
+
+// Files returns the environment file UpdatePackages rewrites.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{environmentFile}
+}
+
+// UpdatePackages pins multiple conda packages to their specified versions.
+func (u *Updater) UpdatePackages(modules []scanner.Module) error {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgrading %d packages...\n", len(modules))
+
+	versions := make(map[string]string, len(modules))
+	for _, m := range modules {
+		if m.Update != nil && m.Update.Version != "" {
+			versions[m.Name] = m.Update.Version
+		}
+	}
+
+	path := filepath.Join(u.workDir, environmentFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", environmentFile, err)
+	}
+	updated := make(map[string]bool)
+	out := rewriteEnvironment(string(data), versions, updated)
+	if out != string(data) {
+		if err := os.WriteFile(path, []byte(out), 0644); err != nil {
+			return fmt.Errorf("failed to update %s: %w", environmentFile, err)
+		}
+	}
+
+	var missing []string
+	for name := range versions {
+		if !updated[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("could not find a version pin for %s in %s", strings.Join(missing, ", "), environmentFile)
+	}
+	return nil
+}
+
+// UpdateSinglePackage pins a single conda package to its specified version.
+func (u *Updater) UpdateSinglePackage(module scanner.Module) error {
+	return u.UpdatePackages([]scanner.Module{module})
+}
+
+var (
+	// topLevelKey matches an unindented "key:" line.
+	topLevelKey = regexp.MustCompile(`^([\w-]+)\s*:`)
+
+	// listItem matches a "- item" line, capturing its indentation and item.
+	listItem = regexp.MustCompile(`^(\s*)-\s*["']?([^"'#]*)`)
+)
+
+// rewriteEnvironment replaces the pinned versions of the dependencies in
+// versions, recording the packages it updated. Pins keep their operator and
+// channel prefix; a build string after the version is dropped, since it
+// belongs to the old version. Entries under the pip: subsection are left
+// alone.
+func rewriteEnvironment(contents string, versions map[string]string, updated map[string]bool) string {
+	pins := make(map[string]*regexp.Regexp, len(versions))
+	for name := range versions {
+		pins[name] = regexp.MustCompile(`^(\s*-\s*["']?(?:[\w./:-]+::)?` + regexp.QuoteMeta(name) +
+			`\s*(?:==|=|\s)\s*)[\w.+!]+(?:=[^\s"'#]*|\s+[^\s"'#]+)?(["'\s]|$)`)
+	}
+
+	lines := strings.Split(contents, "\n")
+	var section string
+	pipIndent := -1 // indentation of the "- pip:" item while inside it
+	for i, line := range lines {
+		if m := topLevelKey.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			pipIndent = -1
+			continue
+		}
+		m := listItem.FindStringSubmatch(line)
+		if m == nil || section != "dependencies" {
+			continue
+		}
+		indent, item := len(m[1]), strings.TrimSpace(m[2])
+		if pipIndent >= 0 {
+			if indent > pipIndent {
+				continue
+			}
+			pipIndent = -1
+		}
+		if strings.HasSuffix(item, ":") {
+			pipIndent = indent
+			continue
+		}
+
+		for name, re := range pins {
+			if re.MatchString(line) {
+				lines[i] = re.ReplaceAllString(line, "${1}"+versions[name]+"${2}")
+				updated[name] = true
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package conda
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestUpdatePackages(t *testing.T) {
+	dir := t.TempDir()
+	env := `name: analysis
+channels:
+  - conda-forge
+dependencies:
+  - python=3.11
+  - numpy=1.26.0=py311h64a7726_0
+  - "pandas==2.1.0"
+  - bioconda::samtools=1.17
+  - libblas 3.9.0 20_linux64_openblas
+  - numpy-base=1.26.0
+  - jupyterlab=4.0.5 # notebooks
+  - pip:
+    - numpy==1.26.0
+`
+	path := filepath.Join(dir, "environment.yml")
+	if err := os.WriteFile(path, []byte(env), 0644); err != nil {
+		t.Fatalf("failed to write environment.yml: %v", err)
+	}
+
+	modules := []scanner.Module{
+		{Name: "python", Update: &scanner.UpdateInfo{Version: "3.12.1"}},
+		{Name: "numpy", Update: &scanner.UpdateInfo{Version: "1.26.4"}},
+		{Name: "pandas", Update: &scanner.UpdateInfo{Version: "2.2.0"}},
+		{Name: "samtools", Update: &scanner.UpdateInfo{Version: "1.18"}},
+		{Name: "libblas", Update: &scanner.UpdateInfo{Version: "3.9.1"}},
+		{Name: "jupyterlab", Update: &scanner.UpdateInfo{Version: "4.0.10"}},
+	}
+	if err := NewUpdater(dir).UpdatePackages(modules); err != nil {
+		t.Fatalf("UpdatePackages failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `name: analysis
+channels:
+  - conda-forge
+dependencies:
+  - python=3.12.1
+  - numpy=1.26.4
+  - "pandas==2.2.0"
+  - bioconda::samtools=1.18
+  - libblas 3.9.1
+  - numpy-base=1.26.0
+  - jupyterlab=4.0.10 # notebooks
+  - pip:
+    - numpy==1.26.0
+`
+	if string(data) != want {
+		t.Errorf("environment.yml =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestUpdatePackages_PinNotFound(t *testing.T) {
+	dir := t.TempDir()
+	env := "dependencies:\n  - scipy>=1.10\n"
+	if err := os.WriteFile(filepath.Join(dir, "environment.yml"), []byte(env), 0644); err != nil {
+		t.Fatalf("failed to write environment.yml: %v", err)
+	}
+
+	err := NewUpdater(dir).UpdateSinglePackage(scanner.Module{Name: "scipy", Update: &scanner.UpdateInfo{Version: "1.12.0"}})
+	if err == nil || !strings.Contains(err.Error(), "could not find a version pin for scipy") {
+		t.Errorf("expected a missing pin error, got %v", err)
+	}
+}
@@ -3,13 +3,24 @@ package gomod
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 
+	"github.com/pragmaticivan/faro/internal/gomod"
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
 )
 
 // Updater implements updater.Updater for Go modules.
 type Updater struct {
+	updater.Backups
+
+	// VerifyChecksums runs `go mod verify` after updating, failing if a
+	// downloaded module doesn't match go.sum
+	VerifyChecksums bool
+
 	workDir string
 	runCmd  func(name string, args ...string) ([]byte, error)
 }
@@ -21,27 +32,70 @@ func NewUpdater(workDir string) *Updater {
 		runCmd: func(name string, args ...string) ([]byte, error) {
 			cmd := exec.Command(name, args...)
 			cmd.Dir = workDir
-			return cmd.CombinedOutput()
+			return scanner.CombinedOutput(cmd)
 		},
 	}
 }
 
+// Files returns go.mod and go.sum, which go get rewrites.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{"go.mod", "go.sum"}
+}
+
 // UpdatePackages updates multiple Go modules to their specified versions.
 func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	if len(modules) == 0 {
 		return nil
 	}
 
-	fmt.Printf("Upgrading %d packages...\n", len(modules))
+	return u.goGet(u.buildGoGetArgs(modules), len(modules))
+}
+
+// UpdatePatch upgrades modules with `go get -u=patch`, letting Go pick the
+// newest patch release of each one's current minor version rather than
+// pinning the versions the scanner found.
+func (u *Updater) UpdatePatch(modules []scanner.Module) error {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	args := []string{"get", "-u=patch"}
+	for _, m := range modules {
+		path := m.Name
+		if path == "" {
+			path = m.Path // Fallback for legacy compatibility
+		}
+		args = append(args, path)
+	}
+	return u.goGet(args, len(modules))
+}
+
+// goGet runs go with the given go get args for n packages, then tidies and,
+// if enabled, verifies the module cache.
+func (u *Updater) goGet(args []string, n int) error {
+	if err := u.Backup(u.workDir, u.Files(nil)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgrading %d packages...\n", n)
 
-	args := u.buildGoGetArgs(modules)
+	if u.shouldPinToolchain() {
+		args = append(args, "toolchain@none")
+	}
 	if out, err := u.runCmd("go", args...); err != nil {
-		return fmt.Errorf("go get failed: %s: %w", string(out), err)
+		return scanner.ToolError("go", fmt.Errorf("go get failed: %s: %w", string(out), err))
 	}
 
 	// Tidy up
 	if out, err := u.runCmd("go", "mod", "tidy"); err != nil {
-		return fmt.Errorf("go mod tidy failed: %s: %w", string(out), err)
+		return scanner.ToolError("go", fmt.Errorf("go mod tidy failed: %s: %w", string(out), err))
+	}
+
+	// Catch tampered or corrupted modules in the cache before they're used
+	if u.VerifyChecksums {
+		if out, err := u.runCmd("go", "mod", "verify"); err != nil {
+			return scanner.ToolError("go", fmt.Errorf("go mod verify failed: %s: %w", strings.TrimSpace(string(out)), err))
+		}
 	}
 
 	return nil
@@ -52,6 +106,22 @@ func (u *Updater) UpdateSinglePackage(module scanner.Module) error {
 	return u.UpdatePackages([]scanner.Module{module})
 }
 
+// shouldPinToolchain reports whether `toolchain@none` should be passed to
+// `go get` so that raising the go directive does not also add a toolchain
+// line. It applies to go.mod files at go 1.21+ without a toolchain directive.
+func (u *Updater) shouldPinToolchain() bool {
+	data, err := os.ReadFile(filepath.Join(u.workDir, "go.mod"))
+	if err != nil {
+		return false
+	}
+	contents := string(data)
+	goVersion := gomod.ParseGoDirective(contents)
+	if goVersion == "" || gomod.CompareGoVersions(goVersion, "1.21") < 0 {
+		return false
+	}
+	return gomod.ParseToolchainDirective(contents) == ""
+}
+
 // buildGoGetArgs constructs the arguments for `go get`.
 func (u *Updater) buildGoGetArgs(modules []scanner.Module) []string {
 	args := []string{"get"}
@@ -2,6 +2,9 @@ package gomod
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
@@ -140,6 +143,43 @@ func TestUpdatePackages_GoModTidyFails(t *testing.T) {
 	}
 }
 
+func TestUpdatePackages_VerifyChecksums(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "github.com/pkg/errors", Update: &scanner.UpdateInfo{Version: "0.9.1"}},
+	}
+
+	var commands []string
+	u := &Updater{
+		workDir: "/test/dir",
+		runCmd: func(name string, args ...string) ([]byte, error) {
+			commands = append(commands, strings.Join(args, " "))
+			if args[0] == "mod" && args[1] == "verify" {
+				return []byte("github.com/pkg/errors v0.9.1: dir has been modified (/go/pkg/mod/github.com/pkg/errors@v0.9.1)\n"), errors.New("exit status 1")
+			}
+			return nil, nil
+		},
+	}
+
+	// Verification is off by default
+	if err := u.UpdatePackages(modules); err != nil {
+		t.Fatalf("UpdatePackages failed: %v", err)
+	}
+	if strings.Contains(strings.Join(commands, ";"), "mod verify") {
+		t.Errorf("expected no go mod verify by default, got %v", commands)
+	}
+
+	u.VerifyChecksums = true
+
+	commands = nil
+	err := u.UpdatePackages(modules)
+	if err == nil || !strings.Contains(err.Error(), "go mod verify failed") || !strings.Contains(err.Error(), "dir has been modified") {
+		t.Fatalf("expected the verify failure to be propagated, got %v", err)
+	}
+	if want := "get github.com/pkg/errors@0.9.1;mod tidy;mod verify"; strings.Join(commands, ";") != want {
+		t.Errorf("commands = %v, want %s", commands, want)
+	}
+}
+
 func TestUpdateSinglePackage(t *testing.T) {
 	module := scanner.Module{
 		Name:   "github.com/pkg/errors",
@@ -228,3 +268,76 @@ func TestBuildGoGetArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatePackages_PinsToolchain(t *testing.T) {
+	tests := []struct {
+		name  string
+		goMod string
+		want  bool
+	}{
+		{"go 1.21 without toolchain", "module test\n\ngo 1.21\n", true},
+		{"existing toolchain", "module test\n\ngo 1.21\n\ntoolchain go1.22.0\n", false},
+		{"pre-1.21 go directive", "module test\n\ngo 1.20\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(tt.goMod), 0644); err != nil {
+				t.Fatalf("failed to write go.mod: %v", err)
+			}
+
+			var goGet string
+			updater := &Updater{
+				workDir: tmpDir,
+				runCmd: func(name string, args ...string) ([]byte, error) {
+					if args[0] == "get" {
+						goGet = strings.Join(args, " ")
+					}
+					return nil, nil
+				},
+			}
+
+			modules := []scanner.Module{{Name: "example.com/pkg", Update: &scanner.UpdateInfo{Version: "v1.1.0"}}}
+			if err := updater.UpdatePackages(modules); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.HasSuffix(goGet, " toolchain@none"); got != tt.want {
+				t.Errorf("toolchain@none in %q = %v, want %v", goGet, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdatePatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module test\n\ngo 1.20\n"), 0644); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+
+	var calls []string
+	updater := &Updater{
+		workDir: tmpDir,
+		runCmd: func(name string, args ...string) ([]byte, error) {
+			calls = append(calls, name+" "+strings.Join(args, " "))
+			return nil, nil
+		},
+	}
+
+	modules := []scanner.Module{
+		{Name: "github.com/pkg/errors", Version: "v0.9.0", Update: &scanner.UpdateInfo{Version: "v0.9.1"}},
+		{Path: "github.com/stretchr/testify", Version: "v1.8.0", Update: &scanner.UpdateInfo{Version: "v1.8.4"}},
+	}
+	if err := updater.UpdatePatch(modules); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Go resolves the patch releases itself, so no versions are pinned
+	want := []string{
+		"go get -u=patch github.com/pkg/errors github.com/stretchr/testify",
+		"go mod tidy",
+	}
+	if !slices.Equal(calls, want) {
+		t.Errorf("got commands %q, want %q", calls, want)
+	}
+}
@@ -0,0 +1,218 @@
+// Package gradle provides Gradle package manager update functionality.
+package gradle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+// Updater implements updater.Updater for Gradle by rewriting version literals
+// in the version catalog and the root build files.
+type Updater struct {
+	updater.Backups
+
+	workDir string
+}
+
+// NewUpdater creates a new Gradle updater.
+func NewUpdater(workDir string) *Updater {
+	return &Updater{workDir: workDir}
+}
+
+// Files returns the version catalog and root build files UpdatePackages
+// rewrites.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{filepath.Join("gradle", "libs.versions.toml"), "build.gradle", "build.gradle.kts"}
+}
+
+// UpdatePackages updates multiple Gradle dependencies, identified by their
+// group:name coordinates, to their specified versions.
+func (u *Updater) UpdatePackages(modules []scanner.Module) error {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgrading %d packages...\n", len(modules))
+
+	versions := make(map[string]string, len(modules))
+	for _, m := range modules {
+		if m.Update != nil && m.Update.Version != "" {
+			versions[m.Name] = m.Update.Version
+		}
+	}
+
+	updated := make(map[string]bool)
+	catalogPath := filepath.Join(u.workDir, "gradle", "libs.versions.toml")
+	if err := rewriteFile(catalogPath, func(s string) string { return rewriteCatalog(s, versions, updated) }); err != nil {
+		return fmt.Errorf("failed to update %s: %w", catalogPath, err)
+	}
+	for _, file := range []string{"build.gradle", "build.gradle.kts"} {
+		path := filepath.Join(u.workDir, file)
+		if err := rewriteFile(path, func(s string) string { return rewriteBuildFile(s, versions, updated) }); err != nil {
+			return fmt.Errorf("failed to update %s: %w", file, err)
+		}
+	}
+
+	var missing []string
+	for name := range versions {
+		if !updated[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("could not find a version literal for %s in the build files or gradle/libs.versions.toml", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// UpdateSinglePackage updates a single Gradle dependency to its specified version.
+func (u *Updater) UpdateSinglePackage(module scanner.Module) error {
+	return u.UpdatePackages([]scanner.Module{module})
+}
+
+// rewriteFile applies rewrite to the contents of path, if it exists.
+func rewriteFile(path string, rewrite func(string) string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	out := rewrite(string(data))
+	if out == string(data) {
+		return nil
+	}
+	return os.WriteFile(path, []byte(out), 0644)
+}
+
+// versionLiteral matches a plain version; interpolated ones like $guavaVersion
+// are left alone.
+const versionLiteral = `[^"'$\s]+`
+
+// rewriteBuildFile updates "group:name:version" strings and map notation
+// (group: 'g', name: 'n', version: 'v') declarations, recording the modules
+// it updated.
+func rewriteBuildFile(contents string, versions map[string]string, updated map[string]bool) string {
+	for name, version := range versions {
+		group, artifact, _ := strings.Cut(name, ":")
+		patterns := []*regexp.Regexp{
+			regexp.MustCompile(`(["']` + regexp.QuoteMeta(name) + `:)` + versionLiteral + `(["'])`),
+			regexp.MustCompile(`(group\s*[:=]\s*["']` + regexp.QuoteMeta(group) + `["']\s*,\s*name\s*[:=]\s*["']` +
+				regexp.QuoteMeta(artifact) + `["']\s*,\s*version\s*[:=]\s*["'])` + versionLiteral + `(["'])`),
+		}
+		for _, re := range patterns {
+			if re.MatchString(contents) {
+				contents = re.ReplaceAllString(contents, "${1}"+version+"${2}")
+				updated[name] = true
+			}
+		}
+	}
+	return contents
+}
+
+var (
+	// catalogEntry matches `alias = ...` lines of a version catalog.
+	catalogEntry = regexp.MustCompile(`^\s*([\w.-]+)\s*=\s*(.*)$`)
+
+	// tableField matches `key = "value"` inside an inline table.
+	tableField = regexp.MustCompile(`([\w.]+)\s*=\s*["']([^"']*)["']`)
+)
+
+// rewriteCatalog updates [libraries] entries of a version catalog, following
+// version.ref into [versions], and records the modules it updated.
+func rewriteCatalog(contents string, versions map[string]string, updated map[string]bool) string {
+	lines := strings.Split(contents, "\n")
+	refs := make(map[string]string) // [versions] key -> new version
+	refModules := make(map[string][]string)
+
+	var section string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			section = strings.Trim(trimmed, "[] ")
+			continue
+		}
+		if section != "libraries" {
+			continue
+		}
+		m := catalogEntry.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := m[2]
+
+		// String notation: alias = "group:name:version"
+		for name, version := range versions {
+			re := regexp.MustCompile(`(["']` + regexp.QuoteMeta(name) + `:)` + versionLiteral + `(["'])`)
+			if re.MatchString(value) {
+				lines[i] = re.ReplaceAllString(lines[i], "${1}"+version+"${2}")
+				updated[name] = true
+			}
+		}
+
+		// Table notation: alias = { module = "g:n", version = "v" } or version.ref
+		fields := make(map[string]string)
+		for _, f := range tableField.FindAllStringSubmatch(value, -1) {
+			fields[f[1]] = f[2]
+		}
+		module := fields["module"]
+		if module == "" && fields["group"] != "" && fields["name"] != "" {
+			module = fields["group"] + ":" + fields["name"]
+		}
+		version, ok := versions[module]
+		if !ok {
+			continue
+		}
+		switch {
+		case fields["version.ref"] != "":
+			refs[fields["version.ref"]] = version
+			refModules[fields["version.ref"]] = append(refModules[fields["version.ref"]], module)
+		case fields["version"] != "":
+			re := regexp.MustCompile(`(\bversion\s*=\s*["'])[^"']*(["'])`)
+			lines[i] = re.ReplaceAllString(line, "${1}"+version+"${2}")
+			updated[module] = true
+		}
+	}
+
+	section = ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			section = strings.Trim(trimmed, "[] ")
+			continue
+		}
+		if section != "versions" {
+			continue
+		}
+		m := catalogEntry.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		version, ok := refs[m[1]]
+		if !ok {
+			continue
+		}
+		re := regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(m[1]) + `\s*=\s*["'])[^"']*(["'])`)
+		if re.MatchString(line) {
+			lines[i] = re.ReplaceAllString(line, "${1}"+version+"${2}")
+			for _, module := range refModules[m[1]] {
+				updated[module] = true
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,125 @@
+package gradle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+func TestUpdatePackages(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"build.gradle": `dependencies {
+    implementation libs.guava
+    implementation 'com.squareup.okhttp3:okhttp:4.10.0'
+    testImplementation group: 'junit', name: 'junit', version: '4.13.2'
+    implementation "org.slf4j:slf4j-api:$slf4jVersion"
+}
+`,
+		"gradle/libs.versions.toml": `[versions]
+guava = "31.1-jre"
+
+[libraries]
+guava = { module = "com.google.guava:guava", version.ref = "guava" }
+junit-jupiter = { group = "org.junit.jupiter", name = "junit-jupiter", version = "5.9.0" }
+commons-lang3 = "org.apache.commons:commons-lang3:3.12.0"
+`,
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	u := NewUpdater(dir)
+	err := u.UpdatePackages([]scanner.Module{
+		{Name: "com.google.guava:guava", Update: &scanner.UpdateInfo{Version: "33.0.0-jre"}},
+		{Name: "org.junit.jupiter:junit-jupiter", Update: &scanner.UpdateInfo{Version: "5.10.1"}},
+		{Name: "org.apache.commons:commons-lang3", Update: &scanner.UpdateInfo{Version: "3.14.0"}},
+		{Name: "com.squareup.okhttp3:okhttp", Update: &scanner.UpdateInfo{Version: "4.12.0"}},
+		{Name: "junit:junit", Update: &scanner.UpdateInfo{Version: "4.13.3"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePackages failed: %v", err)
+	}
+
+	build, _ := os.ReadFile(filepath.Join(dir, "build.gradle"))
+	wantBuild := `dependencies {
+    implementation libs.guava
+    implementation 'com.squareup.okhttp3:okhttp:4.12.0'
+    testImplementation group: 'junit', name: 'junit', version: '4.13.3'
+    implementation "org.slf4j:slf4j-api:$slf4jVersion"
+}
+`
+	if string(build) != wantBuild {
+		t.Errorf("unexpected build.gradle:\n%s", build)
+	}
+
+	catalog, _ := os.ReadFile(filepath.Join(dir, "gradle", "libs.versions.toml"))
+	wantCatalog := `[versions]
+guava = "33.0.0-jre"
+
+[libraries]
+guava = { module = "com.google.guava:guava", version.ref = "guava" }
+junit-jupiter = { group = "org.junit.jupiter", name = "junit-jupiter", version = "5.10.1" }
+commons-lang3 = "org.apache.commons:commons-lang3:3.14.0"
+`
+	if string(catalog) != wantCatalog {
+		t.Errorf("unexpected libs.versions.toml:\n%s", catalog)
+	}
+}
+
+func TestUpdatePackages_VersionNotFound(t *testing.T) {
+	dir := t.TempDir()
+	build := "dependencies {\n    implementation \"org.slf4j:slf4j-api:$slf4jVersion\"\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "build.gradle"), []byte(build), 0644); err != nil {
+		t.Fatalf("failed to write build.gradle: %v", err)
+	}
+
+	err := NewUpdater(dir).UpdatePackages([]scanner.Module{
+		{Name: "org.slf4j:slf4j-api", Update: &scanner.UpdateInfo{Version: "2.0.9"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "org.slf4j:slf4j-api") {
+		t.Fatalf("expected an error naming the unresolved dependency, got %v", err)
+	}
+}
+
+func TestUpdatePackages_WritesBackups(t *testing.T) {
+	dir := t.TempDir()
+	original := "dependencies {\n    implementation 'com.squareup.okhttp3:okhttp:4.10.0'\n}\n"
+	buildPath := filepath.Join(dir, "build.gradle")
+	if err := os.WriteFile(buildPath, []byte(original), 0644); err != nil {
+		t.Fatalf("failed to write build.gradle: %v", err)
+	}
+
+	u := NewUpdater(dir)
+	u.SetBackups(true)
+	err := u.UpdatePackages([]scanner.Module{
+		{Name: "com.squareup.okhttp3:okhttp", Update: &scanner.UpdateInfo{Version: "4.12.0"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePackages failed: %v", err)
+	}
+
+	backup, err := os.ReadFile(buildPath + updater.BackupSuffix)
+	if err != nil {
+		t.Fatalf("expected a backup of build.gradle: %v", err)
+	}
+	if string(backup) != original {
+		t.Fatalf("expected the backup to hold the pre-update content, got:\n%s", backup)
+	}
+	if build, _ := os.ReadFile(buildPath); !strings.Contains(string(build), "4.12.0") {
+		t.Fatalf("expected build.gradle to be updated, got:\n%s", build)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "build.gradle.kts"+updater.BackupSuffix)); !os.IsNotExist(err) {
+		t.Fatalf("expected no backup for a missing file, got %v", err)
+	}
+}
@@ -12,3 +12,35 @@ type Updater interface {
 	// UpdateSinglePackage updates a single package to its specified version.
 	UpdateSinglePackage(module scanner.Module) error
 }
+
+// InRangeUpdater is implemented by updaters that can move packages to the
+// newest version their declared range allows, changing only the lockfile.
+type InRangeUpdater interface {
+	// UpdateInRange updates modules within their declared ranges, leaving
+	// the manifest untouched. It returns an error if any update fails.
+	UpdateInRange(modules []scanner.Module) error
+}
+
+// PatchUpdater is implemented by updaters that can let the package manager
+// resolve patch-level upgrades itself, as `go get -u=patch` does, instead of
+// pinning the versions the scanner found.
+type PatchUpdater interface {
+	// UpdatePatch moves modules to the newest patch release of their
+	// current minor version. It returns an error if any update fails.
+	UpdatePatch(modules []scanner.Module) error
+}
+
+// FileReporter is implemented by updaters that can list the project files
+// they may modify, so callers can snapshot them before updating.
+type FileReporter interface {
+	// Files returns the paths, relative to the project directory, of the
+	// manifests and lockfiles UpdatePackages may rewrite when updating
+	// modules, e.g. the package.json of the workspaces that declare them.
+	Files(modules []scanner.Module) []string
+}
+
+// BackupUpdater is implemented by updaters that can copy the files they
+// modify to <file>.faro.bak first, usually by embedding Backups.
+type BackupUpdater interface {
+	SetBackups(enabled bool)
+}
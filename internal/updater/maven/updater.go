@@ -0,0 +1,72 @@
+// Package maven provides Maven package manager update functionality.
+package maven
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+// Updater implements updater.Updater for Maven.
+type Updater struct {
+	updater.Backups
+
+	workDir     string
+	runMavenCmd func(args ...string) ([]byte, error)
+}
+
+// NewUpdater creates a new Maven updater.
+func NewUpdater(workDir string) *Updater {
+	return &Updater{
+		workDir: workDir,
+		runMavenCmd: func(args ...string) ([]byte, error) {
+			cmd := exec.Command("mvn", args...)
+			cmd.Dir = workDir
+			return scanner.CombinedOutput(cmd)
+		},
+	}
+}
+
+// Files returns pom.xml, the only file UpdatePackages rewrites.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{"pom.xml"}
+}
+
+// UpdatePackages rewrites pom.xml to use the latest releases of the given
+// dependencies, identified by their groupId:artifactId names.
+func (u *Updater) UpdatePackages(modules []scanner.Module) error {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgrading %d packages...\n", len(modules))
+
+	includes := make([]string, 0, len(modules))
+	for _, m := range modules {
+		includes = append(includes, m.Name)
+	}
+
+	args := []string{
+		"-B",
+		"versions:use-latest-releases",
+		"-Dincludes=" + strings.Join(includes, ","),
+		"-DgenerateBackupPoms=false",
+	}
+	if out, err := u.runMavenCmd(args...); err != nil {
+		return scanner.ToolError("mvn", fmt.Errorf("mvn versions:use-latest-releases failed: %s: %w", string(out), err))
+	}
+
+	return nil
+}
+
+// UpdateSinglePackage updates a single Maven dependency to its latest release.
+func (u *Updater) UpdateSinglePackage(module scanner.Module) error {
+	return u.UpdatePackages([]scanner.Module{module})
+}
@@ -0,0 +1,45 @@
+package maven
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pragmaticivan/faro/internal/scanner"
+)
+
+func TestUpdatePackages(t *testing.T) {
+	var gotArgs []string
+	u := &Updater{
+		runMavenCmd: func(args ...string) ([]byte, error) {
+			gotArgs = args
+			return nil, nil
+		},
+	}
+
+	modules := []scanner.Module{
+		{Name: "com.google.guava:guava", Update: &scanner.UpdateInfo{Version: "33.0.0-jre"}},
+		{Name: "org.junit.jupiter:junit-jupiter", Update: &scanner.UpdateInfo{Version: "5.10.1"}},
+	}
+	if err := u.UpdatePackages(modules); err != nil {
+		t.Fatalf("UpdatePackages failed: %v", err)
+	}
+
+	want := "-B versions:use-latest-releases -Dincludes=com.google.guava:guava,org.junit.jupiter:junit-jupiter -DgenerateBackupPoms=false"
+	if got := strings.Join(gotArgs, " "); got != want {
+		t.Errorf("mvn args = %q, want %q", got, want)
+	}
+}
+
+func TestUpdatePackages_Fails(t *testing.T) {
+	u := &Updater{
+		runMavenCmd: func(...string) ([]byte, error) {
+			return []byte("BUILD FAILURE"), errors.New("exit status 1")
+		},
+	}
+
+	err := u.UpdatePackages([]scanner.Module{{Name: "com.google.guava:guava"}})
+	if err == nil || !strings.Contains(err.Error(), "BUILD FAILURE") {
+		t.Fatalf("expected mvn output in the error, got %v", err)
+	}
+}
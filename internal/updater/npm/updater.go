@@ -7,12 +7,17 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+	"github.com/pragmaticivan/faro/internal/workspace"
 )
 
 // Updater implements updater.Updater for npm.
 type Updater struct {
+	updater.Backups
+
 	workDir string
 	runCmd  func(name string, args ...string) ([]byte, error)
 }
@@ -24,53 +29,150 @@ func NewUpdater(workDir string) *Updater {
 		runCmd: func(name string, args ...string) ([]byte, error) {
 			cmd := exec.Command(name, args...)
 			cmd.Dir = workDir
-			return cmd.CombinedOutput()
+			return scanner.CombinedOutput(cmd)
 		},
 	}
 }
 
+// Files returns the root manifest and lockfile npm install rewrites, and the
+// package.json of each workspace that declares one of modules.
+func (u *Updater) Files(modules []scanner.Module) []string {
+	return append([]string{"package.json", "package-lock.json"}, u.workspaceManifests(modules)...)
+}
+
+// workspaceManifests returns the package.json paths of the workspaces, from
+// the root package.json, that declare modules. Workspaces that can't be
+// resolved are left out.
+func (u *Updater) workspaceManifests(modules []scanner.Module) []string {
+	data, err := os.ReadFile(filepath.Join(u.workDir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	patterns, err := workspace.NpmPatterns(data)
+	if err != nil || len(patterns) == 0 {
+		return nil
+	}
+	members, err := workspace.Resolve(u.workDir, patterns)
+	if err != nil {
+		return nil
+	}
+	return workspace.Manifests(members, workspaceNames(modules))
+}
+
 // UpdatePackages updates multiple npm packages to their specified versions.
 func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	if len(modules) == 0 {
 		return nil
 	}
 
-	fmt.Printf("Upgrading %d packages...\n", len(modules))
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
 
-	// Group by dependency type
-	deps := make([]string, 0)
-	devDeps := make([]string, 0)
+	fmt.Printf("Upgrading %d packages...\n", len(modules))
 
-	for _, m := range modules {
-		pkgSpec := m.Name
-		if m.Update != nil && m.Update.Version != "" {
-			pkgSpec = fmt.Sprintf("%s@%s", m.Name, m.Update.Version)
+	// Group by workspace and dependency type
+	groups, order := groupInstalls(modules)
+	for _, g := range order {
+		flag := "--save"
+		if g.dev {
+			flag = "--save-dev"
+		}
+		args := []string{"install", flag}
+		if g.workspace != "" {
+			args = append(args, "-w", g.workspace)
 		}
+		args = append(args, groups[g]...)
 
-		if m.DependencyType == "devDependencies" {
-			devDeps = append(devDeps, pkgSpec)
-		} else {
-			deps = append(deps, pkgSpec)
+		if out, err := u.runCmd("npm", args...); err != nil {
+			if g.dev {
+				return scanner.ToolError("npm", fmt.Errorf("npm install --save-dev failed: %s: %w", string(out), err))
+			}
+			return scanner.ToolError("npm", fmt.Errorf("npm install failed: %s: %w", string(out), err))
 		}
 	}
 
-	// Install production dependencies
-	if len(deps) > 0 {
-		args := append([]string{"install", "--save"}, deps...)
+	return nil
+}
+
+// UpdateInRange runs `npm update` for modules, which installs the newest
+// version each package.json range allows (the wanted version) and only
+// updates package-lock.json.
+func (u *Updater) UpdateInRange(modules []scanner.Module) error {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updating %d packages within their ranges...\n", len(modules))
+
+	for _, ws := range workspaceNames(modules) {
+		args := []string{"update"}
+		if ws != "" {
+			args = append(args, "-w", ws)
+		}
+		for _, m := range modules {
+			if m.Workspace == ws {
+				args = append(args, m.Name)
+			}
+		}
+
 		if out, err := u.runCmd("npm", args...); err != nil {
-			return fmt.Errorf("npm install failed: %s: %w", string(out), err)
+			return scanner.ToolError("npm", fmt.Errorf("npm update failed: %s: %w", string(out), err))
 		}
 	}
+	return nil
+}
 
-	// Install dev dependencies
-	if len(devDeps) > 0 {
-		args := append([]string{"install", "--save-dev"}, devDeps...)
-		if out, err := u.runCmd("npm", args...); err != nil {
-			return fmt.Errorf("npm install --save-dev failed: %s: %w", string(out), err)
+// workspaceNames returns the distinct workspaces of modules, the root ("")
+// first and then by name.
+func workspaceNames(modules []scanner.Module) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range modules {
+		if !seen[m.Workspace] {
+			seen[m.Workspace] = true
+			names = append(names, m.Workspace)
 		}
 	}
+	sort.Strings(names)
+	return names
+}
 
-	return nil
+// installGroup identifies the packages installed by a single npm invocation.
+type installGroup struct {
+	workspace string
+	dev       bool
+}
+
+// groupInstalls buckets package specs by workspace and dependency type. The
+// root project comes first, then workspaces by name; production before dev.
+func groupInstalls(modules []scanner.Module) (map[installGroup][]string, []installGroup) {
+	groups := make(map[installGroup][]string)
+	var order []installGroup
+	for _, m := range modules {
+		pkgSpec := m.Name
+		if m.Update != nil && m.Update.Version != "" {
+			pkgSpec = fmt.Sprintf("%s@%s", m.Name, m.Update.Version)
+		}
+
+		g := installGroup{workspace: m.Workspace, dev: m.DependencyType == "devDependencies"}
+		if _, ok := groups[g]; !ok {
+			order = append(order, g)
+		}
+		groups[g] = append(groups[g], pkgSpec)
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].workspace != order[j].workspace {
+			return order[i].workspace < order[j].workspace
+		}
+		return !order[i].dev && order[j].dev
+	})
+	return groups, order
 }
 
 // UpdateSinglePackage updates a single npm package to its specified version.
@@ -127,7 +229,7 @@ func (u *Updater) UpdatePackageJSON(modules []scanner.Module) error {
 
 	// Run npm install to update lockfile
 	if out, err := u.runCmd("npm", "install"); err != nil {
-		return fmt.Errorf("npm install failed after updating package.json: %s: %w", string(out), err)
+		return scanner.ToolError("npm", fmt.Errorf("npm install failed after updating package.json: %s: %w", string(out), err))
 	}
 
 	return nil
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
 )
 
 func TestNewUpdater(t *testing.T) {
@@ -320,3 +322,127 @@ func TestUpdatePackageJSON_ReadError(t *testing.T) {
 		t.Errorf("expected error to contain 'failed to read package.json', got %v", err)
 	}
 }
+
+func TestUpdatePackages_Workspaces(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "lodash", DependencyType: "devDependencies", Workspace: "pkg-b", Update: &scanner.UpdateInfo{Version: "4.17.21"}},
+		{Name: "lodash", DependencyType: "dependencies", Workspace: "pkg-a", Update: &scanner.UpdateInfo{Version: "4.17.21"}},
+		{Name: "typescript", DependencyType: "devDependencies", Update: &scanner.UpdateInfo{Version: "5.4.0"}},
+	}
+
+	var capturedCommands []string
+	updater := &Updater{
+		workDir: "/test/dir",
+		runCmd: func(name string, args ...string) ([]byte, error) {
+			capturedCommands = append(capturedCommands, name+" "+strings.Join(args, " "))
+			return nil, nil
+		},
+	}
+
+	if err := updater.UpdatePackages(modules); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"npm install --save-dev typescript@5.4.0",
+		"npm install --save -w pkg-a lodash@4.17.21",
+		"npm install --save-dev -w pkg-b lodash@4.17.21",
+	}
+	if strings.Join(capturedCommands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected commands %q, got %q", expected, capturedCommands)
+	}
+}
+
+func TestFiles_Workspaces(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("package.json", `{"name":"root","workspaces":["packages/*"]}`)
+	write("packages/a/package.json", `{"name":"pkg-a"}`)
+	write("packages/b/package.json", `{"name":"pkg-b"}`)
+
+	files := NewUpdater(dir).Files([]scanner.Module{
+		{Name: "lodash", Workspace: "pkg-a", Update: &scanner.UpdateInfo{Version: "4.17.21"}},
+		{Name: "typescript", Update: &scanner.UpdateInfo{Version: "5.4.0"}},
+	})
+
+	want := []string{"package.json", "package-lock.json", filepath.Join("packages", "a", "package.json")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("Files() = %q, want %q", files, want)
+	}
+}
+
+func TestUpdatePackages_BackupAndRestoreWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	rootManifest := `{"name":"root","workspaces":["packages/*"]}`
+	wsManifest := `{"name":"pkg-a","dependencies":{"lodash":"^4.17.0"}}`
+	if err := os.MkdirAll(filepath.Join(dir, "packages", "a"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(rootManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wsPath := filepath.Join(dir, "packages", "a", "package.json")
+	if err := os.WriteFile(wsPath, []byte(wsManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u := NewUpdater(dir)
+	u.SetBackups(true)
+	u.runCmd = func(name string, args ...string) ([]byte, error) {
+		// npm install -w pkg-a rewrites the workspace manifest
+		return nil, os.WriteFile(wsPath, []byte(`{"name":"pkg-a","dependencies":{"lodash":"^4.17.21"}}`), 0644)
+	}
+	modules := []scanner.Module{
+		{Name: "lodash", DependencyType: "dependencies", Workspace: "pkg-a", Update: &scanner.UpdateInfo{Version: "4.17.21"}},
+	}
+	if err := u.UpdatePackages(modules); err != nil {
+		t.Fatalf("UpdatePackages() error: %v", err)
+	}
+
+	restored, err := updater.RestoreBackups(dir)
+	if err != nil {
+		t.Fatalf("RestoreBackups() error: %v", err)
+	}
+	want := filepath.Join("packages", "a", "package.json")
+	if !slices.Contains(restored, want) {
+		t.Errorf("expected %s to be restored, got %v", want, restored)
+	}
+	if data, _ := os.ReadFile(wsPath); string(data) != wsManifest {
+		t.Errorf("workspace manifest = %s, want the original", data)
+	}
+}
+
+func TestUpdateInRange(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "express", Version: "4.18.0", DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "4.18.2", Wanted: "4.18.2"}},
+		{Name: "react", Version: "18.2.0", Workspace: "web", DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "18.3.1", Wanted: "18.3.1"}},
+		{Name: "jest", Version: "29.0.0", DependencyType: "devDependencies", Update: &scanner.UpdateInfo{Version: "29.7.0", Wanted: "29.7.0"}},
+	}
+
+	var capturedCommands []string
+	updater := &Updater{
+		workDir: "/test/dir",
+		runCmd: func(name string, args ...string) ([]byte, error) {
+			capturedCommands = append(capturedCommands, name+" "+strings.Join(args, " "))
+			return []byte("success"), nil
+		},
+	}
+
+	if err := updater.UpdateInRange(modules); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// npm update picks the wanted versions itself and leaves package.json alone
+	expected := []string{"npm update express jest", "npm update -w web react"}
+	if strings.Join(capturedCommands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected commands %q, got %q", expected, capturedCommands)
+	}
+}
@@ -3,6 +3,7 @@ package pip
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,10 +11,13 @@ import (
 	"strings"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
 )
 
 // Updater implements updater.Updater for pip.
 type Updater struct {
+	updater.Backups
+
 	workDir string
 	runCmd  func(name string, args ...string) ([]byte, error)
 }
@@ -25,17 +29,27 @@ func NewUpdater(workDir string) *Updater {
 		runCmd: func(name string, args ...string) ([]byte, error) {
 			cmd := exec.Command(name, args...)
 			cmd.Dir = workDir
-			return cmd.CombinedOutput()
+			return scanner.CombinedOutput(cmd)
 		},
 	}
 }
 
+// Files returns the requirements files UpdatePackages may rewrite. Packages
+// installed into the environment are not covered.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{"requirements.txt", "requirements.in"}
+}
+
 // UpdatePackages updates multiple pip packages to their specified versions.
 func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	if len(modules) == 0 {
 		return nil
 	}
 
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
 	fmt.Printf("Upgrading %d packages...\n", len(modules))
 
 	// Install packages
@@ -46,10 +60,14 @@ func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 		}
 
 		if out, err := u.runCmd("pip", "install", pkgSpec); err != nil {
-			return fmt.Errorf("pip install %s failed: %s: %w", pkgSpec, string(out), err)
+			return scanner.ToolError("pip", fmt.Errorf("pip install %s failed: %s: %w", pkgSpec, string(out), err))
 		}
 	}
 
+	if u.usesPipTools() {
+		return u.updatePipTools(modules)
+	}
+
 	// Update requirements.txt
 	if err := u.updateRequirementsTxt(modules); err != nil {
 		return fmt.Errorf("failed to update requirements.txt: %w", err)
@@ -58,6 +76,94 @@ func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	return nil
 }
 
+// usesPipTools reports whether requirements.txt is compiled by pip-tools from
+// a requirements.in.
+func (u *Updater) usesPipTools() bool {
+	_, err := os.Stat(filepath.Join(u.workDir, "requirements.in"))
+	return err == nil
+}
+
+// updatePipTools raises the constraints in requirements.in and recompiles
+// requirements.txt with pip-compile. Without pip-compile on PATH the pins in
+// requirements.txt are updated directly instead.
+func (u *Updater) updatePipTools(modules []scanner.Module) error {
+	if err := u.updateRequirementsIn(modules); err != nil {
+		return fmt.Errorf("failed to update requirements.in: %w", err)
+	}
+
+	args := []string{"--quiet"}
+	for _, m := range modules {
+		if m.Update != nil && m.Update.Version != "" {
+			args = append(args, "--upgrade-package", fmt.Sprintf("%s==%s", m.Name, m.Update.Version))
+		}
+	}
+	args = append(args, "requirements.in")
+
+	out, err := u.runCmd("pip-compile", args...)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("pip-compile failed: %s: %w", string(out), err)
+	}
+
+	fmt.Println("pip-compile not found; updating requirements.txt pins directly")
+	if err := u.updateRequirementsTxt(modules); err != nil {
+		return fmt.Errorf("failed to update requirements.txt: %w", err)
+	}
+	return nil
+}
+
+// updateRequirementsIn raises the version constraints of the given packages
+// in requirements.in. Unconstrained requirements are left alone, and >= and
+// ~= constraints keep their operator so the file stays loose.
+func (u *Updater) updateRequirementsIn(modules []scanner.Module) error {
+	inPath := filepath.Join(u.workDir, "requirements.in")
+	data, err := os.ReadFile(inPath)
+	if err != nil {
+		return err
+	}
+
+	updateMap := make(map[string]string)
+	for _, m := range modules {
+		if m.Update != nil {
+			updateMap[strings.ToLower(m.Name)] = m.Update.Version
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "-") {
+			continue
+		}
+
+		req, marker, _ := strings.Cut(trimmed, ";")
+		specAt := strings.IndexAny(req, "=><~!")
+		if specAt < 0 {
+			continue
+		}
+		name := strings.TrimSpace(req[:specAt])
+		base, _, _ := strings.Cut(name, "[")
+		newVersion, ok := updateMap[strings.ToLower(base)]
+		if !ok {
+			continue
+		}
+
+		op := "=="
+		spec := strings.TrimSpace(req[specAt:])
+		if strings.HasPrefix(spec, ">=") || strings.HasPrefix(spec, "~=") {
+			op = spec[:2]
+		}
+		lines[i] = name + op + newVersion
+		if marker != "" {
+			lines[i] += "; " + strings.TrimSpace(marker)
+		}
+	}
+
+	return os.WriteFile(inPath, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
 // UpdateSinglePackage updates a single pip package to its specified version.
 func (u *Updater) UpdateSinglePackage(module scanner.Module) error {
 	return u.UpdatePackages([]scanner.Module{module})
@@ -3,6 +3,7 @@ package pip
 import (
 	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -189,3 +190,86 @@ gunicorn
 		t.Errorf("expected requirements.txt content:\n%q\ngot:\n%q", expectedContent, string(updatedReq))
 	}
 }
+
+func TestUpdatePackages_PipTools(t *testing.T) {
+	tempDir := t.TempDir()
+	inContent := `# Direct dependencies
+flask>=2.2
+requests[socks]==2.28.0; python_version >= "3.8"
+django~=4.1.0
+gunicorn
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "requirements.in"), []byte(inContent), 0644); err != nil {
+		t.Fatalf("failed to write requirements.in: %v", err)
+	}
+	txtContent := "flask==2.2.0\n    # via -r requirements.in\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "requirements.txt"), []byte(txtContent), 0644); err != nil {
+		t.Fatalf("failed to write requirements.txt: %v", err)
+	}
+
+	var commands []string
+	updater := &Updater{
+		workDir: tempDir,
+		runCmd: func(name string, args ...string) ([]byte, error) {
+			commands = append(commands, name+" "+strings.Join(args, " "))
+			return nil, nil
+		},
+	}
+
+	modules := []scanner.Module{
+		{Name: "Flask", Update: &scanner.UpdateInfo{Version: "3.0.0"}},
+		{Name: "requests", Update: &scanner.UpdateInfo{Version: "2.31.0"}},
+		{Name: "django", Update: &scanner.UpdateInfo{Version: "4.2.0"}},
+		{Name: "gunicorn", Update: &scanner.UpdateInfo{Version: "21.2.0"}},
+	}
+	if err := updater.UpdatePackages(modules); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := `# Direct dependencies
+flask>=3.0.0
+requests[socks]==2.31.0; python_version >= "3.8"
+django~=4.2.0
+gunicorn
+`
+	got, _ := os.ReadFile(filepath.Join(tempDir, "requirements.in"))
+	if string(got) != want {
+		t.Errorf("unexpected requirements.in:\n%s", got)
+	}
+
+	last := commands[len(commands)-1]
+	if last != "pip-compile --quiet --upgrade-package Flask==3.0.0 --upgrade-package requests==2.31.0 --upgrade-package django==4.2.0 --upgrade-package gunicorn==21.2.0 requirements.in" {
+		t.Errorf("unexpected pip-compile invocation: %s", last)
+	}
+	if txt, _ := os.ReadFile(filepath.Join(tempDir, "requirements.txt")); string(txt) != txtContent {
+		t.Errorf("expected pip-compile to own requirements.txt, got:\n%s", txt)
+	}
+}
+
+func TestUpdatePackages_PipToolsWithoutPipCompile(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "requirements.in"), []byte("flask\n"), 0644); err != nil {
+		t.Fatalf("failed to write requirements.in: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "requirements.txt"), []byte("flask==2.2.0\n    # via -r requirements.in\n"), 0644); err != nil {
+		t.Fatalf("failed to write requirements.txt: %v", err)
+	}
+
+	updater := &Updater{
+		workDir: tempDir,
+		runCmd: func(name string, _ ...string) ([]byte, error) {
+			if name == "pip-compile" {
+				return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+			}
+			return nil, nil
+		},
+	}
+
+	err := updater.UpdatePackages([]scanner.Module{{Name: "flask", Update: &scanner.UpdateInfo{Version: "3.0.0"}}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if txt, _ := os.ReadFile(filepath.Join(tempDir, "requirements.txt")); string(txt) != "flask==3.0.0\n    # via -r requirements.in\n" {
+		t.Errorf("expected requirements.txt pins to be updated, got:\n%s", txt)
+	}
+}
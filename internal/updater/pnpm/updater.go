@@ -3,13 +3,20 @@ package pnpm
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"sort"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+	"github.com/pragmaticivan/faro/internal/workspace"
 )
 
 // Updater implements updater.Updater for pnpm.
 type Updater struct {
+	updater.Backups
+
 	workDir string
 	runCmd  func(name string, args ...string) ([]byte, error)
 }
@@ -21,49 +28,144 @@ func NewUpdater(workDir string) *Updater {
 		runCmd: func(name string, args ...string) ([]byte, error) {
 			cmd := exec.Command(name, args...)
 			cmd.Dir = workDir
-			return cmd.CombinedOutput()
+			return scanner.CombinedOutput(cmd)
 		},
 	}
 }
 
+// Files returns the root manifest and lockfile pnpm rewrites, and the
+// package.json of each workspace that declares one of modules.
+func (u *Updater) Files(modules []scanner.Module) []string {
+	return append([]string{"package.json", "pnpm-lock.yaml"}, u.workspaceManifests(modules)...)
+}
+
+// workspaceManifests returns the package.json paths of the workspaces, from
+// pnpm-workspace.yaml, that declare modules. Workspaces that can't be
+// resolved are left out.
+func (u *Updater) workspaceManifests(modules []scanner.Module) []string {
+	data, err := os.ReadFile(filepath.Join(u.workDir, "pnpm-workspace.yaml"))
+	if err != nil {
+		return nil
+	}
+	members, err := workspace.Resolve(u.workDir, workspace.PnpmPatterns(data))
+	if err != nil {
+		return nil
+	}
+	return workspace.Manifests(members, workspaceNames(modules))
+}
+
 // UpdatePackages updates multiple pnpm packages to their specified versions.
 func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	if len(modules) == 0 {
 		return nil
 	}
 
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
 	fmt.Printf("Upgrading %d packages...\n", len(modules))
 
-	deps := make([]string, 0)
-	devDeps := make([]string, 0)
-	for _, m := range modules {
-		pkgSpec := m.Name
-		if m.Update != nil && m.Update.Version != "" {
-			pkgSpec = fmt.Sprintf("%s@%s", m.Name, m.Update.Version)
+	groups, order := groupInstalls(modules)
+	for _, g := range order {
+		args := []string{"add"}
+		if g.dev {
+			args = append(args, "--save-dev")
 		}
+		if g.workspace != "" {
+			args = append(args, "--filter", g.workspace)
+		}
+		args = append(args, groups[g]...)
 
-		if m.DependencyType == "devDependencies" {
-			devDeps = append(devDeps, pkgSpec)
-		} else {
-			deps = append(deps, pkgSpec)
+		if out, err := u.runCmd("pnpm", args...); err != nil {
+			if g.dev {
+				return scanner.ToolError("pnpm", fmt.Errorf("pnpm add --save-dev failed: %s: %w", string(out), err))
+			}
+			return scanner.ToolError("pnpm", fmt.Errorf("pnpm add failed: %s: %w", string(out), err))
 		}
 	}
 
-	if len(deps) > 0 {
-		args := append([]string{"add"}, deps...)
+	return nil
+}
+
+// UpdateInRange runs `pnpm update --no-save` for modules, which installs the
+// newest version each package.json range allows (the wanted version) and
+// only updates pnpm-lock.yaml.
+func (u *Updater) UpdateInRange(modules []scanner.Module) error {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updating %d packages within their ranges...\n", len(modules))
+
+	for _, ws := range workspaceNames(modules) {
+		args := []string{"update", "--no-save"}
+		if ws != "" {
+			args = append(args, "--filter", ws)
+		}
+		for _, m := range modules {
+			if m.Workspace == ws {
+				args = append(args, m.Name)
+			}
+		}
+
 		if out, err := u.runCmd("pnpm", args...); err != nil {
-			return fmt.Errorf("pnpm add failed: %s: %w", string(out), err)
+			return scanner.ToolError("pnpm", fmt.Errorf("pnpm update failed: %s: %w", string(out), err))
 		}
 	}
+	return nil
+}
 
-	if len(devDeps) > 0 {
-		args := append([]string{"add", "--save-dev"}, devDeps...)
-		if out, err := u.runCmd("pnpm", args...); err != nil {
-			return fmt.Errorf("pnpm add --save-dev failed: %s: %w", string(out), err)
+// workspaceNames returns the distinct workspaces of modules, the root ("")
+// first and then by name.
+func workspaceNames(modules []scanner.Module) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, m := range modules {
+		if !seen[m.Workspace] {
+			seen[m.Workspace] = true
+			names = append(names, m.Workspace)
 		}
 	}
+	sort.Strings(names)
+	return names
+}
 
-	return nil
+// installGroup identifies the packages added by a single pnpm invocation.
+type installGroup struct {
+	workspace string
+	dev       bool
+}
+
+// groupInstalls buckets package specs by workspace and dependency type. The
+// root project comes first, then workspaces by name; production before dev.
+func groupInstalls(modules []scanner.Module) (map[installGroup][]string, []installGroup) {
+	groups := make(map[installGroup][]string)
+	var order []installGroup
+	for _, m := range modules {
+		pkgSpec := m.Name
+		if m.Update != nil && m.Update.Version != "" {
+			pkgSpec = fmt.Sprintf("%s@%s", m.Name, m.Update.Version)
+		}
+
+		g := installGroup{workspace: m.Workspace, dev: m.DependencyType == "devDependencies"}
+		if _, ok := groups[g]; !ok {
+			order = append(order, g)
+		}
+		groups[g] = append(groups[g], pkgSpec)
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].workspace != order[j].workspace {
+			return order[i].workspace < order[j].workspace
+		}
+		return !order[i].dev && order[j].dev
+	})
+	return groups, order
 }
 
 // UpdateSinglePackage updates a single pnpm package to its specified version.
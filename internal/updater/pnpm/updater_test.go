@@ -2,6 +2,8 @@ package pnpm
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -166,3 +168,84 @@ func TestUpdatePackages_AddDevFails(t *testing.T) {
 		t.Errorf("expected error to contain 'pnpm add --save-dev failed', got %v", err)
 	}
 }
+
+func TestUpdatePackages_Workspaces(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "lodash", DependencyType: "dependencies", Workspace: "pkg-a", Update: &scanner.UpdateInfo{Version: "4.17.21"}},
+		{Name: "vitest", DependencyType: "devDependencies", Workspace: "pkg-a", Update: &scanner.UpdateInfo{Version: "1.0.0"}},
+		{Name: "react", DependencyType: "dependencies", Update: &scanner.UpdateInfo{Version: "18.2.0"}},
+	}
+
+	var capturedCommands []string
+	updater := &Updater{
+		workDir: "/test/dir",
+		runCmd: func(name string, args ...string) ([]byte, error) {
+			capturedCommands = append(capturedCommands, name+" "+strings.Join(args, " "))
+			return nil, nil
+		},
+	}
+
+	if err := updater.UpdatePackages(modules); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"pnpm add react@18.2.0",
+		"pnpm add --filter pkg-a lodash@4.17.21",
+		"pnpm add --save-dev --filter pkg-a vitest@1.0.0",
+	}
+	if strings.Join(capturedCommands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected commands %q, got %q", expected, capturedCommands)
+	}
+}
+
+func TestFiles_Workspaces(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("package.json", `{"name":"root"}`)
+	write("pnpm-workspace.yaml", "packages:\n  - 'apps/*'\n")
+	write("apps/web/package.json", `{"name":"web"}`)
+	write("apps/api/package.json", `{"name":"api"}`)
+
+	files := NewUpdater(dir).Files([]scanner.Module{
+		{Name: "react", Workspace: "web", Update: &scanner.UpdateInfo{Version: "18.3.0"}},
+	})
+
+	want := []string{"package.json", "pnpm-lock.yaml", filepath.Join("apps", "web", "package.json")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("Files() = %q, want %q", files, want)
+	}
+}
+
+func TestUpdateInRange(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "express", Version: "4.18.0", Update: &scanner.UpdateInfo{Version: "4.18.2", Wanted: "4.18.2"}},
+		{Name: "react", Version: "18.2.0", Workspace: "web", Update: &scanner.UpdateInfo{Version: "18.3.1", Wanted: "18.3.1"}},
+	}
+
+	var capturedCommands []string
+	updater := &Updater{
+		workDir: "/test/dir",
+		runCmd: func(name string, args ...string) ([]byte, error) {
+			capturedCommands = append(capturedCommands, name+" "+strings.Join(args, " "))
+			return []byte("success"), nil
+		},
+	}
+
+	if err := updater.UpdateInRange(modules); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{"pnpm update --no-save express", "pnpm update --no-save --filter web react"}
+	if strings.Join(capturedCommands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected commands %q, got %q", expected, capturedCommands)
+	}
+}
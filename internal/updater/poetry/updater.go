@@ -6,10 +6,13 @@ import (
 	"os/exec"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
 )
 
 // Updater implements updater.Updater for Poetry.
 type Updater struct {
+	updater.Backups
+
 	workDir      string
 	runPoetryCmd func(args ...string) ([]byte, error)
 }
@@ -21,17 +24,26 @@ func NewUpdater(workDir string) *Updater {
 		runPoetryCmd: func(args ...string) ([]byte, error) {
 			cmd := exec.Command("poetry", args...)
 			cmd.Dir = workDir
-			return cmd.CombinedOutput()
+			return scanner.CombinedOutput(cmd)
 		},
 	}
 }
 
+// Files returns pyproject.toml and poetry.lock, which poetry add rewrites.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{"pyproject.toml", "poetry.lock"}
+}
+
 // UpdatePackages updates multiple Poetry packages to their specified versions.
 func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	if len(modules) == 0 {
 		return nil
 	}
 
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
 	fmt.Printf("Upgrading %d packages...\n", len(modules))
 
 	for _, m := range modules {
@@ -40,21 +52,30 @@ func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 			pkgSpec = fmt.Sprintf("%s@%s", m.Name, m.Update.Version)
 		}
 
-		var args []string
-		if m.DependencyType == "dev" {
-			args = []string{"add", "--group", "dev", pkgSpec}
-		} else {
-			args = []string{"add", pkgSpec}
+		args := []string{"add", pkgSpec}
+		if group := dependencyGroup(m.DependencyType); group != "" {
+			args = []string{"add", "--group", group, pkgSpec}
 		}
 
 		if out, err := u.runPoetryCmd(args...); err != nil {
-			return fmt.Errorf("poetry add failed: %s: %w", string(out), err)
+			return scanner.ToolError("poetry", fmt.Errorf("poetry add failed: %s: %w", string(out), err))
 		}
 	}
 
 	return nil
 }
 
+// dependencyGroup returns the Poetry group for a DependencyType, or "" for
+// main dependencies, extras and transitive dependencies.
+func dependencyGroup(depType string) string {
+	switch depType {
+	case "", "main", "optional", "transitive":
+		return ""
+	default:
+		return depType
+	}
+}
+
 // UpdateSinglePackage updates a single Poetry package to its specified version.
 func (u *Updater) UpdateSinglePackage(module scanner.Module) error {
 	return u.UpdatePackages([]scanner.Module{module})
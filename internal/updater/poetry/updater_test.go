@@ -2,6 +2,7 @@ package poetry
 
 import (
 	"errors"
+	"os/exec"
 	"strings"
 	"testing"
 
@@ -123,6 +124,36 @@ func TestUpdatePackages_DevOnly(t *testing.T) {
 	}
 }
 
+func TestUpdatePackages_CustomGroups(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "requests", DependencyType: "main", Update: &scanner.UpdateInfo{Version: "2.31.0"}},
+		{Name: "hypothesis", DependencyType: "test", Update: &scanner.UpdateInfo{Version: "6.100.0"}},
+		{Name: "mkdocs", DependencyType: "docs", Update: &scanner.UpdateInfo{Version: "1.6.0"}},
+	}
+
+	var capturedCommands []string
+	updater := &Updater{
+		workDir: "/test/dir",
+		runPoetryCmd: func(args ...string) ([]byte, error) {
+			capturedCommands = append(capturedCommands, "poetry "+strings.Join(args, " "))
+			return []byte("success"), nil
+		},
+	}
+
+	if err := updater.UpdatePackages(modules); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"poetry add requests@2.31.0",
+		"poetry add --group test hypothesis@6.100.0",
+		"poetry add --group docs mkdocs@1.6.0",
+	}
+	if strings.Join(capturedCommands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected commands %v, got %v", expected, capturedCommands)
+	}
+}
+
 func TestUpdatePackages_AddFails(t *testing.T) {
 	modules := []scanner.Module{
 		{Name: "requests", DependencyType: "main", Update: &scanner.UpdateInfo{Version: "2.28.1"}},
@@ -171,3 +202,20 @@ func TestUpdateSinglePackage(t *testing.T) {
 		t.Errorf("expected command %q, got %q", expected, capturedCommands[0])
 	}
 }
+
+func TestUpdatePackages_MissingBinary(t *testing.T) {
+	u := &Updater{
+		workDir: ".",
+		runPoetryCmd: func(args ...string) ([]byte, error) {
+			return nil, &exec.Error{Name: "poetry", Err: exec.ErrNotFound}
+		},
+	}
+
+	err := u.UpdatePackages([]scanner.Module{{Name: "requests", Update: &scanner.UpdateInfo{Version: "2.31.0"}}})
+	if err == nil || err.Error() != "poetry not found in PATH; install it or use --manager" {
+		t.Fatalf("expected a friendly missing binary error, got %v", err)
+	}
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("expected the error to wrap exec.ErrNotFound")
+	}
+}
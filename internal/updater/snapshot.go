@@ -0,0 +1,70 @@
+package updater
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Snapshot holds the contents of project files as they were before an
+// update, so a failed verification can put them back.
+type Snapshot struct {
+	dir   string
+	files []string
+	data  map[string][]byte // Missing for files that didn't exist
+	modes map[string]fs.FileMode
+}
+
+// TakeSnapshot reads files, relative to dir. Files that don't exist are
+// recorded too, and Restore removes them if the update created them.
+func TakeSnapshot(dir string, files []string) (*Snapshot, error) {
+	s := &Snapshot{
+		dir:   dir,
+		files: files,
+		data:  make(map[string][]byte, len(files)),
+		modes: make(map[string]fs.FileMode, len(files)),
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		s.data[file] = data
+		s.modes[file] = info.Mode().Perm()
+	}
+	return s, nil
+}
+
+// Files returns the snapshotted paths, relative to the project directory.
+func (s *Snapshot) Files() []string {
+	return s.files
+}
+
+// Restore writes every file back as it was when the snapshot was taken.
+func (s *Snapshot) Restore() error {
+	var errs []error
+	for _, file := range s.files {
+		path := filepath.Join(s.dir, file)
+		data, ok := s.data[file]
+		if !ok {
+			if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := os.WriteFile(path, data, s.modes[file]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", file, err))
+		}
+	}
+	return errors.Join(errs...)
+}
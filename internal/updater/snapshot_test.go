@@ -0,0 +1,46 @@
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot_Restore(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "package.json")
+	if err := os.WriteFile(manifest, []byte(`{"name":"app"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := TakeSnapshot(dir, []string{"package.json", "package-lock.json"})
+	if err != nil {
+		t.Fatalf("TakeSnapshot() error: %v", err)
+	}
+
+	// The update rewrites the manifest and creates the lockfile
+	if err := os.WriteFile(manifest, []byte(`{"name":"changed"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lock := filepath.Join(dir, "package-lock.json")
+	if err := os.WriteFile(lock, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := snap.Restore(); err != nil {
+		t.Fatalf("Restore() error: %v", err)
+	}
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"name":"app"}` {
+		t.Fatalf("expected package.json to be restored, got %s", data)
+	}
+	if info, err := os.Stat(manifest); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("expected package.json to keep mode 0600, got %v (%v)", info.Mode().Perm(), err)
+	}
+	if _, err := os.Stat(lock); !os.IsNotExist(err) {
+		t.Fatalf("expected the created lockfile to be removed, got %v", err)
+	}
+}
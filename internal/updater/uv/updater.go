@@ -6,10 +6,13 @@ import (
 	"os/exec"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
 )
 
 // Updater implements updater.Updater for uv.
 type Updater struct {
+	updater.Backups
+
 	workDir  string
 	runUvCmd func(args ...string) ([]byte, error)
 }
@@ -21,17 +24,26 @@ func NewUpdater(workDir string) *Updater {
 		runUvCmd: func(args ...string) ([]byte, error) {
 			cmd := exec.Command("uv", args...)
 			cmd.Dir = workDir
-			return cmd.CombinedOutput()
+			return scanner.CombinedOutput(cmd)
 		},
 	}
 }
 
+// Files returns pyproject.toml and uv.lock, which uv rewrites.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{"pyproject.toml", "uv.lock"}
+}
+
 // UpdatePackages updates multiple uv packages to their specified versions.
 func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	if len(modules) == 0 {
 		return nil
 	}
 
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
 	fmt.Printf("Upgrading %d packages...\n", len(modules))
 
 	for _, m := range modules {
@@ -42,7 +54,7 @@ func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 
 		args := []string{"pip", "install", pkgSpec}
 		if out, err := u.runUvCmd(args...); err != nil {
-			return fmt.Errorf("uv pip install failed: %s: %w", string(out), err)
+			return scanner.ToolError("uv", fmt.Errorf("uv pip install failed: %s: %w", string(out), err))
 		}
 	}
 
@@ -6,10 +6,13 @@ import (
 	"os/exec"
 
 	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
 )
 
 // Updater implements updater.Updater for yarn.
 type Updater struct {
+	updater.Backups
+
 	workDir string
 	runCmd  func(name string, args ...string) ([]byte, error)
 }
@@ -21,17 +24,26 @@ func NewUpdater(workDir string) *Updater {
 		runCmd: func(name string, args ...string) ([]byte, error) {
 			cmd := exec.Command(name, args...)
 			cmd.Dir = workDir
-			return cmd.CombinedOutput()
+			return scanner.CombinedOutput(cmd)
 		},
 	}
 }
 
+// Files returns the manifest and lockfile yarn rewrites.
+func (u *Updater) Files(_ []scanner.Module) []string {
+	return []string{"package.json", "yarn.lock"}
+}
+
 // UpdatePackages updates multiple yarn packages to their specified versions.
 func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	if len(modules) == 0 {
 		return nil
 	}
 
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
 	fmt.Printf("Upgrading %d packages...\n", len(modules))
 
 	deps := make([]string, 0)
@@ -52,20 +64,44 @@ func (u *Updater) UpdatePackages(modules []scanner.Module) error {
 	if len(deps) > 0 {
 		args := append([]string{"add"}, deps...)
 		if out, err := u.runCmd("yarn", args...); err != nil {
-			return fmt.Errorf("yarn add failed: %s: %w", string(out), err)
+			return scanner.ToolError("yarn", fmt.Errorf("yarn add failed: %s: %w", string(out), err))
 		}
 	}
 
 	if len(devDeps) > 0 {
 		args := append([]string{"add", "--dev"}, devDeps...)
 		if out, err := u.runCmd("yarn", args...); err != nil {
-			return fmt.Errorf("yarn add --dev failed: %s: %w", string(out), err)
+			return scanner.ToolError("yarn", fmt.Errorf("yarn add --dev failed: %s: %w", string(out), err))
 		}
 	}
 
 	return nil
 }
 
+// UpdateInRange runs `yarn upgrade` for modules, which installs the newest
+// version each package.json range allows (the wanted version) and only
+// updates yarn.lock.
+func (u *Updater) UpdateInRange(modules []scanner.Module) error {
+	if len(modules) == 0 {
+		return nil
+	}
+
+	if err := u.Backup(u.workDir, u.Files(modules)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updating %d packages within their ranges...\n", len(modules))
+
+	args := []string{"upgrade"}
+	for _, m := range modules {
+		args = append(args, m.Name)
+	}
+	if out, err := u.runCmd("yarn", args...); err != nil {
+		return scanner.ToolError("yarn", fmt.Errorf("yarn upgrade failed: %s: %w", string(out), err))
+	}
+	return nil
+}
+
 // UpdateSinglePackage updates a single yarn package to its specified version.
 func (u *Updater) UpdateSinglePackage(module scanner.Module) error {
 	return u.UpdatePackages([]scanner.Module{module})
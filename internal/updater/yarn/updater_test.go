@@ -166,3 +166,26 @@ func TestUpdatePackages_AddDevFails(t *testing.T) {
 		t.Errorf("expected error to contain 'yarn add --dev failed', got %v", err)
 	}
 }
+
+func TestUpdateInRange(t *testing.T) {
+	modules := []scanner.Module{
+		{Name: "express", Version: "4.18.0", Update: &scanner.UpdateInfo{Version: "4.18.2", Wanted: "4.18.2"}},
+		{Name: "jest", Version: "29.0.0", DependencyType: "devDependencies", Update: &scanner.UpdateInfo{Version: "29.7.0", Wanted: "29.7.0"}},
+	}
+
+	var capturedCommands []string
+	updater := &Updater{
+		workDir: "/test/dir",
+		runCmd: func(name string, args ...string) ([]byte, error) {
+			capturedCommands = append(capturedCommands, name+" "+strings.Join(args, " "))
+			return []byte("success"), nil
+		},
+	}
+
+	if err := updater.UpdateInRange(modules); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(capturedCommands) != 1 || capturedCommands[0] != "yarn upgrade express jest" {
+		t.Errorf("expected a single yarn upgrade, got %q", capturedCommands)
+	}
+}
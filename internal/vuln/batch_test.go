@@ -0,0 +1,176 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckModules_BatchesQueries(t *testing.T) {
+	var batches []osvBatchRequest
+	var singles []osvQuery
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/querybatch":
+			var req osvBatchRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("bad batch body: %v", err)
+			}
+			batches = append(batches, req)
+			// Only lodash 4.17.20 is vulnerable
+			results := make([]map[string]any, len(req.Queries))
+			for i, q := range req.Queries {
+				results[i] = map[string]any{}
+				if q.Package.Name == "lodash" && q.Version == "4.17.20" {
+					results[i]["vulns"] = []map[string]string{{"id": "GHSA-35jh-r3h4-6jhm"}}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"results": results})
+		case "/v1/query":
+			var q osvQuery
+			_ = json.NewDecoder(r.Body).Decode(&q)
+			singles = append(singles, q)
+			_, _ = w.Write([]byte(`{"vulns":[{"id":"GHSA-35jh-r3h4-6jhm","database_specific":{"severity":"HIGH"}}]}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cache := NewDiskCache(t.TempDir(), time.Hour, nil)
+	if err := cache.Put(osvCacheKey(srv.URL, "npm"), "react", "18.2.0", SeverityCounts{Low: 1, Total: 1}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	client := NewCachedClient("npm", srv.URL, cache)
+
+	queries := []Query{
+		{Name: "lodash", Version: "4.17.20"},
+		{Name: "react", Version: "18.2.0"},
+		{Name: "lodash", Version: "4.17.21"},
+	}
+	got, err := client.CheckModules(context.Background(), queries)
+	if err != nil {
+		t.Fatalf("CheckModules() error: %v", err)
+	}
+
+	want := []SeverityCounts{{High: 1, Total: 1}, {Low: 1, Total: 1}, {}}
+	if len(got) != len(want) {
+		t.Fatalf("CheckModules() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// One batch with the uncached versions, then a single query for severities
+	if len(batches) != 1 || len(batches[0].Queries) != 2 {
+		t.Fatalf("expected one batch of 2 queries, got %+v", batches)
+	}
+	for i, wantQ := range []Query{queries[0], queries[2]} {
+		q := batches[0].Queries[i]
+		if q.Package.Name != wantQ.Name || q.Version != wantQ.Version || q.Package.Ecosystem != "npm" {
+			t.Errorf("batch query %d = %+v, want %s@%s in npm", i, q, wantQ.Name, wantQ.Version)
+		}
+	}
+	if len(singles) != 1 || singles[0].Package.Name != "lodash" || singles[0].Version != "4.17.20" {
+		t.Errorf("expected a severity lookup for lodash 4.17.20 only, got %+v", singles)
+	}
+
+	// Everything is cached now
+	if _, err := client.CheckModules(context.Background(), queries); err != nil {
+		t.Fatalf("second CheckModules() error: %v", err)
+	}
+	if len(batches) != 1 {
+		t.Errorf("expected cached results to skip the network, got %d batches", len(batches))
+	}
+}
+
+func TestCheckModules_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewCachedClient("Go", srv.URL, nil)
+
+	if _, err := client.CheckModules(context.Background(), []Query{{Name: "example.com/a", Version: "v1.0.0"}}); err == nil {
+		t.Fatal("expected an error for a failed batch request")
+	}
+}
+
+func TestCheckModule_UsesConfiguredBaseURL(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// The mirror is served under a path prefix, given with a trailing slash
+	client := NewCachedClient("Go", srv.URL+"/osv/", nil)
+	if _, err := client.CheckModule(context.Background(), "example.com/a", "v1.0.0"); err != nil {
+		t.Fatalf("CheckModule() error: %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/osv/v1/query" {
+		t.Errorf("requests = %v, want [/osv/v1/query]", paths)
+	}
+}
+
+func TestCheckModule_DiskCacheKeyedByBaseURL(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	// Another endpoint's cached result must not answer for this one
+	cache := NewDiskCache(t.TempDir(), time.Hour, nil)
+	if err := cache.Put(osvCacheKey(DefaultBaseURL, "Go"), "example.com/a", "v1.0.0", SeverityCounts{High: 1, Total: 1}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	client := NewCachedClient("Go", srv.URL, cache)
+	got, err := client.CheckModule(context.Background(), "example.com/a", "v1.0.0")
+	if err != nil {
+		t.Fatalf("CheckModule() error: %v", err)
+	}
+	if requests != 1 || got != (SeverityCounts{}) {
+		t.Errorf("expected the mirror to be queried, got %d requests and %+v", requests, got)
+	}
+	if _, ok := cache.Get(osvCacheKey(srv.URL, "Go"), "example.com/a", "v1.0.0"); !ok {
+		t.Error("expected the mirror's result to be cached under its own key")
+	}
+}
+
+func TestParseBaseURL(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{raw: "https://osv.internal.example.com", want: "https://osv.internal.example.com"},
+		{raw: "http://localhost:8080/osv/", want: "http://localhost:8080/osv"},
+		{raw: " https://api.osv.dev ", want: "https://api.osv.dev"},
+		{raw: "api.osv.dev", wantErr: true},
+		{raw: "ftp://api.osv.dev", wantErr: true},
+		{raw: "https://", wantErr: true},
+		{raw: "https://api.osv.dev?key=1", wantErr: true},
+		{raw: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseBaseURL(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBaseURL(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseBaseURL(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
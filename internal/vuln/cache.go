@@ -0,0 +1,117 @@
+package vuln
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached OSV responses are considered fresh.
+const DefaultCacheTTL = 24 * time.Hour
+
+// CacheTTLEnv overrides DefaultCacheTTL, e.g. FARO_CACHE_TTL=1h.
+const CacheTTLEnv = "FARO_CACHE_TTL"
+
+// DiskCache stores vulnerability counts on disk, keyed by a source namespace
+// (the OSV endpoint and ecosystem, or the GHSA ecosystem), package name and
+// version, so repeated runs avoid querying the source again.
+type DiskCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// cacheEntry is the on-disk representation of a cached response.
+type cacheEntry struct {
+	FetchedAt time.Time      `json:"fetchedAt"`
+	Counts    SeverityCounts `json:"counts"`
+}
+
+// DefaultCacheDir returns the directory used for the OSV cache
+// (os.UserCacheDir()/faro/osv).
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache dir: %w", err)
+	}
+	return filepath.Join(base, "faro", "osv"), nil
+}
+
+// NewDiskCache creates a cache rooted at dir. A non-positive ttl uses
+// DefaultCacheTTL and a nil now uses time.Now.
+func NewDiskCache(dir string, ttl time.Duration, now func() time.Time) *DiskCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if now == nil {
+		now = time.Now
+	}
+	return &DiskCache{dir: dir, ttl: ttl, now: now}
+}
+
+// Get returns the cached counts if an entry exists and has not expired.
+func (c *DiskCache) Get(ecosystem, name, version string) (SeverityCounts, bool) {
+	data, err := os.ReadFile(c.path(ecosystem, name, version))
+	if err != nil {
+		return SeverityCounts{}, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return SeverityCounts{}, false
+	}
+	if c.now().Sub(entry.FetchedAt) > c.ttl {
+		return SeverityCounts{}, false
+	}
+	return entry.Counts, true
+}
+
+// Put stores counts for the given package version.
+func (c *DiskCache) Put(ecosystem, name, version string, counts SeverityCounts) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	data, err := json.Marshal(cacheEntry{FetchedAt: c.now(), Counts: counts})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	// Write atomically so concurrent runs never observe a partial entry.
+	tmp, err := os.CreateTemp(c.dir, "entry-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return os.Rename(tmp.Name(), c.path(ecosystem, name, version))
+}
+
+// Clear removes every cached entry.
+func (c *DiskCache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// Dir returns the cache directory.
+func (c *DiskCache) Dir() string {
+	return c.dir
+}
+
+func (c *DiskCache) path(ecosystem, name, version string) string {
+	sum := sha256.Sum256([]byte(ecosystem + "\x00" + name + "\x00" + version))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,92 @@
+package vuln
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDiskCache_PutGet(t *testing.T) {
+	now := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
+	c := NewDiskCache(t.TempDir(), time.Hour, func() time.Time { return now })
+
+	if _, ok := c.Get("Go", "example.com/mod", "v1.0.0"); ok {
+		t.Fatalf("expected miss on empty cache")
+	}
+
+	want := SeverityCounts{High: 1, Critical: 2, Total: 3}
+	if err := c.Put("Go", "example.com/mod", "v1.0.0", want); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	got, ok := c.Get("Go", "example.com/mod", "v1.0.0")
+	if !ok || got != want {
+		t.Fatalf("expected hit %+v, got %+v (ok=%v)", want, got, ok)
+	}
+
+	// Ecosystem and version are part of the key
+	if _, ok := c.Get("npm", "example.com/mod", "v1.0.0"); ok {
+		t.Fatalf("expected miss for other ecosystem")
+	}
+	if _, ok := c.Get("Go", "example.com/mod", "v1.0.1"); ok {
+		t.Fatalf("expected miss for other version")
+	}
+}
+
+func TestDiskCache_TTLExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)
+	c := NewDiskCache(t.TempDir(), 24*time.Hour, func() time.Time { return now })
+
+	if err := c.Put("npm", "left-pad", "1.0.0", SeverityCounts{Low: 1, Total: 1}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	now = now.Add(23 * time.Hour)
+	if _, ok := c.Get("npm", "left-pad", "1.0.0"); !ok {
+		t.Fatalf("expected entry to be fresh before TTL")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok := c.Get("npm", "left-pad", "1.0.0"); ok {
+		t.Fatalf("expected entry to expire after TTL")
+	}
+}
+
+func TestDiskCache_Clear(t *testing.T) {
+	c := NewDiskCache(t.TempDir(), 0, nil)
+	if err := c.Put("PyPI", "requests", "2.0.0", SeverityCounts{}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if _, ok := c.Get("PyPI", "requests", "2.0.0"); ok {
+		t.Fatalf("expected miss after clear")
+	}
+}
+
+func TestCachedClient_UsesDiskCacheBeforeNetwork(t *testing.T) {
+	cache := NewDiskCache(t.TempDir(), time.Hour, nil)
+	want := SeverityCounts{Medium: 2, Total: 2}
+	if err := cache.Put(osvCacheKey(DefaultBaseURL, "Go"), "example.com/cached", "v1.2.3", want); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	// A cancelled context would fail any network request, so a result
+	// proves the disk cache was consulted first.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewCachedClient("Go", "", cache)
+	got, err := client.CheckModule(ctx, "example.com/cached", "v1.2.3")
+	if err != nil {
+		t.Fatalf("expected cache hit, got error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err := client.CheckModule(ctx, "example.com/cached", "v9.9.9"); err == nil {
+		t.Fatalf("expected network error on cache miss")
+	}
+}
@@ -0,0 +1,324 @@
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source selects the advisory database vulnerability checks query.
+type Source string
+
+const (
+	SourceOSV  Source = "osv"  // OSV API (default)
+	SourceGHSA Source = "ghsa" // GitHub Advisory Database via GraphQL
+)
+
+// ParseSource validates a --vuln-source value; empty selects SourceOSV.
+func ParseSource(s string) (Source, error) {
+	switch src := Source(strings.ToLower(strings.TrimSpace(s))); src {
+	case "", SourceOSV:
+		return SourceOSV, nil
+	case SourceGHSA:
+		return src, nil
+	default:
+		return "", fmt.Errorf("unsupported --vuln-source value: %q (supported: osv, ghsa)", s)
+	}
+}
+
+// DefaultGHSAEndpoint is GitHub's GraphQL API endpoint.
+const DefaultGHSAEndpoint = "https://api.github.com/graphql"
+
+// ghsaQuery lists the advisories affecting a package; a package rarely has
+// more than one page of them.
+const ghsaQuery = `query($ecosystem: SecurityAdvisoryEcosystem!, $package: String!, $after: String) {
+  securityVulnerabilities(first: 100, ecosystem: $ecosystem, package: $package, after: $after) {
+    nodes {
+      vulnerableVersionRange
+      advisory { ghsaId severity withdrawnAt }
+    }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// ghsaEcosystems maps OSV ecosystem names to GitHub's SecurityAdvisoryEcosystem.
+var ghsaEcosystems = map[string]string{
+	"Go":       "GO",
+	"npm":      "NPM",
+	"PyPI":     "PIP",
+	"Maven":    "MAVEN",
+	"RubyGems": "RUBYGEMS",
+}
+
+// ghsaVulnerability is one advisory's affected range for a package.
+type ghsaVulnerability struct {
+	VulnerableVersionRange string `json:"vulnerableVersionRange"`
+	Advisory               struct {
+		GHSAID      string `json:"ghsaId"`
+		Severity    string `json:"severity"`
+		WithdrawnAt string `json:"withdrawnAt"`
+	} `json:"advisory"`
+}
+
+// ghsaResponse is the GraphQL response to ghsaQuery.
+type ghsaResponse struct {
+	Data struct {
+		SecurityVulnerabilities struct {
+			Nodes    []ghsaVulnerability `json:"nodes"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"securityVulnerabilities"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// GHSAClient implements Client using the GitHub Advisory Database. Advisories
+// are fetched once per package and matched against each version locally.
+type GHSAClient struct {
+	advisories map[string][]ghsaVulnerability // By package name
+	mu         sync.Mutex
+	httpClient *http.Client
+	endpoint   string
+	token      string
+	ecosystem  string     // OSV ecosystem name: "Go", "npm", "PyPI", etc.
+	diskCache  *DiskCache // Optional persistent cache shared across runs
+}
+
+// NewGHSAClient creates a vulnerability client for ecosystem backed by the
+// GitHub GraphQL API at endpoint (DefaultGHSAEndpoint when empty),
+// authenticating with token. A nil diskCache disables persistent caching.
+func NewGHSAClient(ecosystem, endpoint, token string, diskCache *DiskCache) Client {
+	if endpoint == "" {
+		endpoint = DefaultGHSAEndpoint
+	}
+	return &GHSAClient{
+		advisories: make(map[string][]ghsaVulnerability),
+		endpoint:   endpoint,
+		token:      token,
+		ecosystem:  ecosystem,
+		diskCache:  diskCache,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// CheckModule counts the advisories affecting a package version.
+func (c *GHSAClient) CheckModule(ctx context.Context, name, version string) (SeverityCounts, error) {
+	// Disk entries are keyed apart from OSV's so the sources never mix
+	cacheKey := "ghsa:" + c.ecosystem
+	if c.diskCache != nil {
+		if counts, ok := c.diskCache.Get(cacheKey, name, version); ok {
+			return counts, nil
+		}
+	}
+
+	vulns, err := c.fetch(ctx, name)
+	if err != nil {
+		return SeverityCounts{}, err
+	}
+
+	counts := SeverityCounts{}
+	for _, v := range vulns {
+		if v.Advisory.WithdrawnAt != "" || !inVersionRange(version, v.VulnerableVersionRange) {
+			continue
+		}
+		counts.Total++
+		switch strings.ToUpper(v.Advisory.Severity) {
+		case "LOW":
+			counts.Low++
+		case "HIGH":
+			counts.High++
+		case "CRITICAL":
+			counts.Critical++
+		default:
+			counts.Medium++ // MODERATE, or unknown
+		}
+	}
+
+	if c.diskCache != nil {
+		// A failed write only costs a future network round trip
+		_ = c.diskCache.Put(cacheKey, name, version, counts)
+	}
+	return counts, nil
+}
+
+// CheckModules checks each query in turn; versions of the same package share
+// one advisory lookup.
+func (c *GHSAClient) CheckModules(ctx context.Context, queries []Query) ([]SeverityCounts, error) {
+	results := make([]SeverityCounts, len(queries))
+	for i, q := range queries {
+		counts, err := c.CheckModule(ctx, q.Name, q.Version)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = counts
+	}
+	return results, nil
+}
+
+// fetch returns every advisory affecting the named package, following pages.
+func (c *GHSAClient) fetch(ctx context.Context, name string) ([]ghsaVulnerability, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if vulns, ok := c.advisories[name]; ok {
+		return vulns, nil
+	}
+
+	ecosystem, ok := ghsaEcosystems[c.ecosystem]
+	if !ok {
+		return nil, fmt.Errorf("GitHub Advisory Database does not support the %s ecosystem", c.ecosystem)
+	}
+
+	var vulns []ghsaVulnerability
+	after := ""
+	for {
+		variables := map[string]any{"ecosystem": ecosystem, "package": PackageName(c.ecosystem, name)}
+		if after != "" {
+			variables["after"] = after
+		}
+		var resp ghsaResponse
+		if err := c.post(ctx, map[string]any{"query": ghsaQuery, "variables": variables}, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("GitHub API error: %s", resp.Errors[0].Message)
+		}
+
+		page := resp.Data.SecurityVulnerabilities
+		vulns = append(vulns, page.Nodes...)
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			break
+		}
+		after = page.PageInfo.EndCursor
+	}
+
+	c.advisories[name] = vulns
+	return vulns, nil
+}
+
+// post sends a GraphQL request and decodes the response into out.
+func (c *GHSAClient) post(ctx context.Context, body, out any) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query GitHub API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode GitHub response: %w", err)
+	}
+	return nil
+}
+
+// inVersionRange reports whether version satisfies a GHSA range such as
+// "< 1.2.3", "= 2.0.0" or ">= 1.0.0, < 1.4.1".
+func inVersionRange(version, rng string) bool {
+	rng = strings.TrimSpace(rng)
+	if rng == "" {
+		return false
+	}
+	for _, cond := range strings.Split(rng, ",") {
+		cond = strings.TrimSpace(cond)
+		bound := strings.TrimLeft(cond, "<>=")
+		op := cond[:len(cond)-len(bound)]
+		c := compareVersions(version, bound)
+		var ok bool
+		switch op {
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		case "=", "":
+			ok = c == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares dotted versions segment by segment, numerically
+// where both segments are numbers. A leading "v" and build metadata are
+// ignored, and a pre-release sorts before its release.
+func compareVersions(a, b string) int {
+	a, aPre := splitPrerelease(a)
+	b, bPre := splitPrerelease(b)
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if c := compareSegments(x, y); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareSegments(aPre, bPre)
+}
+
+// splitPrerelease trims a "v" prefix and "+build" suffix and splits off the
+// "-pre" part of a version.
+func splitPrerelease(v string) (string, string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "+")
+	release, pre, _ := strings.Cut(v, "-")
+	return release, pre
+}
+
+// compareSegments compares two version segments, numerically when both are
+// numbers and lexically otherwise.
+func compareSegments(a, b string) int {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return x - y
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,125 @@
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// ghsaRequest is the GraphQL request body the client sends.
+type ghsaRequest struct {
+	Query     string            `json:"query"`
+	Variables map[string]string `json:"variables"`
+}
+
+func TestGHSAClient_CountsAffectingAdvisories(t *testing.T) {
+	var requests []ghsaRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "bearer secret" {
+			t.Errorf("Authorization = %q, want bearer token", got)
+		}
+		var req ghsaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("bad request body: %v", err)
+		}
+		requests = append(requests, req)
+
+		// Two pages of advisories for lodash
+		if req.Variables["after"] == "" {
+			_, _ = w.Write([]byte(`{"data":{"securityVulnerabilities":{
+				"nodes":[
+					{"vulnerableVersionRange":"< 4.17.21","advisory":{"ghsaId":"GHSA-35jh-r3h4-6jhm","severity":"HIGH"}},
+					{"vulnerableVersionRange":">= 4.0.0, < 4.17.12","advisory":{"ghsaId":"GHSA-jf85-cpcp-j695","severity":"CRITICAL"}}
+				],
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"securityVulnerabilities":{
+			"nodes":[
+				{"vulnerableVersionRange":"= 4.17.20","advisory":{"ghsaId":"GHSA-29mw-wpgm-hmr9","severity":"MODERATE"}},
+				{"vulnerableVersionRange":"< 5.0.0","advisory":{"ghsaId":"GHSA-xxxx-xxxx-xxxx","severity":"LOW","withdrawnAt":"2021-01-01T00:00:00Z"}}
+			],
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}`))
+	}))
+	defer srv.Close()
+
+	client := NewGHSAClient("npm", srv.URL, "secret", nil)
+	got, err := client.CheckModules(context.Background(), []Query{
+		{Name: "lodash", Version: "4.17.20"},
+		{Name: "lodash", Version: "4.17.11"},
+		{Name: "lodash", Version: "4.17.21"},
+	})
+	if err != nil {
+		t.Fatalf("CheckModules() error: %v", err)
+	}
+
+	want := []SeverityCounts{
+		{Medium: 1, High: 1, Total: 2},
+		{High: 1, Critical: 1, Total: 2},
+		{},
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// Both pages are fetched once and shared across versions
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+	if v := requests[0].Variables; v["ecosystem"] != "NPM" || v["package"] != "lodash" {
+		t.Errorf("unexpected variables %v", v)
+	}
+	if requests[1].Variables["after"] != "c1" {
+		t.Errorf("expected the second page to start after c1, got %v", requests[1].Variables)
+	}
+}
+
+func TestGHSAClient_GraphQLError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"Bad credentials"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewGHSAClient("Go", srv.URL, "bad", nil)
+	if _, err := client.CheckModule(context.Background(), "example.com/a", "v1.0.0"); err == nil {
+		t.Fatal("expected the GraphQL error to be returned")
+	}
+}
+
+func TestInVersionRange(t *testing.T) {
+	tests := []struct {
+		version string
+		rng     string
+		want    bool
+	}{
+		{"1.2.2", "< 1.2.3", true},
+		{"1.2.3", "< 1.2.3", false},
+		{"1.2.3", "<= 1.2.3", true},
+		{"v0.9.0", ">= 0.5.0, < 0.10.0", true},
+		{"0.10.0", ">= 0.5.0, < 0.10.0", false},
+		{"2.0.0", "= 2.0.0", true},
+		{"2.0.0-rc.1", "< 2.0.0", true},
+		{"1.0", "< 1.0.1", true},
+		{"1.0.0", "", false},
+	}
+	for _, tt := range tests {
+		if got := inVersionRange(tt.version, tt.rng); got != tt.want {
+			t.Errorf("inVersionRange(%q, %q) = %v, want %v", tt.version, tt.rng, got, tt.want)
+		}
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	for in, want := range map[string]Source{"": SourceOSV, "osv": SourceOSV, "GHSA": SourceGHSA} {
+		if got, err := ParseSource(in); err != nil || got != want {
+			t.Errorf("ParseSource(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseSource("snyk"); err == nil {
+		t.Error("expected an error for an unsupported source")
+	}
+}
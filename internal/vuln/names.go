@@ -0,0 +1,27 @@
+package vuln
+
+import "strings"
+
+// goModuleRenames maps Go module paths that were renamed after advisories
+// started being filed to the path the advisories use.
+var goModuleRenames = map[string]string{
+	"github.com/Sirupsen/logrus": "github.com/sirupsen/logrus",
+}
+
+// PackageName returns the name advisories in ecosystem are filed under for
+// the package a scanner reported as name.
+//
+// Go advisories are keyed by module path, so vanity paths such as
+// gopkg.in/yaml.v3 and k8s.io/client-go are already canonical and are kept
+// as they are; rewriting them to the repository hosting the code would miss
+// their advisories. Only renamed modules are mapped.
+func PackageName(ecosystem, name string) string {
+	name = strings.TrimSpace(name)
+	if ecosystem != "Go" {
+		return name
+	}
+	if renamed, ok := goModuleRenames[name]; ok {
+		return renamed
+	}
+	return name
+}
@@ -0,0 +1,23 @@
+package vuln
+
+import "testing"
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		ecosystem string
+		name      string
+		want      string
+	}{
+		{"Go", "gopkg.in/yaml.v3", "gopkg.in/yaml.v3"},
+		{"Go", "k8s.io/client-go", "k8s.io/client-go"},
+		{"Go", "k8s.io/kubernetes", "k8s.io/kubernetes"},
+		{"Go", "github.com/Sirupsen/logrus", "github.com/sirupsen/logrus"},
+		{"Go", " golang.org/x/net ", "golang.org/x/net"},
+		{"npm", "github.com/Sirupsen/logrus", "github.com/Sirupsen/logrus"},
+	}
+	for _, tt := range tests {
+		if got := PackageName(tt.ecosystem, tt.name); got != tt.want {
+			t.Errorf("PackageName(%q, %q) = %q, want %q", tt.ecosystem, tt.name, got, tt.want)
+		}
+	}
+}
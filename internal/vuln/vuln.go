@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -23,14 +24,37 @@ type SeverityCounts struct {
 // Client provides vulnerability checking capabilities
 type Client interface {
 	CheckModule(ctx context.Context, modulePath, version string) (SeverityCounts, error)
+
+	// CheckModules checks many package versions at once, returning counts
+	// in the order of queries
+	CheckModules(ctx context.Context, queries []Query) ([]SeverityCounts, error)
+}
+
+// Query identifies a package version to check.
+type Query struct {
+	Name    string
+	Version string
 }
 
+// DefaultBaseURL is the public OSV API endpoint.
+const DefaultBaseURL = "https://api.osv.dev"
+
+// BaseURLEnv names the environment variable that points faro at an OSV
+// mirror or proxy instead of DefaultBaseURL.
+const BaseURLEnv = "FARO_OSV_URL"
+
+// maxBatchSize is the most queries OSV accepts in one querybatch request.
+const maxBatchSize = 1000
+
 // RealClient implements Client using OSV API
 type RealClient struct {
 	cache      map[string]SeverityCounts
 	cacheMu    sync.RWMutex
 	httpClient *http.Client
-	ecosystem  string // "Go", "npm", "PyPI", etc.
+	baseURL    string
+	ecosystem  string     // "Go", "npm", "PyPI", etc.
+	diskCache  *DiskCache // Optional persistent cache shared across runs
+	diskKey    string     // Disk cache namespace: the endpoint and ecosystem
 }
 
 // NewClient creates a new vulnerability client for Go ecosystem
@@ -40,15 +64,48 @@ func NewClient() Client {
 
 // NewClientForEcosystem creates a new vulnerability client for a specific ecosystem
 func NewClientForEcosystem(ecosystem string) Client {
+	return NewCachedClient(ecosystem, "", nil)
+}
+
+// NewCachedClient creates a vulnerability client for ecosystem that queries
+// the OSV API at baseURL (DefaultBaseURL when empty), consulting diskCache
+// first. A nil diskCache disables persistent caching.
+func NewCachedClient(ecosystem, baseURL string, diskCache *DiskCache) Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
 	return &RealClient{
 		cache:     make(map[string]SeverityCounts),
+		baseURL:   baseURL,
 		ecosystem: ecosystem,
+		diskCache: diskCache,
+		diskKey:   osvCacheKey(baseURL, ecosystem),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
 	}
 }
 
+// osvCacheKey namespaces disk cache entries by OSV endpoint as well as
+// ecosystem, so a mirror's results never answer for another endpoint.
+func osvCacheKey(baseURL, ecosystem string) string {
+	return "osv:" + baseURL + ":" + ecosystem
+}
+
+// ParseBaseURL validates an OSV endpoint given by --osv-url or FARO_OSV_URL.
+// It must be an absolute http or https URL; a trailing slash is dropped.
+func ParseBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid OSV URL %q: expected an http(s) URL such as %s", raw, DefaultBaseURL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid OSV URL %q: query strings and fragments are not supported", raw)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
 // osvQuery represents the request to OSV API
 type osvQuery struct {
 	Package struct {
@@ -73,51 +130,154 @@ type osvResponse struct {
 	} `json:"vulns"`
 }
 
-// CheckModule fetches vulnerability data for a specific module version using OSV API
-func (c *RealClient) CheckModule(ctx context.Context, modulePath, version string) (SeverityCounts, error) {
-	cacheKey := fmt.Sprintf("%s@%s", modulePath, version)
+// osvBatchRequest is the body of an OSV querybatch request.
+type osvBatchRequest struct {
+	Queries []osvQuery `json:"queries"`
+}
+
+// osvBatchResponse lists, per query, the IDs of the matching vulnerabilities.
+// Severities aren't included, so they are fetched separately.
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// newQuery builds the OSV query for a package version in the client's ecosystem.
+func (c *RealClient) newQuery(name, version string) osvQuery {
+	query := osvQuery{}
+	query.Package.Name = PackageName(c.ecosystem, name)
+	query.Package.Ecosystem = c.ecosystem
+	query.Version = version
+	return query
+}
+
+// cached returns counts from the in-memory or disk cache.
+func (c *RealClient) cached(name, version string) (SeverityCounts, bool) {
+	cacheKey := fmt.Sprintf("%s@%s", name, version)
 
-	// Check cache first
 	c.cacheMu.RLock()
 	if counts, ok := c.cache[cacheKey]; ok {
 		c.cacheMu.RUnlock()
-		return counts, nil
+		return counts, true
 	}
 	c.cacheMu.RUnlock()
 
-	counts := SeverityCounts{}
+	// Then the disk cache, which survives between runs
+	if c.diskCache != nil {
+		if counts, ok := c.diskCache.Get(c.diskKey, name, version); ok {
+			c.cacheMu.Lock()
+			c.cache[cacheKey] = counts
+			c.cacheMu.Unlock()
+			return counts, true
+		}
+	}
+	return SeverityCounts{}, false
+}
 
-	// Prepare OSV API query
-	query := osvQuery{}
-	query.Package.Name = modulePath
-	query.Package.Ecosystem = c.ecosystem
-	query.Version = version
+// store caches counts in memory and on disk.
+func (c *RealClient) store(name, version string, counts SeverityCounts) {
+	c.cacheMu.Lock()
+	c.cache[fmt.Sprintf("%s@%s", name, version)] = counts
+	c.cacheMu.Unlock()
 
-	jsonData, err := json.Marshal(query)
+	if c.diskCache != nil {
+		// A failed write only costs a future network round trip
+		_ = c.diskCache.Put(c.diskKey, name, version, counts)
+	}
+}
+
+// post sends body as JSON to an OSV API path and decodes the response into out.
+func (c *RealClient) post(ctx context.Context, path string, body, out any) error {
+	jsonData, err := json.Marshal(body)
 	if err != nil {
-		return counts, fmt.Errorf("failed to marshal query: %w", err)
+		return fmt.Errorf("failed to marshal query: %w", err)
 	}
 
-	// Query OSV API
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.osv.dev/v1/query", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return counts, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return counts, fmt.Errorf("failed to query OSV API: %w", err)
+		return fmt.Errorf("failed to query OSV API: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return counts, fmt.Errorf("OSV API returned status %d", resp.StatusCode)
+		return fmt.Errorf("OSV API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode OSV response: %w", err)
+	}
+	return nil
+}
+
+// CheckModules checks queries with OSV's querybatch endpoint, in chunks of
+// maxBatchSize. Cached versions aren't sent. Since batch results carry no
+// severities, versions with vulnerabilities are then looked up one by one
+// with CheckModule; most versions have none, so this still saves most
+// round trips.
+func (c *RealClient) CheckModules(ctx context.Context, queries []Query) ([]SeverityCounts, error) {
+	results := make([]SeverityCounts, len(queries))
+
+	var pending []int
+	for i, q := range queries {
+		if counts, ok := c.cached(q.Name, q.Version); ok {
+			results[i] = counts
+		} else {
+			pending = append(pending, i)
+		}
+	}
+
+	for start := 0; start < len(pending); start += maxBatchSize {
+		chunk := pending[start:min(start+maxBatchSize, len(pending))]
+
+		var req osvBatchRequest
+		for _, i := range chunk {
+			req.Queries = append(req.Queries, c.newQuery(queries[i].Name, queries[i].Version))
+		}
+		var resp osvBatchResponse
+		if err := c.post(ctx, "/v1/querybatch", req, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Results) != len(chunk) {
+			return nil, fmt.Errorf("OSV API returned %d results for %d queries", len(resp.Results), len(chunk))
+		}
+
+		for j, i := range chunk {
+			q := queries[i]
+			if len(resp.Results[j].Vulns) == 0 {
+				c.store(q.Name, q.Version, SeverityCounts{})
+				continue
+			}
+			counts, err := c.CheckModule(ctx, q.Name, q.Version)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = counts
+		}
+	}
+	return results, nil
+}
+
+// CheckModule fetches vulnerability data for a specific module version using OSV API
+func (c *RealClient) CheckModule(ctx context.Context, modulePath, version string) (SeverityCounts, error) {
+	// Check cache first
+	if counts, ok := c.cached(modulePath, version); ok {
+		return counts, nil
 	}
 
+	counts := SeverityCounts{}
+
 	var osvResp osvResponse
-	if err := json.NewDecoder(resp.Body).Decode(&osvResp); err != nil {
-		return counts, fmt.Errorf("failed to decode OSV response: %w", err)
+	if err := c.post(ctx, "/v1/query", c.newQuery(modulePath, version), &osvResp); err != nil {
+		return counts, err
 	}
 
 	// Count vulnerabilities by severity
@@ -145,9 +305,7 @@ func (c *RealClient) CheckModule(ctx context.Context, modulePath, version string
 	}
 
 	// Cache the result
-	c.cacheMu.Lock()
-	c.cache[cacheKey] = counts
-	c.cacheMu.Unlock()
+	c.store(modulePath, version, counts)
 
 	return counts, nil
 }
@@ -0,0 +1,212 @@
+// Package workspace enumerates the member packages of npm and pnpm workspaces.
+package workspace
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Package is a single workspace member.
+type Package struct {
+	Name string // Name from the member's package.json (falls back to its directory)
+	Dir  string // Directory relative to the workspace root, slash-separated
+}
+
+// NpmPatterns returns the workspace globs declared in a root package.json.
+// Both the array form and the `{"packages": [...]}` object form are supported.
+func NpmPatterns(rootPackageJSON []byte) ([]string, error) {
+	var root struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(rootPackageJSON, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Workspaces) == 0 {
+		return nil, nil
+	}
+
+	var patterns []string
+	if err := json.Unmarshal(root.Workspaces, &patterns); err == nil {
+		return patterns, nil
+	}
+	var obj struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(root.Workspaces, &obj); err != nil {
+		return nil, fmt.Errorf("unsupported workspaces field: %w", err)
+	}
+	return obj.Packages, nil
+}
+
+// PnpmPatterns returns the `packages` globs from a pnpm-workspace.yaml file.
+// This is a minimal YAML reader that only understands the packages list.
+func PnpmPatterns(workspaceYAML []byte) []string {
+	var patterns []string
+	inPackages := false
+
+	sc := bufio.NewScanner(strings.NewReader(string(workspaceYAML)))
+	for sc.Scan() {
+		raw := sc.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(raw, " ") && !strings.HasPrefix(raw, "\t") && !strings.HasPrefix(line, "-") {
+			inPackages = strings.HasPrefix(line, "packages:")
+			continue
+		}
+
+		if inPackages && strings.HasPrefix(line, "-") {
+			value := strings.TrimSpace(strings.TrimPrefix(line, "-"))
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+			value = strings.Trim(value, `"'`)
+			if value != "" {
+				patterns = append(patterns, value)
+			}
+		}
+	}
+	return patterns
+}
+
+// Resolve expands workspace globs under root into member packages. Patterns
+// prefixed with "!" exclude matches; a trailing "/**" matches any nested
+// directory containing a package.json. node_modules is always skipped.
+// Results are sorted by directory.
+func Resolve(root string, patterns []string) ([]Package, error) {
+	dirs := make(map[string]bool)
+	var excludes []string
+
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+		if pattern == "" {
+			continue
+		}
+		if strings.HasPrefix(pattern, "!") {
+			excludes = append(excludes, strings.TrimPrefix(pattern[1:], "./"))
+			continue
+		}
+
+		matches, err := expand(root, pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			dirs[m] = true
+		}
+	}
+
+	var pkgs []Package
+	for dir := range dirs {
+		if excluded(dir, excludes) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(dir), "package.json"))
+		if err != nil {
+			continue
+		}
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		_ = json.Unmarshal(data, &pkg)
+		name := pkg.Name
+		if name == "" {
+			name = filepath.Base(dir)
+		}
+		pkgs = append(pkgs, Package{Name: name, Dir: dir})
+	}
+
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].Dir < pkgs[j].Dir })
+	return pkgs, nil
+}
+
+// Manifests returns the package.json paths, relative to the workspace root,
+// of the members of pkgs named in names, in directory order. Names without a
+// member, such as "" for the root project, are ignored.
+func Manifests(pkgs []Package, names []string) []string {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var files []string
+	for _, pkg := range pkgs {
+		if wanted[pkg.Name] {
+			files = append(files, filepath.Join(filepath.FromSlash(pkg.Dir), "package.json"))
+		}
+	}
+	return files
+}
+
+// expand returns slash-separated directories (relative to root) matched by pattern.
+func expand(root, pattern string) ([]string, error) {
+	if base, ok := strings.CutSuffix(pattern, "/**"); ok {
+		var out []string
+		start := filepath.Join(root, filepath.FromSlash(base))
+		err := filepath.WalkDir(start, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			if path == start {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return nil
+			}
+			out = append(out, filepath.ToSlash(rel))
+			return nil
+		})
+		return out, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace pattern %q: %w", pattern, err)
+	}
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(root, m)
+		if err != nil {
+			continue
+		}
+		if strings.Contains(rel, "node_modules") {
+			continue
+		}
+		out = append(out, filepath.ToSlash(rel))
+	}
+	return out, nil
+}
+
+// excluded reports whether dir matches any of the negated patterns.
+func excluded(dir string, excludes []string) bool {
+	for _, ex := range excludes {
+		if ok, _ := filepath.Match(ex, dir); ok {
+			return true
+		}
+		if base, found := strings.CutSuffix(ex, "/**"); found {
+			base = strings.TrimPrefix(base, "**/")
+			if dir == base || strings.HasPrefix(dir, base+"/") || strings.Contains("/"+dir+"/", "/"+base+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
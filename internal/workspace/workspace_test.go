@@ -0,0 +1,80 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestNpmPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{"array", `{"workspaces": ["packages/*", "apps/web"]}`, []string{"packages/*", "apps/web"}},
+		{"object", `{"workspaces": {"packages": ["packages/*"]}}`, []string{"packages/*"}},
+		{"none", `{"name": "root"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NpmPatterns([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("NpmPatterns() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NpmPatterns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPnpmPatterns(t *testing.T) {
+	yaml := `# workspace
+packages:
+  - 'packages/*'
+  - "apps/*" # apps
+  - '!**/test/**'
+catalog:
+  react: ^18.0.0
+`
+	want := []string{"packages/*", "apps/*", "!**/test/**"}
+	if got := PnpmPatterns([]byte(yaml)); !reflect.DeepEqual(got, want) {
+		t.Errorf("PnpmPatterns() = %v, want %v", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "packages", "a", "package.json"), `{"name": "@scope/a"}`)
+	writeFile(t, filepath.Join(root, "packages", "b", "package.json"), `{}`)
+	writeFile(t, filepath.Join(root, "packages", "test", "package.json"), `{"name": "test"}`)
+	writeFile(t, filepath.Join(root, "packages", "notes.txt"), "not a package")
+	writeFile(t, filepath.Join(root, "apps", "web", "package.json"), `{"name": "web"}`)
+	writeFile(t, filepath.Join(root, "apps", "web", "node_modules", "dep", "package.json"), `{"name": "dep"}`)
+
+	pkgs, err := Resolve(root, []string{"packages/*", "apps/**", "!packages/test"})
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	want := []Package{
+		{Name: "web", Dir: "apps/web"},
+		{Name: "@scope/a", Dir: "packages/a"},
+		{Name: "b", Dir: "packages/b"},
+	}
+	if !reflect.DeepEqual(pkgs, want) {
+		t.Errorf("Resolve() = %+v, want %+v", pkgs, want)
+	}
+}
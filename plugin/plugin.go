@@ -0,0 +1,35 @@
+// Package plugin lets programs that embed faro add support for package
+// managers it doesn't ship with, such as an internal one, without forking.
+// Register a scanner, and optionally an updater, before calling cmd.Execute;
+// the manager is then selected with --manager <name>.
+package plugin
+
+import (
+	"github.com/pragmaticivan/faro/internal/factory"
+	"github.com/pragmaticivan/faro/internal/scanner"
+	"github.com/pragmaticivan/faro/internal/updater"
+)
+
+// Types a custom scanner or updater implements and exchanges with faro.
+type (
+	Scanner         = scanner.Scanner
+	Updater         = updater.Updater
+	Options         = scanner.Options
+	Module          = scanner.Module
+	UpdateInfo      = scanner.UpdateInfo
+	DependencyIndex = scanner.DependencyIndex
+	DependencyInfo  = scanner.DependencyInfo
+)
+
+// RegisterScanner makes faro scan projects of the package manager name with
+// the scanner newScanner creates for the project directory. Registering a
+// built-in name replaces its scanner.
+func RegisterScanner(name string, newScanner func(workDir string) Scanner) {
+	factory.RegisterScanner(name, newScanner)
+}
+
+// RegisterUpdater makes faro apply updates for the package manager name with
+// the updater newUpdater creates for the project directory.
+func RegisterUpdater(name string, newUpdater func(workDir string) Updater) {
+	factory.RegisterUpdater(name, newUpdater)
+}